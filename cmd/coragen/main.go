@@ -0,0 +1,54 @@
+// Command coragen generates cora tools from the //cora:tool annotations of
+// the interfaces in a Go file (see package coragen). It writes them to
+// file_tools.go next to file.go unless -o is given:
+//
+//	coragen [-o out.go] file.go
+//
+// It is meant to be run by go generate:
+//
+//	//go:generate go run github.com/oraraka-deko/cora/cmd/coragen $GOFILE
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/oraraka-deko/cora/cora/coragen"
+)
+
+func main() {
+	out := flag.String("o", "", "output file (default: <file>_tools.go)")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: coragen [-o out.go] file.go")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out string) error {
+	if out == "" {
+		out = strings.TrimSuffix(in, ".go") + "_tools.go"
+	}
+	src, err := os.ReadFile(in)
+	if err != nil {
+		return fmt.Errorf("coragen: %w", err)
+	}
+	code, err := coragen.Generate(in, src)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, code, 0o644); err != nil {
+		return fmt.Errorf("coragen: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package cora
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultAgentStopSignal is the phrase that ends ModeAgentLoop when
+// TextRequest.AgentStopSignal is empty.
+const DefaultAgentStopSignal = "FINAL ANSWER:"
+
+// agentSystemPrompt prepends the agent instructions for goal to the user's
+// system prompt.
+func agentSystemPrompt(system, goal, stopSignal string) string {
+	var b strings.Builder
+	b.WriteString("You are an autonomous agent. Work toward the goal step by step, calling the tools as often as you need, without asking the user for help.")
+	if strings.TrimSpace(goal) != "" {
+		fmt.Fprintf(&b, "\nGoal: %s", goal)
+	}
+	fmt.Fprintf(&b, "\nWhen the goal is complete, reply with %q followed by your answer to the user.", stopSignal)
+	if strings.TrimSpace(system) != "" {
+		b.WriteString("\n\n" + system)
+	}
+	return b.String()
+}
+
+// agentContinuePrompt is sent after a model turn that neither called a tool
+// nor gave the stop signal.
+func agentContinuePrompt(stopSignal string) string {
+	return fmt.Sprintf("Continue working toward the goal. When it is complete, reply with %q followed by your answer.", stopSignal)
+}
+
+// agentFinalAnswer returns the text after the last stopSignal in text and
+// true, or false if the model has not given the signal.
+func agentFinalAnswer(text, stopSignal string) (string, bool) {
+	i := strings.LastIndex(text, stopSignal)
+	if i < 0 {
+		return "", false
+	}
+	return strings.TrimSpace(text[i+len(stopSignal):]), true
+}
@@ -0,0 +1,71 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestModeAgentLoop(t *testing.T) {
+	type chatBody struct {
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	var bodies []chatBody
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body chatBody
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		var msg map[string]any
+		switch len(bodies) {
+		case 1:
+			msg = toolCallMessage("call_1", "lookup", `{}`)
+		case 2:
+			msg = map[string]any{"role": "assistant", "content": "I found the stock level."}
+		default:
+			msg = map[string]any{"role": "assistant", "content": "All checked. DONE: 42 units"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{{"message": msg}}})
+	}))
+	defer srv.Close()
+
+	tools, handlers := lookupTool()
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	req := TextRequest{
+		Provider: ProviderOpenAI, Model: "gpt-test", Input: "How many units are left?", Mode: ModeAgentLoop,
+		Tools: tools, ToolHandlers: handlers, AgentGoal: "Report the stock level", AgentStopSignal: "DONE:",
+	}
+	resp, err := c.Text(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "42 units" || resp.AgentSteps != 3 {
+		t.Errorf("got %q after %d steps", resp.Text, resp.AgentSteps)
+	}
+	// Only the first step called tools; the continuation prompt is no tool round.
+	if resp.ToolRounds != 1 {
+		t.Errorf("ToolRounds = %d, want 1", resp.ToolRounds)
+	}
+	if system := bodies[0].Messages[0]; system.Role != "system" || !strings.Contains(system.Content, "Goal: Report the stock level") {
+		t.Errorf("system prompt = %+v", system)
+	}
+	// The turn without the stop signal was answered with a prompt to continue.
+	last := bodies[2].Messages[len(bodies[2].Messages)-1]
+	if last.Role != "user" || !strings.Contains(last.Content, `"DONE:"`) {
+		t.Errorf("last message of the third call = %+v", last)
+	}
+
+	// The model never gives the signal within the step budget.
+	bodies = nil
+	steps := 2
+	req.AgentStopSignal, req.AgentMaxSteps = "", &steps
+	if _, err := c.Text(context.Background(), req); err == nil || !strings.Contains(err.Error(), "exceeded maximum tool call rounds (2)") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package cora
+
+// Anthropic computer-use built-in tool types (see CoraTool.BuiltinType).
+const (
+	BuiltinToolComputer   = "computer_20241022"
+	BuiltinToolBash       = "bash_20241022"
+	BuiltinToolTextEditor = "text_editor_20241022"
+)
+
+// toAnthropicTools maps tools to the Anthropic Messages API "tools" format.
+// Built-in tools are emitted as {"type": ..., "name": ..., <options>} blocks;
+// regular tools are emitted as custom tools with an input_schema.
+func toAnthropicTools(tools []CoraTool) []map[string]any {
+	out := make([]map[string]any, 0, len(tools))
+	for _, t := range tools {
+		if t.BuiltinType != "" {
+			block := map[string]any{
+				"type": t.BuiltinType,
+				"name": t.Name,
+			}
+			for k, v := range t.BuiltinOptions {
+				block[k] = v
+			}
+			out = append(out, block)
+			continue
+		}
+
+		schema := t.ParametersSchema
+		if schema == nil {
+			schema = map[string]any{"type": "object"}
+		}
+		block := map[string]any{
+			"name":         t.Name,
+			"input_schema": schema,
+		}
+		if t.Description != "" {
+			block["description"] = t.Description
+		}
+		out = append(out, block)
+	}
+	return out
+}
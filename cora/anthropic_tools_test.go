@@ -0,0 +1,73 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestToAnthropicTools_Builtin(t *testing.T) {
+	noop := func(ctx context.Context, args map[string]any) (any, error) { return nil, nil }
+
+	tb := NewToolBuilder()
+	tb.AddBuiltinComputerTool(1024, 768, noop)
+	tb.AddBuiltinBashTool(noop)
+	_ = tb.AddFunc("get_weather", "Get current weather", getWeather)
+	tools, handlers := tb.Build()
+
+	if _, ok := handlers["computer"]; !ok {
+		t.Fatal("expected handler for built-in computer tool")
+	}
+
+	blob, err := json.Marshal(map[string]any{"tools": toAnthropicTools(tools)})
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	var req struct {
+		Tools []map[string]any `json:"tools"`
+	}
+	if err := json.Unmarshal(blob, &req); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if len(req.Tools) != 3 {
+		t.Fatalf("expected 3 tools, got %d", len(req.Tools))
+	}
+
+	computer := req.Tools[0]
+	if computer["type"] != "computer_20241022" || computer["name"] != "computer" {
+		t.Errorf("unexpected computer tool block: %v", computer)
+	}
+	if computer["display_width_px"] != 1024.0 || computer["display_height_px"] != 768.0 {
+		t.Errorf("expected display size in computer tool block: %v", computer)
+	}
+	if _, ok := computer["input_schema"]; ok {
+		t.Error("built-in tools must not carry an input_schema")
+	}
+
+	if req.Tools[1]["type"] != "bash_20241022" || req.Tools[1]["name"] != "bash" {
+		t.Errorf("unexpected bash tool block: %v", req.Tools[1])
+	}
+
+	custom := req.Tools[2]
+	if _, ok := custom["type"]; ok {
+		t.Errorf("custom tools must not carry a type: %v", custom)
+	}
+	if custom["name"] != "get_weather" || custom["input_schema"] == nil {
+		t.Errorf("unexpected custom tool block: %v", custom)
+	}
+}
+
+func TestBuildPlans_BuiltinToolUnsupported(t *testing.T) {
+	tb := NewToolBuilder()
+	tb.AddBuiltinBashTool(func(ctx context.Context, args map[string]any) (any, error) { return "", nil })
+	tools, handlers := tb.Build()
+
+	_, err := buildPlans(ProviderOpenAI, "gpt-test", TextRequest{
+		Mode:         ModeToolCalling,
+		Tools:        tools,
+		ToolHandlers: handlers,
+	}, CoraConfig{}, nil)
+	if err == nil {
+		t.Fatal("expected error for built-in tool on a provider without support")
+	}
+}
@@ -0,0 +1,46 @@
+package cora
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// AudioPart is inline audio sent with a TextRequest's Input, e.g. a spoken
+// question for Gemini (Google only). MIMEType is required, e.g. "audio/wav".
+type AudioPart struct {
+	Data     []byte
+	MIMEType string
+}
+
+// audioMIMETypes maps the file extensions accepted by NewAudioPartFromFile
+// to their MIME types.
+var audioMIMETypes = map[string]string{
+	".wav": "audio/wav",
+	".mp3": "audio/mp3",
+}
+
+// NewAudioPartFromFile reads a WAV or MP3 file into an AudioPart, taking the
+// MIME type from the file extension.
+func NewAudioPartFromFile(path string) (*AudioPart, error) {
+	mimeType, ok := audioMIMETypes[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, fmt.Errorf("cora: unsupported audio file %q: want .wav or .mp3", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cora: reading audio file: %w", err)
+	}
+	return &AudioPart{Data: data, MIMEType: mimeType}, nil
+}
+
+// withAudioPart returns docs with audio appended as an inline document, so
+// that it follows the text part of the user content.
+func withAudioPart(docs []DocumentPart, audio *AudioPart) []DocumentPart {
+	if audio == nil {
+		return docs
+	}
+	return append(slices.Clip(docs), DocumentPart{Data: audio.Data, MIMEType: audio.MIMEType})
+}
@@ -0,0 +1,74 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewAudioPartFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "question.WAV")
+	if err := os.WriteFile(path, []byte("RIFF"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	part, err := NewAudioPartFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part.MIMEType != "audio/wav" || string(part.Data) != "RIFF" {
+		t.Errorf("unexpected part %+v", part)
+	}
+	if _, err := NewAudioPartFromFile(filepath.Join(dir, "notes.txt")); err == nil {
+		t.Error("expected an error for a non-audio extension")
+	}
+}
+
+func TestGoogleProvider_AudioPart(t *testing.T) {
+	var body struct {
+		Contents []struct {
+			Parts []struct {
+				Text       string `json:"text"`
+				InlineData *struct {
+					MIMEType string `json:"mimeType"`
+				} `json:"inlineData"`
+			} `json:"parts"`
+		} `json:"contents"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"candidates":[{"content":{"role":"model","parts":[{"text":"It is sunny."}]}}]}`)
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL, OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:  ProviderGoogle,
+		Model:     "gemini-1.5-flash",
+		Input:     "Answer the spoken question.",
+		AudioPart: &AudioPart{Data: []byte("ID3"), MIMEType: "audio/mp3"},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if len(body.Contents) != 1 || len(body.Contents[0].Parts) != 2 {
+		t.Fatalf("expected one content with text + audio parts, got %+v", body.Contents)
+	}
+	parts := body.Contents[0].Parts
+	if parts[0].Text != "Answer the spoken question." || parts[1].InlineData == nil || parts[1].InlineData.MIMEType != "audio/mp3" {
+		t.Errorf("unexpected parts %+v", parts)
+	}
+
+	_, err = c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-4o", Input: "hi", AudioPart: &AudioPart{Data: []byte("ID3"), MIMEType: "audio/mp3"}})
+	if !errors.Is(err, ErrNotSupportedByProvider) {
+		t.Errorf("expected OpenAI to reject AudioPart with ErrNotSupportedByProvider, got %v", err)
+	}
+}
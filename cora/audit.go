@@ -0,0 +1,180 @@
+package cora
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditLogger records every LLM call made through a Client.
+type AuditLogger interface {
+	LogCall(ctx context.Context, entry AuditEntry)
+}
+
+// AuditEntry describes one Text() or Stream() call.
+type AuditEntry struct {
+	ID       string
+	Provider string
+	Model    string
+	Mode     TextMode
+
+	Input  string
+	System string
+	Output string
+
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64 // estimated cost in USD when known, 0 otherwise
+
+	Timestamp     time.Time
+	CorrelationID string
+	Labels        map[string]string
+	Err           error
+}
+
+// WithAuditLogger sets the logger that receives an AuditEntry after every
+// Text() and Stream() call, and returns c for chaining.
+func (c *Client) WithAuditLogger(al AuditLogger) *Client {
+	c.audit = al
+	return c
+}
+
+func (c *Client) auditText(ctx context.Context, req TextRequest, resp TextResponse, err error) {
+	if c.audit == nil {
+		return
+	}
+	entry := AuditEntry{
+		ID:        newAuditID(),
+		Provider:  string(cmp.Or(resp.UsedProvider, req.Provider)),
+		Model:     cmp.Or(resp.UsedModel, req.Model),
+		Mode:      req.Mode,
+		Input:     req.Input,
+		System:    req.System,
+		Output:    resp.Text,
+		Timestamp: time.Now(),
+		Labels:    mergeLabels(c.cfg.DefaultLabels, req.Labels),
+
+		CorrelationID: CorrelationIDFromContext(ctx),
+		Err:           err,
+	}
+	if resp.PromptTokens != nil {
+		entry.PromptTokens = *resp.PromptTokens
+	}
+	if resp.CompletionTokens != nil {
+		entry.CompletionTokens = *resp.CompletionTokens
+	}
+	if resp.EstimatedCostUSD != nil {
+		entry.Cost = *resp.EstimatedCostUSD
+	}
+	c.audit.LogCall(ctx, entry)
+}
+
+func (c *Client) auditStream(ctx context.Context, req StreamRequest, model, output string, usage *StreamUsage, err error) {
+	if c.audit == nil {
+		return
+	}
+	entry := AuditEntry{
+		ID:        newAuditID(),
+		Provider:  string(req.Provider),
+		Model:     model,
+		Mode:      streamMode(req),
+		Input:     req.Input,
+		System:    req.System,
+		Output:    output,
+		Timestamp: time.Now(),
+		Err:       err,
+
+		CorrelationID: CorrelationIDFromContext(ctx),
+	}
+	if usage != nil {
+		entry.PromptTokens = usage.PromptTokens
+		entry.CompletionTokens = usage.CompletionTokens
+		entry.Cost, _ = estimateCost(c.cfg, model, usage.PromptTokens, usage.CompletionTokens)
+	}
+	c.audit.LogCall(ctx, entry)
+}
+
+func newAuditID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// JSONLineAuditLogger returns an AuditLogger that writes one JSON object per
+// line to w. Each line carries the SHA-256 hash of the previous line's hash
+// and its own content, forming a chain in which any edited or removed line
+// is detectable. It is safe for concurrent use.
+func JSONLineAuditLogger(w io.Writer) AuditLogger {
+	return &jsonLineAuditLogger{w: w}
+}
+
+type jsonLineAuditLogger struct {
+	mu       sync.Mutex
+	w        io.Writer
+	prevHash string
+}
+
+type auditRecord struct {
+	ID               string            `json:"id"`
+	Provider         string            `json:"provider"`
+	Model            string            `json:"model"`
+	Mode             TextMode          `json:"mode"`
+	Input            string            `json:"input"`
+	System           string            `json:"system,omitempty"`
+	Output           string            `json:"output"`
+	PromptTokens     int               `json:"prompt_tokens"`
+	CompletionTokens int               `json:"completion_tokens"`
+	Cost             float64           `json:"cost"`
+	Timestamp        time.Time         `json:"timestamp"`
+	CorrelationID    string            `json:"correlation_id,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	Error            string            `json:"error,omitempty"`
+	PrevHash         string            `json:"prev_hash"`
+	Hash             string            `json:"hash,omitempty"`
+}
+
+func (l *jsonLineAuditLogger) LogCall(ctx context.Context, entry AuditEntry) {
+	rec := auditRecord{
+		ID:               entry.ID,
+		Provider:         entry.Provider,
+		Model:            entry.Model,
+		Mode:             entry.Mode,
+		Input:            entry.Input,
+		System:           entry.System,
+		Output:           entry.Output,
+		PromptTokens:     entry.PromptTokens,
+		CompletionTokens: entry.CompletionTokens,
+		Cost:             entry.Cost,
+		Timestamp:        entry.Timestamp,
+		CorrelationID:    entry.CorrelationID,
+		Labels:           entry.Labels,
+	}
+	if entry.Err != nil {
+		rec.Error = entry.Err.Error()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rec.PrevHash = l.prevHash
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	sum := sha256.Sum256(body)
+	rec.Hash = hex.EncodeToString(sum[:])
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	if _, err := l.w.Write(append(line, '\n')); err == nil {
+		l.prevHash = rec.Hash
+	}
+}
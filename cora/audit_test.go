@@ -0,0 +1,104 @@
+package cora
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingAuditLogger keeps every entry it receives.
+type recordingAuditLogger struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+func (r *recordingAuditLogger) LogCall(ctx context.Context, entry AuditEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+func TestAuditLogger_Text(t *testing.T) {
+	rec := &recordingAuditLogger{}
+	c := (&Client{cfg: CoraConfig{}}).WithAuditLogger(rec)
+	c.openai = &fakeProvider{finalOut: "hello"}
+	c.google = &failingProvider{err: errors.New("boom")}
+
+	_, _ = c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Input:    "hi",
+		System:   "be nice",
+		Labels:   map[string]string{"team": "core"},
+	})
+	_, _ = c.Text(context.Background(), TextRequest{Provider: ProviderGoogle, Model: "gemini-test", Input: "hi"})
+
+	if len(rec.entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(rec.entries))
+	}
+	ok := rec.entries[0]
+	if ok.Provider != "openai" || ok.Model != "gpt-test" || ok.Input != "hi" || ok.System != "be nice" ||
+		ok.Output != "hello" || ok.Labels["team"] != "core" || ok.Err != nil || ok.ID == "" || ok.Timestamp.IsZero() {
+		t.Errorf("unexpected success entry: %+v", ok)
+	}
+	if failed := rec.entries[1]; failed.Err == nil || failed.Provider != "google" {
+		t.Errorf("expected failed call to be logged with its error: %+v", failed)
+	}
+}
+
+func TestAuditLogger_Stream(t *testing.T) {
+	rec := &recordingAuditLogger{}
+	c := (&Client{cfg: CoraConfig{}}).WithAuditLogger(rec)
+	c.openai = &fakeProvider{finalOut: "streamed answer"}
+
+	resp, err := c.Stream(context.Background(), StreamRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	for range resp.Events {
+	}
+
+	if len(rec.entries) != 1 || rec.entries[0].Output != "streamed answer" {
+		t.Errorf("expected stream output in audit log, got %+v", rec.entries)
+	}
+}
+
+func TestJSONLineAuditLogger_HashChain(t *testing.T) {
+	var buf bytes.Buffer
+	logger := JSONLineAuditLogger(&buf)
+	logger.LogCall(context.Background(), AuditEntry{ID: "1", Provider: "openai", Mode: ModeToolCalling, Output: "a"})
+	logger.LogCall(context.Background(), AuditEntry{ID: "2", Provider: "google", Output: "b", Err: errors.New("boom")})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+
+	prev := ""
+	for i, line := range lines {
+		var rec auditRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+		if rec.PrevHash != prev {
+			t.Errorf("line %d: prev_hash %q, want %q", i, rec.PrevHash, prev)
+		}
+		hash := rec.Hash
+		rec.Hash = ""
+		body, _ := json.Marshal(rec)
+		sum := sha256.Sum256(body)
+		if hex.EncodeToString(sum[:]) != hash {
+			t.Errorf("line %d: hash does not match content", i)
+		}
+		prev = hash
+	}
+	if !strings.Contains(lines[0], `"mode":"tool_calling"`) || !strings.Contains(lines[1], `"error":"boom"`) {
+		t.Errorf("unexpected lines: %s", buf.String())
+	}
+}
@@ -0,0 +1,204 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BatchJobStatus is the state of a BatchJob.
+type BatchJobStatus int
+
+const (
+	// BatchJobPending is a job queued but not yet running.
+	BatchJobPending BatchJobStatus = iota
+	// BatchJobRunning is a job being processed.
+	BatchJobRunning
+	// BatchJobSucceeded is a finished job whose results are available.
+	BatchJobSucceeded
+	// BatchJobFailed is a job that failed as a whole.
+	BatchJobFailed
+	// BatchJobCancelled is a job stopped by BatchJob.Cancel.
+	BatchJobCancelled
+	// BatchJobExpired is a job that did not finish in the provider's time limit.
+	BatchJobExpired
+)
+
+var batchJobStatusNames = map[BatchJobStatus]string{
+	BatchJobPending:   "pending",
+	BatchJobRunning:   "running",
+	BatchJobSucceeded: "succeeded",
+	BatchJobFailed:    "failed",
+	BatchJobCancelled: "cancelled",
+	BatchJobExpired:   "expired",
+}
+
+// String returns the status name, e.g. "running".
+func (s BatchJobStatus) String() string {
+	if name, ok := batchJobStatusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("BatchJobStatus(%d)", int(s))
+}
+
+// done reports whether s is a final state.
+func (s BatchJobStatus) done() bool {
+	return s >= BatchJobSucceeded
+}
+
+// defaultBatchPollInterval is used when BatchJob.PollInterval is zero.
+const defaultBatchPollInterval = 30 * time.Second
+
+// BatchJob is an asynchronous batch of requests submitted with
+// Client.BatchText.
+type BatchJob struct {
+	// ID is the provider's job name, e.g. "batches/123" or "batch_abc123".
+	ID string
+	// Status is the last known state of the job, updated by Wait.
+	Status BatchJobStatus
+	// PollInterval is how often Wait checks the job (default 30s).
+	PollInterval time.Duration
+
+	client   *Client
+	runner   batchRunner
+	provider Provider
+	reqs     []TextRequest
+	model    string
+}
+
+// batchRunner is implemented by providers with an asynchronous batch API.
+type batchRunner interface {
+	CreateBatch(ctx context.Context, model string, plans []callPlan) (string, BatchJobStatus, error)
+	// GetBatch returns the job's status and, once it succeeded, one result
+	// (or error) per request, in order.
+	GetBatch(ctx context.Context, id string) (BatchJobStatus, []batchItem, error)
+	CancelBatch(ctx context.Context, id string) error
+}
+
+// batchItem is the outcome of one request of a batch job.
+type batchItem struct {
+	res callResult
+	err error
+}
+
+// BatchText submits reqs as one Gemini or OpenAI batch job, billed at the
+// batch rate (about half the real-time price) and processed asynchronously,
+// typically within hours (at most 24 for OpenAI). Unlike concurrent
+// real-time calls, the results are only available from BatchJob.Wait once
+// the whole job finished.
+//
+// All requests must use the same provider, ProviderGoogle or ProviderOpenAI,
+// the same model, and a single-call mode: ModeBasic, ModeStructuredJSON,
+// ModeClassify or ModeScore.
+func (c *Client) BatchText(ctx context.Context, reqs []TextRequest) (*BatchJob, error) {
+	if len(reqs) == 0 {
+		return nil, errors.New("cora: BatchText requires at least one request")
+	}
+	var model string
+	plans := make([]callPlan, len(reqs))
+	provider := reqs[0].Provider
+	for i, req := range reqs {
+		if req.Provider != ProviderGoogle && req.Provider != ProviderOpenAI {
+			return nil, fmt.Errorf("cora: batch request %d: BatchText only supports ProviderGoogle and ProviderOpenAI", i)
+		}
+		if req.Provider != provider {
+			return nil, fmt.Errorf("cora: batch request %d: provider %q differs from %q; a batch uses a single provider", i, req.Provider, provider)
+		}
+		switch req.Mode {
+		case ModeBasic, ModeStructuredJSON, ModeClassify, ModeScore:
+		default:
+			return nil, fmt.Errorf("cora: batch request %d: mode %s is not supported by BatchText", i, req.Mode)
+		}
+		m, err := c.resolveModel(req)
+		if err != nil {
+			return nil, fmt.Errorf("cora: batch request %d: %w", i, err)
+		}
+		if model == "" {
+			model = m
+		} else if m != model {
+			return nil, fmt.Errorf("cora: batch request %d: model %q differs from %q; a batch uses a single model", i, m, model)
+		}
+		p, err := buildPlans(req.Provider, m, req, c.cfg, c.promptOptimizer)
+		if err != nil {
+			return nil, fmt.Errorf("cora: batch request %d: %w", i, err)
+		}
+		plans[i] = p[0]
+	}
+
+	runner, err := c.batchRunner(provider, model)
+	if err != nil {
+		return nil, err
+	}
+	id, status, err := runner.CreateBatch(ctx, model, plans)
+	if err != nil {
+		return nil, wrapProviderError(provider, err)
+	}
+	return &BatchJob{ID: id, Status: status, client: c, runner: runner, provider: provider, reqs: reqs, model: model}, nil
+}
+
+// Wait polls the job every PollInterval until it finishes and returns one
+// response per request, in order. If some requests failed, the others'
+// responses are returned together with an error describing the failures.
+func (j *BatchJob) Wait(ctx context.Context) ([]TextResponse, error) {
+	interval := j.PollInterval
+	if interval <= 0 {
+		interval = defaultBatchPollInterval
+	}
+	for {
+		status, items, err := j.runner.GetBatch(ctx, j.ID)
+		if err != nil {
+			return nil, wrapProviderError(j.provider, err)
+		}
+		j.Status = status
+		if status.done() {
+			if status != BatchJobSucceeded {
+				return nil, fmt.Errorf("cora: batch job %s %s", j.ID, status)
+			}
+			return j.responses(items)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// responses converts the job's items to TextResponses.
+func (j *BatchJob) responses(items []batchItem) ([]TextResponse, error) {
+	if len(items) != len(j.reqs) {
+		return nil, fmt.Errorf("cora: batch job %s returned %d results for %d requests", j.ID, len(items), len(j.reqs))
+	}
+	out := make([]TextResponse, len(items))
+	var errs []error
+	for i, item := range items {
+		if item.err != nil {
+			errs = append(errs, fmt.Errorf("cora: batch request %d: %w", i, item.err))
+			continue
+		}
+		out[i] = j.client.toTextResponse(j.reqs[i], j.model, item.res)
+	}
+	return out, errors.Join(errs...)
+}
+
+// Cancel asks the provider to stop the job. Requests already processed are
+// still billed.
+func (j *BatchJob) Cancel(ctx context.Context) error {
+	if err := j.runner.CancelBatch(ctx, j.ID); err != nil {
+		return wrapProviderError(j.provider, err)
+	}
+	return nil
+}
+
+func (c *Client) batchRunner(provider Provider, model string) (batchRunner, error) {
+	pc, err := c.providerFor(provider, model)
+	if err != nil {
+		return nil, err
+	}
+	br, ok := pc.(batchRunner)
+	if !ok {
+		return nil, fmt.Errorf("cora: provider %q does not support batch jobs", provider)
+	}
+	return br, nil
+}
@@ -0,0 +1,123 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchServer fakes the Gemini batch API: the job runs for one poll, then
+// succeeds with one inlined response per submitted request.
+func batchServer(t *testing.T) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var calls []string
+	var submitted int
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, ":batchGenerateContent"):
+			var body struct {
+				Batch struct {
+					InputConfig struct {
+						Requests struct {
+							Requests []any `json:"requests"`
+						} `json:"requests"`
+					} `json:"inputConfig"`
+				} `json:"batch"`
+			}
+			data, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(data, &body); err != nil {
+				t.Errorf("bad create body: %v", err)
+			}
+			submitted = len(body.Batch.InputConfig.Requests.Requests)
+			_, _ = io.WriteString(w, `{"name":"batches/123","metadata":{"state":"BATCH_STATE_PENDING"}}`)
+		case strings.HasSuffix(r.URL.Path, ":cancel"):
+			_, _ = io.WriteString(w, `{}`)
+		case strings.HasSuffix(r.URL.Path, "/batches/123"):
+			polls++
+			if polls == 1 {
+				_, _ = io.WriteString(w, `{"name":"batches/123","metadata":{"state":"BATCH_STATE_RUNNING"}}`)
+				return
+			}
+			var responses []map[string]any
+			for i := range submitted {
+				responses = append(responses, map[string]any{"response": map[string]any{
+					"candidates": []any{map[string]any{"content": map[string]any{"role": "model", "parts": []any{map[string]any{"text": []string{"first", "second"}[i%2]}}}}},
+				}})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "batches/123", "metadata": map[string]any{
+				"state":  "BATCH_STATE_SUCCEEDED",
+				"output": map[string]any{"inlinedResponses": map[string]any{"inlinedResponses": responses}},
+			}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	return srv, &calls
+}
+
+func TestBatchText(t *testing.T) {
+	srv, calls := batchServer(t)
+	defer srv.Close()
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	job, err := c.BatchText(context.Background(), []TextRequest{
+		{Provider: ProviderGoogle, Model: "gemini-2.5-flash", Input: "one"},
+		{Provider: ProviderGoogle, Model: "gemini-2.5-flash", Input: "two"},
+	})
+	if err != nil {
+		t.Fatalf("BatchText error: %v", err)
+	}
+	if job.ID != "batches/123" || job.Status != BatchJobPending {
+		t.Fatalf("unexpected job %+v", job)
+	}
+
+	job.PollInterval = time.Millisecond
+	resps, err := job.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("Wait error: %v", err)
+	}
+	if job.Status != BatchJobSucceeded {
+		t.Errorf("Status = %s, want succeeded", job.Status)
+	}
+	if len(resps) != 2 || resps[0].Text != "first" || resps[1].Text != "second" || resps[1].Model != "gemini-2.5-flash" {
+		t.Fatalf("unexpected responses %+v", resps)
+	}
+	if n := len(*calls); n != 3 {
+		t.Errorf("expected create + 2 polls, got %v", *calls)
+	}
+
+	if err := job.Cancel(context.Background()); err != nil {
+		t.Fatalf("Cancel error: %v", err)
+	}
+	if last := (*calls)[len(*calls)-1]; last != "POST /v1beta/batches/123:cancel" {
+		t.Errorf("unexpected cancel request %q", last)
+	}
+}
+
+func TestBatchText_Validation(t *testing.T) {
+	c := New(CoraConfig{GoogleAPIKey: "g-test"})
+	for name, reqs := range map[string][]TextRequest{
+		"provider": {{Provider: ProviderOpenAI, Model: "gpt-4o", Input: "x"}},
+		"mode":     {{Provider: ProviderGoogle, Model: "gemini-2.5-flash", Mode: ModeToolCalling, Input: "x"}},
+		"models": {
+			{Provider: ProviderGoogle, Model: "gemini-2.5-flash", Input: "x"},
+			{Provider: ProviderGoogle, Model: "gemini-2.5-pro", Input: "y"},
+		},
+	} {
+		if _, err := c.BatchText(context.Background(), reqs); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
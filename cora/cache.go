@@ -0,0 +1,113 @@
+package cora
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cache is a size-bounded in-memory cache whose entries expire after a TTL.
+// When the cache is full, the oldest entry is evicted. It is safe for
+// concurrent use.
+type Cache[K comparable, V any] struct {
+	mu      sync.Mutex
+	entries map[K]cacheEntry[V]
+	ttl     time.Duration
+	maxSize int
+	hits    int64
+	misses  int64
+}
+
+type cacheEntry[V any] struct {
+	value     V
+	timestamp time.Time
+}
+
+// NewCache creates a cache with the specified TTL and max size.
+func NewCache[K comparable, V any](ttl time.Duration, maxSize int) *Cache[K, V] {
+	return &Cache[K, V]{
+		entries: make(map[K]cacheEntry[V]),
+		ttl:     ttl,
+		maxSize: maxSize,
+	}
+}
+
+// Get retrieves a cached value if available and not expired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[key]
+	if !exists || time.Since(entry.timestamp) > c.ttl {
+		c.misses++
+		var zero V
+		return zero, false
+	}
+
+	c.hits++
+	return entry.value, true
+}
+
+// Set stores a value in the cache, evicting the oldest entry if the cache is full.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxSize {
+		var oldestKey K
+		var oldestTime time.Time
+		for k, v := range c.entries {
+			if oldestTime.IsZero() || v.timestamp.Before(oldestTime) {
+				oldestKey = k
+				oldestTime = v.timestamp
+			}
+		}
+		if !oldestTime.IsZero() {
+			delete(c.entries, oldestKey)
+		}
+	}
+
+	c.entries[key] = cacheEntry[V]{value: value, timestamp: time.Now()}
+}
+
+// Delete removes the entry for key, if any.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Len returns the number of entries currently stored (including expired ones
+// that have not been evicted yet).
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Stats returns cache hit/miss statistics.
+func (c *Cache[K, V]) Stats() (hits, misses int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Clear removes all cached entries and resets statistics.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[K]cacheEntry[V])
+	c.hits = 0
+	c.misses = 0
+}
+
+// hashKey derives a deterministic cache key from the JSON encoding of v.
+func hashKey(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cache key: %w", err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
+}
@@ -0,0 +1,76 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// CachedContentRequest describes context to cache on the provider side, such
+// as a long document queried repeatedly. Model must be the model that later
+// requests use.
+type CachedContentRequest struct {
+	Model       string
+	System      string
+	Input       string
+	Documents   []DocumentPart
+	FileHandles []FileHandle
+
+	TTL         time.Duration // 0 uses the provider default (1 hour for Gemini)
+	DisplayName string
+}
+
+// CachedContentHandle refers to cached content. Pass Name as
+// TextRequest.CachedContentName to use it.
+type CachedContentHandle struct {
+	Name      string // e.g. "cachedContents/abc-123"
+	Model     string
+	ExpiresAt time.Time
+}
+
+// contentCacher is implemented by providers with server-side context caching.
+type contentCacher interface {
+	CreateCachedContent(ctx context.Context, req CachedContentRequest) (CachedContentHandle, error)
+	DeleteCachedContent(ctx context.Context, name string) error
+}
+
+// CreateCachedContent caches req's content with Gemini context caching, so
+// that later requests referencing it are billed at the cached token rate.
+func (c *Client) CreateCachedContent(ctx context.Context, req CachedContentRequest) (CachedContentHandle, error) {
+	if req.Model == "" {
+		return CachedContentHandle{}, errors.New("cora: model must be specified")
+	}
+	cc, err := c.contentCacher()
+	if err != nil {
+		return CachedContentHandle{}, err
+	}
+	h, err := cc.CreateCachedContent(ctx, req)
+	if err != nil {
+		return CachedContentHandle{}, wrapProviderError(ProviderGoogle, err)
+	}
+	return h, nil
+}
+
+// DeleteCachedContent deletes the cached content with the given name.
+func (c *Client) DeleteCachedContent(ctx context.Context, name string) error {
+	cc, err := c.contentCacher()
+	if err != nil {
+		return err
+	}
+	if err := cc.DeleteCachedContent(ctx, name); err != nil {
+		return wrapProviderError(ProviderGoogle, err)
+	}
+	return nil
+}
+
+func (c *Client) contentCacher() (contentCacher, error) {
+	pc, err := c.ensureProvider(ProviderGoogle)
+	if err != nil {
+		return nil, err
+	}
+	cc, ok := pc.(contentCacher)
+	if !ok {
+		return nil, errors.New("cora: provider \"google\" does not support cached content")
+	}
+	return cc, nil
+}
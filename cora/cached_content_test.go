@@ -0,0 +1,197 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCachedContent_Google(t *testing.T) {
+	var created map[string]any
+	var usedCache string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/cachedContents") && r.Method == http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"name":       "cachedContents/abc-123",
+				"model":      "models/gemini-test",
+				"expireTime": "2030-01-01T00:00:00Z",
+			})
+		case strings.Contains(r.URL.Path, "generateContent"):
+			var body struct {
+				CachedContent string `json:"cachedContent"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			usedCache = body.CachedContent
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"candidates":    []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{{"text": "ok"}}}}},
+				"usageMetadata": map[string]any{"promptTokenCount": 1010, "cachedContentTokenCount": 1000, "candidatesTokenCount": 5, "totalTokenCount": 1015},
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	h, err := c.CreateCachedContent(context.Background(), CachedContentRequest{
+		Model:  "gemini-test",
+		System: "You answer questions about the report.",
+		Input:  "<a very long report>",
+	})
+	if err != nil {
+		t.Fatalf("CreateCachedContent error: %v", err)
+	}
+	if h.Name != "cachedContents/abc-123" || h.ExpiresAt.Year() != 2030 {
+		t.Errorf("unexpected handle %+v", h)
+	}
+	if created["systemInstruction"] == nil || created["contents"] == nil {
+		t.Errorf("expected system instruction and contents in create request, got %v", created)
+	}
+
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider:          ProviderGoogle,
+		Model:             "gemini-test",
+		Input:             "What is the conclusion?",
+		CachedContentName: h.Name,
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if usedCache != "cachedContents/abc-123" {
+		t.Errorf("expected cachedContent in request, got %q", usedCache)
+	}
+	if resp.CachedTokens == nil || *resp.CachedTokens != 1000 {
+		t.Errorf("expected 1000 cached tokens, got %v", resp.CachedTokens)
+	}
+}
+
+// cachedContentServer is a Google server recording the cachedContent of
+// every generateContent request. With toolRound set, the first reply calls
+// the "lookup" tool.
+func cachedContentServer(t *testing.T, toolRound bool) (*httptest.Server, *[]string) {
+	t.Helper()
+	var used []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			CachedContent string `json:"cachedContent"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		used = append(used, body.CachedContent)
+		part := map[string]any{"text": "done"}
+		if toolRound && len(used) == 1 {
+			part = map[string]any{"functionCall": map[string]any{"name": "lookup", "args": map[string]any{}}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{part}}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &used
+}
+
+func TestCachedContent_GoogleToolLoopEveryRound(t *testing.T) {
+	srv, used := cachedContentServer(t, true)
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:          ProviderGoogle,
+		Model:             "gemini-test",
+		Input:             "Look it up.",
+		Mode:              ModeToolCalling,
+		CachedContentName: "cachedContents/abc-123",
+		Tools:             []CoraTool{{Name: "lookup", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{
+			"lookup": func(ctx context.Context, args map[string]any) (any, error) { return "found", nil },
+		},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if len(*used) != 2 {
+		t.Fatalf("expected 2 rounds, got %d", len(*used))
+	}
+	for i, name := range *used {
+		if name != "cachedContents/abc-123" {
+			t.Errorf("round %d: expected cachedContent, got %q", i+1, name)
+		}
+	}
+}
+
+func TestCachedContent_ConversationInherits(t *testing.T) {
+	srv, used := cachedContentServer(t, false)
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL}).
+		WithConversationStore(NewInMemoryConversationStore())
+	req := TextRequest{
+		Provider:          ProviderGoogle,
+		Model:             "gemini-test",
+		Input:             "First question.",
+		ConversationID:    "conv-1",
+		CachedContentName: "cachedContents/abc-123",
+	}
+	if _, err := c.Text(context.Background(), req); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	req.Input, req.CachedContentName = "Follow-up.", ""
+	if _, err := c.Text(context.Background(), req); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	req.ConversationID = "conv-2"
+	if _, err := c.Text(context.Background(), req); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	want := []string{"cachedContents/abc-123", "cachedContents/abc-123", ""}
+	if strings.Join(*used, ",") != strings.Join(want, ",") {
+		t.Errorf("expected cachedContent %q, got %q", want, *used)
+	}
+}
+
+func TestCachedContent_ConversationDropsExpired(t *testing.T) {
+	var used []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			CachedContent string `json:"cachedContent"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		used = append(used, body.CachedContent)
+		w.Header().Set("Content-Type", "application/json")
+		if len(used) == 2 {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"code":403,"message":"CachedContent not found (or permission denied)","status":"PERMISSION_DENIED"}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{{"text": "done"}}}}},
+		})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL}).
+		WithConversationStore(NewInMemoryConversationStore())
+	req := TextRequest{
+		Provider:          ProviderGoogle,
+		Model:             "gemini-test",
+		Input:             "First question.",
+		ConversationID:    "conv-1",
+		CachedContentName: "cachedContents/abc-123",
+	}
+	if _, err := c.Text(context.Background(), req); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	req.Input, req.CachedContentName = "Follow-up.", ""
+	if _, err := c.Text(context.Background(), req); err == nil {
+		t.Fatal("expected the expired cache to fail the turn")
+	}
+	if _, err := c.Text(context.Background(), req); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	want := []string{"cachedContents/abc-123", "cachedContents/abc-123", ""}
+	if strings.Join(used, ",") != strings.Join(want, ",") {
+		t.Errorf("expected cachedContent %q, got %q", want, used)
+	}
+}
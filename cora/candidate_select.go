@@ -0,0 +1,58 @@
+package cora
+
+import "unicode/utf8"
+
+// CandidateSelector picks the completion Text returns when a request samples
+// several (see TextRequest.SelectCandidate). candidates holds at least two
+// choices; their CompletionTokens are set when the provider reports them.
+type CandidateSelector func(candidates []TextResponse) TextResponse
+
+// SelectFirst returns the first candidate, which is what Text returns
+// without a selector.
+func SelectFirst() CandidateSelector {
+	return func(candidates []TextResponse) TextResponse { return candidates[0] }
+}
+
+// SelectLongest returns the candidate with the most characters.
+func SelectLongest() CandidateSelector {
+	return selectBy(func(c TextResponse) int { return utf8.RuneCountInString(c.Text) }, true)
+}
+
+// SelectShortest returns the non-empty candidate with the fewest characters.
+func SelectShortest() CandidateSelector {
+	return func(candidates []TextResponse) TextResponse {
+		best := candidates[0]
+		for _, c := range candidates[1:] {
+			if c.Text != "" && (best.Text == "" || utf8.RuneCountInString(c.Text) < utf8.RuneCountInString(best.Text)) {
+				best = c
+			}
+		}
+		return best
+	}
+}
+
+// SelectByTokens returns the candidate with the fewest ("fewer") or most
+// ("more") completion tokens. Candidates without a reported count are
+// estimated from their text.
+func SelectByTokens(prefer string) CandidateSelector {
+	return selectBy(func(c TextResponse) int {
+		if c.CompletionTokens != nil {
+			return *c.CompletionTokens
+		}
+		return estimateTokens(c.Text)
+	}, prefer == "more")
+}
+
+// selectBy returns a selector picking the first candidate with the highest
+// (most) or lowest score.
+func selectBy(score func(TextResponse) int, most bool) CandidateSelector {
+	return func(candidates []TextResponse) TextResponse {
+		best, bestScore := candidates[0], score(candidates[0])
+		for _, c := range candidates[1:] {
+			if s := score(c); (most && s > bestScore) || (!most && s < bestScore) {
+				best, bestScore = c, s
+			}
+		}
+		return best
+	}
+}
@@ -0,0 +1,69 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSelectCandidate_Google(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		candidate := func(text string, tokens int) map[string]any {
+			return map[string]any{
+				"content":    map[string]any{"role": "model", "parts": []map[string]any{{"text": text}}},
+				"tokenCount": tokens,
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{candidate("short", 1), candidate("a much longer answer", 4)},
+		})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	n := 2
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider:        ProviderGoogle,
+		Model:           "gemini-test",
+		Input:           "hi",
+		N:               &n,
+		SelectCandidate: SelectLongest(),
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "a much longer answer" {
+		t.Errorf("expected the longest candidate, got %q", resp.Text)
+	}
+	if len(resp.Choices) != 2 || resp.Choices[1].CompletionTokens == nil || *resp.Choices[1].CompletionTokens != 4 {
+		t.Errorf("expected both choices with token counts, got %+v", resp.Choices)
+	}
+}
+
+func TestCandidateSelectors(t *testing.T) {
+	three := 3
+	candidates := []TextResponse{
+		{Text: "medium text"},
+		{Text: "tiny"},
+		{Text: "x", CompletionTokens: &three},
+	}
+	tests := []struct {
+		name string
+		sel  CandidateSelector
+		want string
+	}{
+		{"first", SelectFirst(), "medium text"},
+		{"longest", SelectLongest(), "medium text"},
+		{"shortest", SelectShortest(), "x"},
+		{"fewer tokens", SelectByTokens("fewer"), "tiny"},
+		{"more tokens", SelectByTokens("more"), "medium text"},
+	}
+	for _, tt := range tests {
+		if got := tt.sel(candidates).Text; got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
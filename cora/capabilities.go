@@ -0,0 +1,156 @@
+package cora
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ProviderFeature is a capability a provider's model may support.
+type ProviderFeature int
+
+const (
+	// FeatureStreaming is support for Stream().
+	FeatureStreaming ProviderFeature = iota
+	// FeatureStructuredOutput is support for JSON-schema constrained responses.
+	FeatureStructuredOutput
+	// FeatureToolCalling is support for tool/function calling.
+	FeatureToolCalling
+	// FeatureVision is support for image inputs.
+	FeatureVision
+	// FeatureAudio is support for audio inputs (ModeTranscribe).
+	FeatureAudio
+	// FeatureThinking is support for reasoning effort or thinking budgets.
+	FeatureThinking
+)
+
+var providerFeatureNames = map[ProviderFeature]string{
+	FeatureStreaming:        "streaming",
+	FeatureStructuredOutput: "structured_output",
+	FeatureToolCalling:      "tool_calling",
+	FeatureVision:           "vision",
+	FeatureAudio:            "audio",
+	FeatureThinking:         "thinking",
+}
+
+// String returns the feature's name, e.g. "tool_calling".
+func (f ProviderFeature) String() string {
+	if name, ok := providerFeatureNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("ProviderFeature(%d)", int(f))
+}
+
+// MarshalJSON encodes the feature as its name.
+func (f ProviderFeature) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.String())
+}
+
+// UnmarshalJSON accepts a feature name such as "vision".
+func (f *ProviderFeature) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("cora: invalid ProviderFeature %s", data)
+	}
+	for feature, featureName := range providerFeatureNames {
+		if featureName == name {
+			*f = feature
+			return nil
+		}
+	}
+	return fmt.Errorf("cora: unknown ProviderFeature %q", name)
+}
+
+// Feature sets shared by the built-in capability table.
+var (
+	chatFeatures      = []ProviderFeature{FeatureStreaming, FeatureStructuredOutput, FeatureToolCalling}
+	visionFeatures    = append(slices.Clip(chatFeatures), FeatureVision)
+	geminiFeatures    = append(slices.Clip(visionFeatures), FeatureAudio)
+	reasoningFeatures = append(slices.Clip(visionFeatures), FeatureThinking)
+)
+
+// defaultModelCapabilities lists the features of well-known models. Dated
+// variants (e.g. "gpt-4o-2024-08-06") match the longest name they start
+// with. Entries in CoraConfig.ModelCapabilities take precedence.
+var defaultModelCapabilities = map[string][]ProviderFeature{
+	"gpt-4o":           visionFeatures,
+	"gpt-4o-mini":      visionFeatures,
+	"gpt-4.1":          visionFeatures,
+	"gpt-4.1-mini":     visionFeatures,
+	"gpt-4.1-nano":     visionFeatures,
+	"gpt-4-turbo":      {FeatureStreaming, FeatureToolCalling, FeatureVision},
+	"gpt-3.5-turbo":    {FeatureStreaming, FeatureToolCalling},
+	"o1":               reasoningFeatures,
+	"o3":               reasoningFeatures,
+	"o3-mini":          {FeatureStreaming, FeatureStructuredOutput, FeatureToolCalling, FeatureThinking},
+	"o4-mini":          reasoningFeatures,
+	"whisper-1":        {FeatureAudio},
+	"gemini-1.5-flash": geminiFeatures,
+	"gemini-1.5-pro":   geminiFeatures,
+	"gemini-2.0-flash": geminiFeatures,
+	"gemini-2.5-flash": append(slices.Clip(geminiFeatures), FeatureThinking),
+	"gemini-2.5-pro":   append(slices.Clip(geminiFeatures), FeatureThinking),
+	"mistral-large":    chatFeatures,
+	"mistral-small":    visionFeatures,
+	"pixtral-large":    visionFeatures,
+	"command-r":        chatFeatures,
+	"command-r-plus":   chatFeatures,
+}
+
+// defaultProviderCapabilities applies to models missing from both tables.
+var defaultProviderCapabilities = map[Provider][]ProviderFeature{
+	ProviderOpenAI:  {FeatureStreaming, FeatureToolCalling},
+	ProviderGoogle:  {FeatureStreaming, FeatureToolCalling},
+	ProviderMistral: {FeatureStreaming, FeatureToolCalling},
+	ProviderCohere:  {FeatureStreaming, FeatureToolCalling},
+	ProviderBedrock: {FeatureStreaming, FeatureToolCalling},
+}
+
+// SupportsFeature reports whether model on provider supports feature,
+// according to CoraConfig.ModelCapabilities and then the built-in table.
+// Unknown models are assumed to support only streaming and tool calling.
+func (c *Client) SupportsFeature(provider Provider, model string, feature ProviderFeature) bool {
+	if !provider.Valid() {
+		return false
+	}
+	return slices.Contains(c.modelCapabilities(provider, model), feature)
+}
+
+// SupportsMode reports whether model on provider can serve mode.
+func (c *Client) SupportsMode(provider Provider, model string, mode TextMode) bool {
+	switch mode {
+	case ModeBasic, ModeTwoStepEnhance:
+		return provider.Valid()
+	case ModeStructuredJSON, ModeClassify, ModeScore:
+		return c.SupportsFeature(provider, model, FeatureStructuredOutput)
+	case ModeToolCalling, ModeReAct, ModeAgentLoop:
+		return c.SupportsFeature(provider, model, FeatureToolCalling)
+	case ModeTranscribe:
+		return c.SupportsFeature(provider, model, FeatureAudio)
+	default:
+		return false
+	}
+}
+
+// modelCapabilities returns the features of model, preferring the client's
+// ModelCapabilities over the built-in table, then the provider default.
+func (c *Client) modelCapabilities(provider Provider, model string) []ProviderFeature {
+	for _, table := range []map[string][]ProviderFeature{c.cfg.ModelCapabilities, defaultModelCapabilities} {
+		if features, ok := table[model]; ok {
+			return features
+		}
+	}
+	for _, table := range []map[string][]ProviderFeature{c.cfg.ModelCapabilities, defaultModelCapabilities} {
+		best := ""
+		for name := range table {
+			if strings.HasPrefix(model, name+"-") && len(name) > len(best) {
+				best = name
+			}
+		}
+		if best != "" {
+			return table[best]
+		}
+	}
+	return defaultProviderCapabilities[provider]
+}
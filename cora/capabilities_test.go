@@ -0,0 +1,76 @@
+package cora
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSupportsFeature_KnownModels(t *testing.T) {
+	c := New(CoraConfig{})
+	tests := []struct {
+		provider Provider
+		model    string
+		feature  ProviderFeature
+		want     bool
+	}{
+		{ProviderOpenAI, "gpt-4o", FeatureStructuredOutput, true},
+		{ProviderOpenAI, "gpt-4o-2024-08-06", FeatureVision, true},
+		{ProviderOpenAI, "gpt-4o", FeatureThinking, false},
+		{ProviderOpenAI, "gpt-3.5-turbo", FeatureStructuredOutput, false},
+		{ProviderOpenAI, "o3-mini", FeatureThinking, true},
+		{ProviderOpenAI, "o3-mini", FeatureVision, false},
+		{ProviderOpenAI, "whisper-1", FeatureAudio, true},
+		{ProviderGoogle, "gemini-2.5-flash", FeatureThinking, true},
+		{ProviderGoogle, "gemini-2.0-flash", FeatureThinking, false},
+		{ProviderGoogle, "gemini-1.5-pro-002", FeatureAudio, true},
+		{ProviderMistral, "mistral-large-latest", FeatureToolCalling, true},
+		{ProviderOpenAI, "my-finetune", FeatureToolCalling, true},
+		{ProviderOpenAI, "my-finetune", FeatureStructuredOutput, false},
+		{"bogus", "gpt-4o", FeatureStreaming, false},
+	}
+	for _, tt := range tests {
+		if got := c.SupportsFeature(tt.provider, tt.model, tt.feature); got != tt.want {
+			t.Errorf("SupportsFeature(%s, %s, %s) = %v, want %v", tt.provider, tt.model, tt.feature, got, tt.want)
+		}
+	}
+}
+
+func TestSupportsMode(t *testing.T) {
+	c := New(CoraConfig{ModelCapabilities: map[string][]ProviderFeature{
+		"gpt-4o":       {FeatureStreaming},
+		"local-llama3": {FeatureStreaming, FeatureStructuredOutput},
+	}})
+	tests := []struct {
+		model string
+		mode  TextMode
+		want  bool
+	}{
+		{"gpt-4o", ModeBasic, true},
+		{"gpt-4o", ModeToolCalling, false}, // overridden by ModelCapabilities
+		{"gpt-4o-mini", ModeToolCalling, true},
+		{"local-llama3", ModeStructuredJSON, true},
+		{"local-llama3", ModeClassify, true},
+		{"local-llama3", ModeReAct, false},
+		{"whisper-1", ModeTranscribe, true},
+		{"gpt-4o-mini", ModeTranscribe, false},
+	}
+	for _, tt := range tests {
+		if got := c.SupportsMode(ProviderOpenAI, tt.model, tt.mode); got != tt.want {
+			t.Errorf("SupportsMode(openai, %s, %s) = %v, want %v", tt.model, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestProviderFeature_JSON(t *testing.T) {
+	var cfg CoraConfig
+	if err := json.Unmarshal([]byte(`{"ModelCapabilities": {"m": ["vision", "thinking"]}}`), &cfg); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if want := []ProviderFeature{FeatureVision, FeatureThinking}; !reflect.DeepEqual(cfg.ModelCapabilities["m"], want) {
+		t.Errorf("got %v, want %v", cfg.ModelCapabilities["m"], want)
+	}
+	if err := json.Unmarshal([]byte(`["telepathy"]`), new([]ProviderFeature)); err == nil {
+		t.Error("expected an error for an unknown feature")
+	}
+}
@@ -0,0 +1,286 @@
+// Package cassette records the HTTP traffic of a cora.Client to a file and
+// plays it back, so integration tests can run in CI without API keys.
+//
+// Record once against the live APIs, scrub the file with Clean, and commit it:
+//
+//	c := cassette.Record("weather", cora.New(cfg))
+//	// ... run the test ...
+//	_ = cassette.Clean("weather")
+//
+// Later runs use the committed testdata/weather.cassette.json:
+//
+//	c := cassette.Playback("weather")
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/oraraka-deko/cora/cora"
+)
+
+// Dir is the directory cassettes are read from and written to.
+var Dir = "testdata"
+
+// Interaction is one recorded request/response pair.
+type Interaction struct {
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	RequestHeaders  http.Header `json:"request_headers,omitempty"`
+	RequestBody     string      `json:"request_body,omitempty"`
+	StatusCode      int         `json:"status_code"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	ResponseBody    string      `json:"response_body"`
+}
+
+// Cassette is the on-disk format: the interactions in recording order.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Path returns the file used for the cassette name.
+func Path(name string) string {
+	return filepath.Join(Dir, name+".cassette.json")
+}
+
+// Record returns a client with client's configuration whose HTTP traffic is
+// also saved to Path(name), replacing any previous recording. Middleware and
+// loggers attached to client are not carried over.
+func Record(name string, client *cora.Client) *cora.Client {
+	cfg := client.Config()
+	base := http.DefaultTransport
+	hc := &http.Client{}
+	if cfg.HTTPClient != nil {
+		*hc = *cfg.HTTPClient
+		if hc.Transport != nil {
+			base = hc.Transport
+		}
+	}
+	hc.Transport = &recorder{path: Path(name), base: base}
+	cfg.HTTPClient = hc
+	return cora.New(cfg)
+}
+
+// Playback returns a client that answers every request from the cassette at
+// Path(name) instead of the network. Requests are matched in order by method,
+// URL path and body; an unmatched request fails with an error. Every
+// provider is configured with placeholder credentials, except that Bedrock
+// still signs requests with credentials from the AWS chain, so replaying it
+// needs AWS credentials (of any value) in the environment.
+func Playback(name string) *cora.Client {
+	p := &player{}
+	p.cassette, p.err = load(Path(name))
+	return cora.New(cora.CoraConfig{
+		OpenAIAPIKey:  "cassette",
+		GoogleAPIKey:  "cassette",
+		MistralAPIKey: "cassette",
+		CohereAPIKey:  "cassette",
+		BedrockRegion: "us-east-1",
+		HTTPClient:    &http.Client{Transport: p},
+	})
+}
+
+// sensitiveHeaders are removed by Clean.
+var sensitiveHeaders = []string{
+	"Authorization", "Api-Key", "X-Goog-Api-Key", "Openai-Organization",
+	"Cookie", "Set-Cookie", "Openai-Project",
+}
+
+// Clean removes credentials (auth headers, cookies and "key" query
+// parameters) from the cassette at Path(name) so it can be committed.
+func Clean(name string) error {
+	path := Path(name)
+	c, err := load(path)
+	if err != nil {
+		return err
+	}
+	for i := range c.Interactions {
+		in := &c.Interactions[i]
+		for _, h := range sensitiveHeaders {
+			in.RequestHeaders.Del(h)
+			in.ResponseHeaders.Del(h)
+		}
+		in.URL = stripKeyParam(in.URL)
+	}
+	return save(path, c)
+}
+
+func stripKeyParam(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	q := u.Query()
+	if !q.Has("key") {
+		return raw
+	}
+	q.Del("key")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("cassette: decode %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+func save(path string, c *Cassette) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// recorder forwards requests to base and saves every exchange. The file is
+// rewritten after each one so nothing is lost if the test stops early.
+type recorder struct {
+	path string
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Method:          req.Method,
+		URL:             req.URL.String(),
+		RequestHeaders:  req.Header.Clone(),
+		RequestBody:     string(reqBody),
+		StatusCode:      resp.StatusCode,
+		ResponseHeaders: resp.Header.Clone(),
+		ResponseBody:    string(respBody),
+	})
+	if err := save(r.path, &r.cassette); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// readBody reads *body and replaces it with an in-memory copy.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// player serves responses from a loaded cassette.
+type player struct {
+	err error
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+func (p *player) RoundTrip(req *http.Request) (*http.Response, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.used == nil {
+		p.used = make([]bool, len(p.cassette.Interactions))
+	}
+	for i, in := range p.cassette.Interactions {
+		if p.used[i] || !matches(in, req, body) {
+			continue
+		}
+		p.used[i] = true
+		header := in.ResponseHeaders.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			StatusCode:    in.StatusCode,
+			Status:        fmt.Sprintf("%d %s", in.StatusCode, http.StatusText(in.StatusCode)),
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(in.ResponseBody)),
+			ContentLength: int64(len(in.ResponseBody)),
+			Request:       req,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette: no recorded interaction for %s %s", req.Method, req.URL)
+}
+
+// matches compares method, path and body. Hosts and query parameters may
+// differ between recording and playback (e.g. a stripped API key), and so
+// may a base path prefix: a recording made against a custom base URL such
+// as http://localhost:8080 plays back for https://api.openai.com/v1.
+func matches(in Interaction, req *http.Request, body []byte) bool {
+	if in.Method != req.Method {
+		return false
+	}
+	u, err := url.Parse(in.URL)
+	if err != nil {
+		return false
+	}
+	if !strings.HasSuffix(u.Path, req.URL.Path) && !strings.HasSuffix(req.URL.Path, u.Path) {
+		return false
+	}
+	return jsonEqual(in.RequestBody, string(body))
+}
+
+// jsonEqual compares two bodies as JSON when possible, so that key order
+// does not matter, and byte-for-byte otherwise.
+func jsonEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var va, vb any
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return bytes.Equal(ja, jb)
+}
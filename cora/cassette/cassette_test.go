@@ -0,0 +1,107 @@
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/oraraka-deko/cora/cora"
+)
+
+func TestRecordAndPlayback(t *testing.T) {
+	oldDir := Dir
+	Dir = t.TempDir()
+	defer func() { Dir = oldDir }()
+
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": "recorded answer"}}},
+		})
+	}))
+	defer srv.Close()
+
+	req := cora.TextRequest{Provider: cora.ProviderOpenAI, Model: "gpt-test", Input: "hi"}
+
+	rec := Record("basic", cora.New(cora.CoraConfig{OpenAIAPIKey: "sk-secret", OpenAIBaseURL: srv.URL}))
+	resp, err := rec.Text(context.Background(), req)
+	if err != nil {
+		t.Fatalf("recording Text error: %v", err)
+	}
+	if resp.Text != "recorded answer" || hits != 1 {
+		t.Fatalf("unexpected recording result %q (hits %d)", resp.Text, hits)
+	}
+
+	if err := Clean("basic"); err != nil {
+		t.Fatalf("Clean error: %v", err)
+	}
+	data, err := os.ReadFile(Path("basic"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"sk-secret", "session=secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette still contains %q after Clean", secret)
+		}
+	}
+
+	srv.Close()
+	play := Playback("basic")
+	resp, err = play.Text(context.Background(), req)
+	if err != nil {
+		t.Fatalf("playback Text error: %v", err)
+	}
+	if resp.Text != "recorded answer" {
+		t.Errorf("unexpected playback text %q", resp.Text)
+	}
+
+	// Each interaction is played once.
+	if _, err := play.Text(context.Background(), req); err == nil {
+		t.Error("expected an error once the cassette is exhausted")
+	}
+}
+
+func TestPlayback_Cohere(t *testing.T) {
+	oldDir := Dir
+	Dir = t.TempDir()
+	defer func() { Dir = oldDir }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"text": "recorded answer", "finish_reason": "COMPLETE"})
+	}))
+	defer srv.Close()
+
+	req := cora.TextRequest{Provider: cora.ProviderCohere, Model: "command-r", Input: "hi"}
+	rec := Record("cohere", cora.New(cora.CoraConfig{CohereAPIKey: "co-secret", CohereBaseURL: srv.URL + "/v1"}))
+	if _, err := rec.Text(context.Background(), req); err != nil {
+		t.Fatalf("recording Text error: %v", err)
+	}
+
+	srv.Close()
+	resp, err := Playback("cohere").Text(context.Background(), req)
+	if err != nil {
+		t.Fatalf("playback Text error: %v", err)
+	}
+	if resp.Text != "recorded answer" {
+		t.Errorf("unexpected playback text %q", resp.Text)
+	}
+}
+
+func TestPlayback_MissingCassette(t *testing.T) {
+	oldDir := Dir
+	Dir = t.TempDir()
+	defer func() { Dir = oldDir }()
+
+	_, err := Playback("missing").Text(context.Background(), cora.TextRequest{Provider: cora.ProviderOpenAI, Model: "m", Input: "x"})
+	if err == nil {
+		t.Error("expected an error for a missing cassette")
+	}
+}
@@ -0,0 +1,125 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ChunkMergeStrategy selects how Client.TextWithChunking combines the answers
+// to the chunks of its input.
+type ChunkMergeStrategy int
+
+const (
+	// MergeConcat keeps the answers to the chunks as they are; the caller
+	// concatenates them.
+	MergeConcat ChunkMergeStrategy = iota
+	// MergeSummarise makes one more call asking the model to merge the
+	// answers to the chunks into one.
+	MergeSummarise
+)
+
+// TextWithChunking sends req.Input in overlapping chunks of about chunkSize
+// tokens, overlap of which are repeated from the previous chunk, for inputs
+// larger than the model's context window. Chunks break between words and
+// are sent one after the other, each stating its position in the system
+// prompt (see TextRequest.ChunkIndex).
+//
+// It returns one response per chunk. With req.ChunkMerge set to
+// MergeSummarise, the response of the final merge call is appended. An
+// input that fits in one chunk is sent as is.
+func (c *Client) TextWithChunking(ctx context.Context, req TextRequest, chunkSize, overlap int) ([]TextResponse, error) {
+	if chunkSize <= 0 {
+		return nil, errors.New("cora: chunkSize must be positive")
+	}
+	if overlap < 0 || overlap >= chunkSize {
+		return nil, fmt.Errorf("cora: overlap must be in [0, chunkSize), got %d", overlap)
+	}
+	if req.ChunkMerge != MergeConcat && req.ChunkMerge != MergeSummarise {
+		return nil, fmt.Errorf("cora: unknown ChunkMergeStrategy %d", req.ChunkMerge)
+	}
+	chunks := splitChunks(req.Input, chunkSize, overlap)
+	if len(chunks) <= 1 {
+		resp, err := c.Text(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return []TextResponse{resp}, nil
+	}
+
+	out := make([]TextResponse, 0, len(chunks)+1)
+	for i, chunk := range chunks {
+		creq := req
+		creq.Input = chunk
+		creq.ChunkIndex, creq.ChunkTotal = i, len(chunks)
+		resp, err := c.Text(ctx, creq)
+		if err != nil {
+			return nil, fmt.Errorf("cora: chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+		out = append(out, resp)
+	}
+	if req.ChunkMerge != MergeSummarise {
+		return out, nil
+	}
+
+	mreq := req
+	mreq.System = appendInstruction(req.System, fmt.Sprintf(
+		"The input was too long to process at once, so it was split into %d parts that were answered one by one. Merge the answers to the parts below into one coherent answer, removing repetition.",
+		len(chunks)))
+	var sb strings.Builder
+	for i, resp := range out {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		fmt.Fprintf(&sb, "Answer to part %d:\n%s", i+1, resp.Text)
+	}
+	mreq.Input = sb.String()
+	merged, err := c.Text(ctx, mreq)
+	if err != nil {
+		return nil, fmt.Errorf("cora: merging %d chunks: %w", len(chunks), err)
+	}
+	return append(out, merged), nil
+}
+
+// splitChunks splits text between words into chunks of at most chunkSize
+// estimated tokens, each starting with about overlap tokens of the previous
+// one. A single word larger than chunkSize forms its own chunk.
+func splitChunks(text string, chunkSize, overlap int) []string {
+	words := strings.Fields(text)
+	tokens := make([]int, len(words))
+	for i, w := range words {
+		tokens[i] = max(1, estimateTokens(w))
+	}
+	var chunks []string
+	for start := 0; start < len(words); {
+		end, n := start, 0
+		for end < len(words) && (end == start || n+tokens[end] <= chunkSize) {
+			n += tokens[end]
+			end++
+		}
+		chunks = append(chunks, strings.Join(words[start:end], " "))
+		if end == len(words) {
+			break
+		}
+		next, ov := end, 0
+		for next-1 > start && ov+tokens[next-1] <= overlap {
+			ov += tokens[next-1]
+			next--
+		}
+		start = next
+	}
+	return chunks
+}
+
+// withChunkPosition appends the chunk position to system when total > 0.
+func withChunkPosition(system string, index, total int) (string, error) {
+	if total == 0 && index == 0 {
+		return system, nil
+	}
+	if total < 0 || index < 0 || index >= total {
+		return "", fmt.Errorf("cora: ChunkIndex %d out of range for ChunkTotal %d", index, total)
+	}
+	instr := fmt.Sprintf("The input is part %d of %d of a longer text, split into overlapping chunks. Answer based on this part only.", index+1, total)
+	return appendInstruction(system, instr), nil
+}
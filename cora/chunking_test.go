@@ -0,0 +1,105 @@
+package cora
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func chunkingInput(words int) string {
+	parts := make([]string, words)
+	for i := range parts {
+		parts[i] = fmt.Sprintf("w%02d", i)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestTextWithChunking_Concat(t *testing.T) {
+	fake := &fakeProvider{finalOut: "ok"}
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = fake
+
+	resps, err := c.TextWithChunking(context.Background(), TextRequest{
+		Provider: ProviderOpenAI, Model: "gpt-test", System: "Summarise.", Input: chunkingInput(100),
+	}, 20, 0)
+	if err != nil {
+		t.Fatalf("TextWithChunking error: %v", err)
+	}
+	if len(resps) != 5 {
+		t.Fatalf("got %d responses, want 5", len(resps))
+	}
+	plans := fake.ReceivedPlans()
+	for i, p := range plans {
+		if n := len(strings.Fields(p.Input)); n != 20 {
+			t.Errorf("chunk %d has %d words, want 20", i, n)
+		}
+		if want := fmt.Sprintf("part %d of 5", i+1); !strings.HasPrefix(p.System, "Summarise.") || !strings.Contains(p.System, want) {
+			t.Errorf("chunk %d system = %q, want %q", i, p.System, want)
+		}
+	}
+	if !strings.HasPrefix(plans[1].Input, "w20 ") || !strings.HasSuffix(plans[4].Input, " w99") {
+		t.Errorf("unexpected chunk bounds: %q ... %q", plans[1].Input, plans[4].Input)
+	}
+}
+
+func TestTextWithChunking_ResponseCache(t *testing.T) {
+	fake := &fakeProvider{finalOut: "ok"}
+	c := New(CoraConfig{ResponseCacheTTL: time.Minute, ResponseCacheMaxSize: 10, RequestDedup: true})
+	c.openai = fake
+
+	// Identical chunks at different positions get different prompts, so
+	// neither the cache nor deduplication may share their answers.
+	req := TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "same text", ChunkTotal: 2}
+	for i := range 2 {
+		req.ChunkIndex = i
+		if _, err := c.Text(context.Background(), req); err != nil {
+			t.Fatalf("Text error: %v", err)
+		}
+	}
+	if n := len(fake.ReceivedPlans()); n != 2 {
+		t.Errorf("provider called %d times, want 2", n)
+	}
+}
+
+func TestTextWithChunking_OverlapAndSummarise(t *testing.T) {
+	fake := &fakeProvider{finalOut: "ok"}
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = fake
+
+	resps, err := c.TextWithChunking(context.Background(), TextRequest{
+		Provider: ProviderOpenAI, Model: "gpt-test", Input: chunkingInput(100), ChunkMerge: MergeSummarise,
+	}, 20, 5)
+	if err != nil {
+		t.Fatalf("TextWithChunking error: %v", err)
+	}
+	// Chunks start every 15 words: 0, 15, ..., 90, plus the merge call.
+	if len(resps) != 8 {
+		t.Fatalf("got %d responses, want 8", len(resps))
+	}
+	plans := fake.ReceivedPlans()
+	if !strings.HasPrefix(plans[1].Input, "w15 ") {
+		t.Errorf("second chunk = %q, want it to start with the overlap", plans[1].Input)
+	}
+	merge := plans[len(plans)-1]
+	if !strings.Contains(merge.System, "Merge the answers") || strings.Contains(merge.System, "part 1 of") {
+		t.Errorf("merge system = %q", merge.System)
+	}
+	if !strings.Contains(merge.Input, "Answer to part 7:\nok") {
+		t.Errorf("merge input = %q", merge.Input)
+	}
+}
+
+func TestTextWithChunking_Validation(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = &fakeProvider{finalOut: "ok"}
+	req := TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "short"}
+	if _, err := c.TextWithChunking(context.Background(), req, 10, 10); err == nil {
+		t.Error("expected error for overlap >= chunkSize")
+	}
+	req.ChunkIndex = 3
+	if _, err := c.Text(context.Background(), req); err == nil {
+		t.Error("expected error for ChunkIndex without ChunkTotal")
+	}
+}
@@ -0,0 +1,54 @@
+package cora
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ClassifyExample is a labelled input used as a few-shot example in ModeClassify.
+type ClassifyExample struct {
+	Input string
+	Label string
+}
+
+// classifySystemPrompt prepends the classification instructions, listing
+// labels, to the user's system prompt.
+func classifySystemPrompt(system string, labels []string) string {
+	var b strings.Builder
+	b.WriteString("Classify the user's input into exactly one of these labels:\n")
+	for _, l := range labels {
+		fmt.Fprintf(&b, "- %s\n", l)
+	}
+	b.WriteString(`Reply with a JSON object {"label": "<one of the labels>", "confidence": <number between 0 and 1>}.`)
+	if strings.TrimSpace(system) == "" {
+		return b.String()
+	}
+	return b.String() + "\n\n" + system
+}
+
+// classifyResponseSchema returns the structured response schema restricting
+// "label" to labels.
+func classifyResponseSchema(labels []string) map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"label":      map[string]any{"type": "string", "enum": labels},
+			"confidence": map[string]any{"type": "number"},
+		},
+		"required":             []string{"label", "confidence"},
+		"additionalProperties": false,
+	}
+}
+
+// classifyExampleMessages renders few-shot examples as user/assistant turns.
+func classifyExampleMessages(examples []ClassifyExample) []Message {
+	msgs := make([]Message, 0, 2*len(examples))
+	for _, ex := range examples {
+		answer := jsonMarshalNoErr(map[string]any{"label": ex.Label, "confidence": 1.0})
+		msgs = append(msgs,
+			Message{Role: "user", Content: ex.Input},
+			Message{Role: "assistant", Content: string(answer)},
+		)
+	}
+	return msgs
+}
@@ -0,0 +1,62 @@
+package cora
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildPlans_Classify(t *testing.T) {
+	labels := []string{"billing", "bug report", "feature request"}
+	req := TextRequest{
+		Mode:           ModeClassify,
+		Input:          "The app crashes on login.",
+		ClassifyLabels: labels,
+		ClassifyExamples: []ClassifyExample{
+			{Input: "I was charged twice.", Label: "billing"},
+		},
+	}
+	plans, err := buildPlans(ProviderOpenAI, "gpt-test", req, CoraConfig{}, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	plan := plans[0]
+	for _, label := range labels {
+		if !strings.Contains(plan.System, label) {
+			t.Errorf("expected system prompt to list label %q:\n%s", label, plan.System)
+		}
+	}
+	if !plan.Structured || !reflect.DeepEqual(plan.ResponseSchema["properties"].(map[string]any)["label"].(map[string]any)["enum"], labels) {
+		t.Errorf("expected a structured plan restricting label to %v, got %v", labels, plan.ResponseSchema)
+	}
+	want := []Message{
+		{Role: "user", Content: "I was charged twice."},
+		{Role: "assistant", Content: `{"confidence":1,"label":"billing"}`},
+	}
+	if !reflect.DeepEqual(plan.Messages, want) {
+		t.Errorf("few-shot messages = %v, want %v", plan.Messages, want)
+	}
+
+	if _, err := buildPlans(ProviderOpenAI, "gpt-test", TextRequest{Mode: ModeClassify}, CoraConfig{}, nil); err == nil {
+		t.Error("expected error for ModeClassify without labels")
+	}
+}
+
+func TestText_ClassifyReturnsLabel(t *testing.T) {
+	fake := (&fakeProvider{}).WithJSON(map[string]any{"label": "bug report", "confidence": 0.9})
+	c := &Client{cfg: CoraConfig{}, openai: fake}
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider:       ProviderOpenAI,
+		Model:          "gpt-test",
+		Mode:           ModeClassify,
+		Input:          "The app crashes on login.",
+		ClassifyLabels: []string{"billing", "bug report"},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "bug report" || resp.JSON["confidence"] != 0.9 {
+		t.Errorf("unexpected response text %q, JSON %v", resp.Text, resp.JSON)
+	}
+}
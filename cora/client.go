@@ -1,11 +1,20 @@
 package cora
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
 	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
 // GoogleBackend selects the underlying Google backend.
@@ -20,12 +29,86 @@ const (
 	GoogleBackendVertex
 )
 
+var googleBackendNames = map[GoogleBackend]string{
+	GoogleBackendAuto:   "auto",
+	GoogleBackendGemini: "gemini",
+	GoogleBackendVertex: "vertex",
+}
+
+// String returns the backend's name: "auto", "gemini" or "vertex".
+func (b GoogleBackend) String() string {
+	if name, ok := googleBackendNames[b]; ok {
+		return name
+	}
+	return fmt.Sprintf("GoogleBackend(%d)", int(b))
+}
+
+// parseGoogleBackend returns the backend named name.
+func parseGoogleBackend(name string) (GoogleBackend, bool) {
+	for b, n := range googleBackendNames {
+		if n == name {
+			return b, true
+		}
+	}
+	return GoogleBackendAuto, false
+}
+
 // Client is the unified, minimal public client.
 type Client struct {
-	cfg    CoraConfig
-	openai providerClient // lazily init
-	google providerClient // lazily init
+	cfg CoraConfig
+	// providersMu guards the lazy initialization of the provider clients
+	// (see ensureProvider).
+	providersMu sync.Mutex
+	openai      providerClient // lazily init
+	google      providerClient // lazily init
+	mistral     providerClient // lazily init
+	cohere      providerClient // lazily init
+	bedrock     providerClient // lazily init
+	// endpoints holds the clients of cfg.OpenAIEndpoints by name, created on first use.
+	endpointsMu sync.Mutex
+	endpoints   map[string]providerClient
 
+	// inflight collapses identical concurrent Text() calls when cfg.RequestDedup is set.
+	inflight singleflight.Group
+	// responses caches Text() results when cfg.ResponseCacheTTL and cfg.ResponseCacheMaxSize are set.
+	responses *Cache[string, TextResponse]
+	// semantic caches Text() results by input embedding when cfg.SemanticCacheThreshold is set.
+	semantic *semanticCache
+	// audit receives an entry for every Text() and Stream() call (see WithAuditLogger).
+	audit AuditLogger
+	// spans receives a TextSpan for every Text() call (see WithSpanRecorder).
+	spans SpanRecorder
+	// middleware wraps every Text() call (see Use).
+	middleware []Middleware
+	// streamMiddleware sees every Stream() event (see UseStreamMiddleware).
+	streamMiddleware []StreamMiddleware
+	// logger receives structured request logs (see WithLogger).
+	logger *slog.Logger
+	// promptOptimizer rewrites every request's prompts (see WithPromptOptimizer).
+	promptOptimizer PromptOptimizer
+	// transport is the provider transport New builds once for all of the
+	// client's providers (see newProviderTransport).
+	transport http.RoundTripper
+	// spend accumulates estimated call costs (see TotalSpend).
+	spendMu sync.Mutex
+	spend   float64
+	// conversations stores histories for requests with a ConversationID (see WithConversationStore).
+	conversations ConversationStore
+	// conversationCaches remembers each conversation's CachedContentName so
+	// later turns inherit it (see conversationCacheNames).
+	conversationCachesOnce sync.Once
+	conversationCaches     *Cache[string, string]
+	// aliases maps alias model names to real ones (see RegisterModelAlias).
+	aliasMu sync.RWMutex
+	aliases map[string]string
+	// limiters holds the token buckets of cfg.RateLimits, created on first use.
+	limitersMu sync.Mutex
+	limiters   map[Provider]*providerLimiter
+	// health holds the latest result per provider of StartHealthMonitor.
+	healthMu sync.RWMutex
+	health   map[Provider]bool
+	// slots is the semaphore of cfg.MaxConcurrentRequests; nil when unlimited.
+	slots chan struct{}
 }
 
 // New creates a Client with the given config.
@@ -33,49 +116,181 @@ type Client struct {
 func New(cfg CoraConfig) *Client {
 	if cfg.DetectEnv {
 		if cfg.OpenAIAPIKey == "" {
-			cfg.OpenAIAPIKey = os.Getenv("OPENAI_API_KEY")
+			cfg.OpenAIAPIKey = os.Getenv(envName(cfg.EnvPrefix, "OPENAI_API_KEY"))
 		}
 		if cfg.GoogleAPIKey == "" {
-			cfg.GoogleAPIKey = os.Getenv("GOOGLE_API_KEY")
+			cfg.GoogleAPIKey = os.Getenv(envName(cfg.EnvPrefix, "GOOGLE_API_KEY"))
+		}
+		if cfg.MistralAPIKey == "" {
+			cfg.MistralAPIKey = os.Getenv(envName(cfg.EnvPrefix, "MISTRAL_API_KEY"))
 		}
+		if cfg.CohereAPIKey == "" {
+			cfg.CohereAPIKey = os.Getenv(envName(cfg.EnvPrefix, "COHERE_API_KEY"))
+		}
+	}
+	if cfg.TLSInsecureSkipVerify {
+		// Warn even without a DebugLogger: a disabled verification left over
+		// from testing must not go unnoticed.
+		cmp.Or(cfg.DebugLogger, slog.Default()).Warn("cora: TLS certificate verification is disabled (TLSInsecureSkipVerify)")
+	}
+	c := &Client{cfg: cfg, aliases: maps.Clone(cfg.ModelAliases), transport: newProviderTransport(cfg)}
+	if cfg.ResponseCacheTTL > 0 && cfg.ResponseCacheMaxSize > 0 {
+		c.responses = NewCache[string, TextResponse](cfg.ResponseCacheTTL, cfg.ResponseCacheMaxSize)
+		if cfg.SemanticCacheThreshold > 0 && cfg.SemanticCacheEmbedModel != "" {
+			c.semantic = newSemanticCache(cfg.ResponseCacheTTL, cfg.ResponseCacheMaxSize, cfg.SemanticCacheThreshold)
+		}
+	}
+	if cfg.MaxConcurrentRequests > 0 {
+		c.slots = make(chan struct{}, cfg.MaxConcurrentRequests)
 	}
-	return &Client{cfg: cfg}
+	return c
+}
+
+// acquireSlot blocks until fewer than cfg.MaxConcurrentRequests calls are in
+// flight and returns the function releasing the slot. It fails if ctx is
+// done first.
+func (c *Client) acquireSlot(ctx context.Context) (func(), error) {
+	if c.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.slots <- struct{}{}:
+		return func() { <-c.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Config returns a copy of the client's configuration, with any values
+// filled in from the environment by New.
+func (c *Client) Config() CoraConfig {
+	return c.cfg
 }
 
 // Text executes a text request using the requested provider/model and the selected Mode orchestration.
 func (c *Client) Text(ctx context.Context, req TextRequest) (TextResponse, error) {
-	if req.Provider != ProviderOpenAI && req.Provider != ProviderGoogle {
-		return TextResponse{}, fmt.Errorf("cora: unknown provider %q", req.Provider)
+	start := time.Now()
+	resp, err := c.textHandler()(ctx, req)
+	c.recordSpan(req, resp, start, err)
+	return resp, err
+}
+
+// textCore is the innermost TextFunc wrapped by the client's middleware.
+func (c *Client) textCore(ctx context.Context, req TextRequest) (TextResponse, error) {
+	if req.DryRun {
+		return c.dryRun(req)
 	}
-	model := req.Model
-	if model == "" {
-		switch req.Provider {
-		case ProviderOpenAI:
-			model = c.cfg.DefaultModelOpenAI
-		case ProviderGoogle:
-			model = c.cfg.DefaultModelGoogle
+	req, err := c.applyContentPolicy(ctx, req)
+	if err != nil {
+		return TextResponse{}, err
+	}
+	release, err := c.acquireSlot(ctx)
+	if err != nil {
+		return TextResponse{}, err
+	}
+	defer release()
+
+	start := time.Now()
+	c.logStart(ctx, req.Provider, req.Model, req.Mode)
+	resp, err := c.textConversation(ctx, req)
+	if err == nil {
+		resp.CorrelationID = CorrelationIDFromContext(ctx)
+		resp.Latency = time.Since(start)
+	}
+	c.logDone(ctx, cmp.Or(resp.UsedProvider, req.Provider), cmp.Or(resp.UsedModel, req.Model), req.Mode,
+		start, derefInt(resp.PromptTokens), derefInt(resp.CompletionTokens), err)
+	c.auditText(ctx, req, resp, err)
+	return resp, err
+}
+
+// textCached resolves ProviderAuto and serves req from the response cache
+// when possible, otherwise executes and validates it. Responses rejected by
+// req.ValidateResponse are never cached.
+func (c *Client) textCached(ctx context.Context, req TextRequest) (TextResponse, error) {
+	if req.Provider == ProviderAuto {
+		p, err := c.pickWeightedProvider()
+		if err != nil {
+			return TextResponse{}, err
 		}
-		if model == "" {
-			return TextResponse{}, errors.New("cora: model must be specified")
+		req.Provider = p
+	}
+
+	cacheKey, cacheable := c.responseCacheKey(req)
+	if cacheable {
+		if resp, ok := c.responses.Get(cacheKey); ok {
+			return cloneTextResponse(resp), nil
 		}
 	}
+	scope, vec, semantic := c.semanticCacheScope(ctx, req)
+	if semantic {
+		if resp, ok := c.semantic.lookup(scope, vec); ok {
+			return resp, nil
+		}
+	}
+
+	resp, err := c.textValidated(ctx, req)
+	if err != nil {
+		return TextResponse{}, err
+	}
+
+	if cacheable {
+		c.responses.Set(cacheKey, cloneTextResponse(resp))
+	}
+	if semantic {
+		c.semantic.store(scope, vec, resp)
+	}
+	return resp, nil
+}
+
+// textOnce executes req against its own provider, collapsing identical
+// concurrent calls when cfg.RequestDedup is set.
+func (c *Client) textOnce(ctx context.Context, req TextRequest) (TextResponse, error) {
+	if c.cfg.RequestDedup && !req.Mode.usesTools() {
+		return c.textDeduped(ctx, req)
+	}
+	return c.text(ctx, req)
+}
+
+func (c *Client) text(ctx context.Context, req TextRequest) (TextResponse, error) {
+	model, err := c.resolveModel(req)
+	if err != nil {
+		return TextResponse{}, err
+	}
+
+	if req.OverrideSizeLimit {
+		ctx = withSizeLimitOverride(ctx)
+	}
+	ctx, bodyBytes := withRequestBodyCounter(ctx)
+
+	ctx, endTask := traceTask(ctx, c.cfg.EnableTrace, string(req.Provider)+"/"+model)
+	defer endTask()
 
 	// 1) Build call plans based on Mode.
-	plans, err := buildPlans(req.Provider, model, req, c.cfg)
+	endRegion := traceRegion(ctx, c.cfg.EnableTrace, "cora.buildPlans")
+	plans, err := buildPlans(req.Provider, model, req, c.cfg, c.promptOptimizer)
+	endRegion()
 	if err != nil {
 		return TextResponse{}, err
 	}
+	c.logToolRetries(ctx, plans, req.Mode)
 
 	// 2) Execute plans sequentially; later plans may depend on earlier outputs.
 	var finalRes callResult
 	for i, p := range plans {
-		pc, err := c.ensureProvider(p.Provider)
+		p.logger = c.logger
+		p.trace = c.cfg.EnableTrace
+		pc, err := c.providerFor(p.Provider, p.Model)
 		if err != nil {
 			return TextResponse{}, err
 		}
+		if err := c.waitRateLimit(ctx, p); err != nil {
+			return TextResponse{}, err
+		}
+		endRegion := traceRegion(ctx, p.trace, "cora."+string(p.Provider)+".Text")
 		res, err := pc.Text(ctx, p)
+		endRegion()
 		if err != nil {
-			return TextResponse{}, err
+			return TextResponse{}, wrapProviderError(p.Provider, err)
 		}
 		finalRes = res
 
@@ -85,24 +300,103 @@ func (c *Client) Text(ctx context.Context, req TextRequest) (TextResponse, error
 		}
 	}
 
+	out := c.toTextResponse(req, model, finalRes)
+	out.RequestBodyBytes = bodyBytes.Load()
+	return out, nil
+}
+
+// toTextResponse converts the result of req's final plan to a TextResponse,
+// applying the mode's post-processing and recording its estimated cost.
+func (c *Client) toTextResponse(req TextRequest, model string, finalRes callResult) TextResponse {
 	out := TextResponse{
 		Provider: req.Provider,
 		Model:    model,
 		Mode:     req.Mode,
 		Text:     finalRes.Text,
 		JSON:     finalRes.JSON,
+
+		UsedProvider: req.Provider,
+		UsedModel:    model,
 	}
 	out.PromptTokens = finalRes.PromptTokens
 	out.CompletionTokens = finalRes.CompletionTokens
 	out.TotalTokens = finalRes.TotalTokens
-	return out, nil
+	out.ThinkingTokens = finalRes.ThinkingTokens
+	out.CachedTokens = finalRes.CachedTokens
+	out.ReasoningTrace = finalRes.ReasoningTrace
+	out.GroundingMetadata = finalRes.GroundingMetadata
+	out.TranscriptionLanguage = finalRes.TranscriptionLanguage
+	out.ToolCallGraph = finalRes.ToolCallGraph
+	out.ToolRounds = finalRes.ToolRounds
+	out.AgentSteps = finalRes.AgentSteps
+	out.RawProviderResponse = finalRes.RawProviderResponse
+	out.Metadata = finalRes.Metadata
+	if len(finalRes.Choices) > 0 {
+		out.Choices = make([]TextResponse, len(finalRes.Choices))
+		for i, ch := range finalRes.Choices {
+			out.Choices[i] = TextResponse{Provider: req.Provider, Model: model, Mode: req.Mode, Text: ch.Text, JSON: ch.JSON, CompletionTokens: ch.CompletionTokens}
+		}
+		if req.BestOf != nil {
+			best := bestChoice(finalRes.Choices)
+			out.Text, out.JSON = best.Text, best.JSON
+			if req.N == nil {
+				out.Choices = nil
+			}
+		}
+		if req.SelectCandidate != nil && len(out.Choices) > 1 {
+			best := req.SelectCandidate(out.Choices)
+			out.Text, out.JSON = best.Text, best.JSON
+		}
+	}
+	switch req.Mode {
+	case ModeClassify:
+		if label, ok := out.JSON["label"].(string); ok {
+			out.Text = label
+		}
+	case ModeScore:
+		if score, ok := out.JSON["score"].(float64); ok {
+			out.Score = &score
+		}
+	}
+	if out.TotalTokens != nil {
+		if cost, ok := estimateCost(c.cfg, model, derefInt(out.PromptTokens), derefInt(out.CompletionTokens)); ok {
+			out.EstimatedCostUSD = &cost
+			c.addSpend(cost)
+		}
+		if cost, ok := estimateEffectiveCost(c.cfg, model, derefInt(out.PromptTokens), derefInt(out.CachedTokens), derefInt(out.CompletionTokens)); ok {
+			out.EffectiveCostUSD = &cost
+		}
+	}
+	return out
 }
 
+// resolveModel returns the request's model, falling back to the configured
+// default model for the request's provider, with aliases resolved.
+func (c *Client) resolveModel(req TextRequest) (string, error) {
+	model := req.Model
+	if model == "" {
+		switch req.Provider {
+		case ProviderOpenAI:
+			model = c.cfg.DefaultModelOpenAI
+		case ProviderGoogle:
+			model = c.cfg.DefaultModelGoogle
+		}
+		if model == "" {
+			return "", errors.New("cora: model must be specified")
+		}
+	}
+	return c.resolveModelAlias(model)
+}
+
+// ensureProvider returns the client of provider p, creating it on first use.
+// It is safe for concurrent calls.
 func (c *Client) ensureProvider(p Provider) (providerClient, error) {
+	c.providersMu.Lock()
+	defer c.providersMu.Unlock()
 	switch p {
 	case ProviderOpenAI:
 		if c.openai == nil {
-			pc, err := newOpenAIProvider(c.cfg)
+			pc, err := newOpenAIProvider(c.cfg, c.transport)
 			if err != nil {
 				return nil, err
 			}
@@ -111,33 +405,137 @@ func (c *Client) ensureProvider(p Provider) (providerClient, error) {
 		return c.openai, nil
 	case ProviderGoogle:
 		if c.google == nil {
-			pc, err := newGoogleProvider(c.cfg)
+			pc, err := newGoogleProvider(c.cfg, c.transport)
 			if err != nil {
 				return nil, err
 			}
 			c.google = pc
 		}
 		return c.google, nil
+	case ProviderMistral:
+		if c.mistral == nil {
+			pc, err := newMistralProvider(c.cfg, c.transport)
+			if err != nil {
+				return nil, err
+			}
+			c.mistral = pc
+		}
+		return c.mistral, nil
+	case ProviderCohere:
+		if c.cohere == nil {
+			pc, err := newCohereProvider(c.cfg, c.transport)
+			if err != nil {
+				return nil, err
+			}
+			c.cohere = pc
+		}
+		return c.cohere, nil
+	case ProviderBedrock:
+		if c.bedrock == nil {
+			pc, err := newBedrockProvider(c.cfg, c.transport)
+			if err != nil {
+				return nil, err
+			}
+			c.bedrock = pc
+		}
+		return c.bedrock, nil
 	default:
-		return nil, fmt.Errorf("cora: unsupported provider %q", p)
+		return nil, unknownProviderError(p)
 	}
 }
 
 // buildPlans converts a TextRequest + Mode into one or more call plans.
-func buildPlans(provider Provider, model string, req TextRequest, cfg CoraConfig) ([]callPlan, error) {
+// optimizer, if not nil, rewrites the input and system prompt first.
+func buildPlans(provider Provider, model string, req TextRequest, cfg CoraConfig, optimizer PromptOptimizer) ([]callPlan, error) {
 	base := callPlan{
-		Provider:         provider,
-		Model:            model,
-		System:           req.System,
-		Input:            req.Input,
-		Temperature:      req.Temperature,
-		MaxOutputTokens:  req.MaxOutputTokens,
-		Labels:           req.Labels,
-		ToolCacheTTL:     cfg.ToolCacheTTL,
-		ToolCacheMaxSize: cfg.ToolCacheMaxSize,
-		ToolRetryConfig:  cfg.ToolRetryConfig,
+		Provider:           provider,
+		Model:              model,
+		System:             req.System,
+		Input:              req.Input,
+		Messages:           slices.Concat(req.history, req.Messages),
+		Documents:          req.Documents,
+		FileHandles:        req.FileHandles,
+		AudioPart:          req.AudioPart,
+		Temperature:        req.Temperature,
+		MaxOutputTokens:    req.MaxOutputTokens,
+		N:                  max(derefInt(req.N), derefInt(req.BestOf)),
+		ReasoningEffort:    req.ReasoningEffort,
+		ThinkingBudget:     req.ThinkingBudget,
+		Labels:             mergeLabels(cfg.DefaultLabels, req.Labels),
+		ProviderOptions:    req.ProviderOptions,
+		IncludeRawResponse: req.IncludeRawResponse,
+		GroundWithSearch:   req.GroundWithSearch,
+		GroundingThreshold: req.GroundingThreshold,
+		CachedContentName:  req.CachedContentName,
+		ToolCacheTTL:       cfg.ToolCacheTTL,
+		ToolCacheMaxSize:   cfg.ToolCacheMaxSize,
+		ToolRetryConfig:    cfg.ToolRetryConfig,
+	}
+	if req.SanitizeInput {
+		base.Input = inputSanitizer(cfg).Sanitize(req.Input)
+	}
+	system, err := withOutputFormat(req.System, req.OutputFormat, req.CSVHeaders)
+	if err != nil {
+		return nil, err
+	}
+	base.System, err = withChunkPosition(system, req.ChunkIndex, req.ChunkTotal)
+	if err != nil {
+		return nil, err
+	}
+	if vs := req.VertexSearchGrounding; vs != nil {
+		if cfg.GoogleBackend != GoogleBackendVertex {
+			return nil, errors.New("cora: VertexSearchGrounding requires GoogleBackend to be GoogleBackendVertex")
+		}
+		if base.VertexDataStore, err = vertexDataStoreName(cfg, vs.DataStoreID); err != nil {
+			return nil, err
+		}
+		if vs.MaxResults < 0 || vs.MaxResults > 10 {
+			return nil, fmt.Errorf("cora: VertexSearchGrounding.MaxResults must be in [0, 10], got %d", vs.MaxResults)
+		}
+		base.VertexSearchMaxResults = vs.MaxResults
+	}
+
+	if (req.N != nil && *req.N < 1) || (req.BestOf != nil && *req.BestOf < 1) {
+		return nil, errors.New("cora: N and BestOf must be at least 1")
+	}
+
+	if req.ReasoningEffort != nil {
+		switch *req.ReasoningEffort {
+		case "low", "medium", "high":
+		default:
+			return nil, fmt.Errorf("cora: ReasoningEffort must be \"low\", \"medium\" or \"high\", got %q", *req.ReasoningEffort)
+		}
+	}
+
+	if optimizer != nil {
+		base.Input, base.System = optimizer.Optimize(provider, model, base.Input, base.System)
+	}
+
+	if cfg.DebugLogger != nil && base.ToolRetryConfig != nil {
+		rc := *base.ToolRetryConfig
+		rc.OnRetry = debugRetryHook(cfg.DebugLogger, rc.OnRetry)
+		base.ToolRetryConfig = &rc
 	}
 
+	if req.ContextWindowOverride < 0 {
+		return nil, errors.New("cora: ContextWindowOverride must not be negative")
+	}
+
+	plans, err := modePlans(provider, base, req)
+	if err != nil {
+		return nil, err
+	}
+	// Truncate last, so the budget covers the system prompts the mode adds.
+	if req.AutoTruncate && !req.DisableAutoTruncate {
+		for i := range plans {
+			truncateInputForPlan(&plans[i], cfg, req.TruncationStrategy, req.ContextWindowOverride)
+		}
+	}
+	return plans, nil
+}
+
+// modePlans derives the call plans of req's Mode from base.
+func modePlans(provider Provider, base callPlan, req TextRequest) ([]callPlan, error) {
 	switch req.Mode {
 	case ModeBasic:
 		return []callPlan{base}, nil
@@ -146,19 +544,82 @@ func buildPlans(provider Provider, model string, req TextRequest, cfg CoraConfig
 		if len(req.ResponseSchema) == 0 {
 			return nil, errors.New("cora: ResponseSchema is required for ModeStructuredJSON")
 		}
+		if err := validateResponseSchema(req.ResponseSchema); err != nil {
+			return nil, err
+		}
+		if !validSchemaName(req.SchemaName) {
+			return nil, fmt.Errorf("cora: invalid SchemaName %q: use at most 64 letters, digits, '_' or '-'", req.SchemaName)
+		}
 		base.Structured = true
+		base.StrictJSON = !req.DisableStrictJSON
 		base.ResponseSchema = req.ResponseSchema
+		base.SchemaName = req.SchemaName
 		return []callPlan{base}, nil
 
 	case ModeToolCalling:
 		if len(req.Tools) == 0 {
 			return nil, errors.New("cora: Tools must be provided for ModeToolCalling")
 		}
-		base.Tools = req.Tools
-		base.ToolHandlers = req.ToolHandlers
-		base.MaxToolRounds = req.MaxToolRounds
-		base.ParallelTools = req.ParallelTools
-		base.StopOnToolError = req.StopOnToolError
+		for _, t := range req.Tools {
+			if t.BuiltinType != "" {
+				return nil, fmt.Errorf("cora: built-in tool %q (%s) is not supported by provider %q", t.Name, t.BuiltinType, provider)
+			}
+		}
+		setToolOptions(&base, req)
+		return []callPlan{base}, nil
+
+	case ModeReAct:
+		if len(req.Tools) == 0 {
+			return nil, errors.New("cora: Tools must be provided for ModeReAct")
+		}
+		base.System = reActSystemPrompt(base.System)
+		base.ReAct = true
+		setToolOptions(&base, req)
+		return []callPlan{base}, nil
+
+	case ModeAgentLoop:
+		if len(req.Tools) == 0 {
+			return nil, errors.New("cora: Tools must be provided for ModeAgentLoop")
+		}
+		if req.AgentMaxSteps != nil && *req.AgentMaxSteps <= 0 {
+			return nil, errors.New("cora: AgentMaxSteps must be positive")
+		}
+		setToolOptions(&base, req)
+		base.AgentStopSignal = cmp.Or(req.AgentStopSignal, DefaultAgentStopSignal)
+		base.System = agentSystemPrompt(base.System, req.AgentGoal, base.AgentStopSignal)
+		if req.AgentMaxSteps != nil {
+			base.MaxToolRounds = req.AgentMaxSteps
+		}
+		return []callPlan{base}, nil
+
+	case ModeClassify:
+		if len(req.ClassifyLabels) == 0 {
+			return nil, errors.New("cora: ClassifyLabels must be provided for ModeClassify")
+		}
+		base.System = classifySystemPrompt(base.System, req.ClassifyLabels)
+		base.Messages = append(classifyExampleMessages(req.ClassifyExamples), base.Messages...)
+		base.Structured = true
+		base.StrictJSON = true
+		base.ResponseSchema = classifyResponseSchema(req.ClassifyLabels)
+		return []callPlan{base}, nil
+
+	case ModeScore:
+		if err := validateScoreRequest(req); err != nil {
+			return nil, err
+		}
+		lo, hi := scoreRange(req)
+		base.System = scoreSystemPrompt(base.System, req.ScoreRubric, lo, hi)
+		base.Structured = true
+		base.StrictJSON = true
+		base.ResponseSchema = scoreResponseSchema()
+		return []callPlan{base}, nil
+
+	case ModeTranscribe:
+		if len(req.Audio.Data) == 0 {
+			return nil, errors.New("cora: Audio.Data is required for ModeTranscribe")
+		}
+		base.Transcribe = true
+		base.Audio = req.Audio
 		return []callPlan{base}, nil
 
 	case ModeTwoStepEnhance:
@@ -166,6 +627,10 @@ func buildPlans(provider Provider, model string, req TextRequest, cfg CoraConfig
 		p1 := base
 		p1.Proofread = true
 		p1.System = "" // system for the clean-up is internally applied
+		p1.Messages = nil
+		p1.Documents = nil
+		p1.FileHandles = nil
+		p1.AudioPart = nil
 
 		// Plan 2: final answer on improved text (inherits original options)
 		p2 := base
@@ -176,6 +641,46 @@ func buildPlans(provider Provider, model string, req TextRequest, cfg CoraConfig
 	}
 }
 
+// setToolOptions copies the tool-calling settings of req into plan.
+func setToolOptions(plan *callPlan, req TextRequest) {
+	plan.Tools = req.Tools
+	plan.ToolHandlers = req.ToolHandlers
+	plan.StreamingHandlers = req.StreamingHandlers
+	plan.RecordToolGraph = req.RecordToolGraph
+	plan.ToolObserver = req.ToolObserver
+	plan.DynamicSystemPrompt = req.DynamicSystemPrompt
+	plan.RoundSystemPrompts = req.RoundSystemPrompts
+	plan.MaxToolRounds = req.MaxToolRounds
+	plan.MaxTotalToolCalls = req.MaxTotalToolCalls
+	plan.ParallelTools = req.ParallelTools
+	plan.StopOnToolError = req.StopOnToolError
+	plan.CoerceToolArgs = req.CoerceToolArgs
+}
+
+// bestChoice returns the shortest non-empty choice, approximating the one
+// with the lowest perplexity.
+func bestChoice(choices []callChoice) callChoice {
+	best := choices[0]
+	for _, c := range choices[1:] {
+		if c.Text != "" && (best.Text == "" || len(c.Text) < len(best.Text)) {
+			best = c
+		}
+	}
+	return best
+}
+
+// mergeLabels returns a new map holding defaults overridden by labels, or
+// labels itself when there are no defaults.
+func mergeLabels(defaults, labels map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return labels
+	}
+	out := make(map[string]string, len(defaults)+len(labels))
+	maps.Copy(out, defaults)
+	maps.Copy(out, labels)
+	return out
+}
+
 // resultPreferredInput picks the best string to feed into the next step.
 func resultPreferredInput(res callResult) string {
 	if res.Text != "" {
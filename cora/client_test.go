@@ -1,8 +1,12 @@
 package cora
 
 import (
+	"context"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestNew_OpenAIOnly_FromEnv(t *testing.T) {
@@ -89,3 +93,93 @@ func TestNew_RequiresValidConfig(t *testing.T) {
 		t.Fatalf("New returned nil client even with empty config")
 	}
 }
+
+func TestLoadFromEnv_Prefix(t *testing.T) {
+	t.Setenv("TEST_OPENAI_API_KEY", "sk-test")
+	t.Setenv("TEST_GOOGLE_API_KEY", "g-test")
+	t.Setenv("TEST_DEFAULT_MODEL_OPENAI", "gpt-4o-mini")
+	t.Setenv("TEST_OPENAI_BASE_URL", "http://localhost:8080/v1")
+	t.Setenv("TEST_PROVIDER", "openai")
+	t.Setenv("TEST_GOOGLE_BACKEND", "vertex")
+	t.Setenv("TEST_TIMEOUT", "30s")
+	t.Setenv("OPENAI_API_KEY", "sk-prod")
+
+	cfg := LoadFromEnv("TEST")
+	if cfg.OpenAIAPIKey != "sk-test" || cfg.GoogleAPIKey != "g-test" || cfg.DefaultModelOpenAI != "gpt-4o-mini" ||
+		cfg.OpenAIBaseURL != "http://localhost:8080/v1" || cfg.Provider != ProviderOpenAI ||
+		cfg.GoogleBackend != GoogleBackendVertex || cfg.Timeout != 30*time.Second || cfg.EnvPrefix != "TEST" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	if prod := LoadFromEnv(""); prod.OpenAIAPIKey != "sk-prod" {
+		t.Errorf("expected unprefixed key, got %q", prod.OpenAIAPIKey)
+	}
+
+	c := New(CoraConfig{DetectEnv: true, EnvPrefix: "TEST"})
+	if c.cfg.OpenAIAPIKey != "sk-test" {
+		t.Errorf("expected DetectEnv to use EnvPrefix, got %q", c.cfg.OpenAIAPIKey)
+	}
+}
+
+// concurrencyProvider records the most Text calls it saw in flight at once.
+type concurrencyProvider struct {
+	inFlight, peak atomic.Int32
+}
+
+func (p *concurrencyProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	n := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		peak := p.peak.Load()
+		if n <= peak || p.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	return callResult{Text: "ok"}, nil
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	c := New(CoraConfig{MaxConcurrentRequests: 3})
+	pc := &concurrencyProvider{}
+	c.openai = pc
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "m", Input: "hi"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if peak := pc.peak.Load(); peak > 3 {
+		t.Errorf("saw %d concurrent provider calls, want at most 3", peak)
+	}
+
+	// A stream holds its slot until it ends.
+	c = New(CoraConfig{MaxConcurrentRequests: 1})
+	c.openai = openAIChunkServer(t, 0, 0, "a", "b", "c")
+	resp, err := c.Stream(context.Background(), StreamRequest{
+		Provider:      ProviderOpenAI,
+		Model:         "m",
+		Input:         "hi",
+		StreamOptions: StreamOptions{BufferSize: 1}, // blocks until read
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.Text(ctx, TextRequest{Provider: ProviderOpenAI, Model: "m", Input: "hi"}); err == nil {
+		t.Error("expected Text to wait for the open stream's slot")
+	}
+	for range resp.Events {
+	}
+	c.openai = &fakeProvider{}
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "m", Input: "hi"}); err != nil {
+		t.Errorf("Text after the stream ended: %v", err)
+	}
+}
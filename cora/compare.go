@@ -0,0 +1,181 @@
+package cora
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// CompareMetric is a dimension along which CompareResponses ranks two
+// responses.
+type CompareMetric int
+
+const (
+	// MetricLength favours the shorter response text.
+	MetricLength CompareMetric = iota
+	// MetricTokens favours the response that used fewer total tokens.
+	MetricTokens
+	// MetricLatency favours the faster response (TextResponse.Latency).
+	MetricLatency
+	// MetricCost favours the cheaper response (TextResponse.EstimatedCostUSD).
+	MetricCost
+	// MetricSentiment favours the more positive response text, by a small
+	// word-list heuristic.
+	MetricSentiment
+)
+
+var compareMetricNames = map[CompareMetric]string{
+	MetricLength:    "length",
+	MetricTokens:    "tokens",
+	MetricLatency:   "latency",
+	MetricCost:      "cost",
+	MetricSentiment: "sentiment",
+}
+
+// String returns the metric's name, e.g. "latency".
+func (m CompareMetric) String() string {
+	if name, ok := compareMetricNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("CompareMetric(%d)", int(m))
+}
+
+// ComparisonResult is the outcome of CompareResponses.
+type ComparisonResult struct {
+	// Winner is the provider of the response that won the most metrics; it
+	// is empty on a tie.
+	Winner Provider
+	// Scores holds, per compared metric, the relative advantage of the first
+	// response over the second in [-1, 1]: positive when the first won,
+	// negative when the second won and 0 on a tie.
+	Scores map[CompareMetric]float64
+	// Notes explains the per-metric outcome and any metric that was skipped
+	// because a response lacked the data.
+	Notes string
+}
+
+// CompareResponses compares a and b, typically answers to the same prompt
+// from different providers, along metrics and reports which one won each.
+// It makes no API calls. Metrics a response has no data for (nil token
+// counts or cost, zero latency) are skipped.
+func CompareResponses(a, b TextResponse, metrics []CompareMetric) ComparisonResult {
+	res := ComparisonResult{Scores: make(map[CompareMetric]float64, len(metrics))}
+	nameA, nameB := compareLabel(a, "a"), compareLabel(b, "b")
+	var notes []string
+	winsA, winsB := 0, 0
+	for _, m := range metrics {
+		va, vb, ok := compareValues(m, a, b)
+		if !ok {
+			notes = append(notes, fmt.Sprintf("%s: skipped, missing data", m))
+			continue
+		}
+		var score float64
+		if m == MetricSentiment {
+			score = (va - vb) / 2
+		} else {
+			score = lowerIsBetter(va, vb)
+		}
+		res.Scores[m] = score
+		switch {
+		case score > 0:
+			winsA++
+			notes = append(notes, fmt.Sprintf("%s: %s won (%g vs %g)", m, nameA, va, vb))
+		case score < 0:
+			winsB++
+			notes = append(notes, fmt.Sprintf("%s: %s won (%g vs %g)", m, nameB, vb, va))
+		default:
+			notes = append(notes, fmt.Sprintf("%s: tie (%g)", m, va))
+		}
+	}
+	switch {
+	case winsA > winsB:
+		res.Winner = a.Provider
+	case winsB > winsA:
+		res.Winner = b.Provider
+	}
+	res.Notes = strings.Join(notes, "; ")
+	return res
+}
+
+// compareLabel names r in comparison notes.
+func compareLabel(r TextResponse, fallback string) string {
+	if r.Provider == "" {
+		return fallback
+	}
+	if r.Model == "" {
+		return string(r.Provider)
+	}
+	return string(r.Provider) + "/" + r.Model
+}
+
+// compareValues returns the raw values of metric m for a and b, and false
+// when either response lacks them.
+func compareValues(m CompareMetric, a, b TextResponse) (float64, float64, bool) {
+	switch m {
+	case MetricLength:
+		return float64(len([]rune(a.Text))), float64(len([]rune(b.Text))), true
+	case MetricTokens:
+		if a.TotalTokens == nil || b.TotalTokens == nil {
+			return 0, 0, false
+		}
+		return float64(*a.TotalTokens), float64(*b.TotalTokens), true
+	case MetricLatency:
+		if a.Latency <= 0 || b.Latency <= 0 {
+			return 0, 0, false
+		}
+		return a.Latency.Seconds(), b.Latency.Seconds(), true
+	case MetricCost:
+		if a.EstimatedCostUSD == nil || b.EstimatedCostUSD == nil {
+			return 0, 0, false
+		}
+		return *a.EstimatedCostUSD, *b.EstimatedCostUSD, true
+	case MetricSentiment:
+		return sentimentScore(a.Text), sentimentScore(b.Text), true
+	}
+	return 0, 0, false
+}
+
+// lowerIsBetter returns the relative advantage of va over vb when smaller
+// values are better.
+func lowerIsBetter(va, vb float64) float64 {
+	hi := math.Max(math.Abs(va), math.Abs(vb))
+	if hi == 0 {
+		return 0
+	}
+	return (vb - va) / hi
+}
+
+var (
+	positiveWords = map[string]bool{
+		"good": true, "great": true, "excellent": true, "happy": true, "glad": true,
+		"love": true, "best": true, "helpful": true, "positive": true, "wonderful": true,
+		"success": true, "successful": true, "easy": true, "benefit": true, "pleased": true,
+	}
+	negativeWords = map[string]bool{
+		"bad": true, "poor": true, "terrible": true, "sad": true, "sorry": true,
+		"hate": true, "worst": true, "unfortunately": true, "negative": true, "awful": true,
+		"fail": true, "failed": true, "difficult": true, "problem": true, "cannot": true,
+	}
+)
+
+// sentimentScore rates text in [-1, 1] by counting positive and negative
+// words. It is a rough heuristic, not a sentiment model.
+func sentimentScore(text string) float64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	pos, neg := 0, 0
+	for _, w := range words {
+		switch {
+		case positiveWords[w]:
+			pos++
+		case negativeWords[w]:
+			neg++
+		}
+	}
+	if pos+neg == 0 {
+		return 0
+	}
+	return float64(pos-neg) / float64(pos+neg)
+}
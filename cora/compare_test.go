@@ -0,0 +1,51 @@
+package cora
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareResponses(t *testing.T) {
+	gemini := TextResponse{
+		Provider:         ProviderGoogle,
+		Model:            "gemini-2.5-flash",
+		Text:             "Great question! The answer is 42.",
+		TotalTokens:      intPtr(30),
+		EstimatedCostUSD: float64Ptr(0.0001),
+		Latency:          400 * time.Millisecond,
+	}
+	gpt := TextResponse{
+		Provider:         ProviderOpenAI,
+		Model:            "gpt-4o",
+		Text:             "Unfortunately I cannot be sure, but the answer is probably 42.",
+		TotalTokens:      intPtr(45),
+		EstimatedCostUSD: float64Ptr(0.0004),
+		Latency:          200 * time.Millisecond,
+	}
+
+	res := CompareResponses(gemini, gpt, []CompareMetric{MetricLength, MetricTokens, MetricLatency, MetricCost, MetricSentiment})
+	if res.Winner != ProviderGoogle {
+		t.Errorf("Winner = %q, want %q", res.Winner, ProviderGoogle)
+	}
+	for _, m := range []CompareMetric{MetricLength, MetricTokens, MetricCost, MetricSentiment} {
+		if res.Scores[m] <= 0 {
+			t.Errorf("%s score = %g, want the first response to win", m, res.Scores[m])
+		}
+	}
+	if res.Scores[MetricLatency] >= 0 {
+		t.Errorf("latency score = %g, want the second response to win", res.Scores[MetricLatency])
+	}
+	if !strings.Contains(res.Notes, "latency: openai/gpt-4o won") {
+		t.Errorf("unexpected notes: %s", res.Notes)
+	}
+
+	gpt.EstimatedCostUSD = nil
+	res = CompareResponses(gemini, gpt, []CompareMetric{MetricCost})
+	if _, ok := res.Scores[MetricCost]; ok || res.Winner != "" {
+		t.Errorf("expected cost to be skipped and no winner, got %+v", res)
+	}
+}
+
+func intPtr(v int) *int             { return &v }
+func float64Ptr(v float64) *float64 { return &v }
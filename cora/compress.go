@@ -0,0 +1,43 @@
+package cora
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// compressingTransport gzips JSON request bodies and sets Content-Encoding
+// (see CoraConfig.CompressRequests). Other bodies, such as multipart audio
+// uploads, are sent as is.
+type compressingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *compressingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Encoding") != "" ||
+		!strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		return t.base.RoundTrip(req)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := io.Copy(zw, req.Body)
+	req.Body.Close()
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cora: compressing request body: %w", err)
+	}
+
+	data := buf.Bytes()
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(data)), nil }
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Encoding", "gzip")
+	return t.base.RoundTrip(req)
+}
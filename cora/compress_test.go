@@ -0,0 +1,58 @@
+package cora
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompressRequests(t *testing.T) {
+	for _, provider := range []Provider{ProviderOpenAI, ProviderGoogle} {
+		t.Run(string(provider), func(t *testing.T) {
+			var encoding string
+			var body map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				encoding = r.Header.Get("Content-Encoding")
+				zr, err := gzip.NewReader(r.Body)
+				if err != nil {
+					t.Errorf("request body is not gzip: %v", err)
+					return
+				}
+				data, _ := io.ReadAll(zr)
+				if err := json.Unmarshal(data, &body); err != nil {
+					t.Errorf("decompressed body is not JSON: %v", err)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				if provider == ProviderGoogle {
+					_, _ = io.WriteString(w, `{"candidates":[{"content":{"role":"model","parts":[{"text":"ok"}]}}]}`)
+					return
+				}
+				_, _ = io.WriteString(w, `{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`)
+			}))
+			defer srv.Close()
+
+			c := New(CoraConfig{
+				OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL,
+				GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL,
+				CompressRequests: true,
+			})
+			input := strings.Repeat("a long document ", 1000)
+			resp, err := c.Text(context.Background(), TextRequest{Provider: provider, Model: "m", Input: input})
+			if err != nil {
+				t.Fatalf("Text error: %v", err)
+			}
+			if resp.Text != "ok" || encoding != "gzip" {
+				t.Errorf("got text %q, Content-Encoding %q", resp.Text, encoding)
+			}
+			if !strings.Contains(string(jsonMarshalNoErr(body)), "a long document") {
+				t.Errorf("decompressed body does not contain the input: %v", body)
+			}
+		})
+	}
+}
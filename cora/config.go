@@ -1,7 +1,14 @@
 package cora
 
 import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"reflect"
 	"time"
 )
 
@@ -10,19 +17,30 @@ import (
 // Config holds secrets and HTTP knobs.
 type CoraConfig struct {
 
-
 	// Default model per provider if not set per-call.
 	DefaultModelOpenAI string
 	DefaultModelGoogle string
 
-		Provider Provider
+	Provider Provider
 
 	// OpenAI configuration.
 	OpenAIAPIKey     string // falls back to env OPENAI_API_KEY if empty and DetectEnv is true
 	OpenAIBaseURL    string // optional; supports custom or Azure endpoint
 	OpenAIOrgID      string // optional; also supports env OPENAI_ORG_ID
 	OpenAIAPIType    string // "openai" (default) or "azure"
-	OpenAIAPIVersion string // required for Azure
+	OpenAIAPIVersion string // Azure API version; defaults to "2024-02-01"
+
+	// AzureDeployments maps model names to Azure deployment names when
+	// OpenAIAPIType is "azure". Unmapped models are used as the deployment
+	// name with "." and ":" removed.
+	AzureDeployments map[string]string
+
+	// OpenAIEndpoints are further OpenAI-compatible APIs used through
+	// ProviderOpenAI. A request whose model is listed by an endpoint goes to
+	// that endpoint; other models go to DefaultOpenAIEndpoint or, when it is
+	// empty, to OpenAIAPIKey and OpenAIBaseURL.
+	OpenAIEndpoints       []OpenAIEndpoint
+	DefaultOpenAIEndpoint string
 
 	// Google/GenAI configuration.
 	GoogleAPIKey   string // falls back to env GOOGLE_API_KEY if empty and DetectEnv is true
@@ -30,16 +48,385 @@ type CoraConfig struct {
 	GoogleLocation string // required for Vertex AI
 	GoogleBaseURL  string // optional custom endpoint
 	GoogleBackend  GoogleBackend
+	// VertexAIDataStoreID is the default Vertex AI Search data store for
+	// TextRequest.VertexSearchGrounding.
+	VertexAIDataStoreID string
+
+	// Mistral configuration (OpenAI-compatible API).
+	MistralAPIKey  string // falls back to env MISTRAL_API_KEY if empty and DetectEnv is true
+	MistralBaseURL string // optional; defaults to https://api.mistral.ai/v1
+
+	// Cohere configuration (Cohere Chat API).
+	CohereAPIKey  string // falls back to env COHERE_API_KEY if empty and DetectEnv is true
+	CohereBaseURL string // optional; defaults to https://api.cohere.com/v1
+
+	// Bedrock configuration (Converse API). Credentials come from the
+	// standard AWS chain: environment, shared config profile or IAM role.
+	BedrockRegion  string // falls back to the AWS chain's region (AWS_REGION, profile)
+	BedrockProfile string // optional shared config profile
+	BedrockBaseURL string // optional custom endpoint
 
 	// Shared client options.
 	HTTPClient *http.Client
 	Timeout    time.Duration // applied to HTTPOptions.Timeout (genai) and HTTP client (OpenAI) when possible
 
+	// OpenAIHTTPClient and GoogleHTTPClient, when set, replace HTTPClient for
+	// that provider, e.g. to give Google a longer timeout for large documents.
+	OpenAIHTTPClient *http.Client
+	GoogleHTTPClient *http.Client
+
+	// TLSConfig is used by the transport cora builds for provider requests,
+	// e.g. to trust a custom CA or pin certificates. TLSInsecureSkipVerify is
+	// a shortcut that disables certificate verification (for testing only);
+	// New logs a warning about it to DebugLogger, or else slog.Default().
+	// Neither can be combined with HTTPClient, OpenAIHTTPClient or
+	// GoogleHTTPClient: configure TLS on that client's transport instead.
+	TLSConfig             *tls.Config
+	TLSInsecureSkipVerify bool
+
+	// Connection pool settings of the transport cora builds for provider
+	// requests; zero keeps http.DefaultTransport's. The transport is shared
+	// by all providers of a Client except those given their own HTTP client,
+	// so the settings cannot be combined with HTTPClient.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+	DisableKeepAlives   bool
+
+	// CompressRequests gzips JSON request bodies (Content-Encoding: gzip),
+	// reducing upload size for long prompts and documents.
+	CompressRequests bool
+
+	// RequestSigner, when set, signs every provider request after any
+	// compression, e.g. with HMACRequestSigner for private endpoints that
+	// require HMAC-signed requests.
+	RequestSigner RequestSigner
+
+	// HTTPRetryConfig, when set, retries provider HTTP requests that fail
+	// with a transient network error (a timeout, or a refused, reset or
+	// dropped connection) or are answered with 429 Too Many Requests or 503
+	// Service Unavailable, up to MaxAttempts in total with its backoff. Each
+	// retry is logged to DebugLogger. With RespectRetryAfter, the response's
+	// Retry-After header (seconds or an HTTP date) sets the wait instead,
+	// capped at MaxBackoff.
+	HTTPRetryConfig   *RetryConfig
+	RespectRetryAfter bool
+
+	// MaxRequestBodyBytes, when positive, rejects provider requests whose
+	// body is larger with ErrRequestTooLarge before they are sent, guarding
+	// against accidentally huge prompts (see TextRequest.OverrideSizeLimit).
+	// The size is measured after CompressRequests compression.
+	MaxRequestBodyBytes int64
+
+	// DebugLogger, when set, logs provider HTTP requests and responses (with
+	// API keys masked) and tool and HTTP retry attempts at DEBUG level.
+	DebugLogger *slog.Logger
+
+	// EnableTrace annotates runtime/trace execution traces: each Text call
+	// is a task named "provider/model", with regions for planning, provider
+	// calls, tool loops and tool batches. When false (the default) no trace
+	// annotations are made.
+	EnableTrace bool
+
+	// DefaultLabels are added to every request's Labels; a request's own
+	// labels win on key collision.
+	DefaultLabels map[string]string
+
+	// InputSanitizer replaces DefaultInputSanitizer for requests with
+	// TextRequest.SanitizeInput set.
+	InputSanitizer InputSanitizer
+
+	// EmbedBatchSize is the number of inputs sent per embeddings request by
+	// Client.Embed (default: 100 for OpenAI and Mistral, 1 for Google).
+	EmbedBatchSize int
+
 	// Tool execution configuration (applies to all tool calls unless overridden per-request).
 	ToolCacheTTL     time.Duration // TTL for cached tool results; 0 disables cache (default: 0)
 	ToolCacheMaxSize int           // Max number of cached tool results; 0 disables cache (default: 0)
 	ToolRetryConfig  *RetryConfig  // Retry configuration for tool handlers; nil disables retry (default: nil)
 
+	// PricingTable overrides or extends DefaultPricingTable for cost
+	// estimates (TextResponse.EstimatedCostUSD, Client.TotalSpend).
+	PricingTable PricingTable
+
+	// ModelAliases maps alias model names to real ones, e.g. "fast" to
+	// "gemini-2.5-flash", so call sites can switch models in one place.
+	// Aliases may chain; a cycle is an error at request time.
+	ModelAliases map[string]string
+
+	// ModelContextWindows maps model names to context window sizes in tokens,
+	// used by TextRequest.AutoTruncate. Entries override the built-in defaults.
+	ModelContextWindows map[string]int
+
+	// ModelCapabilities maps model names to their features, used by
+	// Client.SupportsFeature and Client.SupportsMode. Entries override the
+	// built-in table.
+	ModelCapabilities map[string][]ProviderFeature
+
+	// Response caching for Text(). Both values must be set to enable the cache.
+	// Tool-calling requests and requests with Temperature > 0 are never cached.
+	ResponseCacheTTL     time.Duration // TTL for cached responses; 0 disables cache (default: 0)
+	ResponseCacheMaxSize int           // Max number of cached responses; 0 disables cache (default: 0)
+
+	// SemanticCacheThreshold extends the response cache to inputs whose
+	// embedding has at least this cosine similarity (e.g. 0.95) to a cached
+	// request's input; the other request fields must still match exactly.
+	// Embeddings come from SemanticCacheEmbedModel on
+	// SemanticCacheEmbedProvider (default: the request's provider). Requires
+	// the response cache; 0 disables semantic matching (default: 0).
+	SemanticCacheThreshold     float64
+	SemanticCacheEmbedModel    string
+	SemanticCacheEmbedProvider Provider
+
+	// RequestDedup collapses identical concurrent Text() calls into a single
+	// provider call whose result is shared by all callers (default: false).
+	RequestDedup bool
+
+	// MaxConcurrentRequests caps the Text() calls and streams in flight at
+	// once; further calls wait for a free slot (default: 0, unlimited).
+	MaxConcurrentRequests int
+
+	// ProviderWeights controls how requests with Provider == ProviderAuto are
+	// routed. Weights are relative and need not sum to 1.
+	ProviderWeights map[Provider]float64
+
+	// RateLimits throttles Text() calls per provider on the client side, so
+	// that bursts of concurrent requests queue instead of failing with 429s.
+	// Each call waits for its provider's bucket, up to its context deadline.
+	RateLimits map[Provider]RateLimit
+
+	// ModelFallbacks maps a model to the models to try, in order, when it
+	// answers with HTTP 503 (overloaded) or 429 (rate limited), e.g.
+	// "gemini-1.5-pro" to ["gemini-1.5-flash"]. The fallbacks run on the same
+	// provider; TextResponse.UsedModel reports the model that answered.
+	ModelFallbacks map[string][]string
+
+	// ContentPolicy, when set, checks every Text and Stream request's input,
+	// system prompt and earlier messages, including a conversation's
+	// history, before it is sent; rejected requests fail with a CoraError
+	// whose Code is ErrCodeContentPolicy. See RegexContentPolicy.
+	ContentPolicy ContentPolicy
+
+	// JudgeProvider and JudgeModel select the model used by
+	// Client.EvaluateResponse; when JudgeProvider is empty the evaluated
+	// request's provider and model are used. A response passes when its mean
+	// score reaches JudgePassThreshold (0-1, default: 0.7).
+	JudgeProvider      Provider
+	JudgeModel         string
+	JudgePassThreshold float64
+
 	// Auto-detection.
 	DetectEnv bool // when true, pull missing values from environment
-}
\ No newline at end of file
+	// EnvPrefix namespaces the variables read by DetectEnv, e.g. "TEST" reads
+	// TEST_OPENAI_API_KEY instead of OPENAI_API_KEY (see LoadFromEnv).
+	EnvPrefix string
+}
+
+// Validate reports configuration problems such as missing credentials or
+// conflicting backend settings. All problems are collected and returned
+// together (via errors.Join) so they can be fixed in one pass.
+func (cfg CoraConfig) Validate() error {
+	var errs []error
+
+	// Google backend settings.
+	switch cfg.GoogleBackend {
+	case GoogleBackendAuto:
+	case GoogleBackendVertex:
+		if cfg.GoogleProject == "" {
+			errs = append(errs, errors.New("cora: GoogleProject is required when GoogleBackend is GoogleBackendVertex"))
+		}
+		if cfg.GoogleLocation == "" {
+			errs = append(errs, errors.New("cora: GoogleLocation is required when GoogleBackend is GoogleBackendVertex"))
+		}
+	case GoogleBackendGemini:
+		if cfg.GoogleAPIKey == "" && !cfg.DetectEnv {
+			errs = append(errs, errors.New("cora: GoogleAPIKey is required when GoogleBackend is GoogleBackendGemini and DetectEnv is false"))
+		}
+	default:
+		errs = append(errs, errors.New("cora: unknown GoogleBackend"))
+	}
+
+	if cfg.Provider == ProviderMistral && cfg.MistralAPIKey == "" && !cfg.DetectEnv {
+		errs = append(errs, errors.New("cora: MistralAPIKey is required when Provider is ProviderMistral and DetectEnv is false"))
+	}
+	if cfg.Provider == ProviderCohere && cfg.CohereAPIKey == "" && !cfg.DetectEnv {
+		errs = append(errs, errors.New("cora: CohereAPIKey is required when Provider is ProviderCohere and DetectEnv is false"))
+	}
+
+	// OpenAI settings. The key is only required when OpenAI is actually in use
+	// and the endpoint is not a local server (e.g. an Ollama or vLLM instance).
+	usesOpenAI := cfg.Provider == ProviderOpenAI || cfg.OpenAIBaseURL != "" || cfg.DefaultModelOpenAI != ""
+	if usesOpenAI && cfg.OpenAIAPIKey == "" && cfg.DefaultOpenAIEndpoint == "" && !cfg.DetectEnv && openAIBaseURLRequiresAuth(cfg.OpenAIBaseURL) {
+		errs = append(errs, errors.New("cora: OpenAIAPIKey is required for the configured OpenAI endpoint when DetectEnv is false"))
+	}
+	errs = append(errs, cfg.validateOpenAIEndpoints()...)
+	switch cfg.OpenAIAPIType {
+	case "", "openai":
+	case "azure":
+		if cfg.OpenAIBaseURL == "" {
+			errs = append(errs, errors.New("cora: OpenAIBaseURL is required when OpenAIAPIType is \"azure\""))
+		}
+	default:
+		errs = append(errs, errors.New("cora: OpenAIAPIType must be \"openai\" or \"azure\""))
+	}
+
+	// Tool cache settings must be enabled together.
+	if cfg.ToolCacheTTL < 0 || cfg.ToolCacheMaxSize < 0 {
+		errs = append(errs, errors.New("cora: ToolCacheTTL and ToolCacheMaxSize must not be negative"))
+	}
+	if cfg.ToolCacheMaxSize > 0 && cfg.ToolCacheTTL <= 0 {
+		errs = append(errs, errors.New("cora: ToolCacheTTL must be set when ToolCacheMaxSize is set"))
+	}
+	if cfg.ToolCacheTTL > 0 && cfg.ToolCacheMaxSize <= 0 {
+		errs = append(errs, errors.New("cora: ToolCacheMaxSize must be set when ToolCacheTTL is set"))
+	}
+
+	// Response cache settings must be enabled together.
+	if cfg.ResponseCacheTTL < 0 || cfg.ResponseCacheMaxSize < 0 {
+		errs = append(errs, errors.New("cora: ResponseCacheTTL and ResponseCacheMaxSize must not be negative"))
+	}
+	if (cfg.ResponseCacheTTL > 0) != (cfg.ResponseCacheMaxSize > 0) {
+		errs = append(errs, errors.New("cora: ResponseCacheTTL and ResponseCacheMaxSize must be set together"))
+	}
+
+	if (cfg.TLSConfig != nil || cfg.TLSInsecureSkipVerify) &&
+		(cfg.HTTPClient != nil || cfg.OpenAIHTTPClient != nil || cfg.GoogleHTTPClient != nil) {
+		errs = append(errs, errors.New("cora: TLSConfig and TLSInsecureSkipVerify cannot be combined with a custom HTTP client"))
+	}
+
+	if cfg.MaxIdleConns < 0 || cfg.MaxIdleConnsPerHost < 0 || cfg.MaxConnsPerHost < 0 || cfg.IdleConnTimeout < 0 {
+		errs = append(errs, errors.New("cora: connection pool settings must not be negative"))
+	}
+	if cfg.hasPoolSettings() && cfg.HTTPClient != nil {
+		errs = append(errs, errors.New("cora: connection pool settings cannot be combined with HTTPClient"))
+	}
+
+	if cfg.ToolRetryConfig != nil && cfg.ToolRetryConfig.MaxAttempts <= 0 {
+		errs = append(errs, errors.New("cora: ToolRetryConfig.MaxAttempts must be positive"))
+	}
+	if cfg.ToolRetryConfig != nil && (cfg.ToolRetryConfig.JitterFactor < 0 || cfg.ToolRetryConfig.JitterFactor > 1) {
+		errs = append(errs, errors.New("cora: ToolRetryConfig.JitterFactor must be between 0 and 1"))
+	}
+	if cfg.HTTPRetryConfig != nil && cfg.HTTPRetryConfig.MaxAttempts <= 0 {
+		errs = append(errs, errors.New("cora: HTTPRetryConfig.MaxAttempts must be positive"))
+	}
+	if cfg.EmbedBatchSize < 0 {
+		errs = append(errs, errors.New("cora: EmbedBatchSize must not be negative"))
+	}
+	if cfg.Timeout < 0 {
+		errs = append(errs, errors.New("cora: Timeout must not be negative"))
+	}
+	if cfg.SemanticCacheThreshold < 0 || cfg.SemanticCacheThreshold > 1 {
+		errs = append(errs, errors.New("cora: SemanticCacheThreshold must be between 0 and 1"))
+	}
+	if cfg.SemanticCacheThreshold > 0 {
+		if cfg.SemanticCacheEmbedModel == "" {
+			errs = append(errs, errors.New("cora: SemanticCacheEmbedModel is required when SemanticCacheThreshold is set"))
+		}
+		if cfg.ResponseCacheTTL <= 0 || cfg.ResponseCacheMaxSize <= 0 {
+			errs = append(errs, errors.New("cora: SemanticCacheThreshold requires ResponseCacheTTL and ResponseCacheMaxSize"))
+		}
+	}
+	if cfg.SemanticCacheEmbedProvider != "" && !cfg.SemanticCacheEmbedProvider.Valid() {
+		errs = append(errs, unknownProviderError(cfg.SemanticCacheEmbedProvider))
+	}
+	if cfg.JudgeProvider != "" && !cfg.JudgeProvider.Valid() {
+		errs = append(errs, unknownProviderError(cfg.JudgeProvider))
+	}
+	if cfg.JudgePassThreshold < 0 || cfg.JudgePassThreshold > 1 {
+		errs = append(errs, errors.New("cora: JudgePassThreshold must be between 0 and 1"))
+	}
+	for p, w := range cfg.ProviderWeights {
+		if w < 0 {
+			errs = append(errs, fmt.Errorf("cora: ProviderWeights[%q] must not be negative", p))
+		}
+	}
+	if cfg.MaxRequestBodyBytes < 0 {
+		errs = append(errs, errors.New("cora: MaxRequestBodyBytes must not be negative"))
+	}
+	if cfg.MaxConcurrentRequests < 0 {
+		errs = append(errs, errors.New("cora: MaxConcurrentRequests must not be negative"))
+	}
+	for p, rl := range cfg.RateLimits {
+		if rl.RequestsPerMinute < 0 || rl.TokensPerMinute < 0 {
+			errs = append(errs, fmt.Errorf("cora: RateLimits[%q] must not be negative", p))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// tlsConfig returns the TLS configuration for cora's own transport, or nil
+// to use the defaults.
+func (cfg CoraConfig) tlsConfig() *tls.Config {
+	if !cfg.TLSInsecureSkipVerify {
+		return cfg.TLSConfig
+	}
+	tc := &tls.Config{}
+	if cfg.TLSConfig != nil {
+		tc = cfg.TLSConfig.Clone()
+	}
+	tc.InsecureSkipVerify = true
+	return tc
+}
+
+// openAIBaseURLRequiresAuth reports whether requests to baseURL need an API key.
+// The official endpoint (empty base URL) and any remote host do; loopback
+// servers are assumed to be unauthenticated local deployments.
+func openAIBaseURLRequiresAuth(baseURL string) bool {
+	if baseURL == "" {
+		return true
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return true
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return false
+	}
+	return true
+}
+
+// Merge returns a new config where every non-zero field of override replaces
+// the corresponding field of base. Pointer fields (e.g. HTTPClient,
+// ToolRetryConfig) win when non-nil, and map fields are merged key by key with
+// override taking precedence. Neither base nor override is modified.
+//
+// Because zero values mean "not set", a boolean can only be switched on by an
+// override, never off.
+func (base CoraConfig) Merge(override CoraConfig) CoraConfig {
+	out := base
+	mergeFields(reflect.ValueOf(&out).Elem(), reflect.ValueOf(override))
+	return out
+}
+
+// mergeFields copies non-zero exported fields of src into dst, merging maps.
+func mergeFields(dst, src reflect.Value) {
+	for i := 0; i < src.NumField(); i++ {
+		if !src.Type().Field(i).IsExported() {
+			continue
+		}
+		sf := src.Field(i)
+		if sf.IsZero() {
+			continue
+		}
+		df := dst.Field(i)
+		if sf.Kind() == reflect.Map && !df.IsNil() {
+			merged := reflect.MakeMapWithSize(sf.Type(), df.Len()+sf.Len())
+			for _, v := range []reflect.Value{df, sf} {
+				iter := v.MapRange()
+				for iter.Next() {
+					merged.SetMapIndex(iter.Key(), iter.Value())
+				}
+			}
+			df.Set(merged)
+			continue
+		}
+		df.Set(sf)
+	}
+}
@@ -0,0 +1,154 @@
+package cora
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileKeys maps the snake_case file keys to the CoraConfig fields that
+// can be stored in a config file. Clients, loggers, TLS and retry settings
+// hold Go values and must be set in code.
+var configFileKeys = func() map[string]reflect.StructField {
+	keys := map[string]reflect.StructField{}
+	t := reflect.TypeFor[CoraConfig]()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		switch f.Type.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64, reflect.Map:
+			keys[snakeCase(f.Name)] = f
+		}
+	}
+	return keys
+}()
+
+// configFileSecrets are the keys validated on load and left out on save.
+var configFileSecrets = []string{"openai_api_key", "google_api_key", "mistral_api_key", "cohere_api_key"}
+
+// snakeCase converts a Go field name such as "OpenAIAPIKey" to "openai_api_key".
+func snakeCase(name string) string {
+	name = strings.ReplaceAll(name, "OpenAI", "Openai")
+	rs := []rune(name)
+	var b strings.Builder
+	for i, r := range rs {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(rs[i-1]) || (i+1 < len(rs) && unicode.IsLower(rs[i+1]))) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// LoadConfigFromFile reads a CoraConfig from a YAML (.yaml, .yml) or JSON
+// (.json) file whose keys are the snake_case field names, e.g.
+// "openai_api_key" or "default_model_google". Durations are strings such as
+// "30s"; google_backend is "auto", "gemini" or "vertex". API keys, when
+// present, must be non-empty strings. Unknown keys are an error.
+func LoadConfigFromFile(path string) (CoraConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CoraConfig{}, fmt.Errorf("cora: reading config file: %w", err)
+	}
+
+	var raw map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	default:
+		return CoraConfig{}, fmt.Errorf("cora: unsupported config file extension %q", ext)
+	}
+	if err != nil {
+		return CoraConfig{}, fmt.Errorf("cora: parsing config file %s: %w", path, err)
+	}
+
+	byField := make(map[string]any, len(raw))
+	for key, v := range raw {
+		f, ok := configFileKeys[key]
+		if !ok {
+			return CoraConfig{}, fmt.Errorf("cora: unknown config key %q", key)
+		}
+		if slices.Contains(configFileSecrets, key) {
+			if s, ok := v.(string); !ok || strings.TrimSpace(s) == "" {
+				return CoraConfig{}, fmt.Errorf("cora: config key %q must be a non-empty string", key)
+			}
+		}
+		switch f.Type {
+		case reflect.TypeFor[time.Duration]():
+			if s, ok := v.(string); ok {
+				d, err := time.ParseDuration(s)
+				if err != nil {
+					return CoraConfig{}, fmt.Errorf("cora: config key %q: %w", key, err)
+				}
+				v = d
+			}
+		case reflect.TypeFor[GoogleBackend]():
+			if s, ok := v.(string); ok {
+				b, ok := parseGoogleBackend(s)
+				if !ok {
+					return CoraConfig{}, fmt.Errorf("cora: config key %q: unknown backend %q", key, s)
+				}
+				v = b
+			}
+		}
+		byField[f.Name] = v
+	}
+
+	// Decode through JSON so that numbers and maps convert to the field types.
+	b, err := json.Marshal(byField)
+	if err != nil {
+		return CoraConfig{}, fmt.Errorf("cora: parsing config file %s: %w", path, err)
+	}
+	var cfg CoraConfig
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return CoraConfig{}, fmt.Errorf("cora: parsing config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// SaveToFile writes the file-storable, non-zero fields of cfg to path as YAML
+// or JSON (by extension), in the format read by LoadConfigFromFile. API keys
+// are redacted: they are never written.
+func (cfg CoraConfig) SaveToFile(path string) error {
+	out := map[string]any{}
+	v := reflect.ValueOf(cfg)
+	for key, f := range configFileKeys {
+		fv := v.FieldByIndex(f.Index)
+		if fv.IsZero() || slices.Contains(configFileSecrets, key) {
+			continue
+		}
+		switch val := fv.Interface().(type) {
+		case time.Duration:
+			out[key] = val.String()
+		case GoogleBackend:
+			out[key] = val.String()
+		default:
+			out[key] = val
+		}
+	}
+
+	var data []byte
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(out)
+	case ".json":
+		data, err = json.MarshalIndent(out, "", "  ")
+	default:
+		return fmt.Errorf("cora: unsupported config file extension %q", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("cora: encoding config: %w", err)
+	}
+	return os.WriteFile(path, data, 0o600)
+}
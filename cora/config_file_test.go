@@ -0,0 +1,105 @@
+package cora
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigFile_RoundTrip(t *testing.T) {
+	cfg := CoraConfig{
+		OpenAIAPIKey:        "sk-secret",
+		OpenAIBaseURL:       "https://proxy.example.com/v1",
+		DefaultModelOpenAI:  "gpt-4o-mini",
+		DefaultModelGoogle:  "gemini-2.5-flash",
+		GoogleBackend:       GoogleBackendVertex,
+		GoogleProject:       "my-project",
+		GoogleLocation:      "us-central1",
+		Timeout:             30 * time.Second,
+		ToolCacheTTL:        time.Minute,
+		ToolCacheMaxSize:    100,
+		RequestDedup:        true,
+		DefaultLabels:       map[string]string{"env": "production"},
+		ModelContextWindows: map[string]int{"my-model": 32000},
+		ProviderWeights:     map[Provider]float64{ProviderOpenAI: 0.7, ProviderGoogle: 0.3},
+	}
+
+	for _, name := range []string{"cora.yaml", "cora.json"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := cfg.SaveToFile(path); err != nil {
+				t.Fatalf("SaveToFile error: %v", err)
+			}
+			data, _ := os.ReadFile(path)
+			if strings.Contains(string(data), "sk-secret") {
+				t.Errorf("API key was written to the file:\n%s", data)
+			}
+
+			got, err := LoadConfigFromFile(path)
+			if err != nil {
+				t.Fatalf("LoadConfigFromFile error: %v", err)
+			}
+			want := cfg
+			want.OpenAIAPIKey = ""
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFromFile_YAMLKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cora.yml")
+	yaml := "openai_api_key: sk-test\ngoogle_backend: gemini\ntimeout: 10s\nembed_batch_size: 50\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile error: %v", err)
+	}
+	if cfg.OpenAIAPIKey != "sk-test" || cfg.GoogleBackend != GoogleBackendGemini || cfg.Timeout != 10*time.Second || cfg.EmbedBatchSize != 50 {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+}
+
+func TestLoadConfigFromFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name, file, content, want string
+	}{
+		{"empty api key", "c.yaml", "google_api_key: \"\"\n", `"google_api_key" must be a non-empty string`},
+		{"non-string api key", "c.json", `{"openai_api_key": 42}`, `"openai_api_key" must be a non-empty string`},
+		{"unknown key", "c.yaml", "http_client: x\n", `unknown config key "http_client"`},
+		{"bad duration", "c.yaml", "timeout: soon\n", `"timeout"`},
+		{"bad extension", "c.toml", "", "unsupported config file extension"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadConfigFromFile(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"OpenAIAPIKey":          "openai_api_key",
+		"DefaultModelOpenAI":    "default_model_openai",
+		"GoogleAPIKey":          "google_api_key",
+		"ToolCacheTTL":          "tool_cache_ttl",
+		"TLSInsecureSkipVerify": "tls_insecure_skip_verify",
+		"EmbedBatchSize":        "embed_batch_size",
+	} {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package cora
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCoraConfig_Validate_Invalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		cfg  CoraConfig
+	}{
+		{"vertex without project", CoraConfig{GoogleBackend: GoogleBackendVertex, GoogleLocation: "us-central1"}},
+		{"vertex without location", CoraConfig{GoogleBackend: GoogleBackendVertex, GoogleProject: "my-project"}},
+		{"gemini without key", CoraConfig{GoogleBackend: GoogleBackendGemini}},
+		{"unknown google backend", CoraConfig{GoogleBackend: GoogleBackend(42)}},
+		{"openai provider without key", CoraConfig{Provider: ProviderOpenAI}},
+		{"remote base url without key", CoraConfig{OpenAIBaseURL: "https://api.x.ai/v1"}},
+		{"default openai model without key", CoraConfig{DefaultModelOpenAI: "gpt-4o"}},
+		{"unknown openai api type", CoraConfig{OpenAIAPIKey: "sk", OpenAIAPIType: "bogus"}},
+		{"azure without base url", CoraConfig{OpenAIAPIKey: "sk", OpenAIAPIType: "azure"}},
+		{"cache size without ttl", CoraConfig{ToolCacheMaxSize: 10}},
+		{"cache ttl without size", CoraConfig{ToolCacheTTL: time.Minute}},
+		{"negative cache ttl", CoraConfig{ToolCacheTTL: -time.Second}},
+		{"retry without attempts", CoraConfig{ToolRetryConfig: &RetryConfig{}}},
+		{"negative timeout", CoraConfig{Timeout: -time.Second}},
+		{"mistral without key", CoraConfig{Provider: ProviderMistral}},
+		{"negative provider weight", CoraConfig{ProviderWeights: map[Provider]float64{ProviderOpenAI: -1}}},
+		{"semantic cache without embed model", CoraConfig{ResponseCacheTTL: time.Minute, ResponseCacheMaxSize: 10, SemanticCacheThreshold: 0.9}},
+		{"semantic cache without response cache", CoraConfig{SemanticCacheThreshold: 0.9, SemanticCacheEmbedModel: "m"}},
+		{"unknown judge provider", CoraConfig{JudgeProvider: "bogus"}},
+		{"judge threshold above 1", CoraConfig{JudgePassThreshold: 1.5}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.cfg.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestCoraConfig_Validate_CollectsAllErrors(t *testing.T) {
+	cfg := CoraConfig{
+		GoogleBackend:    GoogleBackendVertex,
+		ToolCacheMaxSize: 10,
+	}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("expected joined error, got %T", err)
+	}
+	if n := len(joined.Unwrap()); n != 3 {
+		t.Errorf("expected 3 problems, got %d: %v", n, err)
+	}
+}
+
+func TestCoraConfig_Validate_Valid(t *testing.T) {
+	testCases := []struct {
+		name string
+		cfg  CoraConfig
+	}{
+		{"empty", CoraConfig{}},
+		{"openai", CoraConfig{Provider: ProviderOpenAI, OpenAIAPIKey: "sk-test"}},
+		{"openai from env", CoraConfig{Provider: ProviderOpenAI, DetectEnv: true}},
+		{"local openai-compatible server", CoraConfig{OpenAIBaseURL: "http://localhost:11434/v1"}},
+		{"gemini", CoraConfig{GoogleBackend: GoogleBackendGemini, GoogleAPIKey: "gsk-test"}},
+		{"vertex", CoraConfig{GoogleBackend: GoogleBackendVertex, GoogleProject: "p", GoogleLocation: "us-central1"}},
+		{"tool cache", CoraConfig{ToolCacheTTL: time.Minute, ToolCacheMaxSize: 10}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.cfg.Validate(); err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCoraConfig_Merge(t *testing.T) {
+	baseClient := &http.Client{}
+	overrideRetry := &RetryConfig{MaxAttempts: 5}
+
+	base := CoraConfig{
+		DefaultModelOpenAI: "gpt-4o",
+		DefaultModelGoogle: "gemini-2.5-flash",
+		OpenAIAPIKey:       "sk-base",
+		HTTPClient:         baseClient,
+		ToolRetryConfig:    &RetryConfig{MaxAttempts: 2},
+		Timeout:            10 * time.Second,
+	}
+	override := CoraConfig{
+		DefaultModelOpenAI: "gpt-4o-mini",
+		ToolRetryConfig:    overrideRetry,
+		DetectEnv:          true,
+	}
+
+	merged := base.Merge(override)
+
+	if merged.DefaultModelOpenAI != "gpt-4o-mini" {
+		t.Errorf("expected override model, got %q", merged.DefaultModelOpenAI)
+	}
+	if merged.DefaultModelGoogle != "gemini-2.5-flash" {
+		t.Errorf("expected base model to be kept, got %q", merged.DefaultModelGoogle)
+	}
+	if merged.OpenAIAPIKey != "sk-base" {
+		t.Errorf("expected base key to be kept, got %q", merged.OpenAIAPIKey)
+	}
+	if merged.HTTPClient != baseClient {
+		t.Error("expected nil override HTTPClient to keep base client")
+	}
+	if merged.ToolRetryConfig != overrideRetry {
+		t.Error("expected non-nil override ToolRetryConfig to win")
+	}
+	if merged.Timeout != 10*time.Second {
+		t.Errorf("expected base timeout to be kept, got %v", merged.Timeout)
+	}
+	if !merged.DetectEnv {
+		t.Error("expected override DetectEnv to be applied")
+	}
+	if base.DefaultModelOpenAI != "gpt-4o" || base.ToolRetryConfig.MaxAttempts != 2 {
+		t.Error("base config must not be modified")
+	}
+}
+
+func TestMergeFields_Maps(t *testing.T) {
+	type withMap struct {
+		Labels map[string]string
+	}
+	base := withMap{Labels: map[string]string{"env": "prod", "team": "core"}}
+	override := withMap{Labels: map[string]string{"env": "staging"}}
+
+	out := base
+	mergeFields(reflect.ValueOf(&out).Elem(), reflect.ValueOf(override))
+
+	if out.Labels["env"] != "staging" || out.Labels["team"] != "core" {
+		t.Errorf("unexpected merged labels: %v", out.Labels)
+	}
+	if base.Labels["env"] != "prod" {
+		t.Error("base map must not be modified")
+	}
+}
+
+func TestProviderHTTPClients(t *testing.T) {
+	cfg := CoraConfig{
+		OpenAIAPIKey:     "sk-test",
+		GoogleAPIKey:     "g-test",
+		MistralAPIKey:    "m-test",
+		HTTPClient:       &http.Client{Timeout: 5 * time.Second},
+		OpenAIHTTPClient: &http.Client{Timeout: 10 * time.Second},
+		GoogleHTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	oc := openAIClientConfig(cfg, nil)
+	if hc, ok := oc.HTTPClient.(*http.Client); !ok || hc.Timeout != 10*time.Second {
+		t.Errorf("expected OpenAI client with 10s timeout, got %+v", oc.HTTPClient)
+	}
+
+	gp, err := newGoogleProvider(cfg, nil)
+	if err != nil {
+		t.Fatalf("newGoogleProvider error: %v", err)
+	}
+	if got := gp.(*googleProvider).client.ClientConfig().HTTPClient.Timeout; got != 30*time.Second {
+		t.Errorf("expected Google client with 30s timeout, got %v", got)
+	}
+
+	// Providers without their own client fall back to HTTPClient.
+	mc := mistralClientConfig(cfg, nil)
+	if hc, ok := mc.HTTPClient.(*http.Client); !ok || hc.Timeout != 5*time.Second {
+		t.Errorf("expected Mistral client with shared 5s timeout, got %+v", mc.HTTPClient)
+	}
+	cfg.OpenAIHTTPClient = nil
+	if hc := openAIClientConfig(cfg, nil).HTTPClient.(*http.Client); hc.Timeout != 5*time.Second {
+		t.Errorf("expected OpenAI to fall back to HTTPClient, got %v", hc.Timeout)
+	}
+	if cfg.GoogleHTTPClient.Transport != nil {
+		t.Error("the configured client was modified")
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	tc := &tls.Config{RootCAs: roots}
+
+	hc := providerHTTPClient(CoraConfig{TLSConfig: tc}, nil, nil)
+	base := hc.Transport.(*correlationTransport).base.(*sizeLimitTransport).base.(*http.Transport)
+	if base.TLSClientConfig != tc {
+		t.Errorf("expected the transport to use TLSConfig, got %+v", base.TLSClientConfig)
+	}
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL, TLSConfig: tc})
+	resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil || resp.Text != "ok" {
+		t.Fatalf("expected request over custom CA to succeed, got %q, %v", resp.Text, err)
+	}
+
+	// Without the CA the server's certificate is rejected.
+	c = New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"}); err == nil {
+		t.Error("expected certificate verification error without TLSConfig")
+	}
+}
+
+func TestTLSInsecureSkipVerify(t *testing.T) {
+	var logs bytes.Buffer
+	cfg := CoraConfig{TLSInsecureSkipVerify: true, DebugLogger: slog.New(slog.NewTextHandler(&logs, nil))}
+	New(cfg)
+	if !strings.Contains(logs.String(), "level=WARN") {
+		t.Errorf("expected a warning to be logged, got %q", logs.String())
+	}
+
+	// Without a DebugLogger the warning goes to the default logger.
+	var defaultLogs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&defaultLogs, nil)))
+	New(CoraConfig{TLSInsecureSkipVerify: true})
+	if !strings.Contains(defaultLogs.String(), "level=WARN") {
+		t.Errorf("expected a warning on the default logger, got %q", defaultLogs.String())
+	}
+
+	base := providerHTTPClient(cfg, nil, nil).Transport.(*correlationTransport).base
+	// DebugLogger wraps the transport for request logging.
+	tr := base.(*debugTransport).base.(*sizeLimitTransport).base.(*http.Transport)
+	if tr.TLSClientConfig == nil || !tr.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("expected InsecureSkipVerify on the transport, got %+v", tr.TLSClientConfig)
+	}
+}
+
+func TestValidate_TLSWithHTTPClient(t *testing.T) {
+	cfg := CoraConfig{TLSConfig: &tls.Config{}, HTTPClient: &http.Client{}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "TLSConfig") {
+		t.Errorf("expected TLSConfig/HTTPClient conflict, got %v", err)
+	}
+}
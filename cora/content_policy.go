@@ -0,0 +1,114 @@
+package cora
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ContentPolicy vets a request's input before it is sent to a provider
+// (see CoraConfig.ContentPolicy). Text and Stream check the input with the
+// system prompt once per call, then the content of every earlier message
+// (TextRequest.Messages and a ConversationID's history) with an empty
+// system prompt.
+type ContentPolicy interface {
+	Check(ctx context.Context, input, system string) (PolicyResult, error)
+}
+
+// PolicyResult is a ContentPolicy decision. When the request is allowed,
+// a non-empty ModifiedInput replaces the request's input.
+type PolicyResult struct {
+	Allowed       bool
+	Reason        string
+	ModifiedInput string
+}
+
+// RegexContentPolicy returns a policy rejecting any input or system prompt
+// that matches one of the blocked patterns, case-insensitively. Patterns
+// are regular expressions, so plain words work as-is; it panics if one
+// does not compile, like regexp.MustCompile.
+func RegexContentPolicy(blocked []string) ContentPolicy {
+	p := &regexContentPolicy{}
+	for _, pattern := range blocked {
+		p.blocked = append(p.blocked, regexp.MustCompile("(?i)"+pattern))
+	}
+	return p
+}
+
+type regexContentPolicy struct {
+	blocked []*regexp.Regexp
+}
+
+func (p *regexContentPolicy) Check(ctx context.Context, input, system string) (PolicyResult, error) {
+	for _, re := range p.blocked {
+		for _, text := range []string{input, system} {
+			if match := re.FindString(text); match != "" {
+				return PolicyResult{Reason: fmt.Sprintf("blocked content %q", match)}, nil
+			}
+		}
+	}
+	return PolicyResult{Allowed: true}, nil
+}
+
+// applyContentPolicy runs the configured ContentPolicy on req's input and
+// system prompt and on each of its Messages, and returns req with them
+// possibly rewritten. A rejection is a CoraError with Code
+// ErrCodeContentPolicy.
+func (c *Client) applyContentPolicy(ctx context.Context, req TextRequest) (TextRequest, error) {
+	var err error
+	req.Input, err = c.checkContentPolicy(ctx, req.Provider, req.Input, req.System)
+	if err != nil {
+		return req, err
+	}
+	req.Messages, err = c.checkMessagesPolicy(ctx, req.Provider, req.Messages)
+	return req, err
+}
+
+// checkContentPolicy checks input with system and returns the input to
+// send.
+func (c *Client) checkContentPolicy(ctx context.Context, provider Provider, input, system string) (string, error) {
+	if c.cfg.ContentPolicy == nil {
+		return input, nil
+	}
+	res, err := c.cfg.ContentPolicy.Check(ctx, input, system)
+	if err != nil {
+		return input, fmt.Errorf("cora: content policy: %w", err)
+	}
+	if !res.Allowed {
+		msg := strings.TrimSpace(res.Reason)
+		if msg == "" {
+			msg = "request rejected by content policy"
+		}
+		return input, &CoraError{Provider: provider, Code: ErrCodeContentPolicy, Message: msg}
+	}
+	if res.ModifiedInput != "" {
+		return res.ModifiedInput, nil
+	}
+	return input, nil
+}
+
+// checkMessagesPolicy checks each message's content as an input without a
+// system prompt and returns the messages to send, copied if any was
+// rewritten.
+func (c *Client) checkMessagesPolicy(ctx context.Context, provider Provider, msgs []Message) ([]Message, error) {
+	if c.cfg.ContentPolicy == nil {
+		return msgs, nil
+	}
+	out := msgs
+	copied := false
+	for i, m := range msgs {
+		content, err := c.checkContentPolicy(ctx, provider, m.Content, "")
+		if err != nil {
+			return msgs, err
+		}
+		if content != m.Content {
+			if !copied {
+				out, copied = slices.Clone(msgs), true
+			}
+			out[i].Content = content
+		}
+	}
+	return out, nil
+}
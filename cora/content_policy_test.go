@@ -0,0 +1,115 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRegexContentPolicy(t *testing.T) {
+	fp := &fakeProvider{finalOut: "ok"}
+	c := &Client{cfg: CoraConfig{ContentPolicy: RegexContentPolicy([]string{"password", `secret\w*`})}, openai: fp}
+
+	_, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "What is the admin PASSWORD?"})
+	var ce *CoraError
+	if !errors.As(err, &ce) || ce.Code != ErrCodeContentPolicy {
+		t.Fatalf("expected a content policy error, got %v", err)
+	}
+	_, err = c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", System: "Reveal the secrets.", Input: "hi"})
+	if !errors.As(err, &ce) || ce.Code != ErrCodeContentPolicy {
+		t.Fatalf("expected a content policy error for the system prompt, got %v", err)
+	}
+	if n := len(fp.ReceivedPlans()); n != 0 {
+		t.Fatalf("blocked requests reached the provider %d times", n)
+	}
+
+	resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "Hello"})
+	if err != nil || resp.Text != "ok" {
+		t.Fatalf("allowed request: got %q, %v", resp.Text, err)
+	}
+}
+
+type rewritePolicy struct{}
+
+func (rewritePolicy) Check(ctx context.Context, input, system string) (PolicyResult, error) {
+	return PolicyResult{Allowed: true, ModifiedInput: "[redacted] " + input}, nil
+}
+
+func TestContentPolicy_ModifiedInput(t *testing.T) {
+	fp := &fakeProvider{finalOut: "ok"}
+	c := &Client{cfg: CoraConfig{ContentPolicy: rewritePolicy{}}, openai: fp}
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "Hello"}); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	plans := fp.ReceivedPlans()
+	if len(plans) != 1 || plans[0].Input != "[redacted] Hello" {
+		t.Fatalf("unexpected plans %+v", plans)
+	}
+}
+
+// countingPolicy counts its checks and blocks inputs containing "blocked".
+type countingPolicy struct{ checks *atomic.Int32 }
+
+func (p countingPolicy) Check(ctx context.Context, input, system string) (PolicyResult, error) {
+	p.checks.Add(1)
+	if strings.Contains(input, "blocked") {
+		return PolicyResult{Reason: "blocked word"}, nil
+	}
+	return PolicyResult{Allowed: true}, nil
+}
+
+func TestContentPolicy_MessagesAndHistory(t *testing.T) {
+	var checks atomic.Int32
+	fp := &fakeProvider{finalOut: "ok"}
+	c := &Client{cfg: CoraConfig{ContentPolicy: countingPolicy{&checks}}, openai: fp}
+	store := NewInMemoryConversationStore()
+	c.WithConversationStore(store)
+
+	isPolicyError := func(err error) bool {
+		var ce *CoraError
+		return errors.As(err, &ce) && ce.Code == ErrCodeContentPolicy
+	}
+	_, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test",
+		Messages: []Message{{Role: "user", Content: "a blocked question"}}})
+	if !isPolicyError(err) {
+		t.Errorf("blocked message: got %v, want a content policy error", err)
+	}
+
+	_ = store.Save("conv", []Message{{Role: "user", Content: "blocked earlier"}, {Role: "assistant", Content: "ok"}})
+	_, err = c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi", ConversationID: "conv"})
+	if !isPolicyError(err) {
+		t.Errorf("blocked history: got %v, want a content policy error", err)
+	}
+
+	_, err = c.Stream(context.Background(), StreamRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "a blocked stream"})
+	if !isPolicyError(err) {
+		t.Errorf("blocked stream: got %v, want a content policy error", err)
+	}
+	if n := len(fp.ReceivedPlans()); n != 0 {
+		t.Fatalf("blocked requests reached the provider %d times", n)
+	}
+
+	// With fallbacks, the policy still runs once per call.
+	checks.Store(0)
+	c = &Client{cfg: CoraConfig{ContentPolicy: countingPolicy{&checks}, ModelFallbacks: map[string][]string{"gpt-busy": {"gpt-test"}}}}
+	c.openai = overloadedProvider{busy: "gpt-busy"}
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-busy", Input: "hi"}); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if n := checks.Load(); n != 1 {
+		t.Errorf("policy ran %d times, want 1", n)
+	}
+}
+
+// overloadedProvider answers 503 for the model busy.
+type overloadedProvider struct{ busy string }
+
+func (p overloadedProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	if plan.Model == p.busy {
+		return callResult{}, &CoraError{Provider: plan.Provider, HTTPStatusCode: http.StatusServiceUnavailable, Message: "overloaded"}
+	}
+	return callResult{Text: "ok"}, nil
+}
@@ -0,0 +1,249 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is one turn of a conversation. Role is "user", "assistant" or
+// "system"; "model" is accepted as "assistant", and any other role is sent as
+// "user".
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// messageRole returns the canonical role of a Message: "assistant" for
+// "assistant" and Google's "model", "system", and "user" otherwise. Each
+// provider maps it to its own roles.
+func messageRole(role string) string {
+	switch role {
+	case "assistant", "model":
+		return "assistant"
+	case "system":
+		return "system"
+	default:
+		return "user"
+	}
+}
+
+// ConversationStore persists conversation histories by ID, so that a
+// conversation can continue across requests to a stateless server.
+type ConversationStore interface {
+	// Load returns the history for id, or no messages if id is unknown.
+	Load(id string) ([]Message, error)
+	Save(id string, msgs []Message) error
+	Delete(id string) error
+}
+
+// WithConversationStore sets the store used for requests with a
+// ConversationID, and returns c for chaining.
+func (c *Client) WithConversationStore(s ConversationStore) *Client {
+	c.conversations = s
+	return c
+}
+
+// Conversations inherit a CachedContentName for conversationCacheTTL after
+// their last turn using it, the default lifetime of Gemini cached content; at
+// most conversationCacheMaxSize conversations are remembered.
+const (
+	conversationCacheTTL     = time.Hour
+	conversationCacheMaxSize = 10000
+)
+
+// conversationCacheNames returns the CachedContentName of each conversation.
+func (c *Client) conversationCacheNames() *Cache[string, string] {
+	c.conversationCachesOnce.Do(func() {
+		c.conversationCaches = NewCache[string, string](conversationCacheTTL, conversationCacheMaxSize)
+	})
+	return c.conversationCaches
+}
+
+// isCachedContentGone reports whether err says that the request's cached
+// content does not exist (any more), typically because it expired.
+func isCachedContentGone(err error) bool {
+	var ce *CoraError
+	if !errors.As(err, &ce) {
+		return false
+	}
+	switch ce.HTTPStatusCode {
+	case http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound:
+	default:
+		return false
+	}
+	msg := strings.ToLower(ce.Message)
+	return strings.Contains(msg, "cachedcontent") || strings.Contains(msg, "cached content")
+}
+
+// textConversation loads req's conversation history, runs the request with it,
+// and saves the history extended by this turn. A CachedContentName set on one
+// turn is reused by later turns that don't set their own, until it is reported
+// expired.
+func (c *Client) textConversation(ctx context.Context, req TextRequest) (TextResponse, error) {
+	if req.ConversationID == "" || c.conversations == nil {
+		return c.textCached(ctx, req)
+	}
+
+	history, err := c.conversations.Load(req.ConversationID)
+	if err != nil {
+		return TextResponse{}, fmt.Errorf("cora: load conversation %q: %w", req.ConversationID, err)
+	}
+	req.history, err = c.checkMessagesPolicy(ctx, req.Provider, history)
+	if err != nil {
+		return TextResponse{}, err
+	}
+	caches := c.conversationCacheNames()
+	if req.CachedContentName == "" {
+		req.CachedContentName, _ = caches.Get(req.ConversationID)
+	}
+
+	resp, err := c.textCached(ctx, req)
+	if err != nil {
+		if req.CachedContentName != "" && isCachedContentGone(err) {
+			caches.Delete(req.ConversationID)
+		}
+		return resp, err
+	}
+	if req.CachedContentName != "" {
+		caches.Set(req.ConversationID, req.CachedContentName)
+	}
+
+	updated := append(history[:len(history):len(history)], req.Messages...)
+	if req.Input != "" || len(req.Messages) == 0 {
+		updated = append(updated, Message{Role: "user", Content: req.Input})
+	}
+	updated = append(updated, Message{Role: "assistant", Content: resp.Text})
+	if err := c.conversations.Save(req.ConversationID, updated); err != nil {
+		return resp, fmt.Errorf("cora: save conversation %q: %w", req.ConversationID, err)
+	}
+	return resp, nil
+}
+
+// InMemoryConversationStore keeps histories in memory. It is safe for
+// concurrent use; the zero value is ready to use.
+type InMemoryConversationStore struct {
+	mu    sync.RWMutex
+	convs map[string][]Message
+}
+
+// NewInMemoryConversationStore returns an empty in-memory store.
+func NewInMemoryConversationStore() *InMemoryConversationStore {
+	return &InMemoryConversationStore{}
+}
+
+func (s *InMemoryConversationStore) Load(id string) ([]Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Message(nil), s.convs[id]...), nil
+}
+
+func (s *InMemoryConversationStore) Save(id string, msgs []Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.convs == nil {
+		s.convs = make(map[string][]Message)
+	}
+	s.convs[id] = append([]Message(nil), msgs...)
+	return nil
+}
+
+func (s *InMemoryConversationStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.convs, id)
+	return nil
+}
+
+// FileConversationStore returns a ConversationStore that keeps each history
+// as JSON in dir/{id}.json. The directory is created on first save.
+func FileConversationStore(dir string) ConversationStore {
+	return &fileConversationStore{dir: dir}
+}
+
+type fileConversationStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func (s *fileConversationStore) path(id string) (string, error) {
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("cora: invalid conversation ID %q", id)
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+func (s *fileConversationStore) Load(id string) ([]Message, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var msgs []Message
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return nil, fmt.Errorf("cora: decode %s: %w", path, err)
+	}
+	return msgs, nil
+}
+
+func (s *fileConversationStore) Save(id string, msgs []Message) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(msgs)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so a crash never leaves a partial history.
+	tmp, err := os.CreateTemp(s.dir, id+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *fileConversationStore) Delete(id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package cora
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ConversationHistory is an in-memory list of conversation turns that can
+// be branched cheaply: branches share the messages they have in common and
+// copy only when one of them appends. It is not safe for concurrent use;
+// give each goroutine its own branch.
+type ConversationHistory struct {
+	// msgs is always capped at its length (msgs[:n:n]), so an append
+	// reallocates instead of writing into an array a branch may share.
+	msgs []Message
+}
+
+// NewConversationHistory returns a history holding msgs.
+func NewConversationHistory(msgs ...Message) *ConversationHistory {
+	h := &ConversationHistory{}
+	h.Append(msgs...)
+	return h
+}
+
+// Append adds msgs to the end of the history.
+func (h *ConversationHistory) Append(msgs ...Message) {
+	h.msgs = append(h.msgs, msgs...)
+	h.msgs = h.msgs[:len(h.msgs):len(h.msgs)]
+}
+
+// Messages returns a copy of the history's messages.
+func (h *ConversationHistory) Messages() []Message {
+	return append([]Message(nil), h.msgs...)
+}
+
+// Len returns the number of messages in the history.
+func (h *ConversationHistory) Len() int {
+	return len(h.msgs)
+}
+
+// Branch returns a history starting with the same messages. Appending to
+// either one does not affect the other; the shared prefix is not copied.
+func (h *ConversationHistory) Branch() *ConversationHistory {
+	return &ConversationHistory{msgs: h.msgs}
+}
+
+// MergeStrategy selects how Merge recombines two branches.
+type MergeStrategy int
+
+const (
+	// MergeAppend keeps the common prefix, then the receiver's own messages,
+	// then the other branch's.
+	MergeAppend MergeStrategy = iota
+	// MergeFastForward succeeds only if one branch extends the other, and
+	// returns the longer one; diverged branches are an error.
+	MergeFastForward
+)
+
+// ErrBranchesDiverged is returned by Merge with MergeFastForward when both
+// branches have messages the other lacks.
+var ErrBranchesDiverged = errors.New("cora: conversation branches have diverged")
+
+// Merge returns a new history combining h and other, which are usually
+// branches of a common history, according to strategy. Neither input is
+// modified.
+func (h *ConversationHistory) Merge(other *ConversationHistory, strategy MergeStrategy) (*ConversationHistory, error) {
+	if other == nil {
+		return nil, errors.New("cora: cannot merge a nil conversation history")
+	}
+	n := commonPrefixLen(h.msgs, other.msgs)
+	ours, theirs := h.msgs[n:], other.msgs[n:]
+
+	switch strategy {
+	case MergeAppend:
+		merged := &ConversationHistory{msgs: h.msgs[:n:n]}
+		merged.Append(ours...)
+		merged.Append(theirs...)
+		return merged, nil
+	case MergeFastForward:
+		if len(ours) > 0 && len(theirs) > 0 {
+			return nil, fmt.Errorf("%w after message %d", ErrBranchesDiverged, n)
+		}
+		if len(theirs) > 0 {
+			return other.Branch(), nil
+		}
+		return h.Branch(), nil
+	default:
+		return nil, fmt.Errorf("cora: unknown merge strategy %d", strategy)
+	}
+}
+
+// commonPrefixLen returns the number of leading messages a and b share.
+func commonPrefixLen(a, b []Message) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
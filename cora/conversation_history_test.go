@@ -0,0 +1,62 @@
+package cora
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConversationHistory_Branch(t *testing.T) {
+	root := NewConversationHistory(
+		Message{Role: "system", Content: "Be brief."},
+		Message{Role: "user", Content: "Name a color."},
+	)
+	a := root.Branch()
+	b := root.Branch()
+	a.Append(Message{Role: "assistant", Content: "Red."})
+	b.Append(Message{Role: "assistant", Content: "Blue."}, Message{Role: "user", Content: "Another?"})
+
+	if root.Len() != 2 {
+		t.Errorf("root changed: %v", root.Messages())
+	}
+	if got := a.Messages(); len(got) != 3 || got[2].Content != "Red." {
+		t.Errorf("branch a = %v", got)
+	}
+	if got := b.Messages(); len(got) != 4 || got[2].Content != "Blue." {
+		t.Errorf("branch b = %v", got)
+	}
+
+	// Appending to the original after branching leaves the branches alone.
+	root.Append(Message{Role: "assistant", Content: "Green."})
+	if a.Messages()[2].Content != "Red." || b.Messages()[2].Content != "Blue." {
+		t.Errorf("appending to root changed a branch: %v, %v", a.Messages(), b.Messages())
+	}
+}
+
+func TestConversationHistory_Merge(t *testing.T) {
+	root := NewConversationHistory(Message{Role: "user", Content: "hi"})
+	a := root.Branch()
+	a.Append(Message{Role: "assistant", Content: "A"})
+	b := root.Branch()
+	b.Append(Message{Role: "assistant", Content: "B"})
+
+	merged, err := a.Merge(b, MergeAppend)
+	if err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	want := []Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "A"}, {Role: "assistant", Content: "B"}}
+	if !reflect.DeepEqual(merged.Messages(), want) {
+		t.Errorf("MergeAppend = %v, want %v", merged.Messages(), want)
+	}
+
+	if _, err := a.Merge(b, MergeFastForward); !errors.Is(err, ErrBranchesDiverged) {
+		t.Errorf("expected ErrBranchesDiverged, got %v", err)
+	}
+	ff, err := root.Merge(a, MergeFastForward)
+	if err != nil || !reflect.DeepEqual(ff.Messages(), a.Messages()) {
+		t.Errorf("MergeFastForward = %v, %v; want %v", ff.Messages(), err, a.Messages())
+	}
+	if a.Len() != 2 || b.Len() != 2 {
+		t.Error("Merge modified its inputs")
+	}
+}
@@ -0,0 +1,94 @@
+package cora
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+type historyProvider struct {
+	plans []callPlan
+}
+
+func (h *historyProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	h.plans = append(h.plans, plan)
+	return callResult{Text: "re: " + plan.Input}, nil
+}
+
+func TestConversationStore_RoundTrip(t *testing.T) {
+	stores := map[string]ConversationStore{
+		"memory": NewInMemoryConversationStore(),
+		"file":   FileConversationStore(filepath.Join(t.TempDir(), "convs")),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			fake := &historyProvider{}
+			c := (&Client{cfg: CoraConfig{}}).WithConversationStore(store)
+			c.openai = fake
+
+			for _, input := range []string{"hello", "how are you?"} {
+				_, err := c.Text(context.Background(), TextRequest{
+					Provider: ProviderOpenAI, Model: "gpt-test", Input: input, ConversationID: "conv-1",
+				})
+				if err != nil {
+					t.Fatalf("Text error: %v", err)
+				}
+			}
+
+			first := []Message{{Role: "user", Content: "hello"}, {Role: "assistant", Content: "re: hello"}}
+			if len(fake.plans[0].Messages) != 0 {
+				t.Errorf("first turn should have no history, got %v", fake.plans[0].Messages)
+			}
+			if !reflect.DeepEqual(fake.plans[1].Messages, first) {
+				t.Errorf("second turn history = %v, want %v", fake.plans[1].Messages, first)
+			}
+
+			got, err := store.Load("conv-1")
+			if err != nil {
+				t.Fatalf("Load error: %v", err)
+			}
+			want := append(first, Message{Role: "user", Content: "how are you?"}, Message{Role: "assistant", Content: "re: how are you?"})
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("stored history = %v, want %v", got, want)
+			}
+
+			if err := store.Delete("conv-1"); err != nil {
+				t.Fatalf("Delete error: %v", err)
+			}
+			if got, err := store.Load("conv-1"); err != nil || len(got) != 0 {
+				t.Errorf("expected empty history after Delete, got %v, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestFileConversationStore_Layout(t *testing.T) {
+	dir := t.TempDir()
+	store := FileConversationStore(dir)
+	if err := store.Save("abc", []Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "abc.json")); err != nil {
+		t.Errorf("expected abc.json: %v", err)
+	}
+	if err := store.Save("../escape", nil); err == nil {
+		t.Error("expected an error for a conversation ID containing a path separator")
+	}
+}
+
+func TestGoogleContents_History(t *testing.T) {
+	contents := googleContents([]Message{
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "hello"},
+	}, "next", nil, nil)
+	if len(contents) != 3 {
+		t.Fatalf("expected 3 contents, got %d", len(contents))
+	}
+	if contents[1].Role != genai.RoleModel || contents[2].Role != genai.RoleUser {
+		t.Errorf("unexpected roles %q, %q", contents[1].Role, contents[2].Role)
+	}
+}
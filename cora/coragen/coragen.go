@@ -0,0 +1,380 @@
+// Package coragen generates cora tools from annotated Go interfaces. It backs
+// the coragen command, usually run through go generate:
+//
+//	//go:generate go run github.com/oraraka-deko/cora/cmd/coragen $GOFILE
+//
+//	type WeatherService interface {
+//		//cora:tool description:"Get the weather" param:location:"City name"
+//		GetWeather(ctx context.Context, location, unit string) (Weather, error)
+//	}
+//
+// Every method annotated with //cora:tool becomes a tool named after the
+// method in snake_case (get_weather), or after the annotation's name:"...",
+// and described by description:"..." or else by the method's doc comment.
+// The annotation may also sit on an interface with a single method. A method
+// takes a context.Context followed by named parameters and returns either
+// (T, error) or error; pointer parameters are optional.
+//
+// For each interface, the generated file has an AddXxxTools function that
+// registers the tools with a cora.ToolBuilder, e.g.
+// AddWeatherServiceTools(tb, svc). Their handlers call svc's methods, and
+// their schemas are derived from the parameters as by ToolBuilder.AddFunc.
+package coragen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// annotationPrefix starts a tool annotation comment.
+const annotationPrefix = "//cora:tool"
+
+// tool is an annotated interface method.
+type tool struct {
+	name        string
+	description string
+	method      string
+	params      []param
+	// hasResult is false for methods returning only an error.
+	hasResult bool
+}
+
+// param is a method parameter after the context.
+type param struct {
+	name        string
+	typ         string
+	description string
+	variadic    bool
+	optional    bool
+}
+
+// service is an interface with annotated methods.
+type service struct {
+	name  string
+	tools []tool
+}
+
+// Generate returns the Go source of the tools for the annotated interfaces
+// in src, the contents of filename.
+func Generate(filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("coragen: %w", err)
+	}
+
+	var services []service
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			it, ok := ts.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			doc := ts.Doc
+			if doc == nil && len(gd.Specs) == 1 {
+				doc = gd.Doc
+			}
+			svc, err := parseService(fset, ts.Name.Name, doc, it)
+			if err != nil {
+				return nil, err
+			}
+			if len(svc.tools) > 0 {
+				services = append(services, svc)
+			}
+		}
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("coragen: no %s annotations in %s", annotationPrefix, filename)
+	}
+
+	out := render(filepath.Base(filename), file, services)
+	formatted, err := format.Source(out)
+	if err != nil {
+		return nil, fmt.Errorf("coragen: formatting generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// parseService collects the annotated methods of the interface name.
+func parseService(fset *token.FileSet, name string, doc *ast.CommentGroup, it *ast.InterfaceType) (service, error) {
+	svc := service{name: name}
+	var methods []*ast.Field
+	for _, f := range it.Methods.List {
+		if _, ok := f.Type.(*ast.FuncType); ok && len(f.Names) == 1 {
+			methods = append(methods, f)
+		}
+	}
+
+	ifaceAnnotation, hasIfaceAnnotation := annotation(doc)
+	ifaceDoc := doc
+	if hasIfaceAnnotation && len(methods) != 1 {
+		return svc, fmt.Errorf("coragen: %s: annotate the methods of %s, which has %d", fset.Position(it.Pos()), name, len(methods))
+	}
+	for _, m := range methods {
+		ann, ok := annotation(m.Doc)
+		methodDoc := m.Doc
+		if !ok && hasIfaceAnnotation {
+			ann, ok, methodDoc = ifaceAnnotation, true, ifaceDoc
+		}
+		if !ok {
+			continue
+		}
+		t, err := parseTool(m, methodDoc, ann)
+		if err != nil {
+			return svc, fmt.Errorf("coragen: %s: %s.%s: %w", fset.Position(m.Pos()), name, m.Names[0].Name, err)
+		}
+		svc.tools = append(svc.tools, t)
+	}
+	return svc, nil
+}
+
+// annotation returns the text after //cora:tool in doc.
+func annotation(doc *ast.CommentGroup) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+	for _, c := range doc.List {
+		if rest, ok := strings.CutPrefix(c.Text, annotationPrefix); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			return rest, true
+		}
+	}
+	return "", false
+}
+
+// annotationField matches key:"value" and param:name:"value".
+var annotationField = regexp.MustCompile(`^(\w+)(?::(\w+))?:("(?:[^"\\]|\\.)*")`)
+
+// parseTool builds the tool of method m from its annotation. The
+// description defaults to the rest of doc.
+func parseTool(m *ast.Field, doc *ast.CommentGroup, ann string) (tool, error) {
+	t := tool{
+		method:      m.Names[0].Name,
+		name:        snakeCase(m.Names[0].Name),
+		description: strings.Join(strings.Fields(doc.Text()), " "),
+	}
+	paramDocs := map[string]string{}
+	for rest := strings.TrimSpace(ann); rest != ""; rest = strings.TrimSpace(rest) {
+		match := annotationField.FindStringSubmatch(rest)
+		if match == nil {
+			return t, fmt.Errorf("malformed annotation at %q", rest)
+		}
+		rest = rest[len(match[0]):]
+		value, err := strconv.Unquote(match[3])
+		if err != nil {
+			return t, fmt.Errorf("malformed annotation value %s", match[3])
+		}
+		switch {
+		case match[1] == "param" && match[2] != "":
+			paramDocs[match[2]] = value
+		case match[1] == "description" && match[2] == "":
+			t.description = value
+		case match[1] == "name" && match[2] == "":
+			t.name = value
+		default:
+			return t, fmt.Errorf("unknown annotation key %q", strings.TrimSuffix(match[0], ":"+match[3]))
+		}
+	}
+
+	ft := m.Type.(*ast.FuncType)
+	fields := ft.Params.List
+	if len(fields) == 0 || exprString(fields[0].Type) != "context.Context" {
+		return t, errors.New("the first parameter must be a context.Context")
+	}
+	if len(fields[0].Names) > 1 {
+		return t, errors.New("only the first parameter may be a context.Context")
+	}
+	for _, f := range fields[1:] {
+		if len(f.Names) == 0 {
+			return t, errors.New("parameters must be named")
+		}
+		for _, n := range f.Names {
+			p := param{name: n.Name, description: paramDocs[n.Name]}
+			delete(paramDocs, n.Name)
+			typ := f.Type
+			if ell, ok := typ.(*ast.Ellipsis); ok {
+				p.variadic = true
+				typ = &ast.ArrayType{Elt: ell.Elt}
+			}
+			_, p.optional = typ.(*ast.StarExpr)
+			p.typ = exprString(typ)
+			t.params = append(t.params, p)
+		}
+	}
+	for name := range paramDocs {
+		return t, fmt.Errorf("annotation describes unknown parameter %q", name)
+	}
+
+	var results []ast.Expr
+	if ft.Results != nil {
+		for _, f := range ft.Results.List {
+			for range max(len(f.Names), 1) {
+				results = append(results, f.Type)
+			}
+		}
+	}
+	switch {
+	case len(results) == 1 && exprString(results[0]) == "error":
+	case len(results) == 2 && exprString(results[1]) == "error":
+		t.hasResult = true
+	default:
+		return t, errors.New("the method must return (T, error) or error")
+	}
+	return t, nil
+}
+
+// render writes the unformatted source of the generated file.
+func render(source string, file *ast.File, services []service) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by coragen from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", file.Name.Name)
+
+	std, other := []string{`"context"`}, []string{`"github.com/oraraka-deko/cora/cora"`}
+	for _, svc := range services {
+		for _, t := range svc.tools {
+			for _, p := range t.params {
+				for _, imp := range importsFor(file, p.typ) {
+					if strings.Contains(strings.Split(imp, "/")[0], ".") {
+						other = append(other, imp)
+					} else {
+						std = append(std, imp)
+					}
+				}
+			}
+		}
+	}
+	b.WriteString("import (\n")
+	for i, group := range [][]string{std, other} {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		slices.SortFunc(group, func(a, b string) int { return strings.Compare(importPath(a), importPath(b)) })
+		for _, imp := range slices.Compact(group) {
+			b.WriteString(imp + "\n")
+		}
+	}
+	b.WriteString(")\n")
+
+	for _, svc := range services {
+		for _, t := range svc.tools {
+			fmt.Fprintf(&b, "\n// %s are the arguments of the %s tool.\n", argsType(svc, t), t.name)
+			fmt.Fprintf(&b, "type %s struct {\n", argsType(svc, t))
+			for _, p := range t.params {
+				tag := `json:"` + p.name
+				if p.optional {
+					tag += ",omitempty"
+				}
+				tag += `"`
+				if p.description != "" {
+					tag += ` description:` + strconv.Quote(p.description)
+				}
+				fmt.Fprintf(&b, "%s %s `%s`\n", fieldName(p.name), p.typ, tag)
+			}
+			b.WriteString("}\n")
+		}
+
+		fmt.Fprintf(&b, "\n// Add%sTools adds the annotated methods of %s to tb\n", svc.name, svc.name)
+		b.WriteString("// as tools whose handlers call svc.\n")
+		fmt.Fprintf(&b, "func Add%sTools(tb *cora.ToolBuilder, svc %s) error {\n", svc.name, svc.name)
+		for _, t := range svc.tools {
+			fmt.Fprintf(&b, "if err := tb.AddFunc(%q, %q, func(ctx context.Context, in %s) (any, error) {\n", t.name, t.description, argsType(svc, t))
+			call := callExpr(t)
+			if t.hasResult {
+				fmt.Fprintf(&b, "return %s\n", call)
+			} else {
+				fmt.Fprintf(&b, "return nil, %s\n", call)
+			}
+			b.WriteString("}); err != nil {\nreturn err\n}\n")
+		}
+		b.WriteString("return nil\n}\n")
+	}
+	return b.Bytes()
+}
+
+// callExpr is the call of t's method with the decoded arguments.
+func callExpr(t tool) string {
+	args := []string{"ctx"}
+	for _, p := range t.params {
+		arg := "in." + fieldName(p.name)
+		if p.variadic {
+			arg += "..."
+		}
+		args = append(args, arg)
+	}
+	return "svc." + t.method + "(" + strings.Join(args, ", ") + ")"
+}
+
+// argsType names the arguments struct of t.
+func argsType(svc service, t tool) string {
+	r := []rune(svc.name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r) + t.method + "Args"
+}
+
+// fieldName exports a parameter name.
+func fieldName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// snakeCase converts a method name such as GetHTTPStatus to get_http_status.
+func snakeCase(name string) string {
+	r := []rune(name)
+	var b strings.Builder
+	for i, c := range r {
+		if unicode.IsUpper(c) && i > 0 && (unicode.IsLower(r[i-1]) || i+1 < len(r) && unicode.IsLower(r[i+1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(c))
+	}
+	return b.String()
+}
+
+// importsFor returns the import specs of file used by the type typ.
+func importsFor(file *ast.File, typ string) []string {
+	var out []string
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := filepath.Base(path)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if !regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\.`).MatchString(typ) {
+			continue
+		}
+		if imp.Name != nil {
+			out = append(out, imp.Name.Name+" "+imp.Path.Value)
+		} else {
+			out = append(out, imp.Path.Value)
+		}
+	}
+	return out
+}
+
+// importPath returns the quoted path of an import spec.
+func importPath(spec string) string {
+	return spec[strings.IndexByte(spec, '"'):]
+}
+
+// exprString renders a type expression as Go source.
+func exprString(e ast.Expr) string {
+	var b bytes.Buffer
+	_ = format.Node(&b, token.NewFileSet(), e)
+	return b.String()
+}
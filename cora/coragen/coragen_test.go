@@ -0,0 +1,105 @@
+package coragen
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_Example(t *testing.T) {
+	src, err := os.ReadFile("example/weather.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("example/weather_tools.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Generate("example/weather.go", src)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("generated code differs from example/weather_tools.go; run go generate ./cora/coragen/example\n%s", got)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	src := `package svc
+
+import (
+	"context"
+	"time"
+)
+
+//cora:tool
+// Searcher searches documents.
+type Searcher interface {
+	Search(ctx context.Context, query string, since time.Time, tags ...string) ([]string, error)
+}
+
+type Store interface {
+	//cora:tool name:"put_doc" description:"Store a document"
+	Put(ctx context.Context, id string, body []byte) error
+	// Get is not a tool.
+	Get(ctx context.Context, id string) ([]byte, error)
+}
+`
+	got, err := Generate("svc.go", []byte(src))
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for _, want := range []string{
+		"import (\n\t\"context\"\n\t\"time\"\n\n\t\"github.com/oraraka-deko/cora/cora\"\n)",
+		"Since time.Time `json:\"since\"`",
+		"Tags  []string  `json:\"tags\"`",
+		`tb.AddFunc("search", "Searcher searches documents."`,
+		"return svc.Search(ctx, in.Query, in.Since, in.Tags...)",
+		`tb.AddFunc("put_doc", "Store a document"`,
+		"return nil, svc.Put(ctx, in.Id, in.Body)",
+		"func AddStoreTools(tb *cora.ToolBuilder, svc Store) error",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("generated code lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(string(got), "svc.Get(") {
+		t.Errorf("unannotated method Get became a tool:\n%s", got)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name, method, wantErr string
+	}{
+		{"no context", "//cora:tool\n\tF(id string) error", "context.Context"},
+		{"unnamed", "//cora:tool\n\tF(context.Context, string) error", "must be named"},
+		{"results", "//cora:tool\n\tF(ctx context.Context) (int, int)", "must return"},
+		{"unknown key", "//cora:tool title:\"x\"\n\tF(ctx context.Context) error", "unknown annotation key"},
+		{"unknown param", "//cora:tool param:nope:\"x\"\n\tF(ctx context.Context) error", "unknown parameter"},
+		{"malformed", "//cora:tool description:x\n\tF(ctx context.Context) error", "malformed annotation"},
+		{"no annotations", "F(ctx context.Context) error", "no //cora:tool annotations"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "package p\n\nimport \"context\"\n\ntype S interface {\n\t" + tt.method + "\n}\n"
+			_, err := Generate("p.go", []byte(src))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Generate error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"GetWeather":    "get_weather",
+		"GetHTTPStatus": "get_http_status",
+		"ID":            "id",
+		"Run":           "run",
+	} {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
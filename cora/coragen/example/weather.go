@@ -0,0 +1,27 @@
+// Package example shows a service whose tools are generated by coragen.
+package example
+
+import "context"
+
+//go:generate go run github.com/oraraka-deko/cora/cmd/coragen $GOFILE
+
+// Weather is a weather report.
+type Weather struct {
+	Location    string  `json:"location"`
+	Temperature float64 `json:"temperature"`
+	Unit        string  `json:"unit"`
+}
+
+// WeatherService looks up the weather.
+type WeatherService interface {
+	// GetWeather returns the current weather in location.
+	//
+	//cora:tool description:"Get the current weather in a city" param:location:"City name" param:unit:"celsius or fahrenheit"
+	GetWeather(ctx context.Context, location, unit string) (Weather, error)
+
+	// SetAlert alerts when the temperature in location exceeds threshold.
+	// Without a threshold it uses the service's default.
+	//
+	//cora:tool description:"Set a temperature alert for a city" param:location:"City name" param:threshold:"Temperature that triggers the alert"
+	SetAlert(ctx context.Context, location string, threshold *float64) error
+}
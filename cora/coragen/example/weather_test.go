@@ -0,0 +1,69 @@
+package example
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/oraraka-deko/cora/cora"
+)
+
+type fakeWeather struct {
+	alerts []float64
+}
+
+func (f *fakeWeather) GetWeather(ctx context.Context, location, unit string) (Weather, error) {
+	return Weather{Location: location, Temperature: 21.5, Unit: unit}, nil
+}
+
+func (f *fakeWeather) SetAlert(ctx context.Context, location string, threshold *float64) error {
+	if threshold != nil {
+		f.alerts = append(f.alerts, *threshold)
+	}
+	return nil
+}
+
+func TestAddWeatherServiceTools(t *testing.T) {
+	svc := &fakeWeather{}
+	tb := cora.NewToolBuilder()
+	if err := AddWeatherServiceTools(tb, svc); err != nil {
+		t.Fatal(err)
+	}
+	tools, handlers := tb.Build()
+	if len(tools) != 2 {
+		t.Fatalf("got %d tools, want 2", len(tools))
+	}
+
+	get := tools[0]
+	if get.Name != "get_weather" || get.Description != "Get the current weather in a city" {
+		t.Errorf("tool = %q: %q", get.Name, get.Description)
+	}
+	want := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"location": map[string]any{"type": "string", "description": "City name"},
+			"unit":     map[string]any{"type": "string", "description": "celsius or fahrenheit"},
+		},
+		"required": []string{"location", "unit"},
+	}
+	if !reflect.DeepEqual(get.ParametersSchema, want) {
+		t.Errorf("get_weather schema = %v, want %v", get.ParametersSchema, want)
+	}
+	if req := tools[1].ParametersSchema["required"]; !reflect.DeepEqual(req, []string{"location"}) {
+		t.Errorf("set_alert required = %v, want [location]", req)
+	}
+
+	got, err := handlers["get_weather"](context.Background(), map[string]any{"location": "Oslo", "unit": "celsius"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := got.(Weather); w.Location != "Oslo" || w.Unit != "celsius" {
+		t.Errorf("get_weather = %+v", w)
+	}
+	if _, err := handlers["set_alert"](context.Background(), map[string]any{"location": "Oslo", "threshold": 30.0}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(svc.alerts, []float64{30}) {
+		t.Errorf("alerts = %v", svc.alerts)
+	}
+}
@@ -0,0 +1,37 @@
+// Code generated by coragen from weather.go. DO NOT EDIT.
+
+package example
+
+import (
+	"context"
+
+	"github.com/oraraka-deko/cora/cora"
+)
+
+// weatherServiceGetWeatherArgs are the arguments of the get_weather tool.
+type weatherServiceGetWeatherArgs struct {
+	Location string `json:"location" description:"City name"`
+	Unit     string `json:"unit" description:"celsius or fahrenheit"`
+}
+
+// weatherServiceSetAlertArgs are the arguments of the set_alert tool.
+type weatherServiceSetAlertArgs struct {
+	Location  string   `json:"location" description:"City name"`
+	Threshold *float64 `json:"threshold,omitempty" description:"Temperature that triggers the alert"`
+}
+
+// AddWeatherServiceTools adds the annotated methods of WeatherService to tb
+// as tools whose handlers call svc.
+func AddWeatherServiceTools(tb *cora.ToolBuilder, svc WeatherService) error {
+	if err := tb.AddFunc("get_weather", "Get the current weather in a city", func(ctx context.Context, in weatherServiceGetWeatherArgs) (any, error) {
+		return svc.GetWeather(ctx, in.Location, in.Unit)
+	}); err != nil {
+		return err
+	}
+	if err := tb.AddFunc("set_alert", "Set a temperature alert for a city", func(ctx context.Context, in weatherServiceSetAlertArgs) (any, error) {
+		return nil, svc.SetAlert(ctx, in.Location, in.Threshold)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package cora
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// CorrelationIDHeader is the HTTP header carrying the correlation ID.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context carrying id. Calls made with it send
+// the ID as the X-Correlation-ID header and report it in TextResponse and
+// AuditEntry.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the ID set by WithCorrelationID, or "".
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// LabelsHeader is the HTTP header carrying TextRequest.Labels, as a JSON
+// object, on requests to OpenAI-compatible servers.
+const LabelsHeader = "X-Cora-Labels"
+
+type labelsKey struct{}
+
+// withLabelsHeader returns a context whose provider requests carry labels in
+// the X-Cora-Labels header.
+func withLabelsHeader(ctx context.Context, labels map[string]string) context.Context {
+	if len(labels) == 0 {
+		return ctx
+	}
+	b, err := json.Marshal(labels)
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, labelsKey{}, string(b))
+}
+
+// correlationTransport adds the context's correlation ID and labels header
+// to outgoing requests.
+type correlationTransport struct {
+	base http.RoundTripper
+}
+
+func (t *correlationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := CorrelationIDFromContext(req.Context())
+	labels, _ := req.Context().Value(labelsKey{}).(string)
+	if id != "" || labels != "" {
+		req = req.Clone(req.Context())
+	}
+	if id != "" {
+		req.Header.Set(CorrelationIDHeader, id)
+	}
+	if labels != "" {
+		req.Header.Set(LabelsHeader, labels)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// providerHTTPClient returns the HTTP client used by provider SDKs: a copy of
+// client (or of cfg.HTTPClient when client is nil, or a default client) whose
+// transport is wrapped with cora's own round trippers. A client without a
+// transport uses transport, the one its Client built, or else a new one.
+func providerHTTPClient(cfg CoraConfig, transport http.RoundTripper, client *http.Client) *http.Client {
+	hc := &http.Client{}
+	if base := cmp.Or(client, cfg.HTTPClient); base != nil {
+		copied := *base
+		hc = &copied
+	}
+	base := hc.Transport
+	if base == nil {
+		base = transport
+		if base == nil {
+			base = newProviderTransport(cfg)
+		}
+	}
+	if cfg.RequestSigner != nil {
+		base = &signingTransport{base: base, signer: cfg.RequestSigner}
+	}
+	base = &sizeLimitTransport{base: base, limit: cfg.MaxRequestBodyBytes}
+	if cfg.CompressRequests {
+		base = &compressingTransport{base: base}
+	}
+	if cfg.DebugLogger != nil {
+		base = &debugTransport{base: base, logger: cfg.DebugLogger, secrets: configSecrets(cfg)}
+	}
+	if cfg.HTTPRetryConfig != nil {
+		base = &retryTransport{base: base, config: *cfg.HTTPRetryConfig, respectRetryAfter: cfg.RespectRetryAfter, logger: cfg.DebugLogger}
+	}
+	hc.Transport = &correlationTransport{base: base}
+	return hc
+}
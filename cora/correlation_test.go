@@ -0,0 +1,147 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+// headerTransport adds a fixed header, standing in for a user's own transport.
+type headerTransport struct {
+	base http.RoundTripper
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-User-Transport", "yes")
+	return t.base.RoundTrip(req)
+}
+
+func TestCorrelationID_OnTheWire(t *testing.T) {
+	seen := map[string]http.Header{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "generateContent") {
+			seen["google"] = r.Header.Clone()
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{{"text": "ok"}}}}},
+			})
+			return
+		}
+		seen["openai"] = r.Header.Clone()
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": "ok"}}},
+		})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{
+		OpenAIAPIKey:  "sk-test",
+		OpenAIBaseURL: srv.URL,
+		GoogleAPIKey:  "g-test",
+		GoogleBaseURL: srv.URL,
+		HTTPClient:    &http.Client{Transport: headerTransport{base: http.DefaultTransport}},
+	})
+	ctx := WithCorrelationID(context.Background(), "req-42")
+
+	resp, err := c.Text(ctx, TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("OpenAI Text error: %v", err)
+	}
+	if resp.CorrelationID != "req-42" {
+		t.Errorf("expected CorrelationID on response, got %q", resp.CorrelationID)
+	}
+	if _, err := c.Text(ctx, TextRequest{Provider: ProviderGoogle, Model: "gemini-test", Input: "hi"}); err != nil {
+		t.Fatalf("Google Text error: %v", err)
+	}
+
+	for _, p := range []string{"openai", "google"} {
+		h := seen[p]
+		if h.Get(CorrelationIDHeader) != "req-42" {
+			t.Errorf("%s: expected %s header, got %q", p, CorrelationIDHeader, h.Get(CorrelationIDHeader))
+		}
+		if h.Get("X-User-Transport") != "yes" {
+			t.Errorf("%s: expected user transport to be preserved", p)
+		}
+	}
+}
+
+func TestCorrelationIDFromContext_Empty(t *testing.T) {
+	if id := CorrelationIDFromContext(context.Background()); id != "" {
+		t.Errorf("expected empty ID, got %q", id)
+	}
+}
+
+func TestLabels_OnTheWire(t *testing.T) {
+	var openAILabels string
+	var googleLabels []map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "generateContent") {
+			var body struct {
+				Labels map[string]string `json:"labels"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			googleLabels = append(googleLabels, body.Labels)
+			part := map[string]any{"text": "done"}
+			if len(googleLabels) == 1 {
+				part = map[string]any{"functionCall": map[string]any{"name": "lookup", "args": map[string]any{}}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{part}}}},
+			})
+			return
+		}
+		openAILabels = r.Header.Get(LabelsHeader)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": "ok"}}},
+		})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL, GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	labels := map[string]string{"team": "core"}
+
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi", Labels: labels}); err != nil {
+		t.Fatalf("OpenAI Text error: %v", err)
+	}
+	if openAILabels != `{"team":"core"}` {
+		t.Errorf("expected %s header, got %q", LabelsHeader, openAILabels)
+	}
+
+	// The Gemini API rejects labels; they are a Vertex AI feature.
+	gc, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		Backend:     genai.BackendVertexAI,
+		APIKey:      "g-test",
+		HTTPOptions: genai.HTTPOptions{BaseURL: srv.URL},
+	})
+	if err != nil {
+		t.Fatalf("genai.NewClient: %v", err)
+	}
+	c.google = &googleProvider{client: gc}
+	_, err = c.Text(context.Background(), TextRequest{
+		Provider:     ProviderGoogle,
+		Model:        "gemini-test",
+		Input:        "hi",
+		Mode:         ModeToolCalling,
+		Labels:       labels,
+		Tools:        []CoraTool{{Name: "lookup", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{"lookup": func(ctx context.Context, args map[string]any) (any, error) { return "x", nil }},
+	})
+	if err != nil {
+		t.Fatalf("Google Text error: %v", err)
+	}
+	if len(googleLabels) != 2 {
+		t.Fatalf("expected 2 generateContent rounds, got %d", len(googleLabels))
+	}
+	for i, l := range googleLabels {
+		if l["team"] != "core" {
+			t.Errorf("round %d: expected labels, got %v", i+1, l)
+		}
+	}
+}
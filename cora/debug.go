@@ -0,0 +1,111 @@
+package cora
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// debugTransport logs HTTP traffic to the provider APIs at DEBUG level.
+// Known secrets are masked in URLs, headers and bodies before logging.
+type debugTransport struct {
+	base    http.RoundTripper
+	logger  *slog.Logger
+	secrets []string
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	var reqBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+	t.logger.DebugContext(ctx, "cora: http request",
+		"method", req.Method,
+		"url", t.redact(req.URL.String()),
+		"headers", t.redactHeaders(req.Header),
+		"body", t.redact(string(reqBody)),
+	)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.logger.DebugContext(ctx, "cora: http error", "url", t.redact(req.URL.String()), "error", t.redact(err.Error()))
+		return nil, err
+	}
+
+	// Streaming bodies are consumed incrementally by the SDK; don't buffer them.
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.logger.DebugContext(ctx, "cora: http response", "status", resp.StatusCode, "body", "<stream>")
+		return resp, nil
+	}
+	respBody, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if readErr != nil {
+		return nil, readErr
+	}
+	t.logger.DebugContext(ctx, "cora: http response",
+		"status", resp.StatusCode,
+		"body", t.redact(string(respBody)),
+	)
+	return resp, nil
+}
+
+func (t *debugTransport) redact(s string) string {
+	for _, secret := range t.secrets {
+		s = strings.ReplaceAll(s, secret, maskSecret(secret))
+	}
+	return s
+}
+
+func (t *debugTransport) redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		value := t.redact(strings.Join(v, ", "))
+		switch http.CanonicalHeaderKey(k) {
+		case "Authorization", "X-Goog-Api-Key", "Api-Key":
+			if fields := strings.Fields(value); len(fields) == 2 {
+				value = fields[0] + " " + maskSecret(fields[1])
+			} else {
+				value = maskSecret(value)
+			}
+		}
+		out[k] = value
+	}
+	return out
+}
+
+// maskSecret hides all but the last 4 characters of s.
+func maskSecret(s string) string {
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+	return strings.Repeat("*", len(s)-4) + s[len(s)-4:]
+}
+
+// configSecrets returns the API keys in cfg that must never be logged.
+func configSecrets(cfg CoraConfig) []string {
+	var out []string
+	for _, s := range []string{cfg.OpenAIAPIKey, cfg.GoogleAPIKey, cfg.MistralAPIKey, cfg.CohereAPIKey} {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// debugRetryHook logs tool retry attempts and then calls next, if any.
+func debugRetryHook(logger *slog.Logger, next func(int, error)) func(int, error) {
+	return func(attempt int, err error) {
+		logger.Debug("cora: tool retry", "attempt", attempt, "error", err)
+		if next != nil {
+			next(attempt, err)
+		}
+	}
+}
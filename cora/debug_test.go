@@ -1,8 +1,14 @@
 package cora
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -71,3 +77,84 @@ func ptrInt(p *int) int {
 	}
 	return *p
 }
+
+func TestDebugLogger_LogsTrafficWithMaskedKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": "pong"}}},
+		})
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	const apiKey = "sk-supersecret-abcd"
+	c := New(CoraConfig{
+		OpenAIAPIKey:  apiKey,
+		OpenAIBaseURL: srv.URL,
+		DebugLogger:   slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+
+	resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-debug-model", Input: "ping"})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "pong" {
+		t.Errorf("debug transport must not alter the response, got %q", resp.Text)
+	}
+
+	out := logs.String()
+	if !strings.Contains(out, "gpt-debug-model") {
+		t.Error("expected request body with model name in debug log")
+	}
+	if !strings.Contains(out, "pong") {
+		t.Error("expected response body in debug log")
+	}
+	if strings.Contains(out, apiKey) {
+		t.Error("API key leaked into debug log")
+	}
+	if !strings.Contains(out, "abcd") {
+		t.Error("expected last 4 characters of the masked key in debug log")
+	}
+}
+
+func TestDebugLogger_ToolRetries(t *testing.T) {
+	var logs bytes.Buffer
+	cfg := CoraConfig{
+		DebugLogger:     slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		ToolRetryConfig: &RetryConfig{MaxAttempts: 2, RetryableErrors: []error{errFlaky}},
+	}
+	plans, err := buildPlans(ProviderOpenAI, "gpt-test", TextRequest{}, cfg, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+
+	calls := 0
+	h := RetryableToolHandler(func(ctx context.Context, args map[string]any) (any, error) {
+		calls++
+		if calls == 1 {
+			return nil, errFlaky
+		}
+		return "ok", nil
+	}, *plans[0].ToolRetryConfig)
+	if _, err := h(context.Background(), nil); err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	if !strings.Contains(logs.String(), "attempt=1") || !strings.Contains(logs.String(), "flaky") {
+		t.Errorf("expected retry attempt in debug log, got %q", logs.String())
+	}
+	if cfg.ToolRetryConfig.OnRetry != nil {
+		t.Error("buildPlans must not modify the caller's RetryConfig")
+	}
+}
+
+var errFlaky = errors.New("flaky")
+
+func TestMaskSecret(t *testing.T) {
+	if got := maskSecret("sk-1234567890"); got != "*********7890" {
+		t.Errorf("maskSecret = %q", got)
+	}
+	if got := maskSecret("abc"); got != "***" {
+		t.Errorf("maskSecret short = %q", got)
+	}
+}
@@ -0,0 +1,33 @@
+package cora
+
+import (
+	"context"
+)
+
+// textDeduped executes req through the client's singleflight group so that
+// concurrent callers issuing an identical request share one provider call.
+// Tool-calling requests are never deduplicated because their handlers may
+// have side effects.
+//
+// The shared call runs without the callers' cancellation, so one caller
+// giving up does not fail the others; each caller stops waiting when its own
+// ctx is done.
+func (c *Client) textDeduped(ctx context.Context, req TextRequest) (TextResponse, error) {
+	key, err := c.requestKey(req)
+	if err != nil {
+		return c.text(ctx, req)
+	}
+	shared := context.WithoutCancel(ctx)
+	ch := c.inflight.DoChan(key, func() (any, error) {
+		return c.text(shared, req)
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return TextResponse{}, res.Err
+		}
+		return cloneTextResponse(res.Val.(TextResponse)), nil
+	case <-ctx.Done():
+		return TextResponse{}, ctx.Err()
+	}
+}
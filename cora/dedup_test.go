@@ -0,0 +1,162 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// blockingProvider counts calls and blocks each one until release is closed.
+type blockingProvider struct {
+	calls   atomic.Int32
+	release chan struct{}
+	json    map[string]any
+}
+
+func (b *blockingProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	b.calls.Add(1)
+	select {
+	case <-ctx.Done():
+		return callResult{}, ctx.Err()
+	case <-b.release:
+	}
+	return callResult{Text: "shared answer", JSON: cloneJSONMap(b.json)}, nil
+}
+
+// waitingContext reports on waiting when a caller waits for the shared
+// call, i.e. when textDeduped selects on its Done channel.
+type waitingContext struct {
+	context.Context
+	once    sync.Once
+	waiting chan<- struct{}
+}
+
+func (w *waitingContext) Done() <-chan struct{} {
+	w.once.Do(func() { w.waiting <- struct{}{} })
+	return w.Context.Done()
+}
+
+// dedupCallers starts n callers of req, each with the context made by ctx,
+// and returns once all of them wait for the shared call. done[i] is closed
+// when caller i returns.
+func dedupCallers(c *Client, req TextRequest, n int, ctx func(i int) context.Context) (results []TextResponse, errs []error, done []chan struct{}) {
+	waiting := make(chan struct{})
+	results = make([]TextResponse, n)
+	errs = make([]error, n)
+	done = make([]chan struct{}, n)
+	for i := range n {
+		done[i] = make(chan struct{})
+		go func() {
+			defer close(done[i])
+			results[i], errs[i] = c.Text(&waitingContext{Context: ctx(i), waiting: waiting}, req)
+		}()
+	}
+	for range n {
+		<-waiting
+	}
+	return results, errs, done
+}
+
+func TestText_RequestDedup(t *testing.T) {
+	bp := &blockingProvider{release: make(chan struct{})}
+	c := &Client{cfg: CoraConfig{RequestDedup: true}}
+	c.openai = bp
+
+	req := TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Input:    "What is the capital of France?",
+		Mode:     ModeBasic,
+	}
+
+	const callers = 10
+	results, errs, done := dedupCallers(c, req, callers, func(int) context.Context { return context.Background() })
+	close(bp.release)
+	for _, d := range done {
+		<-d
+	}
+
+	if n := bp.calls.Load(); n != 1 {
+		t.Errorf("expected provider to be called once, got %d", n)
+	}
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil {
+			t.Fatalf("caller %d: unexpected error: %v", i, errs[i])
+		}
+		if results[i].Text != "shared answer" {
+			t.Errorf("caller %d: unexpected text %q", i, results[i].Text)
+		}
+	}
+}
+
+func TestText_RequestDedupCallerCancel(t *testing.T) {
+	bp := &blockingProvider{release: make(chan struct{}), json: map[string]any{"tags": []any{"a"}}}
+	c := &Client{cfg: CoraConfig{RequestDedup: true}}
+	c.openai = bp
+	req := TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi", Mode: ModeBasic}
+
+	// The first caller gives up while the call is in flight.
+	first, cancel := context.WithCancel(context.Background())
+	results, errs, done := dedupCallers(c, req, 3, func(i int) context.Context {
+		if i == 0 {
+			return first
+		}
+		return context.Background()
+	})
+	cancel()
+	<-done[0]
+	close(bp.release)
+	<-done[1]
+	<-done[2]
+
+	if !errors.Is(errs[0], context.Canceled) {
+		t.Errorf("canceled caller: got %v, want context.Canceled", errs[0])
+	}
+	for i := 1; i < 3; i++ {
+		if errs[i] != nil || results[i].Text != "shared answer" {
+			t.Fatalf("caller %d: got %q, %v", i, results[i].Text, errs[i])
+		}
+	}
+	// Each caller has its own copy of the JSON.
+	results[1].JSON["tags"].([]any)[0] = "changed"
+	if got := results[2].JSON["tags"].([]any)[0]; got != "a" {
+		t.Errorf("callers share JSON: got %v", got)
+	}
+}
+
+func TestRequestKey(t *testing.T) {
+	c := &Client{}
+	key := func(req TextRequest) string {
+		t.Helper()
+		req.Provider, req.Model = ProviderOpenAI, "m"
+		k, err := c.requestKey(req)
+		if err != nil {
+			t.Fatalf("requestKey error: %v", err)
+		}
+		return k
+	}
+	base := TextRequest{Input: "a"}
+	if key(base) != key(base) {
+		t.Error("expected identical keys for identical requests")
+	}
+	tokens, effort := 10, "low"
+	for name, req := range map[string]TextRequest{
+		"Input":           {Input: "b"},
+		"MaxOutputTokens": {Input: "a", MaxOutputTokens: &tokens},
+		"ReasoningEffort": {Input: "a", ReasoningEffort: &effort},
+		"ProviderOptions": {Input: "a", ProviderOptions: map[string]any{"seed": 1}},
+		"SchemaName": {Input: "a", Mode: ModeStructuredJSON, SchemaName: "x",
+			ResponseSchema: map[string]any{"type": "object"}},
+		"GroundWithSearch": {Input: "a", GroundWithSearch: true},
+	} {
+		if key(req) == key(base) {
+			t.Errorf("%s does not change the key", name)
+		}
+	}
+
+	if _, err := c.requestKey(TextRequest{Provider: ProviderOpenAI, Model: "m", Mode: ModeToolCalling}); err == nil {
+		t.Error("expected tool-calling requests not to be keyed")
+	}
+}
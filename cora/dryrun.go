@@ -0,0 +1,209 @@
+package cora
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// DryRunPlan describes the provider calls a request would make.
+// It is returned in TextResponse.DryRunPlan when TextRequest.DryRun is set.
+type DryRunPlan struct {
+	// Calls are executed in order; ModeTwoStepEnhance produces two.
+	Calls []PlannedCall
+}
+
+// PlannedCall is one provider call as cora would send it.
+type PlannedCall struct {
+	Provider Provider
+	Model    string
+	System   string
+	Input    string
+
+	Temperature     *float32
+	MaxOutputTokens *int
+	ReasoningEffort *string
+	Labels          map[string]string
+
+	Structured     bool
+	ResponseSchema map[string]any
+	Tools          []CoraTool
+
+	// Proofread marks the clean-up step of ModeTwoStepEnhance.
+	Proofread bool
+	// ReAct marks a ModeReAct tool-calling call.
+	ReAct bool
+}
+
+// dryRun validates req and the client config and returns the planned calls
+// without contacting any provider.
+func (c *Client) dryRun(req TextRequest) (TextResponse, error) {
+	if req.Provider == ProviderAuto {
+		p, err := c.pickWeightedProvider()
+		if err != nil {
+			return TextResponse{}, err
+		}
+		req.Provider = p
+	}
+	if !req.Provider.Valid() {
+		return TextResponse{}, unknownProviderError(req.Provider)
+	}
+	model, err := c.resolveModel(req)
+	if err != nil {
+		return TextResponse{}, err
+	}
+	if err := c.cfg.Validate(); err != nil {
+		return TextResponse{}, err
+	}
+	plans, err := buildPlans(req.Provider, model, req, c.cfg, c.promptOptimizer)
+	if err != nil {
+		return TextResponse{}, err
+	}
+
+	dp := &DryRunPlan{Calls: make([]PlannedCall, len(plans))}
+	for i, p := range plans {
+		dp.Calls[i] = PlannedCall{
+			Provider:        p.Provider,
+			Model:           p.Model,
+			System:          p.System,
+			Input:           p.Input,
+			Temperature:     p.Temperature,
+			MaxOutputTokens: p.MaxOutputTokens,
+			ReasoningEffort: p.ReasoningEffort,
+			Labels:          p.Labels,
+			Structured:      p.Structured,
+			ResponseSchema:  p.ResponseSchema,
+			Tools:           p.Tools,
+			Proofread:       p.Proofread,
+			ReAct:           p.ReAct,
+		}
+	}
+	return TextResponse{
+		Provider:     req.Provider,
+		Model:        model,
+		Mode:         req.Mode,
+		Text:         "dry-run",
+		UsedProvider: req.Provider,
+		UsedModel:    model,
+		DryRunPlan:   dp,
+	}, nil
+}
+
+// validateResponseSchema checks a ModeStructuredJSON schema: it must be a
+// valid JSON Schema whose root describes an object.
+func validateResponseSchema(schema map[string]any) error {
+	err := validateJSONSchema(schema)
+	if t, _ := schema["type"].(string); t != "object" {
+		err = errors.Join(errors.New(`$: root type must be "object"`), err)
+	}
+	if err != nil {
+		return fmt.Errorf("cora: invalid ResponseSchema: %w", err)
+	}
+	return nil
+}
+
+var jsonSchemaTypes = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+// validateJSONSchema checks that schema is a well-formed JSON Schema object:
+// known "type" values, object "properties", string "required" entries that
+// name declared properties, and valid "items"/"anyOf"/"oneOf"/"allOf" subschemas.
+func validateJSONSchema(schema map[string]any) error {
+	return validateSchemaAt("$", schema)
+}
+
+func validateSchemaAt(path string, schema map[string]any) error {
+	var errs []error
+
+	switch t := schema["type"].(type) {
+	case nil:
+	case string:
+		if !slices.Contains(jsonSchemaTypes, t) {
+			errs = append(errs, fmt.Errorf("%s: unknown type %q", path, t))
+		}
+	case []any:
+		for _, v := range t {
+			if s, ok := v.(string); !ok || !slices.Contains(jsonSchemaTypes, s) {
+				errs = append(errs, fmt.Errorf("%s: unknown type %v", path, v))
+			}
+		}
+	default:
+		errs = append(errs, fmt.Errorf("%s: type must be a string or list of strings", path))
+	}
+
+	props := map[string]any{}
+	if raw, ok := schema["properties"]; ok {
+		m, ok := raw.(map[string]any)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: properties must be an object", path))
+		}
+		props = m
+		for _, name := range slices.Sorted(maps.Keys(props)) {
+			sub, ok := props[name].(map[string]any)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s.properties.%s: must be an object", path, name))
+				continue
+			}
+			errs = append(errs, validateSchemaAt(path+".properties."+name, sub))
+		}
+	}
+
+	if raw, ok := schema["required"]; ok {
+		for _, name := range toStringList(raw, &errs, path+".required") {
+			if _, ok := props[name]; !ok {
+				errs = append(errs, fmt.Errorf("%s.required: %q is not a declared property", path, name))
+			}
+		}
+	}
+
+	if raw, ok := schema["items"]; ok {
+		if sub, ok := raw.(map[string]any); ok {
+			errs = append(errs, validateSchemaAt(path+".items", sub))
+		} else {
+			errs = append(errs, fmt.Errorf("%s.items: must be an object", path))
+		}
+	}
+
+	for _, key := range []string{"anyOf", "oneOf", "allOf"} {
+		raw, ok := schema[key]
+		if !ok {
+			continue
+		}
+		list, ok := raw.([]any)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s.%s: must be a list", path, key))
+			continue
+		}
+		for i, v := range list {
+			sub, ok := v.(map[string]any)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s.%s[%d]: must be an object", path, key, i))
+				continue
+			}
+			errs = append(errs, validateSchemaAt(fmt.Sprintf("%s.%s[%d]", path, key, i), sub))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// toStringList accepts []string or []any of strings, recording an error otherwise.
+func toStringList(v any, errs *[]error, path string) []string {
+	switch t := v.(type) {
+	case []string:
+		return t
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, e := range t {
+			s, ok := e.(string)
+			if !ok {
+				*errs = append(*errs, fmt.Errorf("%s: entries must be strings", path))
+				continue
+			}
+			out = append(out, s)
+		}
+		return out
+	}
+	*errs = append(*errs, fmt.Errorf("%s: must be a list of strings", path))
+	return nil
+}
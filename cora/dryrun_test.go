@@ -0,0 +1,107 @@
+package cora
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestText_DryRun(t *testing.T) {
+	tools := []CoraTool{{Name: "search", ParametersSchema: map[string]any{"type": "object"}}}
+	handlers := map[string]CoraToolHandler{
+		"search": func(ctx context.Context, args map[string]any) (any, error) { return "ok", nil },
+	}
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"answer": map[string]any{"type": "string"}},
+		"required":   []any{"answer"},
+	}
+
+	testCases := []struct {
+		name  string
+		req   TextRequest
+		check func(t *testing.T, calls []PlannedCall)
+	}{
+		{"basic", TextRequest{Mode: ModeBasic, System: "sys"}, func(t *testing.T, calls []PlannedCall) {
+			if len(calls) != 1 || calls[0].System != "sys" || calls[0].Input != "hi" {
+				t.Errorf("unexpected plan: %+v", calls)
+			}
+		}},
+		{"structured", TextRequest{Mode: ModeStructuredJSON, ResponseSchema: schema}, func(t *testing.T, calls []PlannedCall) {
+			if len(calls) != 1 || !calls[0].Structured || calls[0].ResponseSchema["type"] != "object" {
+				t.Errorf("unexpected plan: %+v", calls)
+			}
+		}},
+		{"tool calling", TextRequest{Mode: ModeToolCalling, Tools: tools, ToolHandlers: handlers}, func(t *testing.T, calls []PlannedCall) {
+			if len(calls) != 1 || len(calls[0].Tools) != 1 || calls[0].Tools[0].Name != "search" {
+				t.Errorf("unexpected plan: %+v", calls)
+			}
+		}},
+		{"two step", TextRequest{Mode: ModeTwoStepEnhance, System: "sys"}, func(t *testing.T, calls []PlannedCall) {
+			if len(calls) != 2 || !calls[0].Proofread || calls[0].System != "" || calls[1].Proofread || calls[1].System != "sys" {
+				t.Errorf("unexpected plan: %+v", calls)
+			}
+		}},
+		{"react", TextRequest{Mode: ModeReAct, Tools: tools, ToolHandlers: handlers}, func(t *testing.T, calls []PlannedCall) {
+			if len(calls) != 1 || !calls[0].ReAct || !strings.Contains(calls[0].System, "Thought:") {
+				t.Errorf("unexpected plan: %+v", calls)
+			}
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &countingProvider{}
+			c := &Client{cfg: CoraConfig{}}
+			c.openai = fake
+
+			req := tc.req
+			req.Provider = ProviderOpenAI
+			req.Model = "gpt-test"
+			req.Input = "hi"
+			req.DryRun = true
+
+			resp, err := c.Text(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Text error: %v", err)
+			}
+			if resp.Text != "dry-run" || resp.DryRunPlan == nil {
+				t.Fatalf("expected dry-run response, got %+v", resp)
+			}
+			if fake.calls.Load() != 0 {
+				t.Error("provider must not be called in dry-run mode")
+			}
+			tc.check(t, resp.DryRunPlan.Calls)
+		})
+	}
+}
+
+func TestText_DryRunInvalidSchema(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Mode:     ModeStructuredJSON,
+		DryRun:   true,
+		ResponseSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"n": map[string]any{"type": "int"}},
+			"required":   []any{"missing"},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected schema validation error")
+	}
+	for _, want := range []string{`unknown type "int"`, `"missing" is not a declared property`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestText_DryRunInvalidConfig(t *testing.T) {
+	c := &Client{cfg: CoraConfig{Timeout: -1}}
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "m", DryRun: true}); err == nil {
+		t.Error("expected config validation error in dry-run mode")
+	}
+}
@@ -0,0 +1,126 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// dynamicPromptRecorder records DynamicSystemPrompt calls and asks for
+// citations from round 2.
+type dynamicPromptRecorder struct {
+	rounds []int
+	last   []string
+}
+
+func (r *dynamicPromptRecorder) prompt(round int, lastResponse string) string {
+	r.rounds = append(r.rounds, round)
+	r.last = append(r.last, lastResponse)
+	return "Cite your sources."
+}
+
+func lookupTool() ([]CoraTool, map[string]CoraToolHandler) {
+	return []CoraTool{{Name: "lookup", ParametersSchema: map[string]any{"type": "object"}}},
+		map[string]CoraToolHandler{"lookup": func(ctx context.Context, args map[string]any) (any, error) { return "found", nil }}
+}
+
+func TestDynamicSystemPrompt_Google(t *testing.T) {
+	var systems []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			SystemInstruction *struct {
+				Parts []struct{ Text string } `json:"parts"`
+			} `json:"systemInstruction"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		system := ""
+		if body.SystemInstruction != nil && len(body.SystemInstruction.Parts) > 0 {
+			system = body.SystemInstruction.Parts[0].Text
+		}
+		systems = append(systems, system)
+		parts := []map[string]any{{"text": "done"}}
+		if len(systems) == 1 {
+			parts = []map[string]any{{"text": "Let me check."}, {"functionCall": map[string]any{"name": "lookup", "args": map[string]any{}}}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": parts}}},
+		})
+	}))
+	defer srv.Close()
+
+	rec := &dynamicPromptRecorder{}
+	tools, handlers := lookupTool()
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:            ProviderGoogle,
+		Model:               "gemini-test",
+		Input:               "Look it up.",
+		System:              "Be brief.",
+		Mode:                ModeToolCalling,
+		Tools:               tools,
+		ToolHandlers:        handlers,
+		DynamicSystemPrompt: rec.prompt,
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if len(rec.rounds) != 1 || rec.rounds[0] != 2 || rec.last[0] != "Let me check." {
+		t.Errorf("DynamicSystemPrompt calls = %v with %q, want round 2 with the first answer", rec.rounds, rec.last)
+	}
+	if len(systems) != 2 || systems[0] != "Be brief." || systems[1] != "Be brief.\n\nCite your sources." {
+		t.Errorf("system instructions = %q", systems)
+	}
+}
+
+func TestDynamicSystemPrompt_OpenAI(t *testing.T) {
+	var rounds [][]map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []map[string]any `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		rounds = append(rounds, body.Messages)
+		msg := map[string]any{"role": "assistant", "content": "done"}
+		if len(rounds) == 1 {
+			msg = toolCallMessage("call_1", "lookup", `{}`)
+			msg["content"] = "Let me check."
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{{"message": msg}}})
+	}))
+	defer srv.Close()
+
+	rec := &dynamicPromptRecorder{}
+	tools, handlers := lookupTool()
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:            ProviderOpenAI,
+		Model:               "gpt-test",
+		Input:               "Look it up.",
+		Mode:                ModeToolCalling,
+		Tools:               tools,
+		ToolHandlers:        handlers,
+		DynamicSystemPrompt: rec.prompt,
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if len(rec.rounds) != 1 || rec.rounds[0] != 2 || rec.last[0] != "Let me check." {
+		t.Errorf("DynamicSystemPrompt calls = %v with %q, want round 2 with the first answer", rec.rounds, rec.last)
+	}
+	if len(rounds) != 2 {
+		t.Fatalf("expected 2 rounds, got %d", len(rounds))
+	}
+	last := rounds[1][len(rounds[1])-1]
+	if last["role"] != "system" || last["content"] != "Cite your sources." {
+		t.Errorf("last message of round 2 = %v, want the dynamic system message", last)
+	}
+	for _, m := range rounds[0] {
+		if m["content"] == "Cite your sources." {
+			t.Error("dynamic system message sent in round 1")
+		}
+	}
+}
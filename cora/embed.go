@@ -0,0 +1,171 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	openai "github.com/sashabaranov/go-openai"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/genai"
+)
+
+// EmbedRequest asks a provider for vector embeddings of Input.
+type EmbedRequest struct {
+	Provider Provider
+	Model    string // e.g. "text-embedding-3-small", "text-embedding-004", "mistral-embed"
+	// Input holds up to maxEmbedInputs strings. Larger inputs are split into
+	// batches of CoraConfig.EmbedBatchSize that are sent in parallel.
+	Input []string
+}
+
+const (
+	// maxEmbedInputs caps the number of strings in one EmbedRequest.
+	maxEmbedInputs = 2048
+	// embedParallelism caps the number of concurrent batch requests.
+	embedParallelism = 8
+)
+
+// EmbedResponse holds one embedding per input, in input order.
+type EmbedResponse struct {
+	Provider   Provider
+	Model      string
+	Embeddings [][]float32
+
+	// Token usage, if available.
+	PromptTokens *int
+}
+
+// embedder is implemented by providers that support embeddings.
+type embedder interface {
+	Embed(ctx context.Context, model string, input []string) (EmbedResponse, error)
+}
+
+// Embed computes embeddings for req.Input using the requested provider/model.
+func (c *Client) Embed(ctx context.Context, req EmbedRequest) (EmbedResponse, error) {
+	if !req.Provider.Valid() {
+		return EmbedResponse{}, unknownProviderError(req.Provider)
+	}
+	if req.Model == "" {
+		return EmbedResponse{}, errors.New("cora: model must be specified")
+	}
+	if len(req.Input) == 0 {
+		return EmbedResponse{}, errors.New("cora: Input must not be empty")
+	}
+	if len(req.Input) > maxEmbedInputs {
+		return EmbedResponse{}, fmt.Errorf("cora: Input must not exceed %d strings, got %d", maxEmbedInputs, len(req.Input))
+	}
+
+	pc, err := c.providerFor(req.Provider, req.Model)
+	if err != nil {
+		return EmbedResponse{}, err
+	}
+	e, ok := pc.(embedder)
+	if !ok {
+		return EmbedResponse{}, fmt.Errorf("cora: provider %q does not support embeddings", req.Provider)
+	}
+	resp, err := c.embedBatched(ctx, e, req)
+	if err != nil {
+		return EmbedResponse{}, wrapProviderError(req.Provider, err)
+	}
+	resp.Provider = req.Provider
+	resp.Model = req.Model
+	return resp, nil
+}
+
+// embedBatchSize returns the configured batch size, or the provider default:
+// Google's embedContent takes one input per call, OpenAI-compatible APIs 100.
+func (c *Client) embedBatchSize(p Provider) int {
+	if c.cfg.EmbedBatchSize > 0 {
+		return c.cfg.EmbedBatchSize
+	}
+	if p == ProviderGoogle {
+		return 1
+	}
+	return 100
+}
+
+// embedBatched splits req.Input into batches, embeds them in parallel and
+// merges the results in input order.
+func (c *Client) embedBatched(ctx context.Context, e embedder, req EmbedRequest) (EmbedResponse, error) {
+	size := c.embedBatchSize(req.Provider)
+	if len(req.Input) <= size {
+		return e.Embed(ctx, req.Model, req.Input)
+	}
+
+	out := EmbedResponse{Embeddings: make([][]float32, len(req.Input))}
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(embedParallelism)
+	for start := 0; start < len(req.Input); start += size {
+		end := min(start+size, len(req.Input))
+		g.Go(func() error {
+			res, err := e.Embed(gctx, req.Model, req.Input[start:end])
+			if err != nil {
+				return err
+			}
+			if len(res.Embeddings) != end-start {
+				return fmt.Errorf("cora: expected %d embeddings, got %d", end-start, len(res.Embeddings))
+			}
+			copy(out.Embeddings[start:end], res.Embeddings)
+			if res.PromptTokens != nil {
+				mu.Lock()
+				total := derefInt(out.PromptTokens) + *res.PromptTokens
+				out.PromptTokens = &total
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return EmbedResponse{}, err
+	}
+	return out, nil
+}
+
+func (p *openAIProvider) Embed(ctx context.Context, model string, input []string) (EmbedResponse, error) {
+	res, err := p.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
+		Input: input,
+		Model: openai.EmbeddingModel(model),
+	})
+	if err != nil {
+		return EmbedResponse{}, err
+	}
+	if len(res.Data) != len(input) {
+		return EmbedResponse{}, fmt.Errorf("cora: expected %d embeddings, got %d", len(input), len(res.Data))
+	}
+
+	out := EmbedResponse{Embeddings: make([][]float32, len(input))}
+	for _, d := range res.Data {
+		if d.Index < 0 || d.Index >= len(input) {
+			return EmbedResponse{}, fmt.Errorf("cora: embedding index %d out of range", d.Index)
+		}
+		out.Embeddings[d.Index] = d.Embedding
+	}
+	if res.Usage.PromptTokens > 0 {
+		pt := res.Usage.PromptTokens
+		out.PromptTokens = &pt
+	}
+	return out, nil
+}
+
+func (p *googleProvider) Embed(ctx context.Context, model string, input []string) (EmbedResponse, error) {
+	contents := make([]*genai.Content, len(input))
+	for i, s := range input {
+		contents[i] = genai.NewContentFromText(s, genai.RoleUser)
+	}
+	res, err := p.client.Models.EmbedContent(ctx, model, contents, nil)
+	if err != nil {
+		return EmbedResponse{}, err
+	}
+	if len(res.Embeddings) != len(input) {
+		return EmbedResponse{}, fmt.Errorf("cora: expected %d embeddings, got %d", len(input), len(res.Embeddings))
+	}
+
+	out := EmbedResponse{Embeddings: make([][]float32, len(input))}
+	for i, e := range res.Embeddings {
+		out.Embeddings[i] = e.Values
+	}
+	return out, nil
+}
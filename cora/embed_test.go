@@ -0,0 +1,71 @@
+package cora
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+type batchEmbedder struct {
+	fakeProvider
+	calls    atomic.Int32
+	maxBatch atomic.Int32
+}
+
+func (b *batchEmbedder) Embed(ctx context.Context, model string, input []string) (EmbedResponse, error) {
+	b.calls.Add(1)
+	if n := int32(len(input)); n > b.maxBatch.Load() {
+		b.maxBatch.Store(n)
+	}
+	out := EmbedResponse{Embeddings: make([][]float32, len(input))}
+	for i, s := range input {
+		v, _ := strconv.Atoi(s)
+		out.Embeddings[i] = []float32{float32(v)}
+	}
+	pt := len(input)
+	out.PromptTokens = &pt
+	return out, nil
+}
+
+func TestEmbed_Batching(t *testing.T) {
+	fake := &batchEmbedder{}
+	c := &Client{cfg: CoraConfig{EmbedBatchSize: 10}}
+	c.openai = fake
+
+	input := make([]string, 250)
+	for i := range input {
+		input[i] = strconv.Itoa(i)
+	}
+	resp, err := c.Embed(context.Background(), EmbedRequest{Provider: ProviderOpenAI, Model: "emb", Input: input})
+	if err != nil {
+		t.Fatalf("Embed error: %v", err)
+	}
+
+	if got := fake.calls.Load(); got != 25 {
+		t.Errorf("expected 25 sub-requests, got %d", got)
+	}
+	if got := fake.maxBatch.Load(); got > 10 {
+		t.Errorf("batch of %d exceeds EmbedBatchSize", got)
+	}
+	if len(resp.Embeddings) != len(input) {
+		t.Fatalf("expected %d embeddings, got %d", len(input), len(resp.Embeddings))
+	}
+	for i, e := range resp.Embeddings {
+		if e[0] != float32(i) {
+			t.Fatalf("embedding %d out of order: %v", i, e)
+		}
+	}
+	if resp.PromptTokens == nil || *resp.PromptTokens != 250 {
+		t.Errorf("expected summed prompt tokens, got %v", resp.PromptTokens)
+	}
+}
+
+func TestEmbed_TooManyInputs(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = &batchEmbedder{}
+	_, err := c.Embed(context.Background(), EmbedRequest{Provider: ProviderOpenAI, Model: "emb", Input: make([]string, maxEmbedInputs+1)})
+	if err == nil {
+		t.Error("expected an error above the input limit")
+	}
+}
@@ -0,0 +1,67 @@
+package cora
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// envName returns the environment variable for name under prefix, e.g.
+// "TEST_OPENAI_API_KEY" for prefix "TEST". An empty prefix yields name.
+func envName(prefix, name string) string {
+	prefix = strings.TrimSuffix(prefix, "_")
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+// envStringFields lists the string fields LoadFromEnv reads, by variable name.
+func envStringFields(cfg *CoraConfig) map[string]*string {
+	return map[string]*string{
+		"OPENAI_API_KEY":          &cfg.OpenAIAPIKey,
+		"OPENAI_BASE_URL":         &cfg.OpenAIBaseURL,
+		"OPENAI_ORG_ID":           &cfg.OpenAIOrgID,
+		"OPENAI_API_TYPE":         &cfg.OpenAIAPIType,
+		"OPENAI_API_VERSION":      &cfg.OpenAIAPIVersion,
+		"GOOGLE_API_KEY":          &cfg.GoogleAPIKey,
+		"GOOGLE_PROJECT":          &cfg.GoogleProject,
+		"GOOGLE_LOCATION":         &cfg.GoogleLocation,
+		"GOOGLE_BASE_URL":         &cfg.GoogleBaseURL,
+		"VERTEX_AI_DATA_STORE_ID": &cfg.VertexAIDataStoreID,
+		"MISTRAL_API_KEY":         &cfg.MistralAPIKey,
+		"MISTRAL_BASE_URL":        &cfg.MistralBaseURL,
+		"COHERE_API_KEY":          &cfg.CohereAPIKey,
+		"COHERE_BASE_URL":         &cfg.CohereBaseURL,
+		"BEDROCK_REGION":          &cfg.BedrockRegion,
+		"BEDROCK_PROFILE":         &cfg.BedrockProfile,
+		"BEDROCK_BASE_URL":        &cfg.BedrockBaseURL,
+		"DEFAULT_MODEL_OPENAI":    &cfg.DefaultModelOpenAI,
+		"DEFAULT_MODEL_GOOGLE":    &cfg.DefaultModelGoogle,
+	}
+}
+
+// LoadFromEnv builds a config from environment variables named
+// {prefix}_OPENAI_API_KEY, {prefix}_GOOGLE_API_KEY,
+// {prefix}_DEFAULT_MODEL_OPENAI and so on for the provider settings, plus
+// {prefix}_PROVIDER, {prefix}_GOOGLE_BACKEND ("gemini" or "vertex") and
+// {prefix}_TIMEOUT (a time.Duration string). With an empty prefix the plain
+// names (OPENAI_API_KEY, ...) are used. Unset variables and unparsable values
+// leave the field at its zero value.
+//
+// Different prefixes let several configurations, e.g. for tests and
+// production, coexist in one process.
+func LoadFromEnv(prefix string) CoraConfig {
+	cfg := CoraConfig{EnvPrefix: prefix}
+	for name, field := range envStringFields(&cfg) {
+		*field = os.Getenv(envName(prefix, name))
+	}
+	cfg.Provider = Provider(os.Getenv(envName(prefix, "PROVIDER")))
+	if b, ok := parseGoogleBackend(os.Getenv(envName(prefix, "GOOGLE_BACKEND"))); ok {
+		cfg.GoogleBackend = b
+	}
+	if d, err := time.ParseDuration(os.Getenv(envName(prefix, "TIMEOUT"))); err == nil {
+		cfg.Timeout = d
+	}
+	return cfg
+}
@@ -0,0 +1,130 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	openai "github.com/sashabaranov/go-openai"
+	"google.golang.org/genai"
+)
+
+// ErrNotSupportedByProvider is returned when a request uses a feature the
+// selected provider cannot handle.
+var ErrNotSupportedByProvider = errors.New("cora: not supported by provider")
+
+// ErrTokenBudgetExceeded is sent as the stream's error event when the
+// response exceeds StreamRequest.MaxTokenBudget.
+var ErrTokenBudgetExceeded = errors.New("cora: stream token budget exceeded")
+
+// ErrorCode classifies a CoraError independently of the provider.
+type ErrorCode string
+
+const (
+	ErrCodeUnknown        ErrorCode = "unknown"
+	ErrCodeInvalidRequest ErrorCode = "invalid_request"
+	ErrCodeAuth           ErrorCode = "auth"
+	ErrCodeRateLimited    ErrorCode = "rate_limited"
+	ErrCodeServer         ErrorCode = "server_error"
+	ErrCodeUnavailable    ErrorCode = "unavailable"
+	ErrCodeOverloaded     ErrorCode = "overloaded"
+	ErrCodeNetwork        ErrorCode = "network"
+	ErrCodeContentPolicy  ErrorCode = "content_policy" // rejected by CoraConfig.ContentPolicy
+)
+
+// CoraError is a provider failure normalized across backends.
+// Use errors.As to inspect it; Unwrap returns the underlying SDK error.
+type CoraError struct {
+	Provider       Provider
+	HTTPStatusCode int // 0 if the request never got a response
+	Code           ErrorCode
+	Message        string
+	Err            error
+}
+
+func (e *CoraError) Error() string {
+	if e.HTTPStatusCode != 0 {
+		return fmt.Sprintf("cora: %s error (%d %s): %s", e.Provider, e.HTTPStatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("cora: %s error (%s): %s", e.Provider, e.Code, e.Message)
+}
+
+func (e *CoraError) Unwrap() error { return e.Err }
+
+// Retryable reports whether the failure is transient (service unavailable,
+// overloaded, or a network error) so the request may succeed elsewhere.
+func (e *CoraError) Retryable() bool {
+	switch e.Code {
+	case ErrCodeUnavailable, ErrCodeOverloaded, ErrCodeNetwork:
+		return true
+	}
+	return false
+}
+
+// isRetryableError reports whether err is a retryable CoraError.
+func isRetryableError(err error) bool {
+	var ce *CoraError
+	return errors.As(err, &ce) && ce.Retryable()
+}
+
+// wrapProviderError converts SDK and transport errors into a *CoraError.
+// Errors it does not recognize (tool failures, cancellation, ...) are
+// returned unchanged.
+func wrapProviderError(provider Provider, err error) error {
+	if err == nil {
+		return nil
+	}
+	var ce *CoraError
+	if errors.As(err, &ce) {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrRequestTooLarge) {
+		return err
+	}
+
+	var oaiAPIErr *openai.APIError
+	var oaiReqErr *openai.RequestError
+	var genaiErr genai.APIError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &oaiAPIErr):
+		return newHTTPCoraError(provider, oaiAPIErr.HTTPStatusCode, oaiAPIErr.Message, err)
+	case errors.As(err, &oaiReqErr):
+		return newHTTPCoraError(provider, oaiReqErr.HTTPStatusCode, oaiReqErr.Error(), err)
+	case errors.As(err, &genaiErr):
+		return newHTTPCoraError(provider, genaiErr.Code, genaiErr.Message, err)
+	case errors.As(err, &netErr):
+		return &CoraError{Provider: provider, Code: ErrCodeNetwork, Message: err.Error(), Err: err}
+	}
+	return err
+}
+
+func newHTTPCoraError(provider Provider, status int, msg string, err error) *CoraError {
+	return &CoraError{
+		Provider:       provider,
+		HTTPStatusCode: status,
+		Code:           errorCodeForStatus(status),
+		Message:        msg,
+		Err:            err,
+	}
+}
+
+func errorCodeForStatus(status int) ErrorCode {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrCodeRateLimited
+	case status == http.StatusServiceUnavailable:
+		return ErrCodeUnavailable
+	case status == 529: // Overloaded (non-standard, used by some providers)
+		return ErrCodeOverloaded
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrCodeAuth
+	case status >= 500:
+		return ErrCodeServer
+	case status >= 400:
+		return ErrCodeInvalidRequest
+	}
+	return ErrCodeUnknown
+}
@@ -0,0 +1,106 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// embeddingEvalK is the cutoff of the @k metrics in an
+// EmbeddingComparisonReport (fewer when the corpus is smaller).
+const embeddingEvalK = 5
+
+// EmbeddingComparisonReport compares how well each provider's embeddings
+// retrieve the relevant document for each query.
+type EmbeddingComparisonReport struct {
+	// K is the cutoff of PrecisionAtK and RecallAtK.
+	K       int
+	Results []EmbeddingEvalResult
+}
+
+// EmbeddingEvalResult holds the retrieval metrics of one provider and model,
+// averaged over the queries.
+type EmbeddingEvalResult struct {
+	Provider Provider
+	Model    string
+
+	PrecisionAtK float64
+	RecallAtK    float64
+	// MRR is the mean reciprocal rank of the relevant document.
+	MRR float64
+}
+
+// CompareEmbeddingProviders embeds queries and corpus with each provider,
+// using the model at the same position in models, and scores cosine
+// retrieval over the corpus. The ground truth pairs them by position: the
+// document relevant to queries[i] is corpus[i], so corpus must have at
+// least as many entries as queries; later corpus entries are distractors.
+func CompareEmbeddingProviders(ctx context.Context, client *Client, queries, corpus []string, providers []Provider, models []string) (EmbeddingComparisonReport, error) {
+	switch {
+	case len(queries) == 0:
+		return EmbeddingComparisonReport{}, errors.New("cora: CompareEmbeddingProviders requires at least one query")
+	case len(corpus) < len(queries):
+		return EmbeddingComparisonReport{}, fmt.Errorf("cora: corpus has %d documents for %d queries; corpus[i] must be the document relevant to queries[i]", len(corpus), len(queries))
+	case len(providers) != len(models):
+		return EmbeddingComparisonReport{}, fmt.Errorf("cora: got %d providers and %d models; each provider needs a model", len(providers), len(models))
+	}
+
+	report := EmbeddingComparisonReport{K: min(embeddingEvalK, len(corpus))}
+	for i, provider := range providers {
+		docs, err := client.Embed(ctx, EmbedRequest{Provider: provider, Model: models[i], Input: corpus})
+		if err != nil {
+			return EmbeddingComparisonReport{}, fmt.Errorf("cora: embedding corpus with %s: %w", provider, err)
+		}
+		qs, err := client.Embed(ctx, EmbedRequest{Provider: provider, Model: models[i], Input: queries})
+		if err != nil {
+			return EmbeddingComparisonReport{}, fmt.Errorf("cora: embedding queries with %s: %w", provider, err)
+		}
+		res := scoreRetrieval(qs.Embeddings, docs.Embeddings, report.K)
+		res.Provider, res.Model = provider, models[i]
+		report.Results = append(report.Results, res)
+	}
+	return report, nil
+}
+
+// scoreRetrieval computes the metrics of EmbeddingEvalResult for queries
+// whose relevant document is the corpus entry at the same position.
+func scoreRetrieval(queries, corpus [][]float32, k int) EmbeddingEvalResult {
+	var index EmbeddingIndex
+	for i, vec := range corpus {
+		index.Add(strconv.Itoa(i), vec)
+	}
+	var res EmbeddingEvalResult
+	for qi, q := range queries {
+		for rank, m := range index.Search(q, index.Len()) {
+			if m.Index != qi {
+				continue
+			}
+			res.MRR += 1 / float64(rank+1)
+			if rank < k {
+				// One relevant document per query: a hit in the top k is
+				// 1/k of precision and all of recall.
+				res.PrecisionAtK += 1 / float64(k)
+				res.RecallAtK++
+			}
+			break
+		}
+	}
+	n := float64(len(queries))
+	res.PrecisionAtK /= n
+	res.RecallAtK /= n
+	res.MRR /= n
+	return res
+}
+
+// Print writes the report to w as a table.
+func (r EmbeddingComparisonReport) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "PROVIDER\tMODEL\tP@%d\tR@%d\tMRR\n", r.K, r.K)
+	for _, res := range r.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%.3f\t%.3f\t%.3f\n", res.Provider, res.Model, res.PrecisionAtK, res.RecallAtK, res.MRR)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,68 @@
+package cora
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+// vectorProvider embeds each input with a fixed vector from vecs.
+type vectorProvider struct {
+	vecs map[string][]float32
+}
+
+func (p *vectorProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	return callResult{}, nil
+}
+
+func (p *vectorProvider) Embed(ctx context.Context, model string, input []string) (EmbedResponse, error) {
+	resp := EmbedResponse{}
+	for _, s := range input {
+		resp.Embeddings = append(resp.Embeddings, p.vecs[s])
+	}
+	return resp, nil
+}
+
+func TestCompareEmbeddingProviders(t *testing.T) {
+	queries := []string{"q-cats", "q-cars"}
+	corpus := []string{"doc-cats", "doc-cars", "doc-tax"}
+
+	// "good" places each query next to its document; "bad" swaps them, so
+	// the relevant document always ranks second.
+	good := &vectorProvider{vecs: map[string][]float32{
+		"q-cats": {1, 0, 0}, "q-cars": {0, 1, 0},
+		"doc-cats": {1, 0.1, 0}, "doc-cars": {0.1, 1, 0}, "doc-tax": {0, 0, 1},
+	}}
+	bad := &vectorProvider{vecs: map[string][]float32{
+		"q-cats": {0, 1, 0}, "q-cars": {1, 0, 0},
+		"doc-cats": {1, 0.1, 0}, "doc-cars": {0.1, 1, 0}, "doc-tax": {0, 0, 1},
+	}}
+	c := &Client{cfg: CoraConfig{}, openai: good, google: bad}
+
+	report, err := CompareEmbeddingProviders(context.Background(), c, queries, corpus,
+		[]Provider{ProviderOpenAI, ProviderGoogle}, []string{"text-embedding-3-small", "text-embedding-004"})
+	if err != nil {
+		t.Fatalf("CompareEmbeddingProviders error: %v", err)
+	}
+	if report.K != 3 || len(report.Results) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	g, b := report.Results[0], report.Results[1]
+	if !near(g.MRR, 1) || !near(g.RecallAtK, 1) || !near(g.PrecisionAtK, 1.0/3) {
+		t.Errorf("good provider: %+v", g)
+	}
+	if !near(b.MRR, 0.5) || !near(b.RecallAtK, 1) {
+		t.Errorf("bad provider: %+v", b)
+	}
+
+	var out strings.Builder
+	if err := report.Print(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "P@3") || !strings.Contains(out.String(), "0.333  1.000  0.500") {
+		t.Errorf("unexpected table:\n%s", out.String())
+	}
+}
@@ -0,0 +1,130 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// defaultJudgePassThreshold is used when CoraConfig.JudgePassThreshold is zero.
+const defaultJudgePassThreshold = 0.7
+
+const judgeInstructions = `You are an impartial judge evaluating an AI assistant's response.
+Score the response against each criterion on a scale from 0 (not met at all) to 1 (fully met).
+Reply with a JSON object {"scores": [{"criterion": "<criterion>", "score": <number from 0 to 1>}, ...], "summary": "<brief overall assessment>"}, with one score per criterion.`
+
+// EvaluationResult is the judge's verdict returned by EvaluateResponse.
+type EvaluationResult struct {
+	// Scores maps each criterion to its score between 0 and 1.
+	Scores map[string]float64
+	// Summary is the judge's overall assessment.
+	Summary string
+	// Passed reports whether the mean score reached the pass threshold
+	// (CoraConfig.JudgePassThreshold, default 0.7).
+	Passed bool
+}
+
+// EvaluateResponse asks a judge model to score response, the answer to
+// original, against each of criteria. The judge is CoraConfig.JudgeProvider
+// and JudgeModel, falling back to the original request's provider and model.
+func (c *Client) EvaluateResponse(ctx context.Context, original TextRequest, response TextResponse, criteria []string) (EvaluationResult, error) {
+	if len(criteria) == 0 {
+		return EvaluationResult{}, errors.New("cora: EvaluateResponse requires at least one criterion")
+	}
+
+	provider, model := c.cfg.JudgeProvider, c.cfg.JudgeModel
+	if provider == "" {
+		provider = original.Provider
+		if model == "" {
+			model = original.Model
+		}
+	}
+
+	temp := float32(0)
+	resp, err := c.Text(ctx, TextRequest{
+		Provider:       provider,
+		Model:          model,
+		Mode:           ModeStructuredJSON,
+		System:         judgeInstructions,
+		Input:          judgeInput(original, response, criteria),
+		Temperature:    &temp,
+		ResponseSchema: judgeResponseSchema(criteria),
+	})
+	if err != nil {
+		return EvaluationResult{}, err
+	}
+	return parseEvaluation(resp.JSON, criteria, c.judgePassThreshold())
+}
+
+func (c *Client) judgePassThreshold() float64 {
+	if c.cfg.JudgePassThreshold > 0 {
+		return c.cfg.JudgePassThreshold
+	}
+	return defaultJudgePassThreshold
+}
+
+// judgeInput renders the request, the response and the criteria for the judge.
+func judgeInput(original TextRequest, response TextResponse, criteria []string) string {
+	var b strings.Builder
+	if strings.TrimSpace(original.System) != "" {
+		fmt.Fprintf(&b, "System prompt:\n%s\n\n", original.System)
+	}
+	fmt.Fprintf(&b, "User request:\n%s\n\nResponse:\n%s\n\nCriteria:\n", original.Input, response.Text)
+	for i, criterion := range criteria {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, criterion)
+	}
+	return b.String()
+}
+
+// judgeResponseSchema is the structured response schema for the judge.
+func judgeResponseSchema(criteria []string) map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"scores": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"criterion": map[string]any{"type": "string", "enum": criteria},
+						"score":     map[string]any{"type": "number"},
+					},
+					"required":             []string{"criterion", "score"},
+					"additionalProperties": false,
+				},
+			},
+			"summary": map[string]any{"type": "string"},
+		},
+		"required":             []string{"scores", "summary"},
+		"additionalProperties": false,
+	}
+}
+
+// parseEvaluation extracts the judge's scores, requiring one per criterion,
+// and decides pass/fail by comparing their mean with threshold.
+func parseEvaluation(m map[string]any, criteria []string, threshold float64) (EvaluationResult, error) {
+	items, _ := m["scores"].([]any)
+	res := EvaluationResult{Scores: make(map[string]float64, len(criteria))}
+	res.Summary, _ = m["summary"].(string)
+	for _, item := range items {
+		entry, _ := item.(map[string]any)
+		criterion, _ := entry["criterion"].(string)
+		score, ok := entry["score"].(float64)
+		if criterion == "" || !ok {
+			continue
+		}
+		res.Scores[criterion] = score
+	}
+
+	var sum float64
+	for _, criterion := range criteria {
+		score, ok := res.Scores[criterion]
+		if !ok {
+			return EvaluationResult{}, fmt.Errorf("cora: judge returned no score for criterion %q", criterion)
+		}
+		sum += score
+	}
+	res.Passed = sum/float64(len(criteria)) >= threshold
+	return res, nil
+}
@@ -0,0 +1,57 @@
+package cora
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEvaluateResponse(t *testing.T) {
+	judge := (&fakeProvider{}).WithJSON(map[string]any{
+		"scores": []any{
+			map[string]any{"criterion": "accurate", "score": 0.9},
+			map[string]any{"criterion": "concise", "score": 0.6},
+		},
+		"summary": "Correct but wordy.",
+	})
+	c := &Client{
+		cfg:    CoraConfig{JudgeProvider: ProviderGoogle, JudgeModel: "gemini-judge", JudgePassThreshold: 0.8},
+		google: judge,
+	}
+	original := TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "What is the capital of France?"}
+	response := TextResponse{Text: "The capital of France is Paris, a city on the Seine."}
+
+	res, err := c.EvaluateResponse(context.Background(), original, response, []string{"accurate", "concise"})
+	if err != nil {
+		t.Fatalf("EvaluateResponse error: %v", err)
+	}
+	if res.Scores["accurate"] != 0.9 || res.Scores["concise"] != 0.6 || res.Summary != "Correct but wordy." {
+		t.Errorf("unexpected result %+v", res)
+	}
+	if res.Passed {
+		t.Error("mean score 0.75 should not pass threshold 0.8")
+	}
+
+	plan := judge.ReceivedPlans()[0]
+	if plan.Model != "gemini-judge" || !plan.Structured {
+		t.Errorf("expected a structured call to the judge model, got %+v", plan)
+	}
+	for _, kw := range []string{original.Input, response.Text, "1. accurate", "2. concise"} {
+		if !strings.Contains(plan.Input, kw) {
+			t.Errorf("judge input should contain %q:\n%s", kw, plan.Input)
+		}
+	}
+
+	c.cfg.JudgePassThreshold = 0
+	if res, err := c.EvaluateResponse(context.Background(), original, response, []string{"accurate", "concise"}); err != nil || !res.Passed {
+		t.Errorf("mean score 0.75 should pass the default threshold, got %+v, %v", res, err)
+	}
+
+	if _, err := c.EvaluateResponse(context.Background(), original, response, []string{"accurate", "polite"}); err == nil ||
+		!strings.Contains(err.Error(), `no score for criterion "polite"`) {
+		t.Errorf("expected missing criterion error, got %v", err)
+	}
+	if _, err := c.EvaluateResponse(context.Background(), original, response, nil); err == nil {
+		t.Error("expected an error without criteria")
+	}
+}
@@ -0,0 +1,147 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// fakeProvider isolates orchestration logic in unit tests (no network).
+// Zero-value fields keep the simple canned behaviour; the With* methods
+// add latency, failures, structured output and tool calls.
+type fakeProvider struct {
+	lastPlan callPlan
+
+	// configurable canned responses
+	proofreadOut string
+	firstOut     string
+	finalOut     string
+
+	// simulate tool call round by returning an indicator in firstOut,
+	// then finalOut on the second round
+	supportsTools bool
+
+	mu        sync.Mutex
+	plans     []callPlan
+	delay     time.Duration
+	failAfter int // successful calls before failErr is returned
+	failErr   error
+	succeeded int
+	json      map[string]any
+	toolCalls []fakeToolCall
+
+	// toolResults holds what the handlers returned for injected tool calls.
+	toolResults []any
+}
+
+type fakeToolCall struct {
+	name string
+	args map[string]any
+}
+
+// WithDelay makes every call take d, simulating API latency.
+func (f *fakeProvider) WithDelay(d time.Duration) *fakeProvider {
+	f.delay = d
+	return f
+}
+
+// WithErrorAfterN makes every call after the first n successful ones fail with err.
+func (f *fakeProvider) WithErrorAfterN(n int, err error) *fakeProvider {
+	f.failAfter, f.failErr = n, err
+	return f
+}
+
+// WithJSON sets a structured answer, returned as both JSON and its text.
+func (f *fakeProvider) WithJSON(m map[string]any) *fakeProvider {
+	f.json = m
+	return f
+}
+
+// InjectToolCall makes the fake "call" toolName with args through the plan's
+// handler before answering, as a model would in a tool round.
+func (f *fakeProvider) InjectToolCall(toolName string, args map[string]any) *fakeProvider {
+	f.toolCalls = append(f.toolCalls, fakeToolCall{name: toolName, args: args})
+	return f
+}
+
+// ReceivedPlans returns every plan passed to Text, in call order.
+func (f *fakeProvider) ReceivedPlans() []callPlan {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]callPlan(nil), f.plans...)
+}
+
+func (f *fakeProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	f.mu.Lock()
+	f.lastPlan = plan
+	f.plans = append(f.plans, plan)
+	f.mu.Unlock()
+
+	// Simulate slow operations budget
+	select {
+	case <-ctx.Done():
+		return callResult{}, ctx.Err()
+	case <-time.After(max(f.delay, time.Millisecond)):
+	}
+
+	f.mu.Lock()
+	if f.failErr != nil && f.succeeded >= f.failAfter {
+		f.mu.Unlock()
+		return callResult{}, f.failErr
+	}
+	f.succeeded++
+	f.mu.Unlock()
+
+	if plan.Proofread {
+		return callResult{Text: coalesce(f.proofreadOut, "[proofread]")}, nil
+	}
+
+	if len(f.toolCalls) > 0 && len(plan.ToolHandlers) > 0 {
+		for _, tc := range f.toolCalls {
+			h, ok := plan.ToolHandlers[tc.name]
+			if !ok {
+				continue
+			}
+			res, err := h(ctx, tc.args)
+			if err != nil {
+				return callResult{}, err
+			}
+			f.mu.Lock()
+			f.toolResults = append(f.toolResults, res)
+			f.mu.Unlock()
+		}
+		return callResult{Text: coalesce(f.finalOut, "final after tool"), toolLoop: true}, nil
+	}
+
+	// Tool calling: first response "requests a tool", then final answer.
+	if f.supportsTools && len(plan.Tools) > 0 && len(plan.ToolHandlers) > 0 {
+		if f.firstOut != "" {
+			// First round with "tool call"
+			out := callResult{Text: f.firstOut}
+			// mutate to simulate tool loop consumption; next call returns final
+			f.firstOut = ""
+			return out, nil
+		}
+		return callResult{Text: coalesce(f.finalOut, "final after tool")}, nil
+	}
+
+	if f.json != nil {
+		b, _ := json.Marshal(f.json)
+		return callResult{Text: string(b), JSON: f.json}, nil
+	}
+
+	// Structured JSON: return a JSON blob string
+	if plan.Structured && len(plan.ResponseSchema) > 0 {
+		return callResult{Text: `{"ok":true,"items":[1,2,3]}`, JSON: map[string]any{"ok": true, "items": []any{1.0, 2.0, 3.0}}}, nil
+	}
+
+	return callResult{Text: coalesce(f.finalOut, "ok")}, nil
+}
+
+func coalesce(s string, def string) string {
+	if s != "" {
+		return s
+	}
+	return def
+}
@@ -0,0 +1,59 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// textWithFallback runs req and, while the failure is retryable, retries it on
+// each of req.FallbackProviders in order. The fallback uses the same model
+// name unless FallbackModels provides one at the same index.
+func (c *Client) textWithFallback(ctx context.Context, req TextRequest) (TextResponse, error) {
+	resp, err := c.textWithModelFallback(ctx, req)
+	for i, p := range req.FallbackProviders {
+		if err == nil || !isRetryableError(err) || ctx.Err() != nil {
+			break
+		}
+		next := req
+		next.Provider = p
+		if i < len(req.FallbackModels) && req.FallbackModels[i] != "" {
+			next.Model = req.FallbackModels[i]
+		}
+		c.logRetry(ctx, "fallback", next.Provider, next.Model, req.Mode, err)
+		resp, err = c.textWithModelFallback(ctx, next)
+	}
+	return resp, err
+}
+
+// textWithModelFallback runs req and, while the model is overloaded or rate
+// limited (HTTP 503 or 429), retries it with each model that
+// CoraConfig.ModelFallbacks lists for the request's model, in order.
+func (c *Client) textWithModelFallback(ctx context.Context, req TextRequest) (TextResponse, error) {
+	resp, err := c.textOnce(ctx, req)
+	if err == nil || len(c.cfg.ModelFallbacks) == 0 {
+		return resp, err
+	}
+	model, merr := c.resolveModel(req)
+	if merr != nil {
+		return resp, err
+	}
+	for _, m := range c.cfg.ModelFallbacks[model] {
+		if !isModelUnavailable(err) || ctx.Err() != nil {
+			break
+		}
+		next := req
+		next.Model = m
+		c.logRetry(ctx, "model fallback", next.Provider, next.Model, req.Mode, err)
+		resp, err = c.textOnce(ctx, next)
+	}
+	return resp, err
+}
+
+// isModelUnavailable reports whether err is a provider response saying the
+// model is overloaded (503) or rate limited (429).
+func isModelUnavailable(err error) bool {
+	var ce *CoraError
+	return errors.As(err, &ce) &&
+		(ce.HTTPStatusCode == http.StatusServiceUnavailable || ce.HTTPStatusCode == http.StatusTooManyRequests)
+}
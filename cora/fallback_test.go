@@ -0,0 +1,147 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// failingProvider always fails with err and records the last plan.
+type failingProvider struct {
+	err      error
+	lastPlan callPlan
+}
+
+func (f *failingProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	f.lastPlan = plan
+	return callResult{}, f.err
+}
+
+func TestText_FallbackProviders(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = &failingProvider{err: &openai.APIError{HTTPStatusCode: 503, Message: "unavailable"}}
+	fallback := &fakeProvider{finalOut: "from google"}
+	c.google = fallback
+
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider:          ProviderOpenAI,
+		Model:             "gpt-test",
+		Input:             "hi",
+		Mode:              ModeBasic,
+		FallbackProviders: []Provider{ProviderGoogle},
+		FallbackModels:    []string{"gemini-test"},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "from google" {
+		t.Errorf("expected fallback answer, got %q", resp.Text)
+	}
+	if resp.UsedProvider != ProviderGoogle || resp.UsedModel != "gemini-test" {
+		t.Errorf("expected google/gemini-test, got %s/%s", resp.UsedProvider, resp.UsedModel)
+	}
+	if fallback.lastPlan.Model != "gemini-test" {
+		t.Errorf("expected fallback model on plan, got %q", fallback.lastPlan.Model)
+	}
+}
+
+func TestText_FallbackSkippedForNonRetryable(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = &failingProvider{err: &openai.APIError{HTTPStatusCode: 400, Message: "bad request"}}
+	fallback := &failingProvider{}
+	c.google = fallback
+
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:          ProviderOpenAI,
+		Model:             "gpt-test",
+		Input:             "hi",
+		FallbackProviders: []Provider{ProviderGoogle},
+	})
+	var ce *CoraError
+	if !errors.As(err, &ce) || ce.HTTPStatusCode != 400 || ce.Code != ErrCodeInvalidRequest {
+		t.Fatalf("expected 400 CoraError, got %v", err)
+	}
+	if fallback.lastPlan.Model != "" {
+		t.Error("fallback must not be called for non-retryable errors")
+	}
+}
+
+func TestWrapProviderError(t *testing.T) {
+	testCases := []struct {
+		name      string
+		err       error
+		code      ErrorCode
+		retryable bool
+	}{
+		{"openai 503", &openai.APIError{HTTPStatusCode: 503}, ErrCodeUnavailable, true},
+		{"openai 529", &openai.RequestError{HTTPStatusCode: 529, Err: errors.New("overloaded")}, ErrCodeOverloaded, true},
+		{"openai 429", &openai.APIError{HTTPStatusCode: 429}, ErrCodeRateLimited, false},
+		{"openai 401", &openai.APIError{HTTPStatusCode: 401}, ErrCodeAuth, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ce *CoraError
+			if !errors.As(wrapProviderError(ProviderOpenAI, tc.err), &ce) {
+				t.Fatal("expected CoraError")
+			}
+			if ce.Code != tc.code || ce.Retryable() != tc.retryable {
+				t.Errorf("got code %s retryable %v", ce.Code, ce.Retryable())
+			}
+			if !errors.Is(ce, tc.err) {
+				t.Error("expected CoraError to unwrap to the SDK error")
+			}
+		})
+	}
+
+	plain := errors.New("tool failed")
+	if wrapProviderError(ProviderOpenAI, plain) != plain {
+		t.Error("unrecognized errors must be returned unchanged")
+	}
+}
+
+// modelStatusProvider fails with an HTTP status for the models in status and
+// answers with the model name otherwise.
+type modelStatusProvider struct {
+	status map[string]int
+	models []string
+}
+
+func (p *modelStatusProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	p.models = append(p.models, plan.Model)
+	if code, ok := p.status[plan.Model]; ok {
+		return callResult{}, newHTTPCoraError(ProviderGoogle, code, "model unavailable", nil)
+	}
+	return callResult{Text: "answer from " + plan.Model}, nil
+}
+
+func TestText_ModelFallbacks(t *testing.T) {
+	pc := &modelStatusProvider{status: map[string]int{"gemini-1.5-pro": 503, "gemini-1.5-pro-002": 429}}
+	c := &Client{cfg: CoraConfig{ModelFallbacks: map[string][]string{
+		"gemini-1.5-pro": {"gemini-1.5-pro-002", "gemini-1.5-flash"},
+	}}}
+	c.google = pc
+
+	resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderGoogle, Model: "gemini-1.5-pro", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "answer from gemini-1.5-flash" || resp.UsedModel != "gemini-1.5-flash" {
+		t.Errorf("expected the flash fallback to answer, got %q from %q", resp.Text, resp.UsedModel)
+	}
+	if len(pc.models) != 3 {
+		t.Errorf("expected 3 attempts, got %v", pc.models)
+	}
+
+	// Other errors are not retried on the fallback models.
+	pc = &modelStatusProvider{status: map[string]int{"gemini-1.5-pro": 400}}
+	c.google = pc
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderGoogle, Model: "gemini-1.5-pro", Input: "hi"}); err == nil {
+		t.Error("expected the 400 error")
+	}
+	if len(pc.models) != 1 {
+		t.Errorf("expected no fallback for a 400, got %v", pc.models)
+	}
+}
@@ -0,0 +1,79 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// FileHandle refers to a file uploaded with Client.UploadFile. Pass it in
+// TextRequest.FileHandles to reference the file without sending it inline,
+// which Gemini requires for documents larger than about 4MB.
+type FileHandle struct {
+	URI       string
+	Name      string // resource name, e.g. "files/abc-123"; used by DeleteFile
+	MIMEType  string
+	ExpiresAt time.Time
+}
+
+// fileStore is implemented by providers with a file storage API.
+type fileStore interface {
+	UploadFile(ctx context.Context, name string, data io.Reader, mimeType string) (FileHandle, error)
+	DeleteFile(ctx context.Context, name string) error
+	ListFiles(ctx context.Context) ([]FileHandle, error)
+}
+
+// UploadFile uploads data to the Gemini Files API under the display name name.
+// Uploaded files expire after 48 hours.
+func (c *Client) UploadFile(ctx context.Context, name string, data io.Reader, mimeType string) (FileHandle, error) {
+	if mimeType == "" {
+		return FileHandle{}, errors.New("cora: mimeType must not be empty")
+	}
+	fs, err := c.fileStore()
+	if err != nil {
+		return FileHandle{}, err
+	}
+	fh, err := fs.UploadFile(ctx, name, data, mimeType)
+	if err != nil {
+		return FileHandle{}, wrapProviderError(ProviderGoogle, err)
+	}
+	return fh, nil
+}
+
+// DeleteFile deletes the uploaded file with the given resource name (FileHandle.Name).
+func (c *Client) DeleteFile(ctx context.Context, name string) error {
+	fs, err := c.fileStore()
+	if err != nil {
+		return err
+	}
+	if err := fs.DeleteFile(ctx, name); err != nil {
+		return wrapProviderError(ProviderGoogle, err)
+	}
+	return nil
+}
+
+// ListFiles returns every file uploaded with the client's Google credentials.
+func (c *Client) ListFiles(ctx context.Context) ([]FileHandle, error) {
+	fs, err := c.fileStore()
+	if err != nil {
+		return nil, err
+	}
+	files, err := fs.ListFiles(ctx)
+	if err != nil {
+		return nil, wrapProviderError(ProviderGoogle, err)
+	}
+	return files, nil
+}
+
+func (c *Client) fileStore() (fileStore, error) {
+	pc, err := c.ensureProvider(ProviderGoogle)
+	if err != nil {
+		return nil, err
+	}
+	fs, ok := pc.(fileStore)
+	if !ok {
+		return nil, errors.New("cora: provider \"google\" does not support file uploads")
+	}
+	return fs, nil
+}
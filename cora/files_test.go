@@ -0,0 +1,77 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUploadFile_Google(t *testing.T) {
+	var uploadMIME, uploaded string
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/upload/v1beta/files"):
+			uploadMIME = r.Header.Get("X-Goog-Upload-Header-Content-Type")
+			w.Header().Set("X-Goog-Upload-Url", srv.URL+"/upload-session")
+			_, _ = w.Write([]byte("{}"))
+		case r.URL.Path == "/upload-session":
+			b, _ := io.ReadAll(r.Body)
+			uploaded = string(b)
+			w.Header().Set("X-Goog-Upload-Status", "final")
+			_ = json.NewEncoder(w).Encode(map[string]any{"file": map[string]any{
+				"name":           "files/abc-123",
+				"uri":            "https://generativelanguage.googleapis.com/v1beta/files/abc-123",
+				"mimeType":       "application/pdf",
+				"expirationTime": "2030-01-02T15:04:05Z",
+			}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	fh, err := c.UploadFile(context.Background(), "report.pdf", strings.NewReader("%PDF-1.7 big"), "application/pdf")
+	if err != nil {
+		t.Fatalf("UploadFile error: %v", err)
+	}
+	if uploadMIME != "application/pdf" {
+		t.Errorf("expected upload MIME type application/pdf, got %q", uploadMIME)
+	}
+	if uploaded != "%PDF-1.7 big" {
+		t.Errorf("unexpected uploaded body %q", uploaded)
+	}
+	if fh.Name != "files/abc-123" || !strings.HasSuffix(fh.URI, "/files/abc-123") || fh.MIMEType != "application/pdf" || fh.ExpiresAt.Year() != 2030 {
+		t.Errorf("unexpected handle %+v", fh)
+	}
+}
+
+func TestGoogleContents_FileHandles(t *testing.T) {
+	contents := googleContents(nil, "Summarize this", nil, []FileHandle{
+		{URI: "https://example.com/files/abc", MIMEType: "application/pdf"},
+	})
+	parts := contents[0].Parts
+	if len(parts) != 2 || parts[1].FileData == nil || parts[1].FileData.FileURI != "https://example.com/files/abc" ||
+		parts[1].FileData.MIMEType != "application/pdf" {
+		t.Fatalf("expected text + file part, got %+v", parts)
+	}
+}
+
+func TestText_FileHandlesRejectedByOpenAI(t *testing.T) {
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test"})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:    ProviderOpenAI,
+		Model:       "gpt-test",
+		Input:       "hi",
+		FileHandles: []FileHandle{{URI: "https://example.com/files/abc", MIMEType: "application/pdf"}},
+	})
+	if !errors.Is(err, ErrNotSupportedByProvider) {
+		t.Fatalf("expected ErrNotSupportedByProvider, got %v", err)
+	}
+}
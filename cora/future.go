@@ -0,0 +1,80 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TextFuture is the pending result of a TextAsync call.
+type TextFuture struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	resp   TextResponse
+	err    error
+}
+
+// TextAsync starts req in its own goroutine and returns immediately. Each
+// future is independent: it can be waited on, polled or cancelled without
+// affecting any other.
+func (c *Client) TextAsync(ctx context.Context, req TextRequest) *TextFuture {
+	ctx, cancel := context.WithCancel(ctx)
+	f := &TextFuture{done: make(chan struct{}), cancel: cancel}
+	go func() {
+		defer close(f.done)
+		defer cancel()
+		f.resp, f.err = c.Text(ctx, req)
+	}()
+	return f
+}
+
+// Wait blocks until the request completes and returns its result.
+func (f *TextFuture) Wait() (TextResponse, error) {
+	<-f.done
+	return f.resp, f.err
+}
+
+// WaitWithTimeout waits up to d for the request to complete. The final result
+// reports whether it did; if not, the request keeps running.
+func (f *TextFuture) WaitWithTimeout(d time.Duration) (TextResponse, error, bool) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-f.done:
+		return f.resp, f.err, true
+	case <-t.C:
+		return TextResponse{}, nil, false
+	}
+}
+
+// Cancel cancels the request's context. Wait then returns the context error
+// unless the request had already completed.
+func (f *TextFuture) Cancel() {
+	f.cancel()
+}
+
+// Done is closed when the request has completed.
+func (f *TextFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// WaitAll waits for every future and returns their responses in the same
+// order. If any failed, the error joins all failures, each prefixed with the
+// future's index.
+func WaitAll(futures ...*TextFuture) ([]TextResponse, error) {
+	out := make([]TextResponse, len(futures))
+	var errs []error
+	for i, f := range futures {
+		resp, err := f.Wait()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("future %d: %w", i, err))
+			continue
+		}
+		out[i] = resp
+	}
+	if len(errs) > 0 {
+		return out, fmt.Errorf("cora: %d of %d requests failed: %w", len(errs), len(futures), errors.Join(errs...))
+	}
+	return out, nil
+}
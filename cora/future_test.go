@@ -0,0 +1,85 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTextAsync_IndependentFutures(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = &delayedProvider{delay: 200 * time.Millisecond, text: "slow"}
+	c.google = &delayedProvider{delay: time.Millisecond, text: "fast"}
+
+	slow := c.TextAsync(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	fast := c.TextAsync(context.Background(), TextRequest{Provider: ProviderGoogle, Model: "gemini-test", Input: "hi"})
+
+	resp, err := fast.Wait()
+	if err != nil || resp.Text != "fast" {
+		t.Fatalf("fast future: %q, %v", resp.Text, err)
+	}
+	select {
+	case <-slow.Done():
+		t.Fatal("slow future completed together with the fast one")
+	default:
+	}
+	if _, _, ok := slow.WaitWithTimeout(time.Millisecond); ok {
+		t.Error("expected WaitWithTimeout to time out")
+	}
+
+	resps, err := WaitAll(slow, fast)
+	if err != nil {
+		t.Fatalf("WaitAll error: %v", err)
+	}
+	if resps[0].Text != "slow" || resps[1].Text != "fast" {
+		t.Errorf("expected responses in future order, got %q, %q", resps[0].Text, resps[1].Text)
+	}
+}
+
+func TestTextAsync_Cancel(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = &blockingProvider{release: make(chan struct{})}
+	c.google = &delayedProvider{delay: time.Millisecond, text: "ok"}
+
+	blocked := c.TextAsync(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	other := c.TextAsync(context.Background(), TextRequest{Provider: ProviderGoogle, Model: "gemini-test", Input: "hi"})
+	blocked.Cancel()
+
+	resps, err := WaitAll(blocked, other)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if resps[1].Text != "ok" {
+		t.Errorf("cancelling one future affected another: %+v", resps[1])
+	}
+}
+
+func TestTextAsync_ConcurrentProviderInit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": "ok"}}},
+		})
+	}))
+	defer srv.Close()
+
+	// The futures of a new client all create its OpenAI provider on first use.
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	futures := make([]*TextFuture, 4)
+	for i := range futures {
+		futures[i] = c.TextAsync(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	}
+	resps, err := WaitAll(futures...)
+	if err != nil {
+		t.Fatalf("WaitAll error: %v", err)
+	}
+	for i, resp := range resps {
+		if resp.Text != "ok" {
+			t.Errorf("future %d: unexpected text %q", i, resp.Text)
+		}
+	}
+}
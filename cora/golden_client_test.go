@@ -0,0 +1,51 @@
+package cora_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/oraraka-deko/cora/cora"
+	"github.com/oraraka-deko/cora/cora/cassette"
+)
+
+// NewGoldenClient returns a client that plays back the cassette
+// testdata/{name}.cassette.json and checks the n-th successful Text response
+// against testdata/{name}_{n}.golden.json.
+func NewGoldenClient(t testing.TB, name string) *cora.Client {
+	t.Helper()
+	c := cassette.Playback(name)
+
+	var mu sync.Mutex
+	var n int
+	c.Use(func(next cora.TextFunc) cora.TextFunc {
+		return func(ctx context.Context, req cora.TextRequest) (cora.TextResponse, error) {
+			resp, err := next(ctx, req)
+			if err == nil {
+				mu.Lock()
+				n++
+				golden := fmt.Sprintf("%s_%d", name, n)
+				mu.Unlock()
+				cora.AssertTextResponseGolden(t, golden, resp)
+			}
+			return resp, err
+		}
+	})
+	return c
+}
+
+func TestNewGoldenClient(t *testing.T) {
+	c := NewGoldenClient(t, "golden_basic")
+	resp, err := c.Text(context.Background(), cora.TextRequest{
+		Provider: cora.ProviderOpenAI,
+		Model:    "gpt-4o-mini",
+		Input:    "Say hello",
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "Hello!" {
+		t.Errorf("unexpected text %q", resp.Text)
+	}
+}
@@ -0,0 +1,115 @@
+package cora
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// goldenDir holds the golden files compared by AssertCallPlanGolden and
+// AssertTextResponseGolden.
+var goldenDir = "testdata"
+
+// AssertCallPlanGolden compares plan, serialized as JSON, with
+// testdata/{name}.golden.json. A missing file is written instead; set
+// UPDATE_GOLDEN=1 to rewrite existing files after an intended change.
+// Tool handlers are recorded by name.
+func AssertCallPlanGolden(t testing.TB, name string, plan callPlan) {
+	t.Helper()
+	assertGolden(t, name, newGoldenPlan(plan))
+}
+
+// AssertTextResponseGolden is AssertCallPlanGolden for a TextResponse.
+func AssertTextResponseGolden(t testing.TB, name string, resp TextResponse) {
+	t.Helper()
+	resp.Latency = 0 // varies from run to run
+	assertGolden(t, name, resp)
+}
+
+func assertGolden(t testing.TB, name string, v any) {
+	t.Helper()
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("golden %s: marshal: %v", name, err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join(goldenDir, name+".golden.json")
+	want, err := os.ReadFile(path)
+	if os.Getenv("UPDATE_GOLDEN") == "1" || errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(goldenDir, 0o755); err != nil {
+			t.Fatalf("golden %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("golden %s: %v", name, err)
+		}
+		t.Logf("golden %s: wrote %s", name, path)
+		return
+	}
+	if err != nil {
+		t.Fatalf("golden %s: %v", name, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("golden %s: mismatch with %s (run with UPDATE_GOLDEN=1 to update)\n--- want\n%s--- got\n%s", name, path, want, got)
+	}
+}
+
+// goldenPlan is the JSON form of a callPlan: functions cannot be marshaled,
+// so handlers are replaced by their sorted names.
+type goldenPlan struct {
+	callPlan
+	ToolHandlers      []string     `json:",omitempty"`
+	StreamingHandlers []string     `json:",omitempty"`
+	ToolRetryConfig   *goldenRetry `json:",omitempty"`
+	ToolObserver      bool         `json:",omitempty"`
+
+	DynamicSystemPrompt bool `json:",omitempty"`
+}
+
+type goldenRetry struct {
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+}
+
+func newGoldenPlan(plan callPlan) goldenPlan {
+	g := goldenPlan{callPlan: plan, ToolObserver: plan.ToolObserver != nil, DynamicSystemPrompt: plan.DynamicSystemPrompt != nil}
+	for name := range plan.ToolHandlers {
+		g.ToolHandlers = append(g.ToolHandlers, name)
+	}
+	for name := range plan.StreamingHandlers {
+		g.StreamingHandlers = append(g.StreamingHandlers, name)
+	}
+	slices.Sort(g.ToolHandlers)
+	slices.Sort(g.StreamingHandlers)
+	if rc := plan.ToolRetryConfig; rc != nil {
+		g.ToolRetryConfig = &goldenRetry{rc.MaxAttempts, rc.InitialBackoff, rc.MaxBackoff, rc.BackoffMultiplier}
+	}
+	return g
+}
+
+func TestBuildPlans_Golden(t *testing.T) {
+	temp := float32(0.2)
+	plans, err := buildPlans(ProviderOpenAI, "gpt-4o-mini", TextRequest{
+		System:      "You are a weather bot.",
+		Input:       "Weather in Paris?",
+		Mode:        ModeToolCalling,
+		Temperature: &temp,
+		Tools: []CoraTool{{
+			Name:             "get_weather",
+			Description:      "Current weather for a city",
+			ParametersSchema: map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}},
+		}},
+		ToolHandlers: map[string]CoraToolHandler{"get_weather": nil},
+	}, CoraConfig{}, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	AssertCallPlanGolden(t, "tool_calling_plan", plans[0])
+}
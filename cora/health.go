@@ -0,0 +1,155 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// ProviderHealthEvent is the outcome of one health check (see
+// Client.StartHealthMonitor).
+type ProviderHealthEvent struct {
+	Provider  Provider
+	Healthy   bool
+	Latency   time.Duration
+	Err       error
+	Timestamp time.Time
+}
+
+// pinger is implemented by providers that can check they are reachable
+// without generating anything.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks that provider is reachable and accepts the configured
+// credentials, by listing its models. With OpenAIEndpoints, OpenAI's check
+// goes to DefaultOpenAIEndpoint. Bedrock returns ErrNotSupportedByProvider.
+func (c *Client) Ping(ctx context.Context, provider Provider) error {
+	pg, err := c.pinger(provider)
+	if err != nil {
+		return err
+	}
+	if err := pg.Ping(ctx); err != nil {
+		return wrapProviderError(provider, err)
+	}
+	return nil
+}
+
+func (c *Client) pinger(provider Provider) (pinger, error) {
+	pc, err := c.providerFor(provider, "")
+	if err != nil {
+		return nil, err
+	}
+	pg, ok := pc.(pinger)
+	if !ok {
+		return nil, fmt.Errorf("%w: provider %q cannot be pinged", ErrNotSupportedByProvider, provider)
+	}
+	return pg, nil
+}
+
+// StartHealthMonitor pings every configured provider that supports Ping now
+// and then every interval, sending the results on the returned channel. The
+// latest result per provider is also available from ProviderHealthStatus.
+// The monitor stops and closes the channel when ctx is done, so the channel
+// must be drained until then. interval must be positive.
+func (c *Client) StartHealthMonitor(ctx context.Context, interval time.Duration) (<-chan ProviderHealthEvent, error) {
+	if interval <= 0 {
+		return nil, errors.New("cora: health monitor interval must be positive")
+	}
+	// Providers are resolved up front so the monitor goroutine never
+	// initializes them concurrently with other calls.
+	pingers := make(map[Provider]pinger)
+	var providers []Provider
+	for _, p := range KnownProviders() {
+		if pg, err := c.pinger(p); err == nil {
+			pingers[p] = pg
+			providers = append(providers, p)
+		}
+	}
+
+	events := make(chan ProviderHealthEvent, max(len(providers), 1))
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			for _, p := range providers {
+				ev := c.checkHealth(ctx, p, pingers[p])
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case events <- ev:
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return events, nil
+}
+
+// checkHealth pings p once and records the result.
+func (c *Client) checkHealth(ctx context.Context, p Provider, pg pinger) ProviderHealthEvent {
+	start := time.Now()
+	err := pg.Ping(ctx)
+	if err != nil {
+		err = wrapProviderError(p, err)
+	}
+	ev := ProviderHealthEvent{Provider: p, Healthy: err == nil, Latency: time.Since(start), Err: err, Timestamp: time.Now()}
+
+	c.healthMu.Lock()
+	if c.health == nil {
+		c.health = make(map[Provider]bool)
+	}
+	c.health[p] = ev.Healthy
+	c.healthMu.Unlock()
+	return ev
+}
+
+// ProviderHealthStatus returns the latest health check result of each
+// provider checked by StartHealthMonitor.
+func (c *Client) ProviderHealthStatus() map[Provider]bool {
+	c.healthMu.RLock()
+	defer c.healthMu.RUnlock()
+	return maps.Clone(c.health)
+}
+
+func (p *openAIProvider) Ping(ctx context.Context) error {
+	_, err := p.client.ListModels(ctx)
+	return err
+}
+
+func (p *googleProvider) Ping(ctx context.Context) error {
+	_, err := p.client.Models.List(ctx, &genai.ListModelsConfig{PageSize: 1})
+	return err
+}
+
+func (p *cohereProvider) Ping(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/models?page_size=1", nil)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	httpResp, err := p.http.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	httpResp.Body.Close()
+	if httpResp.StatusCode/100 != 2 {
+		return newHTTPCoraError(ProviderCohere, httpResp.StatusCode, http.StatusText(httpResp.StatusCode), nil)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyPinger is a provider whose pings alternate between healthy and
+// unhealthy, starting healthy.
+type flakyPinger struct {
+	fakeProvider
+	pings atomic.Int32
+}
+
+func (p *flakyPinger) Ping(ctx context.Context) error {
+	if p.pings.Add(1)%2 == 0 {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestStartHealthMonitor(t *testing.T) {
+	c := &Client{openai: &flakyPinger{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := c.StartHealthMonitor(ctx, time.Millisecond)
+	if err != nil {
+		t.Fatalf("StartHealthMonitor error: %v", err)
+	}
+
+	var got []bool
+	for ev := range events {
+		if ev.Provider != ProviderOpenAI || ev.Timestamp.IsZero() {
+			t.Errorf("unexpected event %+v", ev)
+		}
+		if ev.Healthy != (ev.Err == nil) {
+			t.Errorf("Healthy = %v with Err = %v", ev.Healthy, ev.Err)
+		}
+		got = append(got, ev.Healthy)
+		if status := c.ProviderHealthStatus(); status[ProviderOpenAI] != ev.Healthy {
+			t.Errorf("ProviderHealthStatus = %v, want %v", status, ev.Healthy)
+		}
+		if len(got) == 4 {
+			cancel()
+		}
+	}
+	if len(got) < 4 || !got[0] || got[1] || !got[2] || got[3] {
+		t.Errorf("expected alternating healthy/unhealthy events, got %v", got)
+	}
+}
+
+func TestStartHealthMonitor_Interval(t *testing.T) {
+	c := &Client{openai: &flakyPinger{}}
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if events, err := c.StartHealthMonitor(context.Background(), interval); err == nil || events != nil {
+			t.Errorf("interval %v: expected an error, got %v", interval, err)
+		}
+	}
+}
+
+func TestPing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"invalid api token"}`))
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{CohereAPIKey: "bad", CohereBaseURL: srv.URL, BedrockRegion: "us-east-1"})
+	var ce *CoraError
+	if err := c.Ping(context.Background(), ProviderCohere); !errors.As(err, &ce) || ce.HTTPStatusCode != http.StatusUnauthorized {
+		t.Errorf("expected a 401 CoraError, got %v", err)
+	}
+	if err := c.Ping(context.Background(), ProviderBedrock); !errors.Is(err, ErrNotSupportedByProvider) {
+		t.Errorf("expected ErrNotSupportedByProvider for Bedrock, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// retryTransport retries provider requests that fail with a transient network error
+// or are answered with HTTP 429 or 503 (see CoraConfig.HTTPRetryConfig).
+// With respectRetryAfter it waits as long as the response's Retry-After
+// header asks, capped at config.MaxBackoff, instead of the configured
+// backoff. Each retry is logged at DEBUG level to logger, if set.
+type retryTransport struct {
+	base              http.RoundTripper
+	config            RetryConfig
+	respectRetryAfter bool
+	logger            *slog.Logger
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if !retryableHTTP(ctx, resp, err) || attempt >= t.config.MaxAttempts-1 {
+			return resp, err
+		}
+		// A body that cannot be replayed cannot be retried.
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, err
+		}
+
+		wait := calculateBackoffWithJitter(attempt, t.config)
+		if resp != nil && t.respectRetryAfter {
+			if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				wait = d
+				if t.config.MaxBackoff > 0 {
+					wait = min(wait, t.config.MaxBackoff)
+				}
+			}
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait+t.config.MinDeadlineBuffer {
+			return resp, err
+		}
+
+		cause := err
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+			cause = fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+		cause = fmt.Errorf("cora: %s %s: %w", req.Method, req.URL.Redacted(), cause)
+		if t.logger != nil {
+			t.logger.LogAttrs(ctx, slog.LevelDebug, "cora: retrying HTTP request",
+				slog.Int("attempt", attempt+1),
+				slog.Duration("wait", wait),
+				slog.Any("error", cause),
+			)
+		}
+		if t.config.OnRetry != nil {
+			t.config.OnRetry(attempt+1, cause)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+	}
+}
+
+// retryableHTTP reports whether a round trip's outcome is worth retrying: a
+// transient network error while ctx is still live, or a 429 or 503 response.
+func retryableHTTP(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil && transientNetError(err)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+}
+
+// transientNetError reports whether err is a network failure that may not
+// recur: a timeout, a refused or reset connection, or a connection closed
+// before the response was complete. Errors of the request itself, such as
+// ErrRequestTooLarge, a RequestSigner failure, a bad URL or an untrusted
+// certificate, fail the same way every time.
+func transientNetError(err error) bool {
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// parseRetryAfter parses a Retry-After header value, either a number of
+// seconds or an HTTP date, into the wait from now.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0), true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,159 @@
+package cora
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestHTTPRetry_RetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, `{"error":{"message":"slow down"}}`, http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	retry := RetryConfig{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 5 * time.Second, BackoffMultiplier: 2}
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL, HTTPRetryConfig: &retry, RespectRetryAfter: true})
+	start := time.Now()
+	resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "ok" || calls.Load() != 2 {
+		t.Fatalf("got %q after %d calls", resp.Text, calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", elapsed)
+	}
+}
+
+func TestHTTPRetry_TransientFailures(t *testing.T) {
+	// The first request gets a 503, the second a dropped connection.
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			http.Error(w, `{"error":{"message":"overloaded"}}`, http.StatusServiceUnavailable)
+		case 2:
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	retry := RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, BackoffMultiplier: 2}
+	c := New(CoraConfig{
+		OpenAIAPIKey:    "sk-test",
+		OpenAIBaseURL:   srv.URL,
+		HTTPRetryConfig: &retry,
+		DebugLogger:     slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+	resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "ok" || calls.Load() != 3 {
+		t.Fatalf("got %q after %d calls", resp.Text, calls.Load())
+	}
+	if n := strings.Count(logs.String(), "cora: retrying HTTP request"); n != 2 {
+		t.Errorf("logged %d retries, want 2:\n%s", n, logs.String())
+	}
+	if !strings.Contains(logs.String(), "HTTP 503") {
+		t.Errorf("retry log does not mention the 503:\n%s", logs.String())
+	}
+}
+
+func TestHTTPRetry_PermanentFailures(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	var retries atomic.Int32
+	retry := RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, BackoffMultiplier: 2,
+		OnRetry: func(int, error) { retries.Add(1) }}
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL, HTTPRetryConfig: &retry, MaxRequestBodyBytes: 10})
+	_, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "a prompt larger than the limit"})
+	if !errors.Is(err, ErrRequestTooLarge) {
+		t.Fatalf("got %v, want ErrRequestTooLarge", err)
+	}
+	if n := retries.Load(); n != 0 {
+		t.Errorf("retried an oversized request %d times", n)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("oversized request reached the server %d times", n)
+	}
+}
+
+func TestTransientNetError(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want bool
+	}{
+		{io.ErrUnexpectedEOF, true},
+		{&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{&url.Error{Op: "Post", Err: io.EOF}, true},
+		{ErrRequestTooLarge, false},
+		{errors.New("signing failed"), false},
+		{&url.Error{Op: "Post", Err: errors.New("unsupported protocol scheme")}, false},
+	} {
+		if got := transientNetError(tt.err); got != tt.want {
+			t.Errorf("transientNetError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"5", 5 * time.Second, true},
+		{"Wed, 01 Jan 2025 12:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 01 Jan 2025 11:00:00 GMT", 0, true},
+		{"", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.in, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIsRetryable_RateLimited(t *testing.T) {
+	if !isRetryable(newHTTPCoraError(ProviderOpenAI, http.StatusTooManyRequests, "slow down", nil), nil) {
+		t.Error("a 429 CoraError should be retryable by default")
+	}
+	if isRetryable(newHTTPCoraError(ProviderOpenAI, http.StatusBadRequest, "bad", nil), nil) {
+		t.Error("a 400 CoraError should not be retryable")
+	}
+}
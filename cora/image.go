@@ -0,0 +1,70 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ImageRequest asks a provider to generate images from Prompt.
+type ImageRequest struct {
+	Provider Provider
+	Model    string // e.g. "dall-e-3", "imagen-3.0-generate-002"
+	Prompt   string
+
+	// Width and Height select the image size (OpenAI) or aspect ratio (Google).
+	// Zero uses the provider default.
+	Width, Height int
+	Quality       string // e.g. "standard" or "hd" for DALL-E 3
+	N             int    // number of images; 0 means 1
+}
+
+// ImageResponse holds the generated images. Depending on the provider and
+// model they are returned as URLs, as raw bytes in Data, or both.
+type ImageResponse struct {
+	Provider Provider
+	Model    string
+
+	URLs []string
+	Data [][]byte
+
+	// RevisedPrompt is the prompt the provider actually used, if it rewrote it.
+	RevisedPrompt string
+}
+
+// imageGenerator is implemented by providers that support image generation.
+type imageGenerator interface {
+	GenerateImage(ctx context.Context, req ImageRequest) (ImageResponse, error)
+}
+
+// GenerateImage creates images for req.Prompt using the requested provider/model.
+func (c *Client) GenerateImage(ctx context.Context, req ImageRequest) (ImageResponse, error) {
+	if !req.Provider.Valid() {
+		return ImageResponse{}, unknownProviderError(req.Provider)
+	}
+	if req.Model == "" {
+		return ImageResponse{}, errors.New("cora: model must be specified")
+	}
+	if req.Prompt == "" {
+		return ImageResponse{}, errors.New("cora: Prompt must not be empty")
+	}
+	if req.Width < 0 || req.Height < 0 || req.N < 0 {
+		return ImageResponse{}, errors.New("cora: Width, Height and N must not be negative")
+	}
+
+	pc, err := c.providerFor(req.Provider, req.Model)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+	g, ok := pc.(imageGenerator)
+	if !ok {
+		return ImageResponse{}, fmt.Errorf("cora: provider %q does not support image generation", req.Provider)
+	}
+	resp, err := g.GenerateImage(ctx, req)
+	if err != nil {
+		return ImageResponse{}, wrapProviderError(req.Provider, err)
+	}
+	resp.Provider = req.Provider
+	resp.Model = req.Model
+	return resp, nil
+}
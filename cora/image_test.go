@@ -0,0 +1,62 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGenerateImage_OpenAI(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/images/generations" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"created": 1,
+			"data": []map[string]any{{
+				"url":            "https://images.example.com/cat.png",
+				"revised_prompt": "a fluffy cat",
+			}},
+		})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	resp, err := c.GenerateImage(context.Background(), ImageRequest{
+		Provider: ProviderOpenAI,
+		Model:    "dall-e-3",
+		Prompt:   "a cat",
+		Width:    1024,
+		Height:   1792,
+		Quality:  "hd",
+	})
+	if err != nil {
+		t.Fatalf("GenerateImage error: %v", err)
+	}
+	if len(resp.URLs) != 1 || resp.URLs[0] != "https://images.example.com/cat.png" {
+		t.Errorf("unexpected URLs %v", resp.URLs)
+	}
+	if resp.RevisedPrompt != "a fluffy cat" {
+		t.Errorf("unexpected revised prompt %q", resp.RevisedPrompt)
+	}
+	if got["size"] != "1024x1792" || got["quality"] != "hd" || got["model"] != "dall-e-3" {
+		t.Errorf("unexpected request body %v", got)
+	}
+}
+
+func TestAspectRatio(t *testing.T) {
+	for _, tc := range []struct {
+		w, h int
+		want string
+	}{{1024, 1024, "1:1"}, {1920, 1080, "16:9"}, {768, 1024, "3:4"}} {
+		if got := aspectRatio(tc.w, tc.h); got != tc.want {
+			t.Errorf("aspectRatio(%d, %d) = %q, want %q", tc.w, tc.h, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package cora
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// defaultLiveAudioMIMEType is the input format the Live API expects: 16-bit
+// little-endian PCM at 16kHz.
+const defaultLiveAudioMIMEType = "audio/pcm;rate=16000"
+
+// LiveSessionConfig configures a Gemini Live API session (see
+// Client.LiveSession).
+type LiveSessionConfig struct {
+	Model       string
+	System      string
+	Temperature *float32
+
+	// AudioOutput makes the model answer with audio (LiveEventAudio) rather
+	// than text. Voice optionally names a prebuilt voice, e.g. "Puck".
+	AudioOutput bool
+	Voice       string
+
+	// AudioMIMEType is the format of the data given to SendAudio; it
+	// defaults to 16kHz 16-bit PCM ("audio/pcm;rate=16000").
+	AudioMIMEType string
+
+	// Tools the model may call. Calls to tools with a handler are run and
+	// answered automatically; others are only reported as LiveEventToolCall
+	// and must be answered with SendToolResult.
+	Tools        []CoraTool
+	ToolHandlers map[string]CoraToolHandler
+}
+
+// LiveEventType identifies a LiveEvent.
+type LiveEventType int
+
+const (
+	// LiveEventText is a chunk of the model's text answer.
+	LiveEventText LiveEventType = iota
+	// LiveEventAudio is a chunk of the model's audio answer.
+	LiveEventAudio
+	// LiveEventToolCall is a tool call requested by the model.
+	LiveEventToolCall
+	// LiveEventTurnComplete marks the end of the model's turn.
+	LiveEventTurnComplete
+	// LiveEventInterrupted reports that the user's input interrupted the
+	// model's turn; audio not yet played should be dropped.
+	LiveEventInterrupted
+	// LiveEventError is sent before Events closes on a session failure.
+	LiveEventError
+)
+
+// LiveEvent is one event of a live session.
+type LiveEvent struct {
+	Type LiveEventType
+
+	Text string // LiveEventText
+
+	// Audio and its AudioMIMEType (e.g. "audio/pcm;rate=24000") for
+	// LiveEventAudio.
+	Audio         []byte
+	AudioMIMEType string
+
+	ToolCall *StreamToolCall // LiveEventToolCall
+
+	Err error // LiveEventError
+
+	Timestamp time.Time
+}
+
+// LiveSession is an open Gemini Live API session: a bidirectional stream
+// over which the model answers text and audio input as it arrives. Events
+// must be drained until the session ends.
+type LiveSession struct {
+	session   *genai.Session
+	handlers  map[string]CoraToolHandler
+	audioMIME string
+	events    chan LiveEvent
+
+	// writeMu serializes writes to the connection, which allows one writer.
+	writeMu   sync.Mutex
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// LiveSession opens a Gemini Live API session. The session ends when ctx is
+// done or Close is called.
+func (c *Client) LiveSession(ctx context.Context, cfg LiveSessionConfig) (*LiveSession, error) {
+	if cfg.Model == "" {
+		return nil, errors.New("cora: model must be specified")
+	}
+	pc, err := c.ensureProvider(ProviderGoogle)
+	if err != nil {
+		return nil, err
+	}
+	gp, ok := pc.(*googleProvider)
+	if !ok {
+		return nil, errors.New("cora: live sessions require the Google provider")
+	}
+
+	connectCfg := &genai.LiveConnectConfig{
+		Temperature:        cfg.Temperature,
+		ResponseModalities: []genai.Modality{genai.ModalityText},
+		Tools:              toGenAITools(cfg.Tools),
+	}
+	if cfg.System != "" {
+		connectCfg.SystemInstruction = genai.NewContentFromText(cfg.System, genai.RoleUser)
+	}
+	if cfg.AudioOutput {
+		connectCfg.ResponseModalities = []genai.Modality{genai.ModalityAudio}
+		if cfg.Voice != "" {
+			connectCfg.SpeechConfig = &genai.SpeechConfig{VoiceConfig: &genai.VoiceConfig{
+				PrebuiltVoiceConfig: &genai.PrebuiltVoiceConfig{VoiceName: cfg.Voice},
+			}}
+		}
+	}
+
+	session, err := gp.client.Live.Connect(ctx, cfg.Model, connectCfg)
+	if err != nil {
+		return nil, wrapProviderError(ProviderGoogle, err)
+	}
+	s := &LiveSession{
+		session:   session,
+		handlers:  cfg.ToolHandlers,
+		audioMIME: cmp.Or(cfg.AudioMIMEType, defaultLiveAudioMIMEType),
+		events:    make(chan LiveEvent, 100),
+		done:      make(chan struct{}),
+	}
+	stop := context.AfterFunc(ctx, func() { s.Close() })
+	go func() {
+		defer stop()
+		s.receive(ctx)
+	}()
+	return s, nil
+}
+
+// Events returns the session's events. The channel closes when the session
+// ends.
+func (s *LiveSession) Events() <-chan LiveEvent { return s.events }
+
+// SendText sends input as a complete user turn.
+func (s *LiveSession) SendText(input string) error {
+	return s.write(func() error {
+		return s.session.SendClientContent(genai.LiveClientContentInput{
+			Turns: []*genai.Content{genai.NewContentFromText(input, genai.RoleUser)},
+		})
+	})
+}
+
+// SendAudio streams a chunk of audio in LiveSessionConfig.AudioMIMEType. The
+// model detects the end of speech itself.
+func (s *LiveSession) SendAudio(data []byte) error {
+	return s.write(func() error {
+		return s.session.SendRealtimeInput(genai.LiveRealtimeInput{
+			Audio: &genai.Blob{Data: data, MIMEType: s.audioMIME},
+		})
+	})
+}
+
+// SendToolResult answers a LiveEventToolCall for a tool without a handler.
+func (s *LiveSession) SendToolResult(toolCallID, name string, result any) error {
+	return s.write(func() error {
+		return s.session.SendToolResponse(genai.LiveToolResponseInput{
+			FunctionResponses: []*genai.FunctionResponse{{ID: toolCallID, Name: name, Response: functionResponsePayload(result)}},
+		})
+	})
+}
+
+// Close ends the session. Events closes once the receiving side stops.
+func (s *LiveSession) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.writeMu.Lock()
+		defer s.writeMu.Unlock()
+		s.closeErr = s.session.Close()
+	})
+	return s.closeErr
+}
+
+func (s *LiveSession) write(send func() error) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	select {
+	case <-s.done:
+		return errors.New("cora: live session is closed")
+	default:
+	}
+	if err := send(); err != nil {
+		return wrapProviderError(ProviderGoogle, err)
+	}
+	return nil
+}
+
+// receive translates server messages into events until the connection ends.
+func (s *LiveSession) receive(ctx context.Context) {
+	defer close(s.events)
+	for {
+		msg, err := s.session.Receive()
+		if err != nil {
+			select {
+			case <-s.done:
+			default:
+				s.emit(LiveEvent{Type: LiveEventError, Err: wrapProviderError(ProviderGoogle, err)})
+				s.Close()
+			}
+			return
+		}
+		if sc := msg.ServerContent; sc != nil {
+			if sc.ModelTurn != nil {
+				for _, part := range sc.ModelTurn.Parts {
+					switch {
+					case part.Text != "":
+						s.emit(LiveEvent{Type: LiveEventText, Text: part.Text})
+					case part.InlineData != nil:
+						s.emit(LiveEvent{Type: LiveEventAudio, Audio: part.InlineData.Data, AudioMIMEType: part.InlineData.MIMEType})
+					}
+				}
+			}
+			if sc.Interrupted {
+				s.emit(LiveEvent{Type: LiveEventInterrupted})
+			}
+			if sc.TurnComplete {
+				s.emit(LiveEvent{Type: LiveEventTurnComplete})
+			}
+		}
+		if tc := msg.ToolCall; tc != nil {
+			s.handleToolCalls(ctx, tc.FunctionCalls)
+		}
+	}
+}
+
+// handleToolCalls reports each call and answers those with a handler.
+func (s *LiveSession) handleToolCalls(ctx context.Context, calls []*genai.FunctionCall) {
+	var responses []*genai.FunctionResponse
+	for _, fc := range calls {
+		s.emit(LiveEvent{Type: LiveEventToolCall, ToolCall: &StreamToolCall{ID: fc.ID, Name: fc.Name, Arguments: fc.Args}})
+		handler, ok := s.handlers[fc.Name]
+		if !ok {
+			continue
+		}
+		result, err := handler(ctx, fc.Args)
+		if err != nil {
+			result = map[string]any{"error": fmt.Sprintf("tool %s failed: %v", fc.Name, err)}
+		}
+		responses = append(responses, &genai.FunctionResponse{ID: fc.ID, Name: fc.Name, Response: functionResponsePayload(result)})
+	}
+	if len(responses) == 0 {
+		return
+	}
+	err := s.write(func() error {
+		return s.session.SendToolResponse(genai.LiveToolResponseInput{FunctionResponses: responses})
+	})
+	if err != nil {
+		s.emit(LiveEvent{Type: LiveEventError, Err: err})
+	}
+}
+
+// emit sends ev unless the session has been closed.
+func (s *LiveSession) emit(ev LiveEvent) {
+	ev.Timestamp = time.Now()
+	select {
+	case s.events <- ev:
+	case <-s.done:
+	}
+}
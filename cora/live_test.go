@@ -0,0 +1,147 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// liveServer is a fake Live API endpoint. It records the client messages
+// and answers a user turn with text, audio and a tool call, then completes
+// the turn once the tool response arrives.
+func liveServer(t *testing.T) (*httptest.Server, chan map[string]any) {
+	t.Helper()
+	received := make(chan map[string]any, 10)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "BidiGenerateContent") || r.Header.Get("x-goog-api-key") != "g-test" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+		send := func(msg map[string]any) {
+			b, _ := json.Marshal(msg)
+			_ = conn.WriteMessage(websocket.TextMessage, b)
+		}
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var msg map[string]any
+			_ = json.Unmarshal(data, &msg)
+			received <- msg
+			switch {
+			case msg["setup"] != nil:
+				send(map[string]any{"setupComplete": map[string]any{}})
+			case msg["clientContent"] != nil:
+				send(map[string]any{"serverContent": map[string]any{"modelTurn": map[string]any{"parts": []map[string]any{
+					{"text": "Checking"},
+					{"inlineData": map[string]any{"mimeType": "audio/pcm;rate=24000", "data": "AQID"}},
+				}}}})
+				send(map[string]any{"toolCall": map[string]any{"functionCalls": []map[string]any{
+					{"id": "call-1", "name": "lookup", "args": map[string]any{"q": "weather"}},
+				}}})
+			case msg["toolResponse"] != nil:
+				send(map[string]any{"serverContent": map[string]any{"turnComplete": true}})
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, received
+}
+
+func TestLiveSession(t *testing.T) {
+	srv, received := liveServer(t)
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: "ws://" + strings.TrimPrefix(srv.URL, "http://")})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s, err := c.LiveSession(ctx, LiveSessionConfig{
+		Model:  "gemini-live-test",
+		System: "Be brief.",
+		Tools:  []CoraTool{{Name: "lookup", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{"lookup": func(ctx context.Context, args map[string]any) (any, error) {
+			return map[string]any{"answer": "sunny"}, nil
+		}},
+	})
+	if err != nil {
+		t.Fatalf("LiveSession error: %v", err)
+	}
+	defer s.Close()
+	setup := <-received
+	if setup["setup"].(map[string]any)["model"] != "models/gemini-live-test" {
+		t.Errorf("unexpected setup %v", setup)
+	}
+
+	if err := s.SendText("Weather?"); err != nil {
+		t.Fatalf("SendText error: %v", err)
+	}
+	var types []LiveEventType
+	for ev := range s.Events() {
+		types = append(types, ev.Type)
+		switch ev.Type {
+		case LiveEventText:
+			if ev.Text != "Checking" {
+				t.Errorf("unexpected text %q", ev.Text)
+			}
+		case LiveEventAudio:
+			if string(ev.Audio) != "\x01\x02\x03" || ev.AudioMIMEType != "audio/pcm;rate=24000" {
+				t.Errorf("unexpected audio %v (%s)", ev.Audio, ev.AudioMIMEType)
+			}
+		case LiveEventToolCall:
+			if ev.ToolCall.ID != "call-1" || ev.ToolCall.Arguments["q"] != "weather" {
+				t.Errorf("unexpected tool call %+v", ev.ToolCall)
+			}
+		case LiveEventError:
+			t.Fatalf("unexpected error event: %v", ev.Err)
+		}
+		if ev.Type == LiveEventTurnComplete {
+			break
+		}
+	}
+	want := []LiveEventType{LiveEventText, LiveEventAudio, LiveEventToolCall, LiveEventTurnComplete}
+	if len(types) != len(want) {
+		t.Fatalf("event types = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("event types = %v, want %v", types, want)
+			break
+		}
+	}
+
+	<-received // the user turn
+	resp := (<-received)["toolResponse"].(map[string]any)
+	fr := resp["functionResponses"].([]any)[0].(map[string]any)
+	if fr["id"] != "call-1" || fr["response"].(map[string]any)["answer"] != "sunny" {
+		t.Errorf("unexpected tool response %v", fr)
+	}
+
+	if err := s.SendAudio([]byte{0, 1}); err != nil {
+		t.Fatalf("SendAudio error: %v", err)
+	}
+	audio := (<-received)["realtimeInput"].(map[string]any)["audio"].(map[string]any)
+	if audio["mimeType"] != defaultLiveAudioMIMEType {
+		t.Errorf("unexpected realtime input %v", audio)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("Close error: %v", err)
+	}
+	for range s.Events() {
+	}
+	if err := s.SendText("again"); err == nil {
+		t.Error("expected an error sending on a closed session")
+	}
+}
@@ -0,0 +1,83 @@
+package cora
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithLogger sets the logger that receives structured entries for every
+// Text() and Stream() call, and returns c for chaining:
+//   - DEBUG when a request starts,
+//   - INFO when it completes, with token counts,
+//   - WARN when it is retried (fallback provider, validation or tool retry),
+//   - ERROR when it finally fails.
+//
+// Entries carry provider, model, mode and, on completion, latency_ms,
+// prompt_tokens and completion_tokens attributes.
+func (c *Client) WithLogger(logger *slog.Logger) *Client {
+	c.logger = logger
+	return c
+}
+
+func (c *Client) logStart(ctx context.Context, provider Provider, model string, mode TextMode) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "cora: request started", requestAttrs(provider, model, mode)...)
+}
+
+func (c *Client) logDone(ctx context.Context, provider Provider, model string, mode TextMode, start time.Time, promptTokens, completionTokens int, err error) {
+	if c.logger == nil {
+		return
+	}
+	attrs := append(requestAttrs(provider, model, mode),
+		slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+		slog.Int("prompt_tokens", promptTokens),
+		slog.Int("completion_tokens", completionTokens),
+	)
+	if err != nil {
+		c.logger.LogAttrs(ctx, slog.LevelError, "cora: request failed", append(attrs, slog.Any("error", err))...)
+		return
+	}
+	c.logger.LogAttrs(ctx, slog.LevelInfo, "cora: request completed", attrs...)
+}
+
+func (c *Client) logRetry(ctx context.Context, reason string, provider Provider, model string, mode TextMode, err error) {
+	if c.logger == nil {
+		return
+	}
+	attrs := append(requestAttrs(provider, model, mode), slog.String("reason", reason), slog.Any("error", err))
+	c.logger.LogAttrs(ctx, slog.LevelWarn, "cora: retrying request", attrs...)
+}
+
+// logToolRetries hooks the plans' tool retry configs so that each tool retry
+// is logged. The configs are copied; the caller's are left untouched.
+func (c *Client) logToolRetries(ctx context.Context, plans []callPlan, mode TextMode) {
+	if c.logger == nil {
+		return
+	}
+	for i := range plans {
+		rc := plans[i].ToolRetryConfig
+		if rc == nil {
+			continue
+		}
+		cp := *rc
+		next, p := rc.OnRetry, plans[i]
+		cp.OnRetry = func(attempt int, err error) {
+			c.logRetry(ctx, "tool", p.Provider, p.Model, mode, err)
+			if next != nil {
+				next(attempt, err)
+			}
+		}
+		plans[i].ToolRetryConfig = &cp
+	}
+}
+
+func requestAttrs(provider Provider, model string, mode TextMode) []slog.Attr {
+	return []slog.Attr{
+		slog.String("provider", string(provider)),
+		slog.String("model", model),
+		slog.String("mode", mode.String()),
+	}
+}
@@ -0,0 +1,79 @@
+package cora
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// usageProvider answers with fixed token counts.
+type usageProvider struct{}
+
+func (usageProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	prompt, completion, total := 12, 7, 19
+	return callResult{Text: "ok", PromptTokens: &prompt, CompletionTokens: &completion, TotalTokens: &total}, nil
+}
+
+func newLoggedClient(buf *bytes.Buffer) *Client {
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return (&Client{cfg: CoraConfig{}}).WithLogger(logger)
+}
+
+func TestWithLogger_Text(t *testing.T) {
+	var buf bytes.Buffer
+	c := newLoggedClient(&buf)
+	c.openai = usageProvider{}
+
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"}); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"level=DEBUG", "level=INFO",
+		"provider=openai", "model=gpt-test", "mode=basic",
+		"latency_ms=", "prompt_tokens=12", "completion_tokens=7",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWithLogger_RetryAndFailure(t *testing.T) {
+	var buf bytes.Buffer
+	c := newLoggedClient(&buf)
+	c.openai = &failingProvider{err: &openai.APIError{HTTPStatusCode: 503, Message: "unavailable"}}
+	c.google = &failingProvider{err: errors.New("boom")}
+
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:          ProviderOpenAI,
+		Model:             "gpt-test",
+		Input:             "hi",
+		FallbackProviders: []Provider{ProviderGoogle},
+		FallbackModels:    []string{"gemini-test"},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	out := buf.String()
+	for _, want := range []string{"level=WARN", "reason=fallback", "provider=google", "level=ERROR", "error="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWithLogger_Nil(t *testing.T) {
+	c := (&Client{cfg: CoraConfig{}}).WithLogger(nil)
+	c.openai = usageProvider{}
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"}); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+// Package mcp serves the tools of a cora.ToolBuilder over the Model Context
+// Protocol, so MCP clients such as Claude Desktop can discover and call them.
+//
+// Serve speaks newline-delimited JSON-RPC over a stream such as stdio:
+//
+//	tb := cora.NewToolBuilder()
+//	_ = tb.AddFunc("get_weather", "Current weather for a city", getWeather)
+//	err := mcp.NewMCPServer(tb).Serve(ctx, stdio)
+//
+// The server is also an http.Handler taking one JSON-RPC message per POST.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/oraraka-deko/cora/cora"
+)
+
+// ProtocolVersion is the MCP revision the server implements.
+const ProtocolVersion = "2025-06-18"
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// MCPServer answers MCP requests with the tools registered in a
+// cora.ToolBuilder: tools/list lists them and tools/call runs their handlers.
+type MCPServer struct {
+	// Name and Version identify the server in the initialize response
+	// (default: "cora", "1.0.0").
+	Name    string
+	Version string
+
+	tools    []cora.CoraTool
+	handlers map[string]cora.CoraToolHandler
+}
+
+// NewMCPServer returns a server for the tools registered in registry so far.
+func NewMCPServer(registry *cora.ToolBuilder) *MCPServer {
+	tools, handlers := registry.Build()
+	return &MCPServer{Name: "cora", Version: "1.0.0", tools: tools, handlers: handlers}
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads JSON-RPC messages, one per line, from rw and writes the
+// responses back, until rw reaches EOF or ctx is done. Messages are handled
+// in order; ctx is checked between them.
+func (s *MCPServer) Serve(ctx context.Context, rw io.ReadWriter) error {
+	scanner := bufio.NewScanner(rw)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(rw)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		resp := s.handle(ctx, line)
+		if resp == nil {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("mcp: writing response: %w", err)
+		}
+	}
+	return scanner.Err()
+}
+
+// ServeHTTP handles one JSON-RPC message per POST request. Notifications
+// are acknowledged with 202 Accepted.
+func (s *MCPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp := s.handle(r.Context(), body)
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// handle processes one message and returns its response, or nil for a
+// notification.
+func (s *MCPServer) handle(ctx context.Context, msg []byte) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return errorResponse(json.RawMessage("null"), codeParseError, "parse error: "+err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(cmpID(req.ID), codeInvalidRequest, "invalid JSON-RPC 2.0 request")
+	}
+	notification := len(req.ID) == 0
+
+	result, rerr := s.dispatch(ctx, req)
+	if notification {
+		return nil
+	}
+	if rerr != nil {
+		return &rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: rerr}
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func (s *MCPServer) dispatch(ctx context.Context, req rpcRequest) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": s.Name, "version": s.Version},
+		}, nil
+	case "ping", "notifications/initialized", "notifications/cancelled":
+		return map[string]any{}, nil
+	case "tools/list":
+		return s.listTools(), nil
+	case "tools/call":
+		var params struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "tools/call needs a tool name"}
+		}
+		return s.callTool(ctx, params.Name, params.Arguments)
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+}
+
+func (s *MCPServer) listTools() map[string]any {
+	tools := make([]map[string]any, 0, len(s.tools))
+	for _, t := range s.tools {
+		schema := t.ParametersSchema
+		if schema == nil {
+			schema = map[string]any{"type": "object"}
+		}
+		tools = append(tools, map[string]any{
+			"name":        t.Name,
+			"description": t.Description,
+			"inputSchema": schema,
+		})
+	}
+	return map[string]any{"tools": tools}
+}
+
+// callTool runs the tool's handler. Handler errors are reported in the
+// result with isError set, as MCP expects, not as JSON-RPC errors.
+func (s *MCPServer) callTool(ctx context.Context, name string, args map[string]any) (any, *rpcError) {
+	handler, ok := s.handlers[name]
+	if !ok {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + name}
+	}
+	if args == nil {
+		args = map[string]any{}
+	}
+	out, err := handler(ctx, args)
+	if err != nil {
+		return map[string]any{
+			"content": []map[string]any{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}, nil
+	}
+	content, err := toolContent(out)
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+	}
+	return map[string]any{"content": content, "isError": false}, nil
+}
+
+// toolContent converts a handler result to MCP content blocks: strings are
+// sent as text, a cora.ToolResultPart as text plus an image, and anything
+// else as JSON text.
+func toolContent(out any) ([]map[string]any, error) {
+	switch v := out.(type) {
+	case string:
+		return []map[string]any{{"type": "text", "text": v}}, nil
+	case *cora.ToolResultPart:
+		if v == nil {
+			return []map[string]any{}, nil
+		}
+		return toolContent(*v)
+	case cora.ToolResultPart:
+		var content []map[string]any
+		if v.Text != "" {
+			content = append(content, map[string]any{"type": "text", "text": v.Text})
+		}
+		if v.Image != nil && len(v.Image.Data) > 0 {
+			mime := v.Image.MIMEType
+			if mime == "" {
+				mime = v.MIMEType
+			}
+			content = append(content, map[string]any{
+				"type":     "image",
+				"data":     base64.StdEncoding.EncodeToString(v.Image.Data),
+				"mimeType": mime,
+			})
+		}
+		return content, nil
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, errors.New("tool result is not JSON-serializable: " + err.Error())
+	}
+	return []map[string]any{{"type": "text", "text": string(b)}}, nil
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) *rpcResponse {
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: msg}}
+}
+
+// cmpID returns id, or JSON null when the request had none.
+func cmpID(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/oraraka-deko/cora/cora"
+)
+
+type weatherParams struct {
+	City string `json:"city" description:"City name"`
+}
+
+func testServer(t *testing.T) *MCPServer {
+	t.Helper()
+	tb := cora.NewToolBuilder()
+	err := tb.AddFunc("get_weather", "Current weather for a city", func(ctx context.Context, p weatherParams) (any, error) {
+		if p.City == "" {
+			return nil, errors.New("city is required")
+		}
+		return map[string]any{"city": p.City, "sky": "sunny"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewMCPServer(tb)
+}
+
+// stdio joins a request script and a response buffer into an io.ReadWriter.
+type stdio struct {
+	*strings.Reader
+	*bytes.Buffer
+}
+
+func (s stdio) Read(p []byte) (int, error)  { return s.Reader.Read(p) }
+func (s stdio) Write(p []byte) (int, error) { return s.Buffer.Write(p) }
+
+func TestServe_ListAndCall(t *testing.T) {
+	script := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"get_weather","arguments":{"city":"Paris"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"get_weather","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"resources/list"}`,
+	}, "\n")
+	var out bytes.Buffer
+	if err := testServer(t).Serve(context.Background(), stdio{strings.NewReader(script), &out}); err != nil {
+		t.Fatalf("Serve error: %v", err)
+	}
+
+	type response struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	var resps []response
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var r response
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		resps = append(resps, r)
+	}
+	if len(resps) != 5 {
+		t.Fatalf("expected 5 responses (none for the notification), got %d", len(resps))
+	}
+
+	var list struct {
+		Tools []struct {
+			Name        string         `json:"name"`
+			InputSchema map[string]any `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	_ = json.Unmarshal(resps[1].Result, &list)
+	if len(list.Tools) != 1 || list.Tools[0].Name != "get_weather" || list.Tools[0].InputSchema["type"] != "object" {
+		t.Errorf("unexpected tools/list result %s", resps[1].Result)
+	}
+
+	type callResult struct {
+		Content []struct{ Type, Text string } `json:"content"`
+		IsError bool                          `json:"isError"`
+	}
+	var ok, failed callResult
+	_ = json.Unmarshal(resps[2].Result, &ok)
+	if ok.IsError || len(ok.Content) != 1 || ok.Content[0].Text != `{"city":"Paris","sky":"sunny"}` {
+		t.Errorf("unexpected tools/call result %s", resps[2].Result)
+	}
+	_ = json.Unmarshal(resps[3].Result, &failed)
+	if !failed.IsError || failed.Content[0].Text != "city is required" {
+		t.Errorf("expected a tool error result, got %s", resps[3].Result)
+	}
+	if resps[4].Error == nil || resps[4].Error.Code != codeMethodNotFound {
+		t.Errorf("expected method not found, got %+v", resps[4])
+	}
+}
+
+func TestCallTool_UnserializableResult(t *testing.T) {
+	tb := cora.NewToolBuilder()
+	err := tb.AddFunc("bad", "Returns a channel", func(ctx context.Context, p weatherParams) (any, error) {
+		return make(chan int), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, rerr := NewMCPServer(tb).callTool(context.Background(), "bad", nil)
+	if rerr == nil || rerr.Code != codeInternalError {
+		t.Errorf("expected an internal error, got %+v", rerr)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	srv := httptest.NewServer(testServer(t))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL, "application/json",
+		strings.NewReader(`{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"get_weather","arguments":{"city":"Oslo"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		ID     string `json:"id"`
+		Result struct {
+			Content []struct{ Text string } `json:"content"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.ID != "a" || len(body.Result.Content) != 1 || !strings.Contains(body.Result.Content[0].Text, "Oslo") {
+		t.Errorf("unexpected response %+v", body)
+	}
+
+	resp, err = http.Post(srv.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("notification status = %d, want 202", resp.StatusCode)
+	}
+}
@@ -0,0 +1,111 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+var multiTurnMessages = []Message{
+	{Role: "user", Content: "My name is Ada."},
+	{Role: "assistant", Content: "Nice to meet you, Ada."},
+}
+
+func TestTextMessages_OpenAI(t *testing.T) {
+	var got [][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		var msgs []string
+		for _, m := range body.Messages {
+			msgs = append(msgs, m.Role+":"+m.Content)
+		}
+		got = append(got, msgs)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{
+			{"message": map[string]any{"role": "assistant", "content": "Ada."}},
+		}})
+	}))
+	defer srv.Close()
+
+	tools, handlers := lookupTool()
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	for _, req := range []TextRequest{
+		{Provider: ProviderOpenAI, Model: "gpt-test", System: "Be brief.", Messages: multiTurnMessages, Input: "What is my name?"},
+		{Provider: ProviderOpenAI, Model: "gpt-test", System: "Be brief.", Messages: append(multiTurnMessages, Message{Role: "user", Content: "What is my name?"}),
+			Mode: ModeToolCalling, Tools: tools, ToolHandlers: handlers},
+	} {
+		if _, err := c.Text(context.Background(), req); err != nil {
+			t.Fatalf("Text error: %v", err)
+		}
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(got))
+	}
+	want := []string{"system:Be brief.", "user:My name is Ada.", "assistant:Nice to meet you, Ada.", "user:What is my name?"}
+	for i, msgs := range got {
+		if !slices.Equal(msgs, want) {
+			t.Errorf("call %d: messages = %q, want %q", i+1, msgs, want)
+		}
+	}
+}
+
+func TestTextMessages_Google(t *testing.T) {
+	var got [][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Contents []struct {
+				Role  string `json:"role"`
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"contents"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		var turns []string
+		for _, c := range body.Contents {
+			text := ""
+			if len(c.Parts) > 0 {
+				text = c.Parts[0].Text
+			}
+			turns = append(turns, c.Role+":"+text)
+		}
+		got = append(got, turns)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{{"text": "Ada."}}}}},
+		})
+	}))
+	defer srv.Close()
+
+	tools, handlers := lookupTool()
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	for _, req := range []TextRequest{
+		{Provider: ProviderGoogle, Model: "gemini-test", Messages: multiTurnMessages, Input: "What is my name?"},
+		{Provider: ProviderGoogle, Model: "gemini-test", Messages: append(multiTurnMessages, Message{Role: "user", Content: "What is my name?"}),
+			Mode: ModeToolCalling, Tools: tools, ToolHandlers: handlers},
+	} {
+		if _, err := c.Text(context.Background(), req); err != nil {
+			t.Fatalf("Text error: %v", err)
+		}
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(got))
+	}
+	want := []string{"user:My name is Ada.", "model:Nice to meet you, Ada.", "user:What is my name?"}
+	for i, turns := range got {
+		if !slices.Equal(turns, want) {
+			t.Errorf("call %d: contents = %q, want %q", i+1, turns, want)
+		}
+	}
+}
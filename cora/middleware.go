@@ -0,0 +1,24 @@
+package cora
+
+import "context"
+
+// TextFunc has the signature of Client.Text.
+type TextFunc func(ctx context.Context, req TextRequest) (TextResponse, error)
+
+// Middleware wraps a TextFunc to inspect or modify requests and responses.
+type Middleware func(next TextFunc) TextFunc
+
+// Use appends middleware to the client. The first middleware registered is
+// the outermost: it sees the request first and the response last.
+func (c *Client) Use(mw ...Middleware) {
+	c.middleware = append(c.middleware, mw...)
+}
+
+// textHandler returns textCore wrapped in the client's middleware chain.
+func (c *Client) textHandler() TextFunc {
+	h := TextFunc(c.textCore)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h
+}
@@ -0,0 +1,39 @@
+package cora
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RegisterModelAlias makes requests for model from use model to instead,
+// adding to or overriding CoraConfig.ModelAliases. It is safe to call while
+// requests are in flight.
+func (c *Client) RegisterModelAlias(from, to string) {
+	c.aliasMu.Lock()
+	defer c.aliasMu.Unlock()
+	if c.aliases == nil {
+		c.aliases = make(map[string]string)
+	}
+	c.aliases[from] = to
+}
+
+// resolveModelAlias follows model through the client's aliases and returns
+// the model it finally names, or an error if the aliases form a cycle.
+func (c *Client) resolveModelAlias(model string) (string, error) {
+	c.aliasMu.RLock()
+	defer c.aliasMu.RUnlock()
+	chain := []string{model}
+	for {
+		next, ok := c.aliases[model]
+		if !ok {
+			return model, nil
+		}
+		for _, seen := range chain {
+			if seen == next {
+				return "", fmt.Errorf("cora: circular model alias %s", strings.Join(append(chain, next), " -> "))
+			}
+		}
+		chain = append(chain, next)
+		model = next
+	}
+}
@@ -0,0 +1,32 @@
+package cora
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRegisterModelAlias(t *testing.T) {
+	fake := &fakeProvider{}
+	c := &Client{cfg: CoraConfig{}, google: fake}
+	c.RegisterModelAlias("my-fast-model", "gemini-2.5-flash")
+
+	resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderGoogle, Model: "my-fast-model", Input: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Model != "gemini-2.5-flash" {
+		t.Errorf("Model = %q, want the resolved alias", resp.Model)
+	}
+	if got := fake.ReceivedPlans()[0].Model; got != "gemini-2.5-flash" {
+		t.Errorf("provider received model %q", got)
+	}
+}
+
+func TestModelAliases_Cycle(t *testing.T) {
+	c := New(CoraConfig{ModelAliases: map[string]string{"a": "b", "b": "a"}})
+	_, err := c.Text(context.Background(), TextRequest{Provider: ProviderGoogle, Model: "a", Input: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "circular model alias a -> b -> a") {
+		t.Fatalf("expected a circular alias error, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package cora
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OutputFormat asks the model to format its answer (see
+// TextRequest.OutputFormat).
+type OutputFormat string
+
+const (
+	OutputFormatText     OutputFormat = "text"
+	OutputFormatMarkdown OutputFormat = "markdown"
+	OutputFormatHTML     OutputFormat = "html"
+	// OutputFormatJSON asks for a JSON object without enforcing a schema;
+	// use ModeStructuredJSON to enforce one.
+	OutputFormatJSON OutputFormat = "json"
+	OutputFormatCSV  OutputFormat = "csv"
+)
+
+// outputFormatInstruction returns the system prompt instruction for format,
+// or "" when none is needed.
+func outputFormatInstruction(format OutputFormat, csvHeaders []string) (string, error) {
+	if len(csvHeaders) > 0 && format != OutputFormatCSV {
+		return "", fmt.Errorf("cora: CSVHeaders requires OutputFormatCSV, got OutputFormat %q", format)
+	}
+	switch format {
+	case "":
+		return "", nil
+	case OutputFormatText:
+		return "Respond in plain text, without Markdown or other markup.", nil
+	case OutputFormatMarkdown:
+		return "Respond in Markdown format.", nil
+	case OutputFormatHTML:
+		return "Respond in HTML format, as an HTML fragment without <html> or <body> tags.", nil
+	case OutputFormatJSON:
+		return "Respond with a single valid JSON object and nothing else.", nil
+	case OutputFormatCSV:
+		instr := "Respond in CSV format, with a header row and no other text."
+		if len(csvHeaders) > 0 {
+			instr += " Use exactly these columns, in order: " + strings.Join(csvHeaders, ", ") + "."
+		}
+		return instr, nil
+	default:
+		return "", fmt.Errorf("cora: unknown OutputFormat %q", format)
+	}
+}
+
+// withOutputFormat appends the instruction for format to system.
+func withOutputFormat(system string, format OutputFormat, csvHeaders []string) (string, error) {
+	instr, err := outputFormatInstruction(format, csvHeaders)
+	if err != nil {
+		return system, err
+	}
+	return appendInstruction(system, instr), nil
+}
+
+// appendInstruction appends instr to system as a new paragraph.
+func appendInstruction(system, instr string) string {
+	switch {
+	case instr == "":
+		return system
+	case system == "":
+		return instr
+	default:
+		return system + "\n\n" + instr
+	}
+}
@@ -0,0 +1,50 @@
+package cora
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildPlans_OutputFormat(t *testing.T) {
+	tests := []struct {
+		format  OutputFormat
+		headers []string
+		suffix  string
+	}{
+		{OutputFormatText, nil, "Respond in plain text, without Markdown or other markup."},
+		{OutputFormatMarkdown, nil, "Respond in Markdown format."},
+		{OutputFormatHTML, nil, "Respond in HTML format, as an HTML fragment without <html> or <body> tags."},
+		{OutputFormatJSON, nil, "Respond with a single valid JSON object and nothing else."},
+		{OutputFormatCSV, nil, "Respond in CSV format, with a header row and no other text."},
+		{OutputFormatCSV, []string{"name", "price"}, "Use exactly these columns, in order: name, price."},
+	}
+	for _, tt := range tests {
+		req := TextRequest{Input: "List the products.", System: "You are a shop assistant.", OutputFormat: tt.format, CSVHeaders: tt.headers}
+		plans, err := buildPlans(ProviderOpenAI, "gpt-test", req, CoraConfig{}, nil)
+		if err != nil {
+			t.Fatalf("%s: buildPlans error: %v", tt.format, err)
+		}
+		system := plans[0].System
+		if !strings.HasPrefix(system, "You are a shop assistant.\n\n") || !strings.HasSuffix(system, tt.suffix) {
+			t.Errorf("%s: system = %q, want suffix %q", tt.format, system, tt.suffix)
+		}
+	}
+
+	plans, err := buildPlans(ProviderOpenAI, "gpt-test", TextRequest{Input: "hi", OutputFormat: OutputFormatMarkdown}, CoraConfig{}, nil)
+	if err != nil || plans[0].System != "Respond in Markdown format." {
+		t.Errorf("expected the instruction alone without a system prompt, got %q (%v)", plans[0].System, err)
+	}
+	plans, _ = buildPlans(ProviderOpenAI, "gpt-test", TextRequest{Input: "hi", System: "Be brief."}, CoraConfig{}, nil)
+	if plans[0].System != "Be brief." {
+		t.Errorf("expected the system prompt unchanged without OutputFormat, got %q", plans[0].System)
+	}
+
+	for _, req := range []TextRequest{
+		{Input: "hi", OutputFormat: "yaml"},
+		{Input: "hi", OutputFormat: OutputFormatJSON, CSVHeaders: []string{"a"}},
+	} {
+		if _, err := buildPlans(ProviderOpenAI, "gpt-test", req, CoraConfig{}, nil); err == nil {
+			t.Errorf("expected an error for %+v", req)
+		}
+	}
+}
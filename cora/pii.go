@@ -0,0 +1,187 @@
+package cora
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PIIMasker replaces personally identifiable information in text.
+type PIIMasker interface {
+	Mask(text string) string
+}
+
+// PIIPattern names a kind of PII and the expression that matches it.
+type PIIPattern struct {
+	Name   string // used in placeholders, e.g. "EMAIL" -> "[EMAIL_1]"
+	Regexp *regexp.Regexp
+	// Valid, when set, rejects matches that are not PII (e.g. digit runs
+	// failing a checksum); rejected matches are left unmasked.
+	Valid func(match string) bool
+}
+
+// DefaultPIIPatterns match email addresses, credit card numbers and US SSNs.
+var DefaultPIIPatterns = []PIIPattern{
+	{Name: "EMAIL", Regexp: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	{Name: "CREDIT_CARD", Regexp: regexp.MustCompile(`\b\d{4}[ -]?\d{4}[ -]?\d{4}[ -]?\d{1,4}\b`), Valid: luhnValid},
+	{Name: "SSN", Regexp: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+}
+
+// RegexPIIMasker replaces every match of its patterns with a numbered
+// placeholder such as "[SSN_1]". Used through WithPIIMasker, placeholders
+// in the model's answer are mapped back to the original values.
+type RegexPIIMasker struct {
+	Patterns []PIIPattern
+}
+
+// NewRegexPIIMasker returns a masker for patterns, or DefaultPIIPatterns if none are given.
+func NewRegexPIIMasker(patterns ...PIIPattern) *RegexPIIMasker {
+	if len(patterns) == 0 {
+		patterns = DefaultPIIPatterns
+	}
+	return &RegexPIIMasker{Patterns: patterns}
+}
+
+// Mask replaces PII in text with placeholders.
+func (m *RegexPIIMasker) Mask(text string) string {
+	return m.maskWith(text, newPIIMapping())
+}
+
+func (m *RegexPIIMasker) maskWith(text string, mp *piiMapping) string {
+	for _, p := range m.Patterns {
+		text = p.Regexp.ReplaceAllStringFunc(text, func(match string) string {
+			if p.Valid != nil && !p.Valid(match) {
+				return match
+			}
+			return mp.token(p.Name, match)
+		})
+	}
+	return text
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum used by
+// payment card numbers. Other characters are ignored.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
+
+// piiMapping keeps placeholders consistent across the fields of one request
+// and remembers the originals for unmasking the response.
+type piiMapping struct {
+	byValue map[string]string
+	byToken map[string]string
+	counts  map[string]int
+}
+
+func newPIIMapping() *piiMapping {
+	return &piiMapping{
+		byValue: map[string]string{},
+		byToken: map[string]string{},
+		counts:  map[string]int{},
+	}
+}
+
+func (mp *piiMapping) token(kind, value string) string {
+	if tok, ok := mp.byValue[value]; ok {
+		return tok
+	}
+	mp.counts[kind]++
+	tok := fmt.Sprintf("[%s_%d]", kind, mp.counts[kind])
+	mp.byValue[value] = tok
+	mp.byToken[tok] = value
+	return tok
+}
+
+func (mp *piiMapping) unmask(text string) string {
+	if len(mp.byToken) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, 2*len(mp.byToken))
+	for tok, value := range mp.byToken {
+		pairs = append(pairs, tok, value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// unmaskValue replaces placeholders in the strings of a decoded JSON value.
+func (mp *piiMapping) unmaskValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return mp.unmask(v)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[mp.unmask(k)] = mp.unmaskValue(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = mp.unmaskValue(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// unmaskResponse replaces placeholders in resp's text and JSON, and in those
+// of its choices.
+func (mp *piiMapping) unmaskResponse(resp *TextResponse) {
+	resp.Text = mp.unmask(resp.Text)
+	if resp.JSON != nil {
+		resp.JSON = mp.unmaskValue(resp.JSON).(map[string]any)
+	}
+	for i := range resp.Choices {
+		mp.unmaskResponse(&resp.Choices[i])
+	}
+}
+
+// mappingPIIMasker is implemented by maskers whose placeholders can be reversed.
+type mappingPIIMasker interface {
+	maskWith(text string, mp *piiMapping) string
+}
+
+// WithPIIMasker returns middleware that masks req.Input and req.System before
+// the request is sent. For maskers that produce placeholders (RegexPIIMasker),
+// placeholders in resp.Text and resp.JSON are replaced with the original
+// values.
+func WithPIIMasker(m PIIMasker) Middleware {
+	return func(next TextFunc) TextFunc {
+		return func(ctx context.Context, req TextRequest) (TextResponse, error) {
+			rm, ok := m.(mappingPIIMasker)
+			if !ok {
+				req.Input = m.Mask(req.Input)
+				req.System = m.Mask(req.System)
+				return next(ctx, req)
+			}
+
+			mp := newPIIMapping()
+			req.Input = rm.maskWith(req.Input, mp)
+			req.System = rm.maskWith(req.System, mp)
+			resp, err := next(ctx, req)
+			if err != nil {
+				return resp, err
+			}
+			mp.unmaskResponse(&resp)
+			return resp, nil
+		}
+	}
+}
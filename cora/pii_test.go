@@ -0,0 +1,111 @@
+package cora
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWithPIIMasker_SSN(t *testing.T) {
+	fake := &echoProvider{prefix: "Noted: "}
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = fake
+	c.Use(WithPIIMasker(NewRegexPIIMasker()))
+
+	var sentSystem string
+	c.Use(func(next TextFunc) TextFunc {
+		return func(ctx context.Context, req TextRequest) (TextResponse, error) {
+			sentSystem = req.System
+			return next(ctx, req)
+		}
+	})
+
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		System:   "The customer's SSN is 123-45-6789.",
+		Input:    "Verify SSN 123-45-6789 for jane@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+
+	sentInput := fake.inputs[0]
+	for _, sent := range []string{sentInput, sentSystem} {
+		if strings.Contains(sent, "123-45-6789") {
+			t.Errorf("SSN leaked to provider: %q", sent)
+		}
+		if !strings.Contains(sent, "[SSN_1]") {
+			t.Errorf("expected SSN placeholder, got %q", sent)
+		}
+	}
+	if strings.Contains(sentInput, "jane@example.com") || !strings.Contains(sentInput, "[EMAIL_1]") {
+		t.Errorf("expected email to be masked, got %q", sentInput)
+	}
+
+	// The echoed placeholders are mapped back for the caller.
+	if resp.Text != "Noted: Verify SSN 123-45-6789 for jane@example.com" {
+		t.Errorf("expected placeholders to be unmasked, got %q", resp.Text)
+	}
+}
+
+func TestRegexPIIMasker_Mask(t *testing.T) {
+	m := NewRegexPIIMasker()
+	got := m.Mask("card 4111 1111 1111 1111, ssn 078-05-1120, mail a.b@c.io")
+	want := "card [CREDIT_CARD_1], ssn [SSN_1], mail [EMAIL_1]"
+	if got != want {
+		t.Errorf("Mask = %q, want %q", got, want)
+	}
+
+	// Digit runs that fail the Luhn check are not card numbers.
+	if got := m.Mask("order 1234 5678 9012 3456"); got != "order 1234 5678 9012 3456" {
+		t.Errorf("Mask = %q, want the order number kept", got)
+	}
+}
+
+func TestWithPIIMasker_JSON(t *testing.T) {
+	fake := (&fakeProvider{}).WithJSON(map[string]any{
+		"ssn":     "[SSN_1]",
+		"matches": []any{map[string]any{"email": "[EMAIL_1]"}},
+	})
+	c := &Client{cfg: CoraConfig{}, openai: fake}
+	c.Use(WithPIIMasker(NewRegexPIIMasker()))
+
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Input:    "Extract SSN 123-45-6789 and jane@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.JSON["ssn"] != "123-45-6789" {
+		t.Errorf("ssn = %v, want it unmasked", resp.JSON["ssn"])
+	}
+	if m := resp.JSON["matches"].([]any)[0].(map[string]any); m["email"] != "jane@example.com" {
+		t.Errorf("email = %v, want it unmasked", m["email"])
+	}
+}
+
+func TestClientUse_Order(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = &echoProvider{}
+
+	var order []string
+	mw := func(name string) Middleware {
+		return func(next TextFunc) TextFunc {
+			return func(ctx context.Context, req TextRequest) (TextResponse, error) {
+				order = append(order, name)
+				return next(ctx, req)
+			}
+		}
+	}
+	c.Use(mw("first"), mw("second"))
+
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "m", Input: "x"}); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("unexpected middleware order %v", order)
+	}
+}
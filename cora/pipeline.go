@@ -0,0 +1,106 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Pipeline chains Text() calls so that each step's output feeds the next.
+// Build one with Client.Pipeline, add steps with Step and optionally
+// customize the hand-off between steps with WithTransform.
+type Pipeline struct {
+	client *Client
+	steps  []pipelineStep
+	err    error
+}
+
+type pipelineStep struct {
+	req TextRequest
+	// transform builds the next step's request from this step's response.
+	transform func(TextResponse) TextRequest
+}
+
+// Pipeline returns an empty pipeline that runs its steps on c.
+func (c *Client) Pipeline() *Pipeline {
+	return &Pipeline{client: c}
+}
+
+// Step appends a step. For every step after the first, Input is replaced by
+// the previous step's output (see WithTransform to change this).
+func (p *Pipeline) Step(req TextRequest) *Pipeline {
+	p.steps = append(p.steps, pipelineStep{req: req})
+	return p
+}
+
+// WithTransform sets how the most recently added step's response is turned
+// into the next step's request. Non-zero fields of the returned request
+// override the next step's declared request.
+func (p *Pipeline) WithTransform(fn func(TextResponse) TextRequest) *Pipeline {
+	if len(p.steps) == 0 {
+		p.err = errors.New("cora: WithTransform called before any Step")
+		return p
+	}
+	p.steps[len(p.steps)-1].transform = fn
+	return p
+}
+
+// Run executes the steps sequentially and returns the last step's response.
+func (p *Pipeline) Run(ctx context.Context) (TextResponse, error) {
+	if p.err != nil {
+		return TextResponse{}, p.err
+	}
+	if len(p.steps) == 0 {
+		return TextResponse{}, errors.New("cora: pipeline has no steps")
+	}
+
+	req := p.steps[0].req
+	var resp TextResponse
+	for i, step := range p.steps {
+		var err error
+		resp, err = p.client.Text(ctx, req)
+		if err != nil {
+			return TextResponse{}, fmt.Errorf("cora: pipeline step %d: %w", i+1, err)
+		}
+		if i+1 == len(p.steps) {
+			break
+		}
+
+		req = p.steps[i+1].req
+		if step.transform == nil {
+			req.Input = resultPreferredInput(callResult{Text: resp.Text, JSON: resp.JSON})
+			continue
+		}
+		mergeFields(reflect.ValueOf(&req).Elem(), reflect.ValueOf(step.transform(resp)))
+	}
+	return resp, nil
+}
+
+// RunParallel executes independent requests concurrently and returns their
+// responses in the same order. It fails if any request fails.
+func (p *Pipeline) RunParallel(ctx context.Context, reqs []TextRequest) ([]TextResponse, error) {
+	out := make([]TextResponse, len(reqs))
+	errs := make([]error, len(reqs))
+
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := p.client.Text(ctx, req)
+			if err != nil {
+				errs[i] = fmt.Errorf("cora: parallel step %d: %w", i+1, err)
+				return
+			}
+			out[i] = resp
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
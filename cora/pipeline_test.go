@@ -0,0 +1,84 @@
+package cora
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// echoProvider answers with a prefix followed by the plan's input.
+type echoProvider struct {
+	mu     sync.Mutex
+	prefix string
+	inputs []string
+}
+
+func (e *echoProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	e.mu.Lock()
+	e.inputs = append(e.inputs, plan.Input)
+	e.mu.Unlock()
+	return callResult{Text: e.prefix + plan.Input}, nil
+}
+
+func TestPipeline_TwoSteps(t *testing.T) {
+	openaiFake := &echoProvider{prefix: "entities: "}
+	googleFake := &echoProvider{prefix: "report on "}
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = openaiFake
+	c.google = googleFake
+
+	resp, err := c.Pipeline().
+		Step(TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "Paris and Berlin"}).
+		Step(TextRequest{Provider: ProviderGoogle, Model: "gemini-test"}).
+		Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if resp.Text != "report on entities: Paris and Berlin" {
+		t.Errorf("unexpected final text %q", resp.Text)
+	}
+	if googleFake.inputs[0] != "entities: Paris and Berlin" {
+		t.Errorf("expected step 1 output as step 2 input, got %q", googleFake.inputs[0])
+	}
+}
+
+func TestPipeline_WithTransform(t *testing.T) {
+	fake := &echoProvider{}
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = fake
+
+	resp, err := c.Pipeline().
+		Step(TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "a,b"}).
+		WithTransform(func(r TextResponse) TextRequest {
+			return TextRequest{Input: strings.ToUpper(r.Text), System: "summarize"}
+		}).
+		Step(TextRequest{Provider: ProviderOpenAI, Model: "gpt-test"}).
+		Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if resp.Text != "A,B" {
+		t.Errorf("expected transformed input to reach step 2, got %q", resp.Text)
+	}
+
+	if _, err := c.Pipeline().WithTransform(nil).Run(context.Background()); err == nil {
+		t.Error("expected error for WithTransform before Step")
+	}
+}
+
+func TestPipeline_RunParallel(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = &echoProvider{prefix: "> "}
+
+	resps, err := c.Pipeline().RunParallel(context.Background(), []TextRequest{
+		{Provider: ProviderOpenAI, Model: "gpt-test", Input: "one"},
+		{Provider: ProviderOpenAI, Model: "gpt-test", Input: "two"},
+	})
+	if err != nil {
+		t.Fatalf("RunParallel error: %v", err)
+	}
+	if resps[0].Text != "> one" || resps[1].Text != "> two" {
+		t.Errorf("unexpected responses: %q, %q", resps[0].Text, resps[1].Text)
+	}
+}
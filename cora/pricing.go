@@ -0,0 +1,111 @@
+package cora
+
+import "strings"
+
+// ModelPricing is the price of a model in USD per token.
+type ModelPricing struct {
+	InputPerToken  float64
+	OutputPerToken float64
+	// CachedInputPerToken is the price of prompt tokens served from the
+	// provider's prompt cache (default: half of InputPerToken).
+	CachedInputPerToken float64
+}
+
+// PricingTable maps model names to prices. Dated variants (e.g.
+// "gpt-4o-2024-08-06") match the longest name they start with.
+type PricingTable map[string]ModelPricing
+
+// perMillion converts prices quoted per million tokens.
+func perMillion(input, output float64) ModelPricing {
+	return ModelPricing{InputPerToken: input / 1e6, OutputPerToken: output / 1e6}
+}
+
+// DefaultPricingTable lists list prices of well-known models. Entries in
+// CoraConfig.PricingTable take precedence.
+var DefaultPricingTable = PricingTable{
+	"gpt-4o":           perMillion(2.50, 10.00),
+	"gpt-4o-mini":      perMillion(0.15, 0.60),
+	"gpt-4.1":          perMillion(2.00, 8.00),
+	"gpt-4.1-mini":     perMillion(0.40, 1.60),
+	"gpt-4.1-nano":     perMillion(0.10, 0.40),
+	"gpt-4-turbo":      perMillion(10.00, 30.00),
+	"gpt-3.5-turbo":    perMillion(0.50, 1.50),
+	"o1":               perMillion(15.00, 60.00),
+	"o3":               perMillion(2.00, 8.00),
+	"o3-mini":          perMillion(1.10, 4.40),
+	"o4-mini":          perMillion(1.10, 4.40),
+	"gemini-1.5-flash": perMillion(0.075, 0.30),
+	"gemini-1.5-pro":   perMillion(1.25, 5.00),
+	"gemini-2.0-flash": perMillion(0.10, 0.40),
+	"gemini-2.5-flash": perMillion(0.30, 2.50),
+	"gemini-2.5-pro":   perMillion(1.25, 10.00),
+}
+
+// lookup returns the pricing of model: an exact match, else the longest
+// name that model starts with followed by "-".
+func (t PricingTable) lookup(model string) (ModelPricing, bool) {
+	if p, ok := t[model]; ok {
+		return p, true
+	}
+	best := ""
+	for name := range t {
+		if strings.HasPrefix(model, name+"-") && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return ModelPricing{}, false
+	}
+	return t[best], true
+}
+
+// estimateCost returns the USD cost of a call to model, preferring
+// cfg.PricingTable over DefaultPricingTable. ok is false for unknown models.
+func estimateCost(cfg CoraConfig, model string, promptTokens, completionTokens int) (cost float64, ok bool) {
+	p, ok := modelPricing(cfg, model)
+	if !ok {
+		return 0, false
+	}
+	return float64(promptTokens)*p.InputPerToken + float64(completionTokens)*p.OutputPerToken, true
+}
+
+// estimateEffectiveCost is estimateCost with the cachedTokens of the prompt
+// billed at the model's cached input price.
+func estimateEffectiveCost(cfg CoraConfig, model string, promptTokens, cachedTokens, completionTokens int) (cost float64, ok bool) {
+	p, ok := modelPricing(cfg, model)
+	if !ok {
+		return 0, false
+	}
+	cachedPrice := p.CachedInputPerToken
+	if cachedPrice == 0 {
+		cachedPrice = p.InputPerToken / 2
+	}
+	cachedTokens = min(cachedTokens, promptTokens)
+	return float64(promptTokens-cachedTokens)*p.InputPerToken + float64(cachedTokens)*cachedPrice +
+		float64(completionTokens)*p.OutputPerToken, true
+}
+
+// modelPricing returns the pricing of model, preferring cfg.PricingTable over
+// DefaultPricingTable.
+func modelPricing(cfg CoraConfig, model string) (ModelPricing, bool) {
+	if p, ok := cfg.PricingTable.lookup(model); ok {
+		return p, true
+	}
+	return DefaultPricingTable.lookup(model)
+}
+
+// TotalSpend returns the estimated USD cost of all provider calls made by the
+// client so far. Calls to models without known pricing and responses served
+// from the cache are not counted.
+func (c *Client) TotalSpend() float64 {
+	c.spendMu.Lock()
+	defer c.spendMu.Unlock()
+	return c.spend
+}
+
+// addSpend records the cost of a provider call.
+func (c *Client) addSpend(cost float64) {
+	c.spendMu.Lock()
+	c.spend += cost
+	c.spendMu.Unlock()
+}
@@ -0,0 +1,87 @@
+package cora
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEstimateCost(t *testing.T) {
+	cfg := CoraConfig{PricingTable: PricingTable{"my-model": {InputPerToken: 0.01, OutputPerToken: 0.02}}}
+	tests := []struct {
+		model string
+		want  float64
+		ok    bool
+	}{
+		{"gpt-4o", 1000*2.50/1e6 + 500*10.00/1e6, true},
+		{"gpt-4o-mini", 1000*0.15/1e6 + 500*0.60/1e6, true},
+		{"gpt-4o-2024-08-06", 1000*2.50/1e6 + 500*10.00/1e6, true},
+		{"gemini-1.5-flash-002", 1000*0.075/1e6 + 500*0.30/1e6, true},
+		{"my-model", 1000*0.01 + 500*0.02, true},
+		{"unknown", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := estimateCost(cfg, tt.model, 1000, 500)
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("estimateCost(%q) = %v, %v; want %v, %v", tt.model, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestText_EstimatedCostAndTotalSpend(t *testing.T) {
+	c := &Client{cfg: CoraConfig{PricingTable: PricingTable{"gpt-test": {InputPerToken: 0.001, OutputPerToken: 0.002}}}}
+	c.openai = usageProvider{} // 12 prompt, 7 completion tokens
+
+	for range 2 {
+		resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+		if err != nil {
+			t.Fatalf("Text error: %v", err)
+		}
+		if resp.EstimatedCostUSD == nil || math.Abs(*resp.EstimatedCostUSD-0.026) > 1e-12 {
+			t.Fatalf("expected cost 0.026, got %v", resp.EstimatedCostUSD)
+		}
+	}
+	if got := c.TotalSpend(); math.Abs(got-0.052) > 1e-12 {
+		t.Errorf("expected total spend 0.052, got %v", got)
+	}
+
+	resp, _ := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "unpriced", Input: "hi"})
+	if resp.EstimatedCostUSD != nil {
+		t.Errorf("expected no cost for an unpriced model, got %v", *resp.EstimatedCostUSD)
+	}
+}
+
+func TestText_OpenAICachedPromptTokens(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"choices": [{"message": {"role": "assistant", "content": "hi"}}],
+			"usage": {"prompt_tokens": 400, "completion_tokens": 10, "total_tokens": 410,
+				"prompt_tokens_details": {"cached_tokens": 100}}
+		}`))
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{
+		OpenAIAPIKey:  "sk-test",
+		OpenAIBaseURL: srv.URL,
+		PricingTable:  PricingTable{"gpt-test": {InputPerToken: 0.001, OutputPerToken: 0.002}},
+	})
+	resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.CachedTokens == nil || *resp.CachedTokens != 100 {
+		t.Fatalf("CachedTokens = %v, want 100", resp.CachedTokens)
+	}
+	// 300 uncached and 100 cached prompt tokens at half price, 10 completion tokens.
+	want := 300*0.001 + 100*0.0005 + 10*0.002
+	if resp.EffectiveCostUSD == nil || math.Abs(*resp.EffectiveCostUSD-want) > 1e-12 {
+		t.Errorf("EffectiveCostUSD = %v, want %v", resp.EffectiveCostUSD, want)
+	}
+	if resp.EstimatedCostUSD == nil || *resp.EstimatedCostUSD <= *resp.EffectiveCostUSD {
+		t.Errorf("expected the cache discount to lower the cost below %v", resp.EstimatedCostUSD)
+	}
+}
@@ -0,0 +1,89 @@
+package cora
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PromptOptimizer rewrites a request's input and system prompt for the model
+// it is sent to (see Client.WithPromptOptimizer).
+type PromptOptimizer interface {
+	Optimize(provider Provider, model string, input, system string) (optimizedInput, optimizedSystem string)
+}
+
+// WithPromptOptimizer sets an optimizer applied to the input and system
+// prompt of every Text call before mode-specific prompts are added.
+func (c *Client) WithPromptOptimizer(opt PromptOptimizer) *Client {
+	c.promptOptimizer = opt
+	return c
+}
+
+// OptimizationRule is a set of prompt rewrites for a model family (see
+// ModelSpecificOptimizer). They are applied in field order.
+type OptimizationRule struct {
+	// StripRolePreamble removes a leading "You are a..." sentence from the
+	// system prompt, which reasoning models such as o1 do not need.
+	StripRolePreamble bool
+	// MergeSystemIntoInput moves the system prompt in front of the input,
+	// for models that ignore system prompts.
+	MergeSystemIntoInput bool
+	// InputXMLTag, if set, wraps the input in <InputXMLTag> tags, which
+	// Claude models follow more reliably.
+	InputXMLTag string
+}
+
+// DefaultOptimizationRules are the rules ModelSpecificOptimizer uses when
+// given none.
+var DefaultOptimizationRules = map[string]OptimizationRule{
+	"o1":               {StripRolePreamble: true},
+	"o3":               {StripRolePreamble: true},
+	"claude":           {InputXMLTag: "input"},
+	"anthropic.claude": {InputXMLTag: "input"},
+}
+
+// ModelSpecificOptimizer returns a PromptOptimizer that applies the rule of
+// the request's model. Rules are keyed by model name or family: "o1" matches
+// "o1" and "o1-mini", and the exact name wins over the longest matching
+// family. Models without a rule are left unchanged. A nil rules map uses
+// DefaultOptimizationRules.
+func ModelSpecificOptimizer(rules map[string]OptimizationRule) PromptOptimizer {
+	if rules == nil {
+		rules = DefaultOptimizationRules
+	}
+	return modelSpecificOptimizer(rules)
+}
+
+type modelSpecificOptimizer map[string]OptimizationRule
+
+func (o modelSpecificOptimizer) Optimize(provider Provider, model string, input, system string) (string, string) {
+	rule, ok := o[model]
+	if !ok {
+		best := ""
+		for name := range o {
+			if strings.HasPrefix(model, name+"-") && len(name) > len(best) {
+				best = name
+			}
+		}
+		if best == "" {
+			return input, system
+		}
+		rule = o[best]
+	}
+	return rule.apply(input, system)
+}
+
+// rolePreamble matches a leading "You are a/an ..." sentence.
+var rolePreamble = regexp.MustCompile(`(?i)^\s*you are an? [^.!\n]*[.!]?\s*`)
+
+func (r OptimizationRule) apply(input, system string) (string, string) {
+	if r.StripRolePreamble {
+		system = rolePreamble.ReplaceAllString(system, "")
+	}
+	if r.MergeSystemIntoInput && strings.TrimSpace(system) != "" {
+		input, system = system+"\n\n"+input, ""
+	}
+	if r.InputXMLTag != "" && input != "" {
+		input = "<" + r.InputXMLTag + ">\n" + input + "\n</" + r.InputXMLTag + ">"
+	}
+	return input, system
+}
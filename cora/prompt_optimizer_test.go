@@ -0,0 +1,71 @@
+package cora
+
+import (
+	"context"
+	"testing"
+)
+
+func TestModelSpecificOptimizer(t *testing.T) {
+	fp := &fakeProvider{}
+	c := (&Client{cfg: CoraConfig{}, openai: fp}).WithPromptOptimizer(ModelSpecificOptimizer(nil))
+
+	for _, model := range []string{"o1-mini", "gpt-4o"} {
+		_, err := c.Text(context.Background(), TextRequest{
+			Provider: ProviderOpenAI, Model: model, Input: "Sum 2 and 3.",
+			System: "You are a helpful math tutor. Show your steps.",
+		})
+		if err != nil {
+			t.Fatalf("Text error: %v", err)
+		}
+	}
+
+	plans := fp.ReceivedPlans()
+	if plans[0].System != "Show your steps." {
+		t.Errorf("o1-mini system = %q, want the role preamble removed", plans[0].System)
+	}
+	if plans[1].System != "You are a helpful math tutor. Show your steps." {
+		t.Errorf("gpt-4o system = %q, want it unchanged", plans[1].System)
+	}
+}
+
+func TestOptimizationRule_Apply(t *testing.T) {
+	opt := ModelSpecificOptimizer(map[string]OptimizationRule{
+		"claude":          {InputXMLTag: "input"},
+		"claude-3-haiku":  {MergeSystemIntoInput: true},
+		"legacy-instruct": {MergeSystemIntoInput: true, InputXMLTag: "task"},
+	})
+
+	if in, sys := opt.Optimize(ProviderOpenAI, "claude-3-5-sonnet", "Hi", "Be brief."); in != "<input>\nHi\n</input>" || sys != "Be brief." {
+		t.Errorf("claude family: %q, %q", in, sys)
+	}
+	// The longest matching family wins.
+	if in, sys := opt.Optimize(ProviderOpenAI, "claude-3-haiku-20240307", "Hi", "Be brief."); in != "Be brief.\n\nHi" || sys != "" {
+		t.Errorf("claude-3-haiku: %q, %q", in, sys)
+	}
+	if in, _ := opt.Optimize(ProviderOpenAI, "legacy-instruct", "Hi", "Be brief."); in != "<task>\nBe brief.\n\nHi\n</task>" {
+		t.Errorf("legacy-instruct: %q", in)
+	}
+	if in, sys := opt.Optimize(ProviderOpenAI, "claudette", "Hi", "Be brief."); in != "Hi" || sys != "Be brief." {
+		t.Errorf("unmatched model changed: %q, %q", in, sys)
+	}
+}
+
+func TestPromptOptimizer_RequestKey(t *testing.T) {
+	req := TextRequest{
+		Provider: ProviderOpenAI, Model: "o1-mini", Input: "Sum 2 and 3.",
+		System: "You are a helpful math tutor. Show your steps.",
+	}
+	plain := &Client{cfg: CoraConfig{}}
+	optimized := (&Client{cfg: CoraConfig{}}).WithPromptOptimizer(ModelSpecificOptimizer(nil))
+	a, err := plain.requestKey(req)
+	if err != nil {
+		t.Fatalf("requestKey error: %v", err)
+	}
+	b, err := optimized.requestKey(req)
+	if err != nil {
+		t.Fatalf("requestKey error: %v", err)
+	}
+	if a == b {
+		t.Error("expected the optimized prompt to change the request key")
+	}
+}
@@ -2,6 +2,10 @@ package cora
 
 import (
 	"context"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"slices"
 	"time"
 )
 
@@ -19,34 +23,95 @@ type callPlan struct {
 	// Input/system for this call. Two-step mode may generate multiple plans.
 	System string
 	Input  string
+	// Messages are earlier conversation turns sent before Input.
+	Messages []Message
+	// Documents are attached to the Input user message.
+	Documents []DocumentPart
+	// FileHandles are uploaded files attached to the Input user message.
+	FileHandles []FileHandle
+	// AudioPart is inline audio attached to the Input user message.
+	AudioPart *AudioPart
 
 	// Options
 	Temperature     *float32
 	MaxOutputTokens *int
+	// N is the number of completions to sample (0 or 1 = one).
+	N               int
+	ReasoningEffort *string
+	ThinkingBudget  *int
 	Labels          map[string]string
 
 	// Structured JSON
 	ResponseSchema map[string]any
+	SchemaName     string
 	Structured     bool
+	StrictJSON     bool
 
 	// Tool calling
-	Tools        []CoraTool
-	ToolHandlers map[string]CoraToolHandler
+	Tools             []CoraTool
+	ToolHandlers      map[string]CoraToolHandler
+	StreamingHandlers map[string]StreamingToolHandler
+	RecordToolGraph   bool
+	ToolObserver      ToolObserver
+	// DynamicSystemPrompt, if set, is called before each tool round from
+	// round 2 (see TextRequest.DynamicSystemPrompt).
+	DynamicSystemPrompt func(round int, lastResponse string) string
+	// RoundSystemPrompts extends the system instruction per tool round
+	// (see TextRequest.RoundSystemPrompts; Google only).
+	RoundSystemPrompts map[int]string
+	// AgentStopSignal, when set, runs the tool loop as ModeAgentLoop: a turn
+	// without tool calls ends the loop only if it contains the signal.
+	AgentStopSignal string
 
 	// Tool execution configuration
-	MaxToolRounds   *int
-	ParallelTools   *bool
-	StopOnToolError *bool
+	MaxToolRounds     *int
+	MaxTotalToolCalls *int
+	ParallelTools     *bool
+	StopOnToolError   *bool
+	CoerceToolArgs    bool
 
 	// Client-level tool configuration (from CoraConfig)
 	ToolCacheTTL     time.Duration
 	ToolCacheMaxSize int
 	ToolRetryConfig  *RetryConfig
 
+	// Provider-specific passthrough options (see TextRequest.ProviderOptions).
+	ProviderOptions map[string]any
+	// IncludeRawResponse asks for callResult.RawProviderResponse.
+	IncludeRawResponse bool
+
+	// Google Search grounding (see TextRequest.GroundWithSearch).
+	GroundWithSearch   bool
+	GroundingThreshold *float32
+
+	// VertexDataStore is the resolved data store resource name of
+	// TextRequest.VertexSearchGrounding.
+	VertexDataStore        string
+	VertexSearchMaxResults int
+
+	// CachedContentName references Gemini cached content (see TextRequest.CachedContentName).
+	CachedContentName string
+
+	// Transcribe requests a transcription of Audio instead of a chat completion.
+	Transcribe bool
+	Audio      AudioInput
+
 	// Two-step specific flag to apply proofreading prompt for this call
 	Proofread bool
+
+	// ReAct enables Thought/Action trace extraction in the tool loop.
+	ReAct bool
+
+	// logger is the client's logger (see Client.WithLogger), used by the tool loop.
+	logger *slog.Logger
+	// trace annotates the tool loop in execution traces (see CoraConfig.EnableTrace).
+	trace bool
 }
 
+// proofreadSystemPrompt is the system prompt of the proofreading step in
+// ModeTwoStepEnhance, shared by every provider.
+const proofreadSystemPrompt = "You are a writing assistant. Rewrite the user's input to correct grammar, spelling, and clarity without changing its meaning. Return only the rewritten text."
+
 // callResult is the provider-agnostic result of one call execution.
 type callResult struct {
 	Text string
@@ -55,7 +120,176 @@ type callResult struct {
 	PromptTokens     *int
 	CompletionTokens *int
 	TotalTokens      *int
+	ThinkingTokens   *int
+	CachedTokens     *int
+
+	// ReasoningTrace holds the Thought:/Action: lines collected in ReAct mode.
+	ReasoningTrace []string
+
+	TranscriptionLanguage string
 
-	// toolLoop indicates provider detected tool calls and cora executed one follow-up round.
+	ToolCallGraph *ToolCallGraph
+	// ToolRounds is the number of tool call rounds the tool loop ran.
+	ToolRounds int
+	// AgentSteps is the number of model turns of a ModeAgentLoop loop.
+	AgentSteps int
+	// RawProviderResponse is the final provider response as JSON (see
+	// TextRequest.IncludeRawResponse).
+	RawProviderResponse json.RawMessage
+
+	GroundingMetadata *GroundingMetadata
+
+	// Metadata holds provider-specific extras (see TextResponse.Metadata).
+	Metadata map[string]any
+
+	// Choices holds every completion when the plan asked for N > 1; Text and
+	// JSON are those of the first.
+	Choices []callChoice
+
+	// toolLoop indicates provider detected tool calls and cora executed the tool loop.
 	toolLoop bool
-}
\ No newline at end of file
+}
+
+// callChoice is one of several sampled completions (see callPlan.N).
+type callChoice struct {
+	Text string
+	JSON map[string]any
+	// CompletionTokens is the choice's token count, when reported.
+	CompletionTokens *int
+}
+
+// Clone returns a copy of p whose slices, maps and option pointers can be
+// modified without affecting p, so that a provider never changes a plan
+// shared with concurrent callers. Byte payloads (documents, audio) and
+// handlers are shared; nothing modifies them.
+func (p callPlan) Clone() callPlan {
+	c := p
+	c.Messages = slices.Clone(p.Messages)
+	c.Documents = slices.Clone(p.Documents)
+	c.FileHandles = slices.Clone(p.FileHandles)
+	c.AudioPart = clonePtr(p.AudioPart)
+
+	c.Temperature = clonePtr(p.Temperature)
+	c.MaxOutputTokens = clonePtr(p.MaxOutputTokens)
+	c.ReasoningEffort = clonePtr(p.ReasoningEffort)
+	c.ThinkingBudget = clonePtr(p.ThinkingBudget)
+	c.Labels = maps.Clone(p.Labels)
+
+	c.ResponseSchema = cloneJSONMap(p.ResponseSchema)
+
+	if p.Tools != nil {
+		c.Tools = make([]CoraTool, len(p.Tools))
+		for i, t := range p.Tools {
+			t.ParametersSchema = cloneJSONMap(t.ParametersSchema)
+			t.ResultSchema = cloneJSONMap(t.ResultSchema)
+			t.BuiltinOptions = cloneJSONMap(t.BuiltinOptions)
+			c.Tools[i] = t
+		}
+	}
+	c.ToolHandlers = maps.Clone(p.ToolHandlers)
+	c.StreamingHandlers = maps.Clone(p.StreamingHandlers)
+	c.RoundSystemPrompts = maps.Clone(p.RoundSystemPrompts)
+
+	c.MaxToolRounds = clonePtr(p.MaxToolRounds)
+	c.MaxTotalToolCalls = clonePtr(p.MaxTotalToolCalls)
+	c.ParallelTools = clonePtr(p.ParallelTools)
+	c.StopOnToolError = clonePtr(p.StopOnToolError)
+	c.ToolRetryConfig = clonePtr(p.ToolRetryConfig)
+
+	c.ProviderOptions = cloneJSONMap(p.ProviderOptions)
+	c.GroundingThreshold = clonePtr(p.GroundingThreshold)
+	return c
+}
+
+// clonePtr returns a pointer to a copy of *v, or nil.
+func clonePtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+// cloneJSONMap deep-copies the nested maps and slices of a JSON-like map.
+func cloneJSONMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = cloneJSONValue(v)
+	}
+	return out
+}
+
+func cloneJSONValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return cloneJSONMap(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = cloneJSONValue(e)
+		}
+		return out
+	case []string:
+		return slices.Clone(v)
+	default:
+		return v
+	}
+}
+
+// hasToolHandlers reports whether the plan can run the tool loop.
+func (p callPlan) hasToolHandlers() bool {
+	return len(p.Tools) > 0 && (len(p.ToolHandlers) > 0 || len(p.StreamingHandlers) > 0)
+}
+
+// toolExecutor returns the executor for the plan's tool loop, configured from
+// the plan's settings or the defaults (5 rounds, sequential, stop on error).
+func (p callPlan) toolExecutor() *ToolExecutor {
+	executor := NewToolExecutor(p.ToolHandlers).
+		WithValidator(p.Tools).
+		WithResultValidation(p.Tools).
+		WithSensitiveArgs(p.Tools).
+		WithLogger(p.logger).
+		WithObserver(p.ToolObserver).
+		WithLabels(p.Labels).
+		WithStreamingHandlers(p.StreamingHandlers).
+		WithConversation(append(slices.Clone(p.Messages), Message{Role: "user", Content: p.Input}))
+	executor.trace = p.trace
+	if p.MaxToolRounds != nil {
+		executor = executor.WithMaxRounds(*p.MaxToolRounds)
+	}
+	if p.MaxTotalToolCalls != nil {
+		executor = executor.WithMaxTotalCalls(*p.MaxTotalToolCalls)
+	}
+	if p.ParallelTools != nil {
+		executor = executor.WithParallel(*p.ParallelTools)
+	}
+	if p.StopOnToolError != nil {
+		executor = executor.WithStopOnError(*p.StopOnToolError)
+	}
+	if p.ToolCacheTTL > 0 && p.ToolCacheMaxSize > 0 {
+		executor = executor.WithCache(p.ToolCacheTTL, p.ToolCacheMaxSize)
+	}
+	if p.ToolRetryConfig != nil {
+		executor = executor.WithRetry(*p.ToolRetryConfig)
+	}
+	if p.CoerceToolArgs {
+		executor = executor.WithCoercion(true)
+	}
+	return executor
+}
+
+// rawResponse returns resp as JSON when plan asks for the raw provider
+// response, or nil.
+func rawResponse(plan callPlan, resp any) json.RawMessage {
+	if !plan.IncludeRawResponse {
+		return nil
+	}
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return nil
+	}
+	return b
+}
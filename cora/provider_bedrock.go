@@ -0,0 +1,329 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/aws/smithy-go"
+)
+
+func init() { registerProvider(ProviderBedrock) }
+
+// bedrockModelIDs maps short model names to Bedrock model IDs. Other names,
+// including full IDs and inference profile ARNs, are used as given.
+var bedrockModelIDs = map[string]string{
+	"claude-3-haiku":    "anthropic.claude-3-haiku-20240307-v1:0",
+	"claude-3-sonnet":   "anthropic.claude-3-sonnet-20240229-v1:0",
+	"claude-3-opus":     "anthropic.claude-3-opus-20240229-v1:0",
+	"claude-3-5-haiku":  "anthropic.claude-3-5-haiku-20241022-v1:0",
+	"claude-3-5-sonnet": "anthropic.claude-3-5-sonnet-20241022-v2:0",
+	"claude-3-7-sonnet": "anthropic.claude-3-7-sonnet-20250219-v1:0",
+}
+
+// bedrockModelID returns the Bedrock model ID for model.
+func bedrockModelID(model string) string {
+	if id, ok := bedrockModelIDs[model]; ok {
+		return id
+	}
+	return model
+}
+
+// bedrockProvider calls the Bedrock Converse API, which serves Claude and
+// the other Bedrock models behind one request shape. Credentials come from
+// the standard AWS chain (environment, shared profile, IAM role).
+type bedrockProvider struct {
+	client *bedrockruntime.Client
+}
+
+func newBedrockProvider(cfg CoraConfig, transport http.RoundTripper) (providerClient, error) {
+	var opts []func(*config.LoadOptions) error
+	if cfg.BedrockRegion != "" {
+		opts = append(opts, config.WithRegion(cfg.BedrockRegion))
+	}
+	if cfg.BedrockProfile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(cfg.BedrockProfile))
+	}
+	awsCfg, err := config.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("cora: loading AWS config for ProviderBedrock: %w", err)
+	}
+	if awsCfg.Region == "" {
+		return nil, errors.New("cora: BedrockRegion (or AWS_REGION) is required to use ProviderBedrock")
+	}
+	client := bedrockruntime.NewFromConfig(awsCfg, func(o *bedrockruntime.Options) {
+		o.HTTPClient = providerHTTPClient(cfg, transport, nil)
+		if cfg.BedrockBaseURL != "" {
+			o.BaseEndpoint = aws.String(cfg.BedrockBaseURL)
+		}
+	})
+	return &bedrockProvider{client: client}, nil
+}
+
+func (p *bedrockProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	plan = plan.Clone()
+	switch {
+	case plan.Transcribe:
+		return callResult{}, fmt.Errorf("%w: ModeTranscribe is not supported by Bedrock", ErrNotSupportedByProvider)
+	case plan.Structured:
+		return callResult{}, fmt.Errorf("%w: structured output is not supported by Bedrock", ErrNotSupportedByProvider)
+	case len(plan.Documents) > 0 || len(plan.FileHandles) > 0 || plan.AudioPart != nil:
+		return callResult{}, fmt.Errorf("%w: Bedrock accepts text input only", ErrNotSupportedByProvider)
+	case plan.CachedContentName != "":
+		return callResult{}, fmt.Errorf("%w: CachedContentName is only supported by Google", ErrNotSupportedByProvider)
+	case len(plan.RoundSystemPrompts) > 0:
+		return callResult{}, fmt.Errorf("%w: RoundSystemPrompts is only supported by Google", ErrNotSupportedByProvider)
+	case plan.AgentStopSignal != "":
+		return callResult{}, fmt.Errorf("%w: ModeAgentLoop is only supported by OpenAI and Google", ErrNotSupportedByProvider)
+	}
+
+	in := bedrockInputFromPlan(plan)
+	if plan.hasToolHandlers() {
+		cr, err := p.executeToolLoop(ctx, in, plan)
+		if err != nil {
+			return callResult{}, err
+		}
+		cr.toolLoop = true
+		return cr, nil
+	}
+	out, err := p.converse(ctx, in)
+	if err != nil {
+		return callResult{}, err
+	}
+	return bedrockCallResult(out), nil
+}
+
+// bedrockInputFromPlan builds the Converse request for plan. Bedrock keeps
+// system prompts apart from the conversation, so "system" messages are
+// moved there.
+func bedrockInputFromPlan(plan callPlan) *bedrockruntime.ConverseInput {
+	in := &bedrockruntime.ConverseInput{
+		ModelId:         aws.String(bedrockModelID(plan.Model)),
+		InferenceConfig: &types.InferenceConfiguration{Temperature: plan.Temperature},
+	}
+	if plan.MaxOutputTokens != nil {
+		in.InferenceConfig.MaxTokens = aws.Int32(int32(*plan.MaxOutputTokens))
+	}
+
+	system := plan.System
+	if plan.Proofread {
+		system = proofreadSystemPrompt
+		if plan.Temperature == nil {
+			in.InferenceConfig.Temperature = aws.Float32(0.2)
+		}
+	} else {
+		for _, m := range plan.Messages {
+			role := types.ConversationRoleUser
+			switch messageRole(m.Role) {
+			case "system":
+				in.System = append(in.System, &types.SystemContentBlockMemberText{Value: m.Content})
+				continue
+			case "assistant":
+				role = types.ConversationRoleAssistant
+			}
+			in.Messages = append(in.Messages, bedrockTextMessage(role, m.Content))
+		}
+	}
+	if system != "" {
+		in.System = append([]types.SystemContentBlock{&types.SystemContentBlockMemberText{Value: system}}, in.System...)
+	}
+	// As on the other providers, an empty Input adds no turn after Messages;
+	// Converse rejects empty content.
+	if plan.Input != "" || len(in.Messages) == 0 {
+		in.Messages = append(in.Messages, bedrockTextMessage(types.ConversationRoleUser, plan.Input))
+	}
+
+	if len(plan.Tools) > 0 && !plan.Proofread {
+		in.ToolConfig = &types.ToolConfiguration{}
+		for _, t := range plan.Tools {
+			schema := t.ParametersSchema
+			if schema == nil {
+				schema = map[string]any{"type": "object", "properties": map[string]any{}}
+			}
+			in.ToolConfig.Tools = append(in.ToolConfig.Tools, &types.ToolMemberToolSpec{Value: types.ToolSpecification{
+				Name:        aws.String(t.Name),
+				Description: aws.String(t.Description),
+				InputSchema: &types.ToolInputSchemaMemberJson{Value: document.NewLazyDocument(schema)},
+			}})
+		}
+	}
+	return in
+}
+
+func bedrockTextMessage(role types.ConversationRole, text string) types.Message {
+	return types.Message{Role: role, Content: []types.ContentBlock{&types.ContentBlockMemberText{Value: text}}}
+}
+
+// executeToolLoop handles multi-round tool calling for Bedrock.
+func (p *bedrockProvider) executeToolLoop(ctx context.Context, in *bedrockruntime.ConverseInput, plan callPlan) (_ callResult, err error) {
+	defer traceRegion(ctx, plan.trace, "cora.executeToolLoop")()
+	executor := plan.toolExecutor()
+
+	roundCount := 0
+	// toolRounds counts the rounds in which the model called tools.
+	toolRounds := 0
+	var trace []string
+	var graph *ToolCallGraph
+	if plan.RecordToolGraph {
+		graph = &ToolCallGraph{}
+	}
+
+	var lastCalls []string
+	// Terminal errors carry the state of the loop.
+	defer func() {
+		err = newToolLoopError(err, plan, roundCount, executor.maxRounds, lastCalls, len(in.Messages))
+	}()
+
+	for {
+		roundCount++
+		if roundCount > executor.maxRounds {
+			return callResult{}, fmt.Errorf("exceeded maximum tool call rounds (%d)", executor.maxRounds)
+		}
+
+		out, err := p.converse(ctx, in)
+		if err != nil {
+			return callResult{}, err
+		}
+		msg, _ := out.Output.(*types.ConverseOutputMemberMessage)
+		if plan.ReAct {
+			trace = append(trace, parseReActTrace(bedrockText(out))...)
+		}
+
+		var uses []types.ToolUseBlock
+		if msg != nil {
+			for _, block := range msg.Value.Content {
+				if tu, ok := block.(*types.ContentBlockMemberToolUse); ok {
+					uses = append(uses, tu.Value)
+				}
+			}
+		}
+		if len(uses) == 0 {
+			cr := bedrockCallResult(out)
+			cr.ToolCallGraph = graph
+			cr.ToolRounds = toolRounds
+			if plan.ReAct {
+				cr.Text = reActFinalAnswer(cr.Text)
+				cr.ReasoningTrace = trace
+			}
+			return cr, nil
+		}
+
+		// The assistant turn with its tool uses goes back into the history.
+		in.Messages = append(in.Messages, msg.Value)
+
+		toolRounds++
+
+		// Over the call budget: skip this round's calls and ask for an answer.
+		// Bedrock requires a result for every tool use, so each gets the note.
+		if note, over := executor.callBudgetNote(len(uses)); over {
+			results := make([]types.ContentBlock, len(uses))
+			for i, use := range uses {
+				results[i] = bedrockToolResult(use.ToolUseId, map[string]any{"error": note})
+			}
+			in.Messages = append(in.Messages, types.Message{Role: types.ConversationRoleUser, Content: results})
+			continue
+		}
+
+		calls := make([]toolCallRequest, len(uses))
+		for i, use := range uses {
+			var args map[string]any
+			if use.Input != nil {
+				if err := use.Input.UnmarshalSmithyDocument(&args); err != nil {
+					return callResult{}, fmt.Errorf("cora: decoding arguments of tool %s: %w", aws.ToString(use.Name), err)
+				}
+			}
+			calls[i] = toolCallRequest{name: aws.ToString(use.Name), args: args}
+		}
+		lastCalls = toolCallNames(calls)
+		results, err := executor.executeRound(ctx, roundCount, calls)
+		if graph != nil {
+			for i, r := range results {
+				graph.AddCall(roundCount, calls[i].name, executor.maskArgs(calls[i].name, calls[i].args), r.result, r.duration)
+			}
+		}
+		if err != nil {
+			return callResult{}, err
+		}
+
+		blocks := make([]types.ContentBlock, 0, len(results))
+		for i, result := range results {
+			payloads := []map[string]any{functionResponsePayload(result.result)}
+			if result.partials != nil {
+				payloads = payloads[:0]
+				for _, partial := range result.partials {
+					payloads = append(payloads, functionResponsePayload(partial))
+				}
+			}
+			blocks = append(blocks, bedrockToolResult(uses[i].ToolUseId, payloads...))
+		}
+		in.Messages = append(in.Messages, types.Message{Role: types.ConversationRoleUser, Content: blocks})
+	}
+}
+
+// bedrockToolResult returns the tool result block answering toolUseID, with
+// one JSON content block per payload.
+func bedrockToolResult(toolUseID *string, payloads ...map[string]any) types.ContentBlock {
+	content := make([]types.ToolResultContentBlock, len(payloads))
+	for i, payload := range payloads {
+		content[i] = &types.ToolResultContentBlockMemberJson{Value: document.NewLazyDocument(payload)}
+	}
+	return &types.ContentBlockMemberToolResult{Value: types.ToolResultBlock{ToolUseId: toolUseID, Content: content}}
+}
+
+// converse sends in to the Converse API. Service errors are returned as a
+// CoraError carrying the HTTP status code and Bedrock's message.
+func (p *bedrockProvider) converse(ctx context.Context, in *bedrockruntime.ConverseInput) (*bedrockruntime.ConverseOutput, error) {
+	out, err := p.client.Converse(ctx, in)
+	if err == nil {
+		return out, nil
+	}
+	var respErr *awshttp.ResponseError
+	if !errors.As(err, &respErr) {
+		return nil, err
+	}
+	msg := respErr.Error()
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		msg = apiErr.ErrorMessage()
+	}
+	return nil, newHTTPCoraError(ProviderBedrock, respErr.HTTPStatusCode(), msg, err)
+}
+
+// bedrockText joins the text blocks of the output message.
+func bedrockText(out *bedrockruntime.ConverseOutput) string {
+	msg, ok := out.Output.(*types.ConverseOutputMemberMessage)
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	for _, block := range msg.Value.Content {
+		if text, ok := block.(*types.ContentBlockMemberText); ok {
+			b.WriteString(text.Value)
+		}
+	}
+	return b.String()
+}
+
+func bedrockCallResult(out *bedrockruntime.ConverseOutput) callResult {
+	res := callResult{Text: bedrockText(out)}
+	if u := out.Usage; u != nil {
+		pt, ct := int(aws.ToInt32(u.InputTokens)), int(aws.ToInt32(u.OutputTokens))
+		tt := pt + ct
+		res.PromptTokens = &pt
+		res.CompletionTokens = &ct
+		res.TotalTokens = &tt
+		if u.CacheReadInputTokens != nil {
+			cached := int(*u.CacheReadInputTokens)
+			res.CachedTokens = &cached
+		}
+	}
+	return res
+}
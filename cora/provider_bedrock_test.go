@@ -0,0 +1,166 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+// bedrockTestServer serves the Converse API at /model/{id}/converse,
+// answering each decoded request with respond.
+func bedrockTestServer(t *testing.T, respond func(req map[string]any) map[string]any) *httptest.Server {
+	t.Helper()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/model/anthropic.claude-3-5-sonnet-20241022-v2:0/converse" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") == "" {
+			t.Error("request is not signed")
+		}
+		var req map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(respond(req))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func bedrockAssistant(content ...map[string]any) map[string]any {
+	return map[string]any{
+		"output":     map[string]any{"message": map[string]any{"role": "assistant", "content": content}},
+		"stopReason": "end_turn",
+		"usage":      map[string]any{"inputTokens": 12, "outputTokens": 4, "totalTokens": 16},
+	}
+}
+
+func TestBedrockProvider_Text(t *testing.T) {
+	var got map[string]any
+	srv := bedrockTestServer(t, func(req map[string]any) map[string]any {
+		got = req
+		return bedrockAssistant(map[string]any{"text": "Hola"})
+	})
+
+	c := New(CoraConfig{BedrockRegion: "us-east-1", BedrockBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderBedrock,
+		Model:    "claude-3-5-sonnet",
+		System:   "Be terse.",
+		Input:    "Say hi in Spanish",
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "Hola" {
+		t.Errorf("Text = %q, want Hola", resp.Text)
+	}
+	if resp.TotalTokens == nil || *resp.TotalTokens != 16 {
+		t.Errorf("expected 16 total tokens, got %v", resp.TotalTokens)
+	}
+	system, _ := got["system"].([]any)
+	if len(system) != 1 || system[0].(map[string]any)["text"] != "Be terse." {
+		t.Errorf("unexpected system %v", got["system"])
+	}
+}
+
+func TestBedrockProvider_ToolLoop(t *testing.T) {
+	var reqs []map[string]any
+	srv := bedrockTestServer(t, func(req map[string]any) map[string]any {
+		reqs = append(reqs, req)
+		if len(reqs) == 1 {
+			return bedrockAssistant(map[string]any{"toolUse": map[string]any{
+				"toolUseId": "tu_1",
+				"name":      "get_weather",
+				"input":     map[string]any{"city": "Paris"},
+			}})
+		}
+		return bedrockAssistant(map[string]any{"text": "It is sunny in Paris."})
+	})
+
+	var city string
+	c := New(CoraConfig{BedrockRegion: "us-east-1", BedrockBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderBedrock,
+		Model:    "anthropic.claude-3-5-sonnet-20241022-v2:0",
+		Mode:     ModeToolCalling,
+		Input:    "Weather in Paris?",
+		Tools: []CoraTool{{
+			Name:             "get_weather",
+			Description:      "Get the weather",
+			ParametersSchema: map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}},
+		}},
+		ToolHandlers: map[string]CoraToolHandler{
+			"get_weather": func(ctx context.Context, args map[string]any) (any, error) {
+				city, _ = args["city"].(string)
+				return map[string]any{"forecast": "sunny"}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "It is sunny in Paris." || city != "Paris" {
+		t.Errorf("unexpected result %q (city %q)", resp.Text, city)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(reqs))
+	}
+	// user, assistant tool use, user tool result
+	msgs, _ := reqs[1]["messages"].([]any)
+	if len(msgs) != 3 {
+		t.Fatalf("expected 3 messages in the second request, got %d", len(msgs))
+	}
+	content := msgs[2].(map[string]any)["content"].([]any)
+	result, _ := content[0].(map[string]any)["toolResult"].(map[string]any)
+	if result["toolUseId"] != "tu_1" {
+		t.Errorf("unexpected tool result %v", content[0])
+	}
+}
+
+func TestBedrockProvider_Error(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Amzn-ErrorType", "AccessDeniedException")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message":"no access to model"}`))
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{BedrockRegion: "us-east-1", BedrockBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{Provider: ProviderBedrock, Model: "claude-3-5-sonnet", Input: "hi"})
+	var ce *CoraError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected CoraError, got %v", err)
+	}
+	if ce.HTTPStatusCode != http.StatusForbidden || ce.Message != "no access to model" {
+		t.Errorf("unexpected error %+v", ce)
+	}
+}
+
+func TestBedrockInputFromPlan_Messages(t *testing.T) {
+	in := bedrockInputFromPlan(callPlan{Model: "claude-3-5-sonnet", Messages: []Message{
+		{Role: "user", Content: "Hi"},
+		{Role: "model", Content: "Hello"},
+		{Role: "user", Content: "How are you?"},
+	}})
+	if len(in.Messages) != 3 {
+		t.Fatalf("expected 3 turns and no empty one, got %d", len(in.Messages))
+	}
+	if in.Messages[1].Role != types.ConversationRoleAssistant {
+		t.Errorf("model turn sent as %q, want assistant", in.Messages[1].Role)
+	}
+	if in.Messages[2].Role != types.ConversationRoleUser {
+		t.Errorf("last turn sent as %q, want user", in.Messages[2].Role)
+	}
+}
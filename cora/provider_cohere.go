@@ -0,0 +1,370 @@
+package cora
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+func init() { registerProvider(ProviderCohere) }
+
+const defaultCohereBaseURL = "https://api.cohere.com/v1"
+
+// cohereProvider calls the Cohere Chat API, which is not OpenAI-compatible:
+// the current turn is sent as "message" with earlier turns in
+// "chat_history", and tool results go back in "tool_results".
+type cohereProvider struct {
+	http    *http.Client
+	baseURL string
+	apiKey  string
+}
+
+func newCohereProvider(cfg CoraConfig, transport http.RoundTripper) (providerClient, error) {
+	if cfg.CohereAPIKey == "" {
+		return nil, errors.New("cora: Cohere key is required to use ProviderCohere")
+	}
+	return &cohereProvider{
+		http:    providerHTTPClient(cfg, transport, nil),
+		baseURL: strings.TrimSuffix(cmp.Or(cfg.CohereBaseURL, defaultCohereBaseURL), "/"),
+		apiKey:  cfg.CohereAPIKey,
+	}, nil
+}
+
+type cohereChatRequest struct {
+	Model          string                `json:"model"`
+	Message        string                `json:"message"`
+	Preamble       string                `json:"preamble,omitempty"`
+	ChatHistory    []cohereMessage       `json:"chat_history,omitempty"`
+	Temperature    *float32              `json:"temperature,omitempty"`
+	MaxTokens      *int                  `json:"max_tokens,omitempty"`
+	ResponseFormat *cohereResponseFormat `json:"response_format,omitempty"`
+	Tools          []cohereTool          `json:"tools,omitempty"`
+	ToolResults    []cohereToolResult    `json:"tool_results,omitempty"`
+}
+
+type cohereMessage struct {
+	Role      string           `json:"role"` // USER, CHATBOT or SYSTEM
+	Message   string           `json:"message"`
+	ToolCalls []cohereToolCall `json:"tool_calls,omitempty"`
+}
+
+type cohereResponseFormat struct {
+	Type   string         `json:"type"`
+	Schema map[string]any `json:"schema,omitempty"`
+}
+
+type cohereTool struct {
+	Name                 string                         `json:"name"`
+	Description          string                         `json:"description"`
+	ParameterDefinitions map[string]cohereParameterSpec `json:"parameter_definitions,omitempty"`
+}
+
+type cohereParameterSpec struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+}
+
+type cohereToolCall struct {
+	Name       string         `json:"name"`
+	Parameters map[string]any `json:"parameters"`
+}
+
+type cohereToolResult struct {
+	Call    cohereToolCall   `json:"call"`
+	Outputs []map[string]any `json:"outputs"`
+}
+
+type cohereChatResponse struct {
+	Text         string           `json:"text"`
+	FinishReason string           `json:"finish_reason"` // e.g. "COMPLETE", "MAX_TOKENS"
+	ToolCalls    []cohereToolCall `json:"tool_calls"`
+	Meta         struct {
+		BilledUnits struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"billed_units"`
+	} `json:"meta"`
+}
+
+func (p *cohereProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	plan = plan.Clone()
+	ctx = withLabelsHeader(ctx, plan.Labels)
+	switch {
+	case plan.Transcribe:
+		return callResult{}, fmt.Errorf("%w: ModeTranscribe is not supported by Cohere", ErrNotSupportedByProvider)
+	case len(plan.Documents) > 0 || len(plan.FileHandles) > 0 || plan.AudioPart != nil:
+		return callResult{}, fmt.Errorf("%w: Cohere chat accepts text input only", ErrNotSupportedByProvider)
+	case plan.CachedContentName != "":
+		return callResult{}, fmt.Errorf("%w: CachedContentName is only supported by Google", ErrNotSupportedByProvider)
+	case len(plan.RoundSystemPrompts) > 0:
+		return callResult{}, fmt.Errorf("%w: RoundSystemPrompts is only supported by Google", ErrNotSupportedByProvider)
+	case plan.AgentStopSignal != "":
+		return callResult{}, fmt.Errorf("%w: ModeAgentLoop is only supported by OpenAI and Google", ErrNotSupportedByProvider)
+	}
+
+	req := cohereRequestFromPlan(plan)
+	if plan.hasToolHandlers() {
+		cr, err := p.executeToolLoop(ctx, req, plan)
+		if err != nil {
+			return callResult{}, err
+		}
+		cr.toolLoop = true
+		return cr, nil
+	}
+	resp, err := p.chat(ctx, req)
+	if err != nil {
+		return callResult{}, err
+	}
+	return resp.toCallResult(), nil
+}
+
+// cohereRequestFromPlan builds the chat request for plan.
+func cohereRequestFromPlan(plan callPlan) cohereChatRequest {
+	req := cohereChatRequest{
+		Model:       plan.Model,
+		Message:     plan.Input,
+		Preamble:    plan.System,
+		Temperature: plan.Temperature,
+		MaxTokens:   plan.MaxOutputTokens,
+	}
+	if plan.Proofread {
+		req.Preamble = proofreadSystemPrompt
+		if req.Temperature == nil {
+			t := float32(0.2)
+			req.Temperature = &t
+		}
+		return req
+	}
+	for _, m := range plan.Messages {
+		role := "USER"
+		switch messageRole(m.Role) {
+		case "assistant":
+			role = "CHATBOT"
+		case "system":
+			role = "SYSTEM"
+		}
+		req.ChatHistory = append(req.ChatHistory, cohereMessage{Role: role, Message: m.Content})
+	}
+	// Cohere requires a message: without Input, the last user turn of the
+	// history is the one answered.
+	if n := len(req.ChatHistory); req.Message == "" && n > 0 && req.ChatHistory[n-1].Role == "USER" {
+		req.Message = req.ChatHistory[n-1].Message
+		req.ChatHistory = req.ChatHistory[:n-1]
+	}
+	if plan.Structured && len(plan.ResponseSchema) > 0 {
+		req.ResponseFormat = &cohereResponseFormat{Type: "json_object", Schema: plan.ResponseSchema}
+	}
+	for _, t := range plan.Tools {
+		req.Tools = append(req.Tools, cohereTool{
+			Name:                 t.Name,
+			Description:          t.Description,
+			ParameterDefinitions: cohereParameterDefinitions(t.ParametersSchema),
+		})
+	}
+	return req
+}
+
+// cohereParameterDefinitions flattens a JSON schema object into Cohere's
+// parameter_definitions: one entry per top-level property. Nested schemas
+// are described by their type only.
+func cohereParameterDefinitions(schema map[string]any) map[string]cohereParameterSpec {
+	props, _ := schema["properties"].(map[string]any)
+	if len(props) == 0 {
+		return nil
+	}
+	required := map[string]bool{}
+	switch r := schema["required"].(type) {
+	case []string:
+		for _, name := range r {
+			required[name] = true
+		}
+	case []any:
+		for _, name := range r {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+	defs := make(map[string]cohereParameterSpec, len(props))
+	for name, v := range props {
+		prop, _ := v.(map[string]any)
+		typ, _ := prop["type"].(string)
+		desc, _ := prop["description"].(string)
+		defs[name] = cohereParameterSpec{Type: cohereParameterType(typ), Description: desc, Required: required[name]}
+	}
+	return defs
+}
+
+// cohereParameterType maps a JSON schema type to Cohere's Python-style type names.
+func cohereParameterType(t string) string {
+	switch t {
+	case "string":
+		return "str"
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "list"
+	case "object":
+		return "dict"
+	}
+	return "str"
+}
+
+// executeToolLoop handles multi-round tool calling for Cohere.
+func (p *cohereProvider) executeToolLoop(ctx context.Context, req cohereChatRequest, plan callPlan) (_ callResult, err error) {
+	defer traceRegion(ctx, plan.trace, "cora.executeToolLoop")()
+	executor := plan.toolExecutor()
+
+	roundCount := 0
+	// toolRounds counts the rounds in which the model called tools.
+	toolRounds := 0
+	var trace []string
+	var graph *ToolCallGraph
+	if plan.RecordToolGraph {
+		graph = &ToolCallGraph{}
+	}
+
+	var lastCalls []string
+	// Terminal errors carry the state of the loop.
+	defer func() {
+		err = newToolLoopError(err, plan, roundCount, executor.maxRounds, lastCalls, len(req.ChatHistory)+1)
+	}()
+
+	for {
+		roundCount++
+		if roundCount > executor.maxRounds {
+			return callResult{}, fmt.Errorf("exceeded maximum tool call rounds (%d)", executor.maxRounds)
+		}
+
+		resp, err := p.chat(ctx, req)
+		if err != nil {
+			return callResult{}, err
+		}
+		if plan.ReAct {
+			trace = append(trace, parseReActTrace(resp.Text)...)
+		}
+
+		if len(resp.ToolCalls) == 0 {
+			cr := resp.toCallResult()
+			cr.ToolCallGraph = graph
+			cr.ToolRounds = toolRounds
+			if plan.ReAct {
+				cr.Text = reActFinalAnswer(cr.Text)
+				cr.ReasoningTrace = trace
+			}
+			return cr, nil
+		}
+
+		// The current message moves to the history, followed by the
+		// model's tool calls; the next turn carries their results.
+		req.ChatHistory = append(req.ChatHistory,
+			cohereMessage{Role: "USER", Message: req.Message},
+			cohereMessage{Role: "CHATBOT", Message: resp.Text, ToolCalls: resp.ToolCalls},
+		)
+		req.Message = ""
+		req.ToolResults = nil
+
+		toolRounds++
+
+		// Over the call budget: skip this round's calls and ask for an answer.
+		if note, over := executor.callBudgetNote(len(resp.ToolCalls)); over {
+			req.Message = note
+			req.Tools = nil
+			continue
+		}
+
+		calls := make([]toolCallRequest, len(resp.ToolCalls))
+		for i, tc := range resp.ToolCalls {
+			calls[i] = toolCallRequest{name: tc.Name, args: tc.Parameters}
+		}
+		lastCalls = toolCallNames(calls)
+		results, err := executor.executeRound(ctx, roundCount, calls)
+		if graph != nil {
+			for i, r := range results {
+				graph.AddCall(roundCount, calls[i].name, executor.maskArgs(calls[i].name, calls[i].args), r.result, r.duration)
+			}
+		}
+		if err != nil {
+			return callResult{}, err
+		}
+
+		for i, result := range results {
+			outputs := []map[string]any{functionResponsePayload(result.result)}
+			if result.partials != nil {
+				outputs = outputs[:0]
+				for _, partial := range result.partials {
+					outputs = append(outputs, functionResponsePayload(partial))
+				}
+			}
+			req.ToolResults = append(req.ToolResults, cohereToolResult{Call: resp.ToolCalls[i], Outputs: outputs})
+		}
+	}
+}
+
+// chat sends req to the chat endpoint. Error responses are returned as a
+// CoraError carrying Cohere's status code and message.
+func (p *cohereProvider) chat(ctx context.Context, req cohereChatRequest) (cohereChatResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return cohereChatResponse{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat", bytes.NewReader(body))
+	if err != nil {
+		return cohereChatResponse{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	httpResp, err := p.http.Do(httpReq)
+	if err != nil {
+		return cohereChatResponse{}, err
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return cohereChatResponse{}, err
+	}
+	if httpResp.StatusCode/100 != 2 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		msg := strings.TrimSpace(string(data))
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			msg = apiErr.Message
+		}
+		return cohereChatResponse{}, newHTTPCoraError(ProviderCohere, httpResp.StatusCode, msg, nil)
+	}
+
+	var resp cohereChatResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return cohereChatResponse{}, fmt.Errorf("cora: decoding Cohere response: %w", err)
+	}
+	return resp, nil
+}
+
+func (r cohereChatResponse) toCallResult() callResult {
+	res := callResult{Text: r.Text}
+	var m map[string]any
+	if json.Unmarshal([]byte(r.Text), &m) == nil {
+		res.JSON = m
+	}
+	if u := r.Meta.BilledUnits; u.InputTokens+u.OutputTokens > 0 {
+		pt, ct, tt := u.InputTokens, u.OutputTokens, u.InputTokens+u.OutputTokens
+		res.PromptTokens = &pt
+		res.CompletionTokens = &ct
+		res.TotalTokens = &tt
+	}
+	return res
+}
@@ -0,0 +1,149 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCohereProvider_Text(t *testing.T) {
+	var got cohereChatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat" {
+			http.NotFound(w, r)
+			return
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer co-test" {
+			t.Errorf("unexpected Authorization header %q", auth)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"text":          `{"answer":"hola"}`,
+			"finish_reason": "COMPLETE",
+			"meta":          map[string]any{"billed_units": map[string]any{"input_tokens": 7, "output_tokens": 3}},
+		})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{CohereAPIKey: "co-test", CohereBaseURL: srv.URL + "/v1"})
+	schema := map[string]any{"type": "object", "properties": map[string]any{"answer": map[string]any{"type": "string"}}}
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider:       ProviderCohere,
+		Model:          "command-r-plus",
+		Mode:           ModeStructuredJSON,
+		System:         "Be terse.",
+		Input:          "Say hi in Spanish",
+		ResponseSchema: schema,
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if got.Message != "Say hi in Spanish" || got.Preamble != "Be terse." || got.Model != "command-r-plus" {
+		t.Errorf("unexpected request %+v", got)
+	}
+	if got.ResponseFormat == nil || got.ResponseFormat.Type != "json_object" {
+		t.Errorf("expected json_object response_format, got %+v", got.ResponseFormat)
+	}
+	if resp.JSON["answer"] != "hola" {
+		t.Errorf("unexpected JSON %v", resp.JSON)
+	}
+	if resp.TotalTokens == nil || *resp.TotalTokens != 10 {
+		t.Errorf("expected 10 total tokens, got %v", resp.TotalTokens)
+	}
+}
+
+func TestCohereProvider_ToolLoop(t *testing.T) {
+	var reqs []cohereChatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cohereChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		reqs = append(reqs, req)
+		w.Header().Set("Content-Type", "application/json")
+		if len(req.ToolResults) == 0 {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"text":       "",
+				"tool_calls": []map[string]any{{"name": "get_weather", "parameters": map[string]any{"city": "Paris"}}},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"text": "It is sunny in Paris.", "finish_reason": "COMPLETE"})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{CohereAPIKey: "co-test", CohereBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderCohere,
+		Model:    "command-r",
+		Mode:     ModeToolCalling,
+		Input:    "Weather in Paris?",
+		Tools: []CoraTool{{
+			Name:        "get_weather",
+			Description: "Current weather for a city",
+			ParametersSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"city": map[string]any{"type": "string", "description": "City name"}},
+				"required":   []string{"city"},
+			},
+		}},
+		ToolHandlers: map[string]CoraToolHandler{
+			"get_weather": func(ctx context.Context, args map[string]any) (any, error) {
+				return "sunny in " + args["city"].(string), nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "It is sunny in Paris." {
+		t.Errorf("unexpected text %q", resp.Text)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 chat calls, got %d", len(reqs))
+	}
+	if def := reqs[0].Tools[0].ParameterDefinitions["city"]; def.Type != "str" || !def.Required {
+		t.Errorf("unexpected parameter definition %+v", def)
+	}
+	second := reqs[1]
+	if len(second.ChatHistory) != 2 || second.ChatHistory[1].Role != "CHATBOT" || len(second.ChatHistory[1].ToolCalls) != 1 {
+		t.Errorf("unexpected chat history %+v", second.ChatHistory)
+	}
+	if len(second.ToolResults) != 1 || second.ToolResults[0].Outputs[0]["output"] != "sunny in Paris" {
+		t.Errorf("unexpected tool results %+v", second.ToolResults)
+	}
+}
+
+func TestCohereProvider_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"trial key rate limit exceeded"}`))
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{CohereAPIKey: "co-test", CohereBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{Provider: ProviderCohere, Model: "command-r", Input: "hi"})
+	var ce *CoraError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected *CoraError, got %v", err)
+	}
+	if ce.HTTPStatusCode != http.StatusTooManyRequests || ce.Provider != ProviderCohere {
+		t.Errorf("unexpected error %+v", ce)
+	}
+}
+
+func TestCohereRequestFromPlan_Messages(t *testing.T) {
+	req := cohereRequestFromPlan(callPlan{Model: "command-r", Messages: []Message{
+		{Role: "user", Content: "Hi"},
+		{Role: "model", Content: "Hello"},
+		{Role: "user", Content: "How are you?"},
+	}})
+	if req.Message != "How are you?" {
+		t.Errorf("Message = %q, want the last user turn", req.Message)
+	}
+	if len(req.ChatHistory) != 2 || req.ChatHistory[1].Role != "CHATBOT" {
+		t.Errorf("unexpected chat history %+v", req.ChatHistory)
+	}
+}
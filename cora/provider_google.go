@@ -1,68 +1,82 @@
 package cora
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
 	"strings"
 
 	"google.golang.org/genai"
 )
 
+func init() { registerProvider(ProviderGoogle) }
+
 type googleProvider struct {
 	client *genai.Client
 }
 
-func newGoogleProvider(cfg CoraConfig) (providerClient, error) {
-	if cfg.GoogleAPIKey == "" {
-		return nil, errors.New("cora: Google API key is required to use ProviderGoogle")
+func newGoogleProvider(cfg CoraConfig, transport http.RoundTripper) (providerClient, error) {
+	gc, err := googleClientConfig(cfg, transport)
+	if err != nil {
+		return nil, err
 	}
-	gc, err := genai.NewClient(context.Background(), &genai.ClientConfig{
-		APIKey: cfg.GoogleAPIKey,
-		HTTPOptions: genai.HTTPOptions{
-			BaseURL: cfg.GoogleBaseURL,
-		},
-		// Backend: default Gemini Developer API for this step.
-	})
+	client, err := genai.NewClient(context.Background(), gc)
 	if err != nil {
 		return nil, err
 	}
-	return &googleProvider{client: gc}, nil
+	return &googleProvider{client: client}, nil
 }
 
-func (p *googleProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
-	if plan.Proofread {
-		return p.proofread(ctx, plan)
+// googleClientConfig returns the genai client configuration for cfg. With
+// GoogleBackendVertex the client targets Vertex AI in GoogleProject and
+// GoogleLocation and authenticates with Application Default Credentials;
+// otherwise it uses the Gemini Developer API, which needs GoogleAPIKey.
+func googleClientConfig(cfg CoraConfig, transport http.RoundTripper) (*genai.ClientConfig, error) {
+	gc := &genai.ClientConfig{
+		HTTPClient: providerHTTPClient(cfg, transport, cfg.GoogleHTTPClient),
+		HTTPOptions: genai.HTTPOptions{
+			BaseURL: cfg.GoogleBaseURL,
+		},
 	}
-
-	// --- Common Config Setup ---
-	cfg := &genai.GenerateContentConfig{}
-	if strings.TrimSpace(plan.System) != "" {
-		cfg.SystemInstruction = &genai.Content{
-			Parts: []*genai.Part{{Text: plan.System}},
+	if cfg.GoogleBackend == GoogleBackendVertex {
+		if cfg.GoogleProject == "" || cfg.GoogleLocation == "" {
+			return nil, errors.New("cora: GoogleProject and GoogleLocation are required to use ProviderGoogle on Vertex AI")
 		}
+		gc.Backend = genai.BackendVertexAI
+		gc.Project = cfg.GoogleProject
+		gc.Location = cfg.GoogleLocation
+		return gc, nil
 	}
-	if plan.Temperature != nil {
-		cfg.Temperature = genai.Ptr[float32](*plan.Temperature)
-	}
-	if plan.MaxOutputTokens != nil {
-		cfg.MaxOutputTokens = int32(*plan.MaxOutputTokens)
+
+	if cfg.GoogleAPIKey == "" {
+		return nil, errors.New("cora: Google API key is required to use ProviderGoogle")
 	}
-	if len(plan.Labels) > 0 {
-		cfg.Labels = plan.Labels
+	gc.APIKey = cfg.GoogleAPIKey
+	if cfg.GoogleBackend == GoogleBackendGemini {
+		gc.Backend = genai.BackendGeminiAPI
 	}
+	return gc, nil
+}
 
-	// Structured JSON
-	if plan.Structured && len(plan.ResponseSchema) > 0 {
-		cfg.ResponseMIMEType = "application/json"
-		cfg.ResponseJsonSchema = plan.ResponseSchema
+func (p *googleProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	plan = plan.Clone()
+	if plan.Proofread {
+		return p.proofread(ctx, plan)
+	}
+	if plan.Transcribe {
+		return p.transcribe(ctx, plan)
 	}
 
+	// --- Common Config Setup ---
+	cfg := googleConfigFromPlan(plan)
+
 	// --- Tool Calling Path: Delegate to executeToolLoop ---
 	// Check if this is a tool-calling request
-	if len(plan.Tools) > 0 && len(plan.ToolHandlers) > 0 {
+	if plan.hasToolHandlers() {
 		// Configure tools for the loop
-		cfg.Tools = toGenAITools(plan.Tools)
+		cfg.Tools = append(cfg.Tools, toGenAITools(plan.Tools)...)
 		cfg.ToolConfig = &genai.ToolConfig{
 			FunctionCallingConfig: &genai.FunctionCallingConfig{
 				Mode: genai.FunctionCallingConfigModeAny,
@@ -71,9 +85,7 @@ func (p *googleProvider) Text(ctx context.Context, plan callPlan) (callResult, e
 
 		// Build the initial history for the tool loop.
 		// It must be in the []*genai.Content format.
-		initialHistory := []*genai.Content{
-			{Role: "user", Parts: []*genai.Part{{Text: plan.Input}}},
-		}
+		initialHistory := googleContents(plan.Messages, plan.Input, withAudioPart(plan.Documents, plan.AudioPart), plan.FileHandles)
 
 		// DELEGATE TO THE TOOL LOOP
 		cr, err := p.executeToolLoop(ctx, plan.Model, initialHistory, cfg, plan)
@@ -86,22 +98,97 @@ func (p *googleProvider) Text(ctx context.Context, plan callPlan) (callResult, e
 
 	// --- Original Path (No Tools) ---
 	// If not tool calling, proceed with the simple GenerateContent call.
-	contents := genai.Text(plan.Input)
+	contents := googleContents(plan.Messages, plan.Input, withAudioPart(plan.Documents, plan.AudioPart), plan.FileHandles)
 	res, err := p.client.Models.GenerateContent(ctx, plan.Model, contents, cfg)
 	if err != nil {
 		return callResult{}, err
 	}
 	cr := toCallResultFromGenAI(res)
+	cr.RawProviderResponse = rawResponse(plan, res)
 
 	return cr, nil
 }
 
+// googleConfigFromPlan builds the GenerateContentConfig shared by the plain and
+// tool-calling paths.
+func googleConfigFromPlan(plan callPlan) *genai.GenerateContentConfig {
+	cfg := &genai.GenerateContentConfig{}
+	if strings.TrimSpace(plan.System) != "" {
+		cfg.SystemInstruction = &genai.Content{
+			Parts: []*genai.Part{{Text: plan.System}},
+		}
+	}
+	if plan.Temperature != nil {
+		cfg.Temperature = genai.Ptr[float32](*plan.Temperature)
+	}
+	if plan.MaxOutputTokens != nil {
+		cfg.MaxOutputTokens = int32(*plan.MaxOutputTokens)
+	}
+	if plan.N > 1 {
+		cfg.CandidateCount = int32(plan.N)
+	}
+	if len(plan.Labels) > 0 {
+		cfg.Labels = plan.Labels
+	}
+	if plan.CachedContentName != "" {
+		cfg.CachedContent = plan.CachedContentName
+	}
+	if plan.ThinkingBudget != nil {
+		// A zero budget is sent as-is: it disables thinking.
+		cfg.ThinkingConfig = &genai.ThinkingConfig{ThinkingBudget: genai.Ptr(int32(*plan.ThinkingBudget))}
+	}
+
+	// Structured JSON
+	if plan.Structured && len(plan.ResponseSchema) > 0 {
+		cfg.ResponseMIMEType = "application/json"
+		cfg.ResponseJsonSchema = plan.ResponseSchema
+	}
+
+	// Provider-specific passthrough options.
+	if raw, ok := plan.ProviderOptions["safety_settings"]; ok {
+		var settings []*genai.SafetySetting
+		if blob, err := json.Marshal(raw); err == nil && json.Unmarshal(blob, &settings) == nil {
+			cfg.SafetySettings = settings
+		}
+	}
+
+	if plan.GroundWithSearch {
+		cfg.Tools = append(cfg.Tools, googleSearchTool(plan.GroundingThreshold))
+	}
+	if plan.VertexDataStore != "" {
+		cfg.Tools = append(cfg.Tools, vertexSearchTool(plan.VertexDataStore, plan.VertexSearchMaxResults))
+	}
+	return cfg
+}
+
+// CountTokens counts the plan's prompt tokens using the countTokens endpoint.
+// The Gemini API does not accept a system instruction when counting, so the
+// system prompt is counted as an additional content block.
+func (p *googleProvider) CountTokens(ctx context.Context, plan callPlan) (int, error) {
+	contents := googleContents(plan.Messages, plan.Input, nil, nil)
+	if strings.TrimSpace(plan.System) != "" {
+		contents = append(genai.Text(plan.System), contents...)
+	}
+	res, err := p.client.Models.CountTokens(ctx, plan.Model, contents, nil)
+	if err != nil {
+		return 0, err
+	}
+	return int(res.TotalTokens), nil
+}
+
 func (p *googleProvider) proofread(ctx context.Context, plan callPlan) (callResult, error) {
-	sys := &genai.Content{Parts: []*genai.Part{{
-		Text: "You are a writing assistant. Rewrite the user's input to correct grammar, spelling, and clarity without changing its meaning. Return only the rewritten text.",
-	}}}
+	res, err := p.client.Models.GenerateContent(ctx, plan.Model, buildGoogleContents(plan.Input, nil), proofreadGoogleConfig(plan))
+	if err != nil {
+		return callResult{}, err
+	}
+	return toCallResultFromGenAI(res), nil
+}
+
+// proofreadGoogleConfig is the generation config of the proofreading step:
+// the shared proofread prompt and a low temperature unless plan sets one.
+func proofreadGoogleConfig(plan callPlan) *genai.GenerateContentConfig {
 	cfg := &genai.GenerateContentConfig{
-		SystemInstruction: sys,
+		SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: proofreadSystemPrompt}}},
 		Temperature:       genai.Ptr[float32](0.2),
 	}
 	if plan.Temperature != nil {
@@ -110,11 +197,153 @@ func (p *googleProvider) proofread(ctx context.Context, plan callPlan) (callResu
 	if plan.MaxOutputTokens != nil {
 		cfg.MaxOutputTokens = int32(*plan.MaxOutputTokens)
 	}
-	res, err := p.client.Models.GenerateContent(ctx, plan.Model, genai.Text(plan.Input), cfg)
+	return cfg
+}
+
+// googleContents converts earlier conversation turns plus the new input and
+// its documents into Gemini contents. Gemini calls the assistant role "model";
+// system messages are sent as user turns since only one system instruction is
+// supported. An empty input without documents or files adds no turn after a
+// non-empty history.
+func googleContents(history []Message, input string, docs []DocumentPart, files []FileHandle) []*genai.Content {
+	if input == "" && len(docs) == 0 && len(files) == 0 && len(history) > 0 {
+		return buildGoogleHistory(history, nil)
+	}
+	parts := []*genai.Part{genai.NewPartFromText(input)}
+	for _, d := range docs {
+		if len(d.Data) > 0 {
+			parts = append(parts, &genai.Part{InlineData: &genai.Blob{Data: d.Data, MIMEType: d.MIMEType}})
+		} else {
+			parts = append(parts, &genai.Part{FileData: &genai.FileData{FileURI: d.URL, MIMEType: d.MIMEType}})
+		}
+	}
+	for _, f := range files {
+		parts = append(parts, &genai.Part{FileData: &genai.FileData{FileURI: f.URI, MIMEType: f.MIMEType}})
+	}
+	return buildGoogleHistory(history, []*genai.Content{genai.NewContentFromParts(parts, genai.RoleUser)})
+}
+
+// buildGoogleHistory converts earlier conversation turns to Gemini contents
+// ("assistant" and "model" become the model role) and appends the current
+// input turn. The system prompt is not part of the history: Gemini takes it as the
+// request's SystemInstruction.
+func buildGoogleHistory(messages []Message, input []*genai.Content) []*genai.Content {
+	contents := make([]*genai.Content, 0, len(messages)+len(input))
+	for _, m := range messages {
+		role := genai.Role(genai.RoleUser)
+		if messageRole(m.Role) == "assistant" {
+			role = genai.RoleModel
+		}
+		contents = append(contents, genai.NewContentFromText(m.Content, role))
+	}
+	return append(contents, input...)
+}
+
+// buildGoogleContents returns the user content for input, with images as
+// inline blobs after the text. Without images it is genai.Text(input).
+func buildGoogleContents(input string, images []ImagePart) []*genai.Content {
+	if len(images) == 0 {
+		return genai.Text(input)
+	}
+	parts := make([]*genai.Part, 0, len(images)+1)
+	parts = append(parts, genai.NewPartFromText(input))
+	for _, img := range images {
+		parts = append(parts, genai.NewPartFromBytes(img.Data, img.MIMEType))
+	}
+	return []*genai.Content{genai.NewContentFromParts(parts, genai.RoleUser)}
+}
+
+// googleSearchTool returns the Google Search grounding tool. With a threshold
+// it uses dynamic retrieval, which only searches when the model's predicted
+// benefit exceeds the threshold.
+func googleSearchTool(threshold *float32) *genai.Tool {
+	if threshold == nil {
+		return &genai.Tool{GoogleSearch: &genai.GoogleSearch{}}
+	}
+	return &genai.Tool{GoogleSearchRetrieval: &genai.GoogleSearchRetrieval{
+		DynamicRetrievalConfig: &genai.DynamicRetrievalConfig{
+			Mode:             genai.DynamicRetrievalConfigModeDynamic,
+			DynamicThreshold: genai.Ptr(*threshold),
+		},
+	}}
+}
+
+// vertexDataStoreName resolves id, or cfg.VertexAIDataStoreID when empty, to
+// a data store resource name.
+func vertexDataStoreName(cfg CoraConfig, id string) (string, error) {
+	id = cmp.Or(id, cfg.VertexAIDataStoreID)
+	switch {
+	case id == "":
+		return "", errors.New("cora: VertexSearchGrounding requires a DataStoreID or CoraConfig.VertexAIDataStoreID")
+	case strings.HasPrefix(id, "projects/"):
+		return id, nil
+	case cfg.GoogleProject == "":
+		return "", errors.New("cora: VertexSearchGrounding with a bare DataStoreID requires GoogleProject")
+	default:
+		return "projects/" + cfg.GoogleProject + "/locations/global/collections/default_collection/dataStores/" + id, nil
+	}
+}
+
+// vertexSearchTool returns the retrieval tool grounding in a Vertex AI Search
+// data store.
+func vertexSearchTool(dataStore string, maxResults int) *genai.Tool {
+	search := &genai.VertexAISearch{Datastore: dataStore}
+	if maxResults > 0 {
+		search.MaxResults = genai.Ptr(int32(maxResults))
+	}
+	return &genai.Tool{Retrieval: &genai.Retrieval{VertexAISearch: search}}
+}
+
+// toGroundingMetadata extracts the search queries, web sources and retrieved
+// documents from gm.
+func toGroundingMetadata(gm *genai.GroundingMetadata) *GroundingMetadata {
+	if gm == nil {
+		return nil
+	}
+	out := &GroundingMetadata{WebSearchQueries: gm.WebSearchQueries}
+	for _, chunk := range gm.GroundingChunks {
+		switch {
+		case chunk == nil:
+		case chunk.Web != nil:
+			out.Sources = append(out.Sources, GroundingSource{
+				Title:  chunk.Web.Title,
+				URI:    chunk.Web.URI,
+				Domain: chunk.Web.Domain,
+			})
+		case chunk.RetrievedContext != nil && (chunk.RetrievedContext.URI != "" || chunk.RetrievedContext.Text != ""):
+			out.Sources = append(out.Sources, GroundingSource{
+				Title:   chunk.RetrievedContext.Title,
+				URI:     chunk.RetrievedContext.URI,
+				Snippet: chunk.RetrievedContext.Text,
+			})
+		}
+	}
+	return out
+}
+
+// transcribe sends the audio inline with a transcription instruction; Gemini
+// understands audio in context.
+func (p *googleProvider) transcribe(ctx context.Context, plan callPlan) (callResult, error) {
+	instruction := "Transcribe this audio verbatim. Return only the transcript."
+	if plan.Audio.Language != "" {
+		instruction += " The spoken language is " + plan.Audio.Language + "."
+	}
+	if strings.TrimSpace(plan.Input) != "" {
+		instruction += "\n\n" + plan.Input
+	}
+	contents := []*genai.Content{genai.NewContentFromParts([]*genai.Part{
+		genai.NewPartFromText(instruction),
+		{InlineData: &genai.Blob{Data: plan.Audio.Data, MIMEType: plan.Audio.MIMEType}},
+	}, genai.RoleUser)}
+
+	res, err := p.client.Models.GenerateContent(ctx, plan.Model, contents, googleConfigFromPlan(plan))
 	if err != nil {
 		return callResult{}, err
 	}
-	return toCallResultFromGenAI(res), nil
+	cr := toCallResultFromGenAI(res)
+	cr.JSON = nil
+	cr.TranscriptionLanguage = plan.Audio.Language
+	return cr, nil
 }
 
 func toGenAITools(tools []CoraTool) []*genai.Tool {
@@ -133,22 +362,36 @@ func toGenAITools(tools []CoraTool) []*genai.Tool {
 	return out
 }
 
+// genAIMetadata collects the extras of res for callResult.Metadata, or nil.
+func genAIMetadata(res *genai.GenerateContentResponse) map[string]any {
+	md := map[string]any{}
+	if res.ModelVersion != "" {
+		md["model_version"] = res.ModelVersion
+	}
+	if res.ResponseID != "" {
+		md["response_id"] = res.ResponseID
+	}
+	cand := res.Candidates[0]
+	if gm := cand.GroundingMetadata; gm != nil && len(gm.GroundingSupports) > 0 {
+		md["grounding_attributions"] = gm.GroundingSupports
+	}
+	if cm := cand.CitationMetadata; cm != nil && len(cm.Citations) > 0 {
+		md["citations"] = cm.Citations
+	}
+	if len(md) == 0 {
+		return nil
+	}
+	return md
+}
+
 func toCallResultFromGenAI(res *genai.GenerateContentResponse) callResult {
 	cr := callResult{}
 	if res == nil || len(res.Candidates) == 0 || res.Candidates[0].Content == nil {
 		return cr
 	}
-	parts := res.Candidates[0].Content.Parts
-	for _, p := range parts {
-		if p.Text != "" {
-			// If multiple text parts, concatenate with a newline.
-			if cr.Text == "" {
-				cr.Text = p.Text
-			} else {
-				cr.Text += "\n" + p.Text
-			}
-		}
-	}
+	cr.GroundingMetadata = toGroundingMetadata(res.Candidates[0].GroundingMetadata)
+	cr.Metadata = genAIMetadata(res)
+	cr.Text = genAICandidateText(res.Candidates[0])
 	// Attempt to parse text as JSON for structured responses.
 	if cr.Text != "" {
 		var m map[string]any
@@ -156,6 +399,17 @@ func toCallResultFromGenAI(res *genai.GenerateContentResponse) callResult {
 			cr.JSON = m
 		}
 	}
+	if len(res.Candidates) > 1 {
+		for _, c := range res.Candidates {
+			choice := callChoice{Text: genAICandidateText(c)}
+			if c.TokenCount > 0 {
+				tokens := int(c.TokenCount)
+				choice.CompletionTokens = &tokens
+			}
+			_ = json.Unmarshal([]byte(choice.Text), &choice.JSON)
+			cr.Choices = append(cr.Choices, choice)
+		}
+	}
 
 	if res.UsageMetadata != nil {
 		if res.UsageMetadata.PromptTokenCount > 0 {
@@ -170,10 +424,32 @@ func toCallResultFromGenAI(res *genai.GenerateContentResponse) callResult {
 			tt := int(res.UsageMetadata.TotalTokenCount)
 			cr.TotalTokens = &tt
 		}
+		if res.UsageMetadata.ThoughtsTokenCount > 0 {
+			th := int(res.UsageMetadata.ThoughtsTokenCount)
+			cr.ThinkingTokens = &th
+		}
+		if res.UsageMetadata.CachedContentTokenCount > 0 {
+			cached := int(res.UsageMetadata.CachedContentTokenCount)
+			cr.CachedTokens = &cached
+		}
 	}
 	return cr
 }
 
+// genAICandidateText joins the text parts of c with newlines.
+func genAICandidateText(c *genai.Candidate) string {
+	if c == nil || c.Content == nil {
+		return ""
+	}
+	var texts []string
+	for _, p := range c.Content.Parts {
+		if p.Text != "" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
 func normalizeJSON(v any) (map[string]any, error) {
 	switch t := v.(type) {
 	case map[string]any:
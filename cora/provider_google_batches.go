@@ -0,0 +1,71 @@
+package cora
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genai"
+)
+
+func (p *googleProvider) CreateBatch(ctx context.Context, model string, plans []callPlan) (string, BatchJobStatus, error) {
+	src := &genai.BatchJobSource{InlinedRequests: make([]*genai.InlinedRequest, len(plans))}
+	for i, plan := range plans {
+		cfg := googleConfigFromPlan(plan)
+		// Request labels are not accepted in batch requests.
+		cfg.Labels = nil
+		src.InlinedRequests[i] = &genai.InlinedRequest{
+			Contents: googleContents(plan.Messages, plan.Input, withAudioPart(plan.Documents, plan.AudioPart), plan.FileHandles),
+			Config:   cfg,
+		}
+	}
+	job, err := p.client.Batches.Create(ctx, model, src, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	return job.Name, batchJobStatusFromGenAI(job.State), nil
+}
+
+func (p *googleProvider) GetBatch(ctx context.Context, id string) (BatchJobStatus, []batchItem, error) {
+	job, err := p.client.Batches.Get(ctx, id, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	status := batchJobStatusFromGenAI(job.State)
+	if status != BatchJobSucceeded || job.Dest == nil {
+		return status, nil, nil
+	}
+	items := make([]batchItem, len(job.Dest.InlinedResponses))
+	for i, r := range job.Dest.InlinedResponses {
+		switch {
+		case r.Error != nil:
+			items[i].err = errors.New(r.Error.Message)
+		case r.Response == nil:
+			items[i].err = errors.New("no response")
+		default:
+			items[i].res = toCallResultFromGenAI(r.Response)
+		}
+	}
+	return status, items, nil
+}
+
+func (p *googleProvider) CancelBatch(ctx context.Context, id string) error {
+	return p.client.Batches.Cancel(ctx, id, nil)
+}
+
+// batchJobStatusFromGenAI maps a Gemini job state to a BatchJobStatus.
+func batchJobStatusFromGenAI(state genai.JobState) BatchJobStatus {
+	switch state {
+	case genai.JobStateRunning, genai.JobStateCancelling, genai.JobStateUpdating:
+		return BatchJobRunning
+	case genai.JobStateSucceeded, genai.JobStatePartiallySucceeded:
+		return BatchJobSucceeded
+	case genai.JobStateFailed:
+		return BatchJobFailed
+	case genai.JobStateCancelled:
+		return BatchJobCancelled
+	case genai.JobStateExpired:
+		return BatchJobExpired
+	default:
+		return BatchJobPending
+	}
+}
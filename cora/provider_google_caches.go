@@ -0,0 +1,29 @@
+package cora
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+func (p *googleProvider) CreateCachedContent(ctx context.Context, req CachedContentRequest) (CachedContentHandle, error) {
+	cfg := &genai.CreateCachedContentConfig{
+		TTL:         req.TTL,
+		DisplayName: req.DisplayName,
+		Contents:    googleContents(nil, req.Input, req.Documents, req.FileHandles),
+	}
+	if strings.TrimSpace(req.System) != "" {
+		cfg.SystemInstruction = genai.NewContentFromText(req.System, genai.RoleUser)
+	}
+	cc, err := p.client.Caches.Create(ctx, req.Model, cfg)
+	if err != nil {
+		return CachedContentHandle{}, err
+	}
+	return CachedContentHandle{Name: cc.Name, Model: cc.Model, ExpiresAt: cc.ExpireTime}, nil
+}
+
+func (p *googleProvider) DeleteCachedContent(ctx context.Context, name string) error {
+	_, err := p.client.Caches.Delete(ctx, name, nil)
+	return err
+}
@@ -0,0 +1,36 @@
+package cora
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/genai"
+)
+
+func (p *googleProvider) UploadFile(ctx context.Context, name string, data io.Reader, mimeType string) (FileHandle, error) {
+	f, err := p.client.Files.Upload(ctx, data, &genai.UploadFileConfig{DisplayName: name, MIMEType: mimeType})
+	if err != nil {
+		return FileHandle{}, err
+	}
+	return toFileHandle(f), nil
+}
+
+func (p *googleProvider) DeleteFile(ctx context.Context, name string) error {
+	_, err := p.client.Files.Delete(ctx, name, nil)
+	return err
+}
+
+func (p *googleProvider) ListFiles(ctx context.Context) ([]FileHandle, error) {
+	var out []FileHandle
+	for f, err := range p.client.Files.All(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, toFileHandle(f))
+	}
+	return out, nil
+}
+
+func toFileHandle(f *genai.File) FileHandle {
+	return FileHandle{URI: f.URI, Name: f.Name, MIMEType: f.MIMEType, ExpiresAt: f.ExpirationTime}
+}
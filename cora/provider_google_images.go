@@ -0,0 +1,49 @@
+package cora
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/genai"
+)
+
+// GenerateImage generates images with Imagen. Imagen takes an aspect ratio
+// rather than a pixel size, so Width and Height are reduced to a ratio
+// such as "16:9".
+func (p *googleProvider) GenerateImage(ctx context.Context, req ImageRequest) (ImageResponse, error) {
+	cfg := &genai.GenerateImagesConfig{NumberOfImages: int32(req.N)}
+	if req.Width > 0 && req.Height > 0 {
+		cfg.AspectRatio = aspectRatio(req.Width, req.Height)
+	}
+
+	res, err := p.client.Models.GenerateImages(ctx, req.Model, req.Prompt, cfg)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+
+	var out ImageResponse
+	for _, img := range res.GeneratedImages {
+		if img == nil || img.Image == nil {
+			continue
+		}
+		if len(img.Image.ImageBytes) > 0 {
+			out.Data = append(out.Data, img.Image.ImageBytes)
+		}
+		if img.Image.GCSURI != "" {
+			out.URLs = append(out.URLs, img.Image.GCSURI)
+		}
+		if out.RevisedPrompt == "" {
+			out.RevisedPrompt = img.EnhancedPrompt
+		}
+	}
+	return out, nil
+}
+
+// aspectRatio returns w:h in lowest terms, e.g. 1920x1080 -> "16:9".
+func aspectRatio(w, h int) string {
+	a, b := w, h
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return fmt.Sprintf("%d:%d", w/a, h/a)
+}
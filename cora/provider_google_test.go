@@ -0,0 +1,242 @@
+package cora
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+func TestGoogleConfigFromPlan_SafetySettings(t *testing.T) {
+	plan := callPlan{
+		ProviderOptions: map[string]any{
+			"safety_settings": []map[string]any{
+				{"category": "HARM_CATEGORY_HARASSMENT", "threshold": "BLOCK_LOW_AND_ABOVE"},
+				{"category": "HARM_CATEGORY_HATE_SPEECH", "threshold": "BLOCK_NONE"},
+			},
+		},
+	}
+
+	cfg := googleConfigFromPlan(plan)
+	if len(cfg.SafetySettings) != 2 {
+		t.Fatalf("expected 2 safety settings, got %d", len(cfg.SafetySettings))
+	}
+	if cfg.SafetySettings[0].Category != genai.HarmCategoryHarassment ||
+		cfg.SafetySettings[0].Threshold != genai.HarmBlockThresholdBlockLowAndAbove {
+		t.Errorf("unexpected first setting: %+v", cfg.SafetySettings[0])
+	}
+	if cfg.SafetySettings[1].Threshold != genai.HarmBlockThresholdBlockNone {
+		t.Errorf("unexpected second setting: %+v", cfg.SafetySettings[1])
+	}
+}
+
+func TestGoogleConfigFromPlan_InvalidSafetySettingsIgnored(t *testing.T) {
+	cfg := googleConfigFromPlan(callPlan{ProviderOptions: map[string]any{"safety_settings": "not a list"}})
+	if cfg.SafetySettings != nil {
+		t.Errorf("expected invalid safety settings to be ignored, got %+v", cfg.SafetySettings)
+	}
+}
+
+func TestBuildPlans_ProviderOptions(t *testing.T) {
+	opts := map[string]any{"safety_settings": []map[string]any{}}
+	plans, err := buildPlans(ProviderGoogle, "gemini-test", TextRequest{ProviderOptions: opts}, CoraConfig{}, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	if _, ok := plans[0].ProviderOptions["safety_settings"]; !ok {
+		t.Error("expected ProviderOptions to reach the call plan")
+	}
+}
+
+func TestGoogleConfigFromPlan_GroundWithSearch(t *testing.T) {
+	if cfg := googleConfigFromPlan(callPlan{}); len(cfg.Tools) != 0 {
+		t.Fatalf("expected no tools without GroundWithSearch, got %d", len(cfg.Tools))
+	}
+
+	cfg := googleConfigFromPlan(callPlan{GroundWithSearch: true})
+	if len(cfg.Tools) != 1 || cfg.Tools[0].GoogleSearch == nil {
+		t.Fatalf("expected a GoogleSearch tool, got %+v", cfg.Tools)
+	}
+
+	threshold := float32(0.3)
+	cfg = googleConfigFromPlan(callPlan{GroundWithSearch: true, GroundingThreshold: &threshold})
+	if len(cfg.Tools) != 1 || cfg.Tools[0].GoogleSearchRetrieval == nil {
+		t.Fatalf("expected a GoogleSearchRetrieval tool, got %+v", cfg.Tools)
+	}
+	drc := cfg.Tools[0].GoogleSearchRetrieval.DynamicRetrievalConfig
+	if drc == nil || drc.Mode != genai.DynamicRetrievalConfigModeDynamic || drc.DynamicThreshold == nil || *drc.DynamicThreshold != 0.3 {
+		t.Errorf("unexpected dynamic retrieval config: %+v", drc)
+	}
+}
+
+func TestToCallResultFromGenAI_GroundingMetadata(t *testing.T) {
+	res := &genai.GenerateContentResponse{Candidates: []*genai.Candidate{{
+		Content: genai.NewContentFromText("Spain won.", genai.RoleModel),
+		GroundingMetadata: &genai.GroundingMetadata{
+			WebSearchQueries: []string{"euro 2024 winner"},
+			GroundingChunks: []*genai.GroundingChunk{
+				{Web: &genai.GroundingChunkWeb{Title: "uefa.com", URI: "https://example.com/a", Domain: "uefa.com"}},
+				{RetrievedContext: &genai.GroundingChunkRetrievedContext{}},
+			},
+		},
+	}}}
+
+	cr := toCallResultFromGenAI(res)
+	gm := cr.GroundingMetadata
+	if gm == nil {
+		t.Fatal("expected grounding metadata")
+	}
+	if len(gm.WebSearchQueries) != 1 || gm.WebSearchQueries[0] != "euro 2024 winner" {
+		t.Errorf("unexpected queries %v", gm.WebSearchQueries)
+	}
+	if len(gm.Sources) != 1 || gm.Sources[0].URI != "https://example.com/a" {
+		t.Errorf("unexpected sources %+v", gm.Sources)
+	}
+}
+
+func TestGoogleConfigFromPlan_ThinkingBudget(t *testing.T) {
+	if cfg := googleConfigFromPlan(callPlan{}); cfg.ThinkingConfig != nil {
+		t.Errorf("expected no ThinkingConfig by default, got %+v", cfg.ThinkingConfig)
+	}
+
+	req := TextRequest{Provider: ProviderGoogle, Model: "gemini-2.5-flash"}.WithThinking(1024)
+	plans, err := buildPlans(req.Provider, req.Model, req, CoraConfig{}, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	cfg := googleConfigFromPlan(plans[0])
+	if cfg.ThinkingConfig == nil || cfg.ThinkingConfig.ThinkingBudget == nil || *cfg.ThinkingConfig.ThinkingBudget != 1024 {
+		t.Fatalf("expected thinking budget 1024, got %+v", cfg.ThinkingConfig)
+	}
+
+	// Zero disables thinking and must be sent, not omitted.
+	cfg = googleConfigFromPlan(callPlan{ThinkingBudget: new(int)})
+	if cfg.ThinkingConfig == nil || cfg.ThinkingConfig.ThinkingBudget == nil || *cfg.ThinkingConfig.ThinkingBudget != 0 {
+		t.Fatalf("expected explicit zero budget, got %+v", cfg.ThinkingConfig)
+	}
+	blob, err := json.Marshal(cfg.ThinkingConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(blob), `"thinkingBudget":0`) {
+		t.Errorf("zero budget missing from request JSON: %s", blob)
+	}
+}
+
+func TestToCallResultFromGenAI_ThinkingTokens(t *testing.T) {
+	res := &genai.GenerateContentResponse{
+		Candidates:    []*genai.Candidate{{Content: genai.NewContentFromText("ok", genai.RoleModel)}},
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{ThoughtsTokenCount: 42},
+	}
+	cr := toCallResultFromGenAI(res)
+	if cr.ThinkingTokens == nil || *cr.ThinkingTokens != 42 {
+		t.Errorf("expected 42 thinking tokens, got %v", cr.ThinkingTokens)
+	}
+}
+
+func TestGoogleContents_Documents(t *testing.T) {
+	pdf := []byte("%PDF-1.7 test")
+	contents := googleContents(nil, "Summarize this", []DocumentPart{
+		{Data: pdf, MIMEType: "application/pdf"},
+		{URL: "gs://bucket/report.pdf", MIMEType: "application/pdf"},
+	}, nil)
+	if len(contents) != 1 {
+		t.Fatalf("expected a single user content, got %d", len(contents))
+	}
+	parts := contents[0].Parts
+	if len(parts) != 3 || parts[0].Text != "Summarize this" {
+		t.Fatalf("expected text + 2 document parts, got %+v", parts)
+	}
+	if parts[1].InlineData == nil || parts[1].InlineData.MIMEType != "application/pdf" || string(parts[1].InlineData.Data) != string(pdf) {
+		t.Errorf("unexpected inline part %+v", parts[1].InlineData)
+	}
+	if parts[2].FileData == nil || parts[2].FileData.FileURI != "gs://bucket/report.pdf" || parts[2].FileData.MIMEType != "application/pdf" {
+		t.Errorf("unexpected file part %+v", parts[2].FileData)
+	}
+}
+
+func TestGoogleCandidateCount(t *testing.T) {
+	if cfg := googleConfigFromPlan(callPlan{N: 2}); cfg.CandidateCount != 2 {
+		t.Errorf("CandidateCount = %d, want 2", cfg.CandidateCount)
+	}
+
+	cr := toCallResultFromGenAI(&genai.GenerateContentResponse{Candidates: []*genai.Candidate{
+		{Content: genai.NewContentFromText("first", genai.RoleModel)},
+		{Content: genai.NewContentFromText(`{"a":1}`, genai.RoleModel)},
+	}})
+	if cr.Text != "first" || len(cr.Choices) != 2 {
+		t.Fatalf("unexpected result %+v", cr)
+	}
+	if cr.Choices[1].JSON["a"] != float64(1) {
+		t.Errorf("expected the second choice to be parsed as JSON, got %+v", cr.Choices[1])
+	}
+}
+
+func TestVertexSearchGrounding(t *testing.T) {
+	req := TextRequest{
+		Provider: ProviderGoogle, Model: "gemini-2.5-flash", Input: "What is our refund policy?",
+		VertexSearchGrounding: &VertexSearchGrounding{MaxResults: 5},
+	}
+	cfg := CoraConfig{GoogleBackend: GoogleBackendVertex, GoogleProject: "acme", GoogleLocation: "us-central1", VertexAIDataStoreID: "policies"}
+	plans, err := buildPlans(req.Provider, req.Model, req, cfg, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	gcfg := googleConfigFromPlan(plans[0])
+	if len(gcfg.Tools) != 1 || gcfg.Tools[0].Retrieval == nil || gcfg.Tools[0].Retrieval.VertexAISearch == nil {
+		t.Fatalf("expected a Vertex AI Search retrieval tool, got %+v", gcfg.Tools)
+	}
+	search := gcfg.Tools[0].Retrieval.VertexAISearch
+	if want := "projects/acme/locations/global/collections/default_collection/dataStores/policies"; search.Datastore != want {
+		t.Errorf("Datastore = %q, want %q", search.Datastore, want)
+	}
+	if search.MaxResults == nil || *search.MaxResults != 5 {
+		t.Errorf("MaxResults = %v, want 5", search.MaxResults)
+	}
+
+	full := "projects/p/locations/eu/collections/default_collection/dataStores/docs"
+	req.VertexSearchGrounding = &VertexSearchGrounding{DataStoreID: full}
+	plans, err = buildPlans(req.Provider, req.Model, req, cfg, nil)
+	if err != nil || plans[0].VertexDataStore != full {
+		t.Errorf("full resource name: plan data store %q, err %v", plans[0].VertexDataStore, err)
+	}
+
+	cfg.GoogleBackend = GoogleBackendGemini
+	if _, err := buildPlans(req.Provider, req.Model, req, cfg, nil); err == nil || !strings.Contains(err.Error(), "GoogleBackendVertex") {
+		t.Errorf("expected a backend error, got %v", err)
+	}
+}
+
+func TestGoogleClientConfig_Backends(t *testing.T) {
+	cfg := CoraConfig{GoogleBackend: GoogleBackendVertex, GoogleProject: "acme", GoogleLocation: "us-central1"}
+	gc, err := googleClientConfig(cfg, nil)
+	if err != nil {
+		t.Fatalf("googleClientConfig error: %v", err)
+	}
+	if gc.Backend != genai.BackendVertexAI || gc.Project != "acme" || gc.Location != "us-central1" || gc.APIKey != "" {
+		t.Errorf("unexpected Vertex config %+v", gc)
+	}
+
+	cfg.GoogleLocation = ""
+	if _, err := googleClientConfig(cfg, nil); err == nil {
+		t.Error("expected an error for Vertex AI without a location")
+	}
+
+	gc, err = googleClientConfig(CoraConfig{GoogleBackend: GoogleBackendGemini, GoogleAPIKey: "g-test"}, nil)
+	if err != nil || gc.Backend != genai.BackendGeminiAPI || gc.APIKey != "g-test" {
+		t.Errorf("unexpected Gemini config %+v, err %v", gc, err)
+	}
+	if _, err := googleClientConfig(CoraConfig{}, nil); err == nil {
+		t.Error("expected an error for the Gemini API without an API key")
+	}
+}
+
+func TestToGroundingMetadata_RetrievedContext(t *testing.T) {
+	gm := toGroundingMetadata(&genai.GroundingMetadata{GroundingChunks: []*genai.GroundingChunk{
+		{RetrievedContext: &genai.GroundingChunkRetrievedContext{Title: "Refunds", URI: "gs://docs/refunds.pdf", Text: "Refunds within 30 days."}},
+	}})
+	if len(gm.Sources) != 1 || gm.Sources[0].Snippet != "Refunds within 30 days." || gm.Sources[0].URI != "gs://docs/refunds.pdf" {
+		t.Errorf("unexpected sources %+v", gm.Sources)
+	}
+}
@@ -3,46 +3,31 @@ package cora
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"google.golang.org/genai"
 )
 
 // executeToolLoop handles multi-round tool calling for Google.
-func (p *googleProvider) executeToolLoop(ctx context.Context, model string, contents any, cfg *genai.GenerateContentConfig, plan callPlan) (callResult, error) {
-	// Configure executor with plan settings or defaults
-	maxRounds := 5
-	if plan.MaxToolRounds != nil {
-		maxRounds = *plan.MaxToolRounds
-	}
-
-	parallelTools := false
-	if plan.ParallelTools != nil {
-		parallelTools = *plan.ParallelTools
-	}
+func (p *googleProvider) executeToolLoop(ctx context.Context, model string, contents any, cfg *genai.GenerateContentConfig, plan callPlan) (_ callResult, err error) {
+	defer traceRegion(ctx, plan.trace, "cora.executeToolLoop")()
+	executor := plan.toolExecutor()
 
-	stopOnError := true
-	if plan.StopOnToolError != nil {
-		stopOnError = *plan.StopOnToolError
-	}
-
-	executor := NewToolExecutor(plan.ToolHandlers).
-		WithMaxRounds(maxRounds).
-		WithParallel(parallelTools).
-		WithStopOnError(stopOnError).
-		WithValidator(plan.Tools)
-
-	// Apply cache if configured
-	if plan.ToolCacheTTL > 0 && plan.ToolCacheMaxSize > 0 {
-		executor = executor.WithCache(plan.ToolCacheTTL, plan.ToolCacheMaxSize)
+	roundCount := 0
+	// toolRounds counts the rounds in which the model called tools; the
+	// others (the answer, agent continuation prompts) are not tool rounds.
+	toolRounds := 0
+	var trace []string
+	var graph *ToolCallGraph
+	if plan.RecordToolGraph {
+		graph = &ToolCallGraph{}
 	}
 
-	// Apply retry if configured
-	if plan.ToolRetryConfig != nil {
-		executor = executor.WithRetry(*plan.ToolRetryConfig)
+	// Every round is tagged with the request's labels.
+	if len(plan.Labels) > 0 {
+		cfg.Labels = plan.Labels
 	}
 
-	roundCount := 0
-
 	// Convert initial contents to proper type
 	var currentContents []*genai.Content
 	if c, ok := contents.([]*genai.Content); ok {
@@ -54,20 +39,70 @@ func (p *googleProvider) executeToolLoop(ctx context.Context, model string, cont
 		}
 	}
 
+	var lastCalls []string
+	// Terminal errors carry the state of the loop.
+	defer func() {
+		err = newToolLoopError(err, plan, roundCount, executor.maxRounds, lastCalls, len(currentContents))
+	}()
+
+	var lastText string
 	for {
 		roundCount++
 		if roundCount > executor.maxRounds {
 			return callResult{}, fmt.Errorf("exceeded maximum tool call rounds (%d)", executor.maxRounds)
 		}
+		if extra := plan.RoundSystemPrompts[roundCount-1]; extra != "" {
+			cfg.SystemInstruction = &genai.Content{Parts: []*genai.Part{{Text: appendInstruction(systemText(cfg.SystemInstruction), extra)}}}
+		}
+		if roundCount > 1 && plan.DynamicSystemPrompt != nil {
+			if extra := plan.DynamicSystemPrompt(roundCount, lastText); extra != "" {
+				cfg.SystemInstruction = &genai.Content{Parts: []*genai.Part{{Text: appendInstruction(systemText(cfg.SystemInstruction), extra)}}}
+			}
+		}
 
 		res, err := p.client.Models.GenerateContent(ctx, model, currentContents, cfg)
 		if err != nil {
 			return callResult{}, err
 		}
+		lastText = res.Text()
+
+		if plan.ReAct {
+			trace = append(trace, parseReActTrace(res.Text())...)
+		}
 
 		fcs := res.FunctionCalls()
 		if len(fcs) == 0 {
-			return toCallResultFromGenAI(res), nil
+			if plan.AgentStopSignal != "" && len(res.Candidates) > 0 && res.Candidates[0].Content != nil {
+				if _, done := agentFinalAnswer(lastText, plan.AgentStopSignal); !done {
+					currentContents = append(currentContents, res.Candidates[0].Content,
+						genai.NewContentFromText(agentContinuePrompt(plan.AgentStopSignal), genai.RoleUser))
+					continue
+				}
+			}
+			cr := toCallResultFromGenAI(res)
+			cr.RawProviderResponse = rawResponse(plan, res)
+			cr.ToolCallGraph = graph
+			cr.ToolRounds = toolRounds
+			if plan.ReAct {
+				cr.Text = reActFinalAnswer(cr.Text)
+				cr.ReasoningTrace = trace
+			}
+			if plan.AgentStopSignal != "" {
+				cr.Text, _ = agentFinalAnswer(cr.Text, plan.AgentStopSignal)
+				cr.AgentSteps = roundCount
+			}
+			return cr, nil
+		}
+
+		toolRounds++
+
+		// Over the call budget: skip this round's calls and ask for an answer.
+		if note, over := executor.callBudgetNote(len(fcs)); over {
+			currentContents = append(currentContents, genai.NewContentFromText(note, genai.RoleUser))
+			cfg.ToolConfig = &genai.ToolConfig{
+				FunctionCallingConfig: &genai.FunctionCallingConfig{Mode: genai.FunctionCallingConfigModeNone},
+			}
+			continue
 		}
 
 		// Execute function calls
@@ -76,7 +111,13 @@ func (p *googleProvider) executeToolLoop(ctx context.Context, model string, cont
 			calls[i] = toolCallRequest{name: fc.Name, args: fc.Args}
 		}
 
-		results, err := executor.executeBatch(ctx, calls)
+		lastCalls = toolCallNames(calls)
+		results, err := executor.executeRound(ctx, roundCount, calls)
+		if graph != nil {
+			for i, r := range results {
+				graph.AddCall(roundCount, calls[i].name, executor.maskArgs(calls[i].name, calls[i].args), r.result, r.duration)
+			}
+		}
 		if err != nil {
 			return callResult{}, err
 		}
@@ -84,6 +125,30 @@ func (p *googleProvider) executeToolLoop(ctx context.Context, model string, cont
 		// Build function response content
 		respContent := &genai.Content{Role: "user"}
 		for i, result := range results {
+			if result.partials != nil {
+				// One function response per partial result.
+				for _, partial := range result.partials {
+					respContent.Parts = append(respContent.Parts, &genai.Part{
+						FunctionResponse: &genai.FunctionResponse{
+							Name:     fcs[i].Name,
+							Response: functionResponsePayload(partial),
+						},
+					})
+				}
+				continue
+			}
+			if part, ok := asToolResultPart(result.result); ok {
+				respContent.Parts = append(respContent.Parts, &genai.Part{
+					FunctionResponse: &genai.FunctionResponse{
+						Name:     fcs[i].Name,
+						Response: map[string]any{"output": part.Text},
+					},
+				})
+				if img := part.image(); img != nil {
+					respContent.Parts = append(respContent.Parts, genai.NewPartFromBytes(img.Data, img.MIMEType))
+				}
+				continue
+			}
 			payload, _ := normalizeJSON(result.result)
 			respContent.Parts = append(respContent.Parts, &genai.Part{
 				FunctionResponse: &genai.FunctionResponse{
@@ -98,3 +163,26 @@ func (p *googleProvider) executeToolLoop(ctx context.Context, model string, cont
 		currentContents = append(currentContents, res.Candidates[0].Content, respContent)
 	}
 }
+
+// functionResponsePayload converts v into a FunctionResponse.Response,
+// wrapping values that are not JSON objects as {"output": v}.
+func functionResponsePayload(v any) map[string]any {
+	if m, err := normalizeJSON(v); err == nil {
+		return m
+	}
+	return map[string]any{"output": v}
+}
+
+// systemText returns the text of a system instruction.
+func systemText(c *genai.Content) string {
+	if c == nil {
+		return ""
+	}
+	var parts []string
+	for _, p := range c.Parts {
+		if p != nil && p.Text != "" {
+			parts = append(parts, p.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
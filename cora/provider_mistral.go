@@ -0,0 +1,32 @@
+package cora
+
+import (
+	"errors"
+	"net/http"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+func init() { registerProvider(ProviderMistral) }
+
+const defaultMistralBaseURL = "https://api.mistral.ai/v1"
+
+// newMistralProvider returns an OpenAI-compatible provider pointed at the
+// Mistral API. Chat, tool calling, structured output, streaming and
+// embeddings (mistral-embed) all go through the go-openai client.
+func newMistralProvider(cfg CoraConfig, transport http.RoundTripper) (providerClient, error) {
+	if cfg.MistralAPIKey == "" {
+		return nil, errors.New("cora: Mistral key is required to use ProviderMistral")
+	}
+	return &openAIProvider{client: openai.NewClientWithConfig(mistralClientConfig(cfg, transport))}, nil
+}
+
+func mistralClientConfig(cfg CoraConfig, transport http.RoundTripper) openai.ClientConfig {
+	oc := openai.DefaultConfig(cfg.MistralAPIKey)
+	oc.BaseURL = defaultMistralBaseURL
+	if cfg.MistralBaseURL != "" {
+		oc.BaseURL = cfg.MistralBaseURL
+	}
+	oc.HTTPClient = providerHTTPClient(cfg, transport, nil)
+	return oc
+}
@@ -0,0 +1,70 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMistralClientConfig_BaseURL(t *testing.T) {
+	oc := mistralClientConfig(CoraConfig{MistralAPIKey: "mk"}, nil)
+	if oc.BaseURL != "https://api.mistral.ai/v1" {
+		t.Errorf("expected default Mistral base URL, got %q", oc.BaseURL)
+	}
+
+	oc = mistralClientConfig(CoraConfig{MistralAPIKey: "mk", MistralBaseURL: "https://mistral.internal/v1"}, nil)
+	if oc.BaseURL != "https://mistral.internal/v1" {
+		t.Errorf("expected custom Mistral base URL, got %q", oc.BaseURL)
+	}
+}
+
+func TestMistralProvider_TextAndEmbed(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if got := r.Header.Get("Authorization"); got != "Bearer mk-test" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/chat/completions":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": "bonjour"}}},
+			})
+		case "/v1/embeddings":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{
+					{"index": 1, "embedding": []float32{0, 1}},
+					{"index": 0, "embedding": []float32{1, 0}},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{MistralAPIKey: "mk-test", MistralBaseURL: srv.URL + "/v1"})
+
+	resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderMistral, Model: "mistral-medium", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "bonjour" {
+		t.Errorf("unexpected text %q", resp.Text)
+	}
+
+	emb, err := c.Embed(context.Background(), EmbedRequest{Provider: ProviderMistral, Model: "mistral-embed", Input: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Embed error: %v", err)
+	}
+	if len(emb.Embeddings) != 2 || emb.Embeddings[0][0] != 1 || emb.Embeddings[1][1] != 1 {
+		t.Errorf("expected embeddings in input order, got %v", emb.Embeddings)
+	}
+
+	if len(paths) != 2 || paths[0] != "/v1/chat/completions" || paths[1] != "/v1/embeddings" {
+		t.Errorf("unexpected request paths %v", paths)
+	}
+}
@@ -1,73 +1,164 @@
 package cora
 
 import (
+	"cmp"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"net/http"
+	"slices"
 	"strings"
 
 	openai "github.com/sashabaranov/go-openai"
 	"github.com/sashabaranov/go-openai/jsonschema"
 )
 
+func init() { registerProvider(ProviderOpenAI) }
+
 type openAIProvider struct {
 	client *openai.Client
 }
 
-func newOpenAIProvider(cfg CoraConfig) (providerClient, error) {
+func newOpenAIProvider(cfg CoraConfig, transport http.RoundTripper) (providerClient, error) {
 	if cfg.OpenAIAPIKey == "" {
 		return nil, errors.New("cora: OpenAI key is required to use ProviderOpenAI")
 	}
-	oc := openai.DefaultConfig(cfg.OpenAIAPIKey)
-	if cfg.OpenAIBaseURL != "" {
-		oc.BaseURL = cfg.OpenAIBaseURL
-	}
-	if cfg.OpenAIOrgID != "" {
-		oc.OrgID = cfg.OpenAIOrgID
-	}
-	if cfg.HTTPClient != nil {
-		oc.HTTPClient = cfg.HTTPClient
+	return &openAIProvider{client: openai.NewClientWithConfig(openAIClientConfig(cfg, transport))}, nil
+}
+
+// defaultAzureAPIVersion is used when OpenAIAPIType is "azure" and
+// OpenAIAPIVersion is empty.
+const defaultAzureAPIVersion = "2024-02-01"
+
+// openAIClientConfig builds the go-openai config for cfg. For Azure, requests
+// authenticate with the api-key header and are routed to the deployment
+// mapped from the model name via cfg.AzureDeployments.
+func openAIClientConfig(cfg CoraConfig, transport http.RoundTripper) openai.ClientConfig {
+	var oc openai.ClientConfig
+	if cfg.OpenAIAPIType == "azure" {
+		oc = openai.DefaultAzureConfig(cfg.OpenAIAPIKey, cfg.OpenAIBaseURL)
+		oc.APIVersion = cmp.Or(cfg.OpenAIAPIVersion, defaultAzureAPIVersion)
+		defaultMapper := oc.AzureModelMapperFunc
+		oc.AzureModelMapperFunc = func(model string) string {
+			if deployment, ok := cfg.AzureDeployments[model]; ok {
+				return deployment
+			}
+			return defaultMapper(model)
+		}
+	} else {
+		oc = openai.DefaultConfig(cfg.OpenAIAPIKey)
+		if cfg.OpenAIBaseURL != "" {
+			oc.BaseURL = cfg.OpenAIBaseURL
+		}
+		if cfg.OpenAIOrgID != "" {
+			oc.OrgID = cfg.OpenAIOrgID
+		}
 	}
-	return &openAIProvider{client: openai.NewClientWithConfig(oc)}, nil
+	oc.HTTPClient = providerHTTPClient(cfg, transport, cfg.OpenAIHTTPClient)
+	return oc
 }
 
 func (p *openAIProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	plan = plan.Clone()
+	ctx = withLabelsHeader(ctx, plan.Labels)
 	if plan.Proofread {
 		return p.proofread(ctx, plan)
 	}
+	if plan.Transcribe {
+		return p.transcribe(ctx, plan)
+	}
+
+	if len(plan.FileHandles) > 0 {
+		return callResult{}, fmt.Errorf("%w: FileHandles are only supported by Google", ErrNotSupportedByProvider)
+	}
+	if plan.CachedContentName != "" {
+		return callResult{}, fmt.Errorf("%w: CachedContentName is only supported by Google", ErrNotSupportedByProvider)
+	}
+	if len(plan.RoundSystemPrompts) > 0 {
+		return callResult{}, fmt.Errorf("%w: RoundSystemPrompts is only supported by Google", ErrNotSupportedByProvider)
+	}
+	if plan.AudioPart != nil {
+		return callResult{}, fmt.Errorf("%w: AudioPart is only supported by Google", ErrNotSupportedByProvider)
+	}
+	if err := checkOpenAIDocuments(plan.Documents); err != nil {
+		return callResult{}, err
+	}
+	req := openAIRequestFromPlan(plan)
 
+	// Multi-round tool loop
+	if plan.hasToolHandlers() {
+		cr, err := p.executeToolLoop(ctx, req, plan)
+		if err != nil {
+			return callResult{}, err
+		}
+		cr.toolLoop = true
+		return cr, nil
+	}
+
+	resp, err := p.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return callResult{}, err
+	}
+	cr := p.toCallResult(resp)
+	cr.RawProviderResponse = rawResponse(plan, resp)
+	return cr, nil
+}
+
+// openAIRequestFromPlan builds the chat completion request for plan.
+func openAIRequestFromPlan(plan callPlan) openai.ChatCompletionRequest {
+	// Reasoning models (o1/o3/o4) reject system messages and temperature;
+	// fold the system prompt into the user message instead.
+	reasoning := plan.ReasoningEffort != nil
+	input := plan.Input
 	msgs := make([]openai.ChatCompletionMessage, 0, 4)
 	if strings.TrimSpace(plan.System) != "" {
-		msgs = append(msgs, openai.ChatCompletionMessage{
-			Role:    openai.ChatMessageRoleSystem,
-			Content: plan.System,
-		})
+		if reasoning {
+			input = "Instructions:\n" + plan.System + "\n\n" + plan.Input
+		} else {
+			msgs = append(msgs, openai.ChatCompletionMessage{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: plan.System,
+			})
+		}
+	}
+	for _, m := range plan.Messages {
+		msgs = append(msgs, openai.ChatCompletionMessage{Role: messageRole(m.Role), Content: m.Content})
+	}
+	if input != "" || len(plan.Documents) > 0 || len(plan.Messages) == 0 {
+		msgs = append(msgs, openAIUserMessage(input, plan.Documents))
 	}
-	msgs = append(msgs, openai.ChatCompletionMessage{
-		Role:    openai.ChatMessageRoleUser,
-		Content: plan.Input,
-	})
 
 	req := openai.ChatCompletionRequest{
 		Model:    plan.Model,
 		Messages: msgs,
 	}
-	if plan.Temperature != nil {
+	if reasoning {
+		req.ReasoningEffort = *plan.ReasoningEffort
+	} else if plan.Temperature != nil {
 		req.Temperature = *plan.Temperature
 	}
 	if plan.MaxOutputTokens != nil {
 		req.MaxCompletionTokens = *plan.MaxOutputTokens
 	}
+	if plan.N > 1 {
+		req.N = plan.N
+	}
 
 	// Structured JSON
 	if plan.Structured && len(plan.ResponseSchema) > 0 {
+		schema := plan.ResponseSchema
+		if plan.StrictJSON {
+			schema = normalizeSchemaForOpenAI(schema)
+		}
 		req.ResponseFormat = &openai.ChatCompletionResponseFormat{
 			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
 			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
-				Name:   "cora_response",
-				Schema: rawJSONSchema{m: plan.ResponseSchema},
-				Strict: true,
+				Name:   cmp.Or(plan.SchemaName, defaultSchemaName),
+				Schema: rawJSONSchema{m: schema},
+				Strict: plan.StrictJSON,
 			},
 		}
 	}
@@ -88,29 +179,39 @@ func (p *openAIProvider) Text(ctx context.Context, plan callPlan) (callResult, e
 		// ToolChoice left to provider defaults (auto).
 	}
 
-	// First round
-	resp, err := p.client.CreateChatCompletion(ctx, req)
-	if err != nil {
-		return callResult{}, err
-	}
-	cr := p.toCallResult(resp)
+	return req
+}
 
-	// Tool loop (single follow-up round for simplicity)
-	if len(resp.Choices) > 0 && len(resp.Choices[0].Message.ToolCalls) > 0 && len(plan.ToolHandlers) > 0 {
-		toolMsgs, err := p.invokeTools(ctx, resp.Choices[0].Message.ToolCalls, plan.ToolHandlers)
-		if err != nil {
-			return callResult{}, err
+// checkOpenAIDocuments rejects documents the chat completions API cannot take:
+// only images can be attached, as image_url parts.
+func checkOpenAIDocuments(docs []DocumentPart) error {
+	for _, d := range docs {
+		if !strings.HasPrefix(d.MIMEType, "image/") {
+			return fmt.Errorf("%w: OpenAI chat completions accept image documents only, got %q", ErrNotSupportedByProvider, d.MIMEType)
 		}
-		req.Messages = append(req.Messages, resp.Choices[0].Message) // include tool call message
-		req.Messages = append(req.Messages, toolMsgs...)
-		resp2, err := p.client.CreateChatCompletion(ctx, req)
-		if err != nil {
-			return callResult{}, err
+	}
+	return nil
+}
+
+// openAIUserMessage builds the user message for input, switching to a
+// multi-part message when documents are attached. Inline data is sent as a
+// base64 data URL.
+func openAIUserMessage(input string, docs []DocumentPart) openai.ChatCompletionMessage {
+	if len(docs) == 0 {
+		return openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: input}
+	}
+	parts := []openai.ChatMessagePart{{Type: openai.ChatMessagePartTypeText, Text: input}}
+	for _, d := range docs {
+		url := d.URL
+		if len(d.Data) > 0 {
+			url = "data:" + d.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(d.Data)
 		}
-		cr = p.toCallResult(resp2)
-		cr.toolLoop = true
+		parts = append(parts, openai.ChatMessagePart{
+			Type:     openai.ChatMessagePartTypeImageURL,
+			ImageURL: &openai.ChatMessageImageURL{URL: url},
+		})
 	}
-	return cr, nil
+	return openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, MultiContent: parts}
 }
 
 func (p *openAIProvider) toCallResult(resp openai.ChatCompletionResponse) callResult {
@@ -123,6 +224,16 @@ func (p *openAIProvider) toCallResult(resp openai.ChatCompletionResponse) callRe
 			res.JSON = m
 		}
 	}
+	if len(resp.Choices) > 1 {
+		for _, c := range resp.Choices {
+			choice := callChoice{Text: c.Message.Content}
+			_ = json.Unmarshal([]byte(choice.Text), &choice.JSON)
+			res.Choices = append(res.Choices, choice)
+		}
+	}
+	if resp.SystemFingerprint != "" {
+		res.Metadata = map[string]any{"system_fingerprint": resp.SystemFingerprint}
+	}
 	// Usage
 	if resp.Usage.TotalTokens > 0 {
 		pt := resp.Usage.PromptTokens
@@ -132,62 +243,197 @@ func (p *openAIProvider) toCallResult(resp openai.ChatCompletionResponse) callRe
 		res.CompletionTokens = &ct
 		res.TotalTokens = &tt
 	}
+	if d := resp.Usage.CompletionTokensDetails; d != nil && d.ReasoningTokens > 0 {
+		rt := d.ReasoningTokens
+		res.ThinkingTokens = &rt
+	}
+	if d := resp.Usage.PromptTokensDetails; d != nil && d.CachedTokens > 0 {
+		cached := d.CachedTokens
+		res.CachedTokens = &cached
+	}
 	return res
 }
 
 func (p *openAIProvider) proofread(ctx context.Context, plan callPlan) (callResult, error) {
-	sys := "You are a writing assistant. Rewrite the user's input to correct grammar, spelling, and clarity without changing its meaning. Return only the rewritten text."
+	resp, err := p.client.CreateChatCompletion(ctx, openAIProofreadRequest(plan))
+	if err != nil {
+		return callResult{}, err
+	}
+	return p.toCallResult(resp), nil
+}
+
+// openAIProofreadRequest builds the chat completion request of the
+// proofreading step: the shared proofread prompt and a low temperature unless
+// plan sets one. Reasoning models accept only the default temperature, so
+// they get none.
+func openAIProofreadRequest(plan callPlan) openai.ChatCompletionRequest {
 	req := openai.ChatCompletionRequest{
 		Model: plan.Model,
 		Messages: []openai.ChatCompletionMessage{
-			{Role: openai.ChatMessageRoleSystem, Content: sys},
+			{Role: openai.ChatMessageRoleSystem, Content: proofreadSystemPrompt},
 			{Role: openai.ChatMessageRoleUser, Content: plan.Input},
 		},
 	}
-	// Lower randomness by default in proofreading.
-	if plan.Temperature != nil {
+	switch {
+	case isReasoningModel(plan.Model):
+	case plan.Temperature != nil:
 		req.Temperature = *plan.Temperature
-	} else {
+	default:
 		req.Temperature = 0.2
 	}
 	if plan.MaxOutputTokens != nil {
 		req.MaxCompletionTokens = *plan.MaxOutputTokens
 	}
-	resp, err := p.client.CreateChatCompletion(ctx, req)
-	if err != nil {
-		return callResult{}, err
+	return req
+}
+
+// isReasoningModel reports whether model is an OpenAI reasoning model (the
+// o-series or gpt-5), which rejects a non-default temperature.
+func isReasoningModel(model string) bool {
+	if rest, ok := strings.CutPrefix(model, "o"); ok && rest != "" && rest[0] >= '0' && rest[0] <= '9' {
+		return true
 	}
-	return p.toCallResult(resp), nil
+	return strings.HasPrefix(model, "gpt-5")
 }
 
-func (p *openAIProvider) invokeTools(ctx context.Context, calls []openai.ToolCall, handlers map[string]CoraToolHandler) ([]openai.ChatCompletionMessage, error) {
-	out := make([]openai.ChatCompletionMessage, 0, len(calls))
-	for _, tc := range calls {
-		fn := tc.Function.Name
-		h, ok := handlers[fn]
-		if !ok {
-			return nil, fmt.Errorf("cora: no handler for tool %q", fn)
+// defaultSchemaName names the response schema when TextRequest.SchemaName is
+// empty.
+const defaultSchemaName = "cora_response"
+
+// validSchemaName reports whether name is empty or a valid OpenAI schema name.
+func validSchemaName(name string) bool {
+	if len(name) > 64 {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
 		}
-		var args map[string]any
-		if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
-			return nil, fmt.Errorf("cora: parsing tool args for %q: %w", fn, err)
+	}
+	return true
+}
+
+// openAIUnsupportedSchemaKeys are JSON Schema keywords rejected by OpenAI
+// strict structured outputs.
+var openAIUnsupportedSchemaKeys = []string{"$schema", "$id", "$comment", "$defs", "definitions", "default", "examples"}
+
+// normalizeSchemaForOpenAI returns a copy of schema usable with strict
+// structured outputs: local "$ref"s into "$defs" or "definitions" are
+// inlined, unsupported keywords are removed and objects without
+// "additionalProperties" get false. Strict mode requires every property, so
+// each object's "required" lists all of them, and those that were optional
+// become nullable: the model answers null rather than leaving them out.
+func normalizeSchemaForOpenAI(schema map[string]any) map[string]any {
+	defs := map[string]any{}
+	for _, key := range []string{"$defs", "definitions"} {
+		if m, ok := schema[key].(map[string]any); ok {
+			for name, def := range m {
+				defs["#/"+key+"/"+name] = def
+			}
 		}
-		result, err := h(ctx, args)
-		if err != nil {
-			return nil, fmt.Errorf("cora: tool %q failed: %w", fn, err)
+	}
+	out, _ := normalizeSchemaNode(schema, defs, nil).(map[string]any)
+	return out
+}
+
+// normalizeSchemaNode normalizes v; seen holds the refs being inlined, so
+// that a recursive ref is left in place rather than expanded forever.
+func normalizeSchemaNode(v any, defs map[string]any, seen []string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok && !slices.Contains(seen, ref) {
+			if def, ok := defs[ref]; ok {
+				return normalizeSchemaNode(def, defs, append(seen, ref))
+			}
 		}
-		blob, err := json.Marshal(result)
-		if err != nil {
-			return nil, fmt.Errorf("cora: tool %q result not JSON-serializable: %w", fn, err)
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			if slices.Contains(openAIUnsupportedSchemaKeys, k) {
+				continue
+			}
+			if k == "properties" {
+				// Property names are not keywords: keep them all.
+				props := map[string]any{}
+				if m, ok := child.(map[string]any); ok {
+					for name, p := range m {
+						props[name] = normalizeSchemaNode(p, defs, seen)
+					}
+				}
+				out[k] = props
+				continue
+			}
+			out[k] = normalizeSchemaNode(child, defs, seen)
 		}
-		out = append(out, openai.ChatCompletionMessage{
-			Role:       openai.ChatMessageRoleTool,
-			Name:       fn,
-			ToolCallID: tc.ID,
-			Content:    string(blob),
-		})
+		if props, ok := out["properties"].(map[string]any); ok {
+			required := schemaRequired(v["required"])
+			names := slices.Sorted(maps.Keys(props))
+			all := make([]any, len(names))
+			for i, name := range names {
+				if !slices.Contains(required, name) {
+					props[name] = nullableSchema(props[name])
+				}
+				all[i] = name
+			}
+			out["required"] = all
+		}
+		if out["type"] == "object" {
+			if _, ok := out["additionalProperties"]; !ok {
+				out["additionalProperties"] = false
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = normalizeSchemaNode(child, defs, seen)
+		}
+		return out
+	default:
+		return v
 	}
-	return out, nil
+}
+
+// schemaRequired returns the property names of a "required" keyword.
+func schemaRequired(v any) []string {
+	switch v := v.(type) {
+	case []string:
+		return v
+	case []any:
+		names := make([]string, 0, len(v))
+		for _, n := range v {
+			if s, ok := n.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// nullableSchema returns schema extended to also accept null: "null" is
+// added to its type (and enum), or, without a type, schema becomes one
+// branch of an anyOf with {"type": "null"}.
+func nullableSchema(schema any) any {
+	m, ok := schema.(map[string]any)
+	if !ok {
+		return schema
+	}
+	switch t := m["type"].(type) {
+	case string:
+		if t != "null" {
+			m["type"] = []any{t, "null"}
+		}
+	case []any:
+		if !slices.Contains(t, any("null")) {
+			m["type"] = append(slices.Clip(t), "null")
+		}
+	default:
+		return map[string]any{"anyOf": []any{m, map[string]any{"type": "null"}}}
+	}
+	if enum, ok := m["enum"].([]any); ok && !slices.Contains(enum, nil) {
+		m["enum"] = append(slices.Clip(enum), nil)
+	}
+	return m
 }
 
 func toOpenAIJSONSchema(m map[string]any) any {
@@ -198,4 +444,4 @@ func toOpenAIJSONSchema(m map[string]any) any {
 	// Best-effort: try to coerce common fields into jsonschema.Definition.
 	// For now, pass raw marshaler to satisfy interface; OpenAI SDK accepts arbitrary structs too.
 	return m
-}
\ No newline at end of file
+}
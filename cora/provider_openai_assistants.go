@@ -0,0 +1,347 @@
+package cora
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// assistantPollInterval is how often RunThread checks a run's status.
+var assistantPollInterval = 500 * time.Millisecond
+
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// AssistantConfig describes an OpenAI assistant created with CreateAssistant.
+type AssistantConfig struct {
+	Model        string
+	Name         string
+	Instructions string
+	// Tools are exposed to the assistant as functions. Runs that call them
+	// need tool outputs, which RunThread does not submit.
+	Tools       []CoraTool
+	Temperature *float32
+}
+
+// The Assistants API (threads, runs and messages) is separate from Text:
+// state lives on OpenAI's servers and a run answers every message added to
+// its thread since the last run.
+
+// CreateAssistant creates an OpenAI assistant and returns its ID.
+func (c *Client) CreateAssistant(ctx context.Context, cfg AssistantConfig) (string, error) {
+	if cfg.Model == "" {
+		return "", errors.New("cora: model must be specified")
+	}
+	p, err := c.assistantsProvider()
+	if err != nil {
+		return "", err
+	}
+	req := openai.AssistantRequest{Model: cfg.Model, Temperature: cfg.Temperature}
+	if cfg.Name != "" {
+		req.Name = &cfg.Name
+	}
+	if cfg.Instructions != "" {
+		req.Instructions = &cfg.Instructions
+	}
+	for _, t := range cfg.Tools {
+		req.Tools = append(req.Tools, openai.AssistantTool{
+			Type: openai.AssistantToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.ParametersSchema,
+			},
+		})
+	}
+	a, err := p.client.CreateAssistant(ctx, req)
+	if err != nil {
+		return "", wrapProviderError(ProviderOpenAI, err)
+	}
+	return a.ID, nil
+}
+
+// CreateThread creates an empty thread and returns its ID.
+func (c *Client) CreateThread(ctx context.Context) (string, error) {
+	p, err := c.assistantsProvider()
+	if err != nil {
+		return "", err
+	}
+	th, err := p.client.CreateThread(ctx, openai.ThreadRequest{})
+	if err != nil {
+		return "", wrapProviderError(ProviderOpenAI, err)
+	}
+	return th.ID, nil
+}
+
+// AddMessage appends a message to a thread. role is "user" or "assistant".
+func (c *Client) AddMessage(ctx context.Context, threadID, role, content string) error {
+	p, err := c.assistantsProvider()
+	if err != nil {
+		return err
+	}
+	if _, err := p.client.CreateMessage(ctx, threadID, openai.MessageRequest{Role: role, Content: content}); err != nil {
+		return wrapProviderError(ProviderOpenAI, err)
+	}
+	return nil
+}
+
+// RunThread runs assistantID on the thread, waits for the run to finish and
+// returns the assistant's reply. Runs that stop to call tools fail, as do
+// failed, cancelled and expired runs.
+func (c *Client) RunThread(ctx context.Context, threadID, assistantID string) (TextResponse, error) {
+	p, err := c.assistantsProvider()
+	if err != nil {
+		return TextResponse{}, err
+	}
+	start := time.Now()
+	run, err := p.client.CreateRun(ctx, threadID, openai.RunRequest{AssistantID: assistantID})
+	if err != nil {
+		return TextResponse{}, wrapProviderError(ProviderOpenAI, err)
+	}
+
+	for !assistantRunDone(run.Status) {
+		select {
+		case <-ctx.Done():
+			return TextResponse{}, ctx.Err()
+		case <-time.After(assistantPollInterval):
+		}
+		run, err = p.client.RetrieveRun(ctx, threadID, run.ID)
+		if err != nil {
+			return TextResponse{}, wrapProviderError(ProviderOpenAI, err)
+		}
+	}
+	if err := assistantRunError(run); err != nil {
+		return TextResponse{}, err
+	}
+
+	limit, order := 1, "desc"
+	msgs, err := p.client.ListMessage(ctx, threadID, &limit, &order, nil, nil, &run.ID)
+	if err != nil {
+		return TextResponse{}, wrapProviderError(ProviderOpenAI, err)
+	}
+	var text string
+	if len(msgs.Messages) > 0 {
+		text = assistantMessageText(msgs.Messages[0])
+	}
+	resp := TextResponse{
+		Provider:     ProviderOpenAI,
+		Model:        run.Model,
+		Text:         text,
+		UsedProvider: ProviderOpenAI,
+		UsedModel:    run.Model,
+		Latency:      time.Since(start),
+	}
+	if u := run.Usage; u.TotalTokens > 0 {
+		pt, ct, tt := u.PromptTokens, u.CompletionTokens, u.TotalTokens
+		resp.PromptTokens, resp.CompletionTokens, resp.TotalTokens = &pt, &ct, &tt
+	}
+	return resp, nil
+}
+
+// RunThreadStream is RunThread with the reply streamed: the returned events
+// carry the reply's text chunks, then the run's usage and a done event, or
+// an error event if the run fails.
+func (c *Client) RunThreadStream(ctx context.Context, threadID, assistantID string) (*StreamResponse, error) {
+	if c.cfg.OpenAIAPIKey == "" {
+		return nil, errors.New("cora: OpenAI key is required to use the Assistants API")
+	}
+	if c.cfg.OpenAIAPIType == "azure" {
+		return nil, fmt.Errorf("%w: streaming assistant runs is not supported on Azure", ErrNotSupportedByProvider)
+	}
+	body, err := json.Marshal(map[string]any{"assistant_id": assistantID, "stream": true})
+	if err != nil {
+		return nil, err
+	}
+	base := strings.TrimSuffix(cmp.Or(c.cfg.OpenAIBaseURL, defaultOpenAIBaseURL), "/")
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/threads/"+threadID+"/runs", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	httpReq.Header.Set("Authorization", "Bearer "+c.cfg.OpenAIAPIKey)
+	httpReq.Header.Set("OpenAI-Beta", "assistants=v2")
+	if c.cfg.OpenAIOrgID != "" {
+		httpReq.Header.Set("OpenAI-Organization", c.cfg.OpenAIOrgID)
+	}
+
+	start := time.Now()
+	httpResp, err := providerHTTPClient(c.cfg, c.transport, c.cfg.OpenAIHTTPClient).Do(httpReq)
+	if err != nil {
+		return nil, wrapProviderError(ProviderOpenAI, err)
+	}
+	if httpResp.StatusCode/100 != 2 {
+		defer httpResp.Body.Close()
+		return nil, openAIHTTPError(httpResp)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	events := make(chan StreamEvent, 100)
+	go func() {
+		defer close(events)
+		defer cancel()
+		defer httpResp.Body.Close()
+		stop := context.AfterFunc(streamCtx, func() { httpResp.Body.Close() })
+		defer stop()
+		readAssistantRunEvents(streamCtx, httpResp.Body, events)
+	}()
+	return &StreamResponse{Events: events, Cancel: cancel, provider: ProviderOpenAI, startedAt: start}, nil
+}
+
+// openAIHTTPError returns the CoraError of a failed OpenAI API response,
+// with the API's error message when the body has one.
+func openAIHTTPError(resp *http.Response) error {
+	data, _ := io.ReadAll(resp.Body)
+	var apiErr struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	msg := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+		msg = apiErr.Error.Message
+	}
+	return newHTTPCoraError(ProviderOpenAI, resp.StatusCode, msg, nil)
+}
+
+// readAssistantRunEvents translates the server-sent events of a streamed
+// run into stream events on events.
+func readAssistantRunEvents(ctx context.Context, r io.Reader, events chan<- StreamEvent) {
+	var seq atomic.Int64
+	send := func(ev StreamEvent) bool {
+		ev.Timestamp = time.Now()
+		ev.SequenceNumber = int(seq.Add(1))
+		ev.provider = ProviderOpenAI
+		select {
+		case <-ctx.Done():
+			return false
+		case events <- ev:
+			return true
+		}
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	var event string
+	for sc.Scan() {
+		line := sc.Text()
+		if name, ok := strings.CutPrefix(line, "event:"); ok {
+			event = strings.TrimSpace(name)
+			continue
+		}
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+
+		switch event {
+		case "thread.message.delta":
+			var delta struct {
+				Delta struct {
+					Content []openai.MessageContent `json:"content"`
+				} `json:"delta"`
+			}
+			if json.Unmarshal([]byte(data), &delta) != nil {
+				continue
+			}
+			for _, part := range delta.Delta.Content {
+				if part.Text != nil && part.Text.Value != "" && !send(StreamEvent{Type: EventTypeChunk, Text: part.Text.Value}) {
+					return
+				}
+			}
+		case "thread.run.completed", "thread.run.failed", "thread.run.cancelled", "thread.run.expired",
+			"thread.run.incomplete", "thread.run.requires_action":
+			var run openai.Run
+			if err := json.Unmarshal([]byte(data), &run); err != nil {
+				send(StreamEvent{Type: EventTypeError, Err: fmt.Errorf("cora: decoding assistant run: %w", err)})
+				return
+			}
+			if err := assistantRunError(run); err != nil {
+				send(StreamEvent{Type: EventTypeError, Err: err})
+				return
+			}
+			if u := run.Usage; u.TotalTokens > 0 {
+				send(StreamEvent{Type: EventTypeUsage, Usage: &StreamUsage{
+					PromptTokens:     u.PromptTokens,
+					CompletionTokens: u.CompletionTokens,
+					TotalTokens:      u.TotalTokens,
+				}})
+			}
+			send(StreamEvent{Type: EventTypeDone, Model: run.Model})
+			return
+		case "error":
+			var apiErr struct {
+				Message string `json:"message"`
+			}
+			_ = json.Unmarshal([]byte(data), &apiErr)
+			send(StreamEvent{Type: EventTypeError, Err: &CoraError{Provider: ProviderOpenAI, Code: ErrCodeServer, Message: cmp.Or(apiErr.Message, data)}})
+			return
+		}
+	}
+	if err := sc.Err(); err != nil && ctx.Err() == nil {
+		send(StreamEvent{Type: EventTypeError, Err: err})
+		return
+	}
+	if ctx.Err() == nil {
+		send(StreamEvent{Type: EventTypeError, Err: errors.New("cora: assistant run stream ended before the run finished")})
+	}
+}
+
+// assistantsProvider returns the OpenAI provider used by the Assistants API.
+func (c *Client) assistantsProvider() (*openAIProvider, error) {
+	pc, err := c.ensureProvider(ProviderOpenAI)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := pc.(*openAIProvider)
+	if !ok {
+		return nil, fmt.Errorf("cora: the Assistants API requires the OpenAI provider")
+	}
+	return p, nil
+}
+
+// assistantRunDone reports whether a run with status s has stopped.
+func assistantRunDone(s openai.RunStatus) bool {
+	switch s {
+	case openai.RunStatusQueued, openai.RunStatusInProgress, openai.RunStatusCancelling:
+		return false
+	}
+	return true
+}
+
+// assistantRunError returns the error of a stopped run, or nil if it completed.
+func assistantRunError(run openai.Run) error {
+	switch run.Status {
+	case openai.RunStatusCompleted:
+		return nil
+	case openai.RunStatusRequiresAction:
+		return fmt.Errorf("%w: assistant run %s requires tool outputs", ErrNotSupportedByProvider, run.ID)
+	}
+	msg := fmt.Sprintf("assistant run %s %s", run.ID, run.Status)
+	if run.LastError != nil {
+		msg += ": " + run.LastError.Message
+	}
+	return &CoraError{Provider: ProviderOpenAI, Code: ErrCodeServer, Message: msg}
+}
+
+// assistantMessageText joins the text parts of an assistant message.
+func assistantMessageText(m openai.Message) string {
+	var b strings.Builder
+	for _, part := range m.Content {
+		if part.Text != nil {
+			b.WriteString(part.Text.Value)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,141 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// assistantsServer mocks the Assistants API endpoints used by cora. Runs
+// are in progress on creation and complete on the first retrieval.
+func assistantsServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	var messages []string
+	var polls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("OpenAI-Beta") != "assistants=v2" {
+			t.Errorf("%s %s without the assistants beta header", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		run := map[string]any{"id": "run_1", "thread_id": "thread_1", "model": "gpt-4o", "status": "in_progress"}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/assistants":
+			if body["model"] != "gpt-4o" || body["instructions"] != "Be helpful." {
+				t.Errorf("unexpected assistant request %v", body)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "asst_1", "object": "assistant"})
+		case r.Method == http.MethodPost && r.URL.Path == "/threads":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "thread_1", "object": "thread"})
+		case r.Method == http.MethodPost && r.URL.Path == "/threads/thread_1/messages":
+			messages = append(messages, fmt.Sprint(body["role"], ": ", body["content"]))
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "msg_1", "object": "thread.message"})
+		case r.Method == http.MethodPost && r.URL.Path == "/threads/thread_1/runs":
+			if body["assistant_id"] != "asst_1" {
+				t.Errorf("unexpected run request %v", body)
+			}
+			if body["stream"] == true {
+				w.Header().Set("Content-Type", "text/event-stream")
+				fmt.Fprint(w, "event: thread.run.created\ndata: {\"id\":\"run_1\",\"status\":\"queued\"}\n\n")
+				for _, chunk := range []string{"Hello", " there"} {
+					fmt.Fprintf(w, "event: thread.message.delta\ndata: {\"delta\":{\"content\":[{\"type\":\"text\",\"text\":{\"value\":%q}}]}}\n\n", chunk)
+				}
+				fmt.Fprint(w, "event: thread.run.completed\ndata: {\"id\":\"run_1\",\"model\":\"gpt-4o\",\"status\":\"completed\",\"usage\":{\"prompt_tokens\":9,\"completion_tokens\":2,\"total_tokens\":11}}\n\n")
+				fmt.Fprint(w, "event: done\ndata: [DONE]\n\n")
+				return
+			}
+			_ = json.NewEncoder(w).Encode(run)
+		case r.Method == http.MethodGet && r.URL.Path == "/threads/thread_1/runs/run_1":
+			polls.Add(1)
+			run["status"] = "completed"
+			run["usage"] = map[string]any{"prompt_tokens": 9, "completion_tokens": 2, "total_tokens": 11}
+			_ = json.NewEncoder(w).Encode(run)
+		case r.Method == http.MethodGet && r.URL.Path == "/threads/thread_1/messages":
+			if r.URL.Query().Get("run_id") != "run_1" {
+				t.Errorf("messages listed without the run ID: %s", r.URL.RawQuery)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": []map[string]any{{
+				"id": "msg_2", "role": "assistant",
+				"content": []map[string]any{{"type": "text", "text": map[string]any{"value": "Hello there", "annotations": []any{}}}},
+			}}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &messages
+}
+
+func TestAssistants_RunThread(t *testing.T) {
+	defer func(d time.Duration) { assistantPollInterval = d }(assistantPollInterval)
+	assistantPollInterval = time.Millisecond
+
+	srv, messages := assistantsServer(t)
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	ctx := context.Background()
+
+	assistantID, err := c.CreateAssistant(ctx, AssistantConfig{Model: "gpt-4o", Instructions: "Be helpful."})
+	if err != nil || assistantID != "asst_1" {
+		t.Fatalf("CreateAssistant = %q, %v", assistantID, err)
+	}
+	threadID, err := c.CreateThread(ctx)
+	if err != nil || threadID != "thread_1" {
+		t.Fatalf("CreateThread = %q, %v", threadID, err)
+	}
+	if err := c.AddMessage(ctx, threadID, "user", "Say hello"); err != nil {
+		t.Fatalf("AddMessage error: %v", err)
+	}
+	if len(*messages) != 1 || (*messages)[0] != "user: Say hello" {
+		t.Errorf("unexpected messages %v", *messages)
+	}
+
+	resp, err := c.RunThread(ctx, threadID, assistantID)
+	if err != nil {
+		t.Fatalf("RunThread error: %v", err)
+	}
+	if resp.Text != "Hello there" || resp.Model != "gpt-4o" || resp.TotalTokens == nil || *resp.TotalTokens != 11 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+func TestAssistants_RunThreadStream(t *testing.T) {
+	srv, _ := assistantsServer(t)
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+
+	stream, err := c.RunThreadStream(context.Background(), "thread_1", "asst_1")
+	if err != nil {
+		t.Fatalf("RunThreadStream error: %v", err)
+	}
+	var text strings.Builder
+	var types []StreamEventType
+	var usage *StreamUsage
+	for ev := range stream.Events {
+		types = append(types, ev.Type)
+		switch ev.Type {
+		case EventTypeChunk:
+			text.WriteString(ev.Text)
+		case EventTypeUsage:
+			usage = ev.Usage
+		case EventTypeError:
+			t.Fatalf("stream error: %v", ev.Err)
+		}
+	}
+	if text.String() != "Hello there" {
+		t.Errorf("streamed text = %q", text.String())
+	}
+	if usage == nil || usage.TotalTokens != 11 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+	if len(types) == 0 || types[len(types)-1] != EventTypeDone {
+		t.Errorf("event types = %v, want a final done event", types)
+	}
+}
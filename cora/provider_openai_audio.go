@@ -0,0 +1,48 @@
+package cora
+
+import (
+	"bytes"
+	"context"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// audioExtensions maps audio MIME types to the file extensions the
+// transcription endpoint uses to detect the format.
+var audioExtensions = map[string]string{
+	"audio/mpeg":  "mp3",
+	"audio/mp3":   "mp3",
+	"audio/mp4":   "m4a",
+	"audio/x-m4a": "m4a",
+	"audio/wav":   "wav",
+	"audio/x-wav": "wav",
+	"audio/webm":  "webm",
+	"audio/ogg":   "ogg",
+	"audio/flac":  "flac",
+}
+
+// transcribe calls the /audio/transcriptions endpoint. Input, if any, is
+// passed as the prompt that guides spelling and style.
+func (p *openAIProvider) transcribe(ctx context.Context, plan callPlan) (callResult, error) {
+	ext, ok := audioExtensions[plan.Audio.MIMEType]
+	if !ok {
+		ext = "mp3"
+	}
+	req := openai.AudioRequest{
+		Model:    plan.Model,
+		FilePath: "audio." + ext,
+		Reader:   bytes.NewReader(plan.Audio.Data),
+		Prompt:   plan.Input,
+		Language: plan.Audio.Language,
+		Format:   openai.AudioResponseFormatVerboseJSON,
+	}
+	if plan.Temperature != nil {
+		req.Temperature = *plan.Temperature
+	}
+
+	resp, err := p.client.CreateTranscription(ctx, req)
+	if err != nil {
+		return callResult{}, err
+	}
+	return callResult{Text: resp.Text, TranscriptionLanguage: resp.Language}, nil
+}
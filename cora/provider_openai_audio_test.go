@@ -0,0 +1,56 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestText_TranscribeOpenAI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/transcriptions" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parse multipart form: %v", err)
+		}
+		if got := r.FormValue("model"); got != "whisper-1" {
+			t.Errorf("unexpected model %q", got)
+		}
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("missing file: %v", err)
+		} else if header.Filename != "audio.wav" {
+			t.Errorf("unexpected filename %q", header.Filename)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"text": "hello world", "language": "english"})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "whisper-1",
+		Mode:     ModeTranscribe,
+		Audio:    AudioInput{Data: []byte("RIFF....WAVE"), MIMEType: "audio/wav"},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "hello world" {
+		t.Errorf("unexpected transcript %q", resp.Text)
+	}
+	if resp.TranscriptionLanguage != "english" {
+		t.Errorf("unexpected language %q", resp.TranscriptionLanguage)
+	}
+}
+
+func TestBuildPlans_TranscribeRequiresAudio(t *testing.T) {
+	if _, err := buildPlans(ProviderOpenAI, "whisper-1", TextRequest{Mode: ModeTranscribe}, CoraConfig{}, nil); err == nil {
+		t.Error("expected an error without audio data")
+	}
+}
@@ -0,0 +1,191 @@
+package cora
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// openAIBatchLine is one line of an OpenAI batch output or error file.
+type openAIBatchLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int                           `json:"status_code"`
+		Body       openai.ChatCompletionResponse `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *openAIProvider) CreateBatch(ctx context.Context, model string, plans []callPlan) (string, BatchJobStatus, error) {
+	upload := openai.UploadBatchFileRequest{FileName: "cora-batch.jsonl"}
+	for i, plan := range plans {
+		if err := checkOpenAIDocuments(plan.Documents); err != nil {
+			return "", 0, err
+		}
+		// The index is the custom ID, which maps results back to requests.
+		upload.AddChatCompletion(strconv.Itoa(i), openAIRequestFromPlan(plan))
+	}
+	batch, err := p.client.CreateBatchWithUploadFile(ctx, openai.CreateBatchWithUploadFileRequest{
+		Endpoint:               openai.BatchEndpointChatCompletions,
+		UploadBatchFileRequest: upload,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return batch.ID, batchJobStatusFromOpenAI(batch.Status), nil
+}
+
+func (p *openAIProvider) GetBatch(ctx context.Context, id string) (BatchJobStatus, []batchItem, error) {
+	batch, err := p.client.RetrieveBatch(ctx, id)
+	if err != nil {
+		return 0, nil, err
+	}
+	status := batchJobStatusFromOpenAI(batch.Status)
+	if status != BatchJobSucceeded {
+		return status, nil, nil
+	}
+	items := make([]batchItem, batch.RequestCounts.Total)
+	seen := make([]bool, len(items))
+	for _, fileID := range []*string{batch.OutputFileID, batch.ErrorFileID} {
+		if fileID == nil || *fileID == "" {
+			continue
+		}
+		if err := p.readBatchFile(ctx, *fileID, items, seen); err != nil {
+			return 0, nil, err
+		}
+	}
+	for i := range items {
+		if !seen[i] {
+			items[i].err = errors.New("no response")
+		}
+	}
+	return status, items, nil
+}
+
+// readBatchFile fills items from a batch output or error file.
+func (p *openAIProvider) readBatchFile(ctx context.Context, fileID string, items []batchItem, seen []bool) error {
+	content, err := p.client.GetFileContent(ctx, fileID)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	sc := bufio.NewScanner(content)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var line openAIBatchLine
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			return fmt.Errorf("cora: decoding batch file %s: %w", fileID, err)
+		}
+		i, err := strconv.Atoi(line.CustomID)
+		if err != nil || i < 0 || i >= len(items) {
+			return fmt.Errorf("cora: batch file %s: unexpected custom_id %q", fileID, line.CustomID)
+		}
+		seen[i] = true
+		switch {
+		case line.Error != nil:
+			items[i].err = errors.New(line.Error.Message)
+		case line.Response == nil:
+			items[i].err = errors.New("no response")
+		case line.Response.StatusCode != 200:
+			items[i].err = fmt.Errorf("status %d", line.Response.StatusCode)
+		default:
+			items[i].res = p.toCallResult(line.Response.Body)
+		}
+	}
+	return sc.Err()
+}
+
+func (p *openAIProvider) CancelBatch(ctx context.Context, id string) error {
+	_, err := p.client.CancelBatch(ctx, id)
+	return err
+}
+
+// batchJobStatusFromOpenAI maps an OpenAI batch status to a BatchJobStatus.
+func batchJobStatusFromOpenAI(status string) BatchJobStatus {
+	switch status {
+	case "in_progress", "finalizing", "cancelling":
+		return BatchJobRunning
+	case "completed":
+		return BatchJobSucceeded
+	case "failed":
+		return BatchJobFailed
+	case "cancelled":
+		return BatchJobCancelled
+	case "expired":
+		return BatchJobExpired
+	default:
+		return BatchJobPending
+	}
+}
+
+// OpenAIBatchJob is a batch of OpenAI requests submitted with
+// Client.SubmitBatchFile.
+type OpenAIBatchJob struct {
+	// ID is the OpenAI batch ID, e.g. "batch_abc123".
+	ID string
+	// Status is the job status as of the last poll.
+	Status BatchJobStatus
+
+	job     *BatchJob
+	results []TextResponse
+	err     error
+	done    bool
+}
+
+// SubmitBatchFile uploads reqs as a JSONL file and submits it to the OpenAI
+// Batch API, which processes it within 24 hours at half the real-time price.
+// All requests must use ProviderOpenAI; see BatchText for the other
+// restrictions.
+func (c *Client) SubmitBatchFile(ctx context.Context, reqs []TextRequest) (*OpenAIBatchJob, error) {
+	for i, req := range reqs {
+		if req.Provider != ProviderOpenAI {
+			return nil, fmt.Errorf("cora: batch request %d: SubmitBatchFile only supports ProviderOpenAI", i)
+		}
+	}
+	job, err := c.BatchText(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+	return &OpenAIBatchJob{ID: job.ID, Status: job.Status, job: job}, nil
+}
+
+// Wait polls the job every pollInterval (30s if zero) until it finished or
+// ctx is done. It fails if the job itself did not succeed; the responses,
+// including failures of individual requests, are then available from Results.
+func (j *OpenAIBatchJob) Wait(ctx context.Context, pollInterval time.Duration) error {
+	j.job.PollInterval = pollInterval
+	results, err := j.job.Wait(ctx)
+	j.Status = j.job.Status
+	if results == nil {
+		return err
+	}
+	j.results, j.err, j.done = results, err, true
+	return nil
+}
+
+// Results returns the responses in request order once Wait returned. A
+// request that failed within the batch has an empty response and is
+// reported in the returned error.
+func (j *OpenAIBatchJob) Results() ([]TextResponse, error) {
+	if !j.done {
+		return nil, fmt.Errorf("cora: batch %s has no results yet (status %s)", j.ID, j.Status)
+	}
+	return j.results, j.err
+}
+
+// Cancel asks OpenAI to cancel the job.
+func (j *OpenAIBatchJob) Cancel(ctx context.Context) error {
+	return j.job.Cancel(ctx)
+}
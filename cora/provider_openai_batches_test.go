@@ -0,0 +1,118 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// openAIBatchServer fakes the OpenAI file and batch APIs: the job runs for
+// one poll, then completes with the answers out of order and the second
+// request in the error file.
+func openAIBatchServer(t *testing.T) (*httptest.Server, *[]map[string]any) {
+	var mu sync.Mutex
+	var lines []map[string]any
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/files":
+			f, _, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("upload without file: %v", err)
+				return
+			}
+			data, _ := io.ReadAll(f)
+			for _, l := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+				var line map[string]any
+				if err := json.Unmarshal([]byte(l), &line); err != nil {
+					t.Errorf("bad batch line %q: %v", l, err)
+				}
+				lines = append(lines, line)
+			}
+			_, _ = io.WriteString(w, `{"id":"file-in","object":"file","purpose":"batch"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/batches":
+			_, _ = io.WriteString(w, `{"id":"batch_1","status":"validating"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/batches/batch_1":
+			polls++
+			if polls == 1 {
+				_, _ = io.WriteString(w, `{"id":"batch_1","status":"in_progress"}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"id":"batch_1","status":"completed","output_file_id":"file-out","error_file_id":"file-err","request_counts":{"total":3,"completed":2,"failed":1}}`)
+		case r.URL.Path == "/files/file-out/content":
+			_, _ = io.WriteString(w, `{"custom_id":"2","response":{"status_code":200,"body":{"choices":[{"message":{"role":"assistant","content":"third"}}],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}}}
+{"custom_id":"0","response":{"status_code":200,"body":{"choices":[{"message":{"role":"assistant","content":"first"}}]}}}
+`)
+		case r.URL.Path == "/files/file-err/content":
+			_, _ = io.WriteString(w, `{"custom_id":"1","response":null,"error":{"code":"invalid_request","message":"bad input"}}
+`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	return srv, &lines
+}
+
+func TestSubmitBatchFile(t *testing.T) {
+	srv, lines := openAIBatchServer(t)
+	defer srv.Close()
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	job, err := c.SubmitBatchFile(context.Background(), []TextRequest{
+		{Provider: ProviderOpenAI, Model: "gpt-4o-mini", Input: "one"},
+		{Provider: ProviderOpenAI, Model: "gpt-4o-mini", Input: "two"},
+		{Provider: ProviderOpenAI, Model: "gpt-4o-mini", Input: "three"},
+	})
+	if err != nil {
+		t.Fatalf("SubmitBatchFile error: %v", err)
+	}
+	if job.ID != "batch_1" || job.Status != BatchJobPending {
+		t.Fatalf("job = %s %s", job.ID, job.Status)
+	}
+	if len(*lines) != 3 {
+		t.Fatalf("uploaded %d lines, want 3", len(*lines))
+	}
+	if got := (*lines)[1]; got["custom_id"] != "1" || got["url"] != "/v1/chat/completions" {
+		t.Errorf("line 1 = %v", got)
+	}
+	if _, err := job.Results(); err == nil {
+		t.Error("Results before Wait should fail")
+	}
+
+	if err := job.Wait(context.Background(), time.Millisecond); err != nil {
+		t.Fatalf("Wait error: %v", err)
+	}
+	if job.Status != BatchJobSucceeded {
+		t.Errorf("Status = %s", job.Status)
+	}
+	out, err := job.Results()
+	if err == nil || !strings.Contains(err.Error(), "batch request 1: bad input") {
+		t.Errorf("Results error = %v", err)
+	}
+	if len(out) != 3 || out[0].Text != "first" || out[1].Text != "" || out[2].Text != "third" {
+		t.Fatalf("Results = %+v", out)
+	}
+	if out[2].TotalTokens == nil || *out[2].TotalTokens != 4 {
+		t.Errorf("TotalTokens = %v", out[2].TotalTokens)
+	}
+}
+
+func TestSubmitBatchFile_RejectsOtherProviders(t *testing.T) {
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", GoogleAPIKey: "g-test"})
+	_, err := c.SubmitBatchFile(context.Background(), []TextRequest{
+		{Provider: ProviderGoogle, Model: "gemini-2.5-flash", Input: "one"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "only supports ProviderOpenAI") {
+		t.Errorf("err = %v", err)
+	}
+}
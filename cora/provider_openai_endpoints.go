@@ -0,0 +1,86 @@
+package cora
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// OpenAIEndpoint is an additional OpenAI-compatible API, such as X.AI or a
+// self-hosted server, serving the listed models (see
+// CoraConfig.OpenAIEndpoints).
+type OpenAIEndpoint struct {
+	Name    string
+	BaseURL string
+	APIKey  string
+	Models  []string
+}
+
+// openAIEndpointFor returns the endpoint that serves model: the first one
+// listing it, else DefaultOpenAIEndpoint. It returns nil when requests go to
+// OpenAIAPIKey and OpenAIBaseURL.
+func (cfg CoraConfig) openAIEndpointFor(model string) *OpenAIEndpoint {
+	for i, ep := range cfg.OpenAIEndpoints {
+		if slices.Contains(ep.Models, model) {
+			return &cfg.OpenAIEndpoints[i]
+		}
+	}
+	for i, ep := range cfg.OpenAIEndpoints {
+		if ep.Name == cfg.DefaultOpenAIEndpoint {
+			return &cfg.OpenAIEndpoints[i]
+		}
+	}
+	return nil
+}
+
+// validateOpenAIEndpoints checks that endpoints are named uniquely, have a
+// base URL, and that DefaultOpenAIEndpoint names one of them.
+func (cfg CoraConfig) validateOpenAIEndpoints() []error {
+	var errs []error
+	names := make(map[string]bool, len(cfg.OpenAIEndpoints))
+	for _, ep := range cfg.OpenAIEndpoints {
+		switch {
+		case ep.Name == "":
+			errs = append(errs, errors.New("cora: every OpenAIEndpoint needs a Name"))
+		case names[ep.Name]:
+			errs = append(errs, fmt.Errorf("cora: duplicate OpenAIEndpoint %q", ep.Name))
+		}
+		names[ep.Name] = true
+		if ep.BaseURL == "" {
+			errs = append(errs, fmt.Errorf("cora: OpenAIEndpoint %q needs a BaseURL", ep.Name))
+		}
+	}
+	if cfg.DefaultOpenAIEndpoint != "" && !names[cfg.DefaultOpenAIEndpoint] {
+		errs = append(errs, fmt.Errorf("cora: DefaultOpenAIEndpoint %q is not one of OpenAIEndpoints", cfg.DefaultOpenAIEndpoint))
+	}
+	return errs
+}
+
+// providerFor returns the client for requests to provider with model. OpenAI
+// requests are routed to the matching OpenAIEndpoint, if any.
+func (c *Client) providerFor(provider Provider, model string) (providerClient, error) {
+	if provider != ProviderOpenAI || len(c.cfg.OpenAIEndpoints) == 0 {
+		return c.ensureProvider(provider)
+	}
+	ep := c.cfg.openAIEndpointFor(model)
+	if ep == nil {
+		return c.ensureProvider(provider)
+	}
+
+	c.endpointsMu.Lock()
+	defer c.endpointsMu.Unlock()
+	if pc, ok := c.endpoints[ep.Name]; ok {
+		return pc, nil
+	}
+	oc := openai.DefaultConfig(ep.APIKey)
+	oc.BaseURL = ep.BaseURL
+	oc.HTTPClient = providerHTTPClient(c.cfg, c.transport, c.cfg.OpenAIHTTPClient)
+	pc := &openAIProvider{client: openai.NewClientWithConfig(oc)}
+	if c.endpoints == nil {
+		c.endpoints = make(map[string]providerClient)
+	}
+	c.endpoints[ep.Name] = pc
+	return pc, nil
+}
@@ -0,0 +1,129 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// openAICompatServer answers chat completions with name and records the
+// Authorization header of each request.
+func openAICompatServer(t *testing.T, name string, auth *[]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*auth = append(*auth, name+" "+r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": name}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestOpenAIEndpoints_Routing(t *testing.T) {
+	var auth []string
+	openaiSrv := openAICompatServer(t, "openai", &auth)
+	xaiSrv := openAICompatServer(t, "xai", &auth)
+	localSrv := openAICompatServer(t, "local", &auth)
+
+	cfg := CoraConfig{
+		OpenAIAPIKey:  "sk-openai",
+		OpenAIBaseURL: openaiSrv.URL,
+		OpenAIEndpoints: []OpenAIEndpoint{
+			{Name: "xai", BaseURL: xaiSrv.URL, APIKey: "xai-key", Models: []string{"grok-2"}},
+			{Name: "local", BaseURL: localSrv.URL, Models: []string{"llama3"}},
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	c := New(cfg)
+	for _, tc := range []struct{ model, want string }{
+		{"grok-2", "xai"},
+		{"gpt-4o", "openai"},
+		{"llama3", "local"},
+		{"grok-2", "xai"},
+	} {
+		resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: tc.model, Input: "hi"})
+		if err != nil {
+			t.Fatalf("%s: Text error: %v", tc.model, err)
+		}
+		if resp.Text != tc.want {
+			t.Errorf("%s: routed to %q, want %q", tc.model, resp.Text, tc.want)
+		}
+	}
+	want := "xai Bearer xai-key,openai Bearer sk-openai,local ,xai Bearer xai-key"
+	if got := strings.Join(auth, ","); got != want {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+
+	// With DefaultOpenAIEndpoint, unlisted models go to that endpoint and no
+	// OpenAI key is needed.
+	cfg = CoraConfig{
+		OpenAIEndpoints:       cfg.OpenAIEndpoints,
+		DefaultOpenAIEndpoint: "local",
+		Provider:              ProviderOpenAI,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	resp, err := New(cfg).Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "mistral-7b", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "local" {
+		t.Errorf("routed to %q, want the default endpoint", resp.Text)
+	}
+}
+
+func TestOpenAIEndpoints_Validate(t *testing.T) {
+	cfg := CoraConfig{
+		OpenAIAPIKey: "sk",
+		OpenAIEndpoints: []OpenAIEndpoint{
+			{Name: "xai", BaseURL: "https://api.x.ai/v1"},
+			{Name: "xai"},
+		},
+		DefaultOpenAIEndpoint: "groq",
+	}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{`duplicate OpenAIEndpoint "xai"`, `needs a BaseURL`, `DefaultOpenAIEndpoint "groq"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestOpenAIEndpoints_Embed(t *testing.T) {
+	var hits []string
+	embedServer := func(name string) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits = append(hits, name+" "+r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{{"object": "embedding", "index": 0, "embedding": []float32{1, 0}}},
+			})
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	openaiSrv, localSrv := embedServer("openai"), embedServer("local")
+
+	c := New(CoraConfig{
+		OpenAIAPIKey:    "sk-openai",
+		OpenAIBaseURL:   openaiSrv.URL,
+		OpenAIEndpoints: []OpenAIEndpoint{{Name: "local", BaseURL: localSrv.URL, Models: []string{"nomic-embed"}}},
+	})
+	if _, err := c.Embed(context.Background(), EmbedRequest{Provider: ProviderOpenAI, Model: "nomic-embed", Input: []string{"hi"}}); err != nil {
+		t.Fatalf("Embed error: %v", err)
+	}
+	if want := "local /embeddings"; len(hits) != 1 || hits[0] != want {
+		t.Errorf("requests = %q, want %q", hits, want)
+	}
+}
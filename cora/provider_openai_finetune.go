@@ -0,0 +1,194 @@
+package cora
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// defaultFineTunePollInterval is how often FineTuneJob.Wait checks a job
+// when PollInterval is zero.
+const defaultFineTunePollInterval = 30 * time.Second
+
+// FineTuneRequest starts an OpenAI fine-tuning job (see
+// Client.CreateFineTuneJob). The files are uploaded JSONL files with
+// purpose "fine-tune".
+type FineTuneRequest struct {
+	TrainingFileID   string
+	ValidationFileID string
+	Model            string
+	// Hyperparameters may set "n_epochs", "batch_size" and
+	// "learning_rate_multiplier", each a number or "auto".
+	Hyperparameters map[string]any
+}
+
+// FineTuneJob is an OpenAI fine-tuning job. Status is one of OpenAI's job
+// statuses ("validating_files", "queued", "running", "succeeded", "failed"
+// or "cancelled") as of the last update; FineTunedModel is set once the job
+// succeeded and names the model to use in requests.
+type FineTuneJob struct {
+	ID             string
+	Status         string
+	FineTunedModel string
+
+	// PollInterval is how often Wait checks the job (default 30s).
+	PollInterval time.Duration
+
+	provider *openAIProvider
+}
+
+// CreateFineTuneJob starts a fine-tuning job on OpenAI.
+func (c *Client) CreateFineTuneJob(ctx context.Context, req FineTuneRequest) (*FineTuneJob, error) {
+	if req.TrainingFileID == "" {
+		return nil, errors.New("cora: TrainingFileID is required")
+	}
+	if req.Model == "" {
+		return nil, errors.New("cora: model must be specified")
+	}
+	hp, err := fineTuneHyperparameters(req.Hyperparameters)
+	if err != nil {
+		return nil, err
+	}
+	p, err := c.fineTuneProvider()
+	if err != nil {
+		return nil, err
+	}
+	job, err := p.client.CreateFineTuningJob(ctx, openai.FineTuningJobRequest{
+		TrainingFile:    req.TrainingFileID,
+		ValidationFile:  req.ValidationFileID,
+		Model:           req.Model,
+		Hyperparameters: hp,
+	})
+	if err != nil {
+		return nil, wrapProviderError(ProviderOpenAI, err)
+	}
+	return newFineTuneJob(p, job), nil
+}
+
+// ListFineTuneJobs returns the organization's fine-tuning jobs, most recent
+// first (the first page of at most 20).
+func (c *Client) ListFineTuneJobs(ctx context.Context) ([]FineTuneJob, error) {
+	p, err := c.fineTuneProvider()
+	if err != nil {
+		return nil, err
+	}
+	if c.cfg.OpenAIAPIType == "azure" {
+		return nil, fmt.Errorf("%w: listing fine-tuning jobs is not supported on Azure", ErrNotSupportedByProvider)
+	}
+	base := strings.TrimSuffix(cmp.Or(c.cfg.OpenAIBaseURL, defaultOpenAIBaseURL), "/")
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/fine_tuning/jobs", nil)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.cfg.OpenAIAPIKey)
+	if c.cfg.OpenAIOrgID != "" {
+		httpReq.Header.Set("OpenAI-Organization", c.cfg.OpenAIOrgID)
+	}
+	httpResp, err := providerHTTPClient(c.cfg, c.transport, c.cfg.OpenAIHTTPClient).Do(httpReq)
+	if err != nil {
+		return nil, wrapProviderError(ProviderOpenAI, err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode/100 != 2 {
+		return nil, openAIHTTPError(httpResp)
+	}
+	var list struct {
+		Data []openai.FineTuningJob `json:"data"`
+	}
+	if err := json.NewDecoder(httpResp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("cora: decoding fine-tuning jobs: %w", err)
+	}
+	jobs := make([]FineTuneJob, len(list.Data))
+	for i, job := range list.Data {
+		jobs[i] = *newFineTuneJob(p, job)
+	}
+	return jobs, nil
+}
+
+// Wait polls the job until it finished or ctx is done, updating Status and
+// FineTunedModel. It fails if the job failed or was cancelled.
+func (j *FineTuneJob) Wait(ctx context.Context) error {
+	interval := j.PollInterval
+	if interval <= 0 {
+		interval = defaultFineTunePollInterval
+	}
+	for {
+		job, err := j.provider.client.RetrieveFineTuningJob(ctx, j.ID)
+		if err != nil {
+			return wrapProviderError(ProviderOpenAI, err)
+		}
+		j.update(job)
+		switch j.Status {
+		case "succeeded":
+			return nil
+		case "failed", "cancelled":
+			return &CoraError{Provider: ProviderOpenAI, Code: ErrCodeServer, Message: fmt.Sprintf("fine-tuning job %s %s", j.ID, j.Status)}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Cancel asks OpenAI to cancel the job and updates Status.
+func (j *FineTuneJob) Cancel(ctx context.Context) error {
+	job, err := j.provider.client.CancelFineTuningJob(ctx, j.ID)
+	if err != nil {
+		return wrapProviderError(ProviderOpenAI, err)
+	}
+	j.update(job)
+	return nil
+}
+
+func newFineTuneJob(p *openAIProvider, job openai.FineTuningJob) *FineTuneJob {
+	j := &FineTuneJob{provider: p}
+	j.update(job)
+	return j
+}
+
+func (j *FineTuneJob) update(job openai.FineTuningJob) {
+	j.ID, j.Status, j.FineTunedModel = job.ID, job.Status, job.FineTunedModel
+}
+
+// fineTuneHyperparameters converts FineTuneRequest.Hyperparameters.
+func fineTuneHyperparameters(m map[string]any) (*openai.Hyperparameters, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	hp := &openai.Hyperparameters{}
+	for k, v := range m {
+		switch k {
+		case "n_epochs":
+			hp.Epochs = v
+		case "batch_size":
+			hp.BatchSize = v
+		case "learning_rate_multiplier":
+			hp.LearningRateMultiplier = v
+		default:
+			return nil, fmt.Errorf("cora: unknown fine-tuning hyperparameter %q", k)
+		}
+	}
+	return hp, nil
+}
+
+// fineTuneProvider returns the OpenAI provider used for fine-tuning.
+func (c *Client) fineTuneProvider() (*openAIProvider, error) {
+	pc, err := c.ensureProvider(ProviderOpenAI)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := pc.(*openAIProvider)
+	if !ok {
+		return nil, errors.New("cora: fine-tuning requires the OpenAI provider")
+	}
+	return p, nil
+}
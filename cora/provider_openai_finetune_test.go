@@ -0,0 +1,91 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFineTuneJob(t *testing.T) {
+	var created map[string]any
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/fine_tuning/jobs":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			_, _ = io.WriteString(w, `{"id":"ftjob-1","status":"validating_files","model":"text-embedding-3-small"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/fine_tuning/jobs/ftjob-1":
+			polls++
+			if polls == 1 {
+				_, _ = io.WriteString(w, `{"id":"ftjob-1","status":"running"}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"id":"ftjob-1","status":"succeeded","fine_tuned_model":"ft:text-embedding-3-small:acme::abc"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/fine_tuning/jobs":
+			_, _ = io.WriteString(w, `{"object":"list","data":[{"id":"ftjob-2","status":"running"},{"id":"ftjob-1","status":"succeeded","fine_tuned_model":"ft:x"}]}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/fine_tuning/jobs/ftjob-2/cancel":
+			_, _ = io.WriteString(w, `{"id":"ftjob-2","status":"cancelled"}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	job, err := c.CreateFineTuneJob(context.Background(), FineTuneRequest{
+		TrainingFileID:  "file-train",
+		Model:           "text-embedding-3-small",
+		Hyperparameters: map[string]any{"n_epochs": 3},
+	})
+	if err != nil {
+		t.Fatalf("CreateFineTuneJob error: %v", err)
+	}
+	if job.ID != "ftjob-1" || job.Status != "validating_files" {
+		t.Errorf("job = %+v", job)
+	}
+	hp, _ := created["hyperparameters"].(map[string]any)
+	if created["training_file"] != "file-train" || hp["n_epochs"] != float64(3) {
+		t.Errorf("create body = %v", created)
+	}
+
+	job.PollInterval = time.Millisecond
+	if err := job.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait error: %v", err)
+	}
+	if job.Status != "succeeded" || job.FineTunedModel != "ft:text-embedding-3-small:acme::abc" {
+		t.Errorf("job after Wait = %+v", job)
+	}
+
+	jobs, err := c.ListFineTuneJobs(context.Background())
+	if err != nil {
+		t.Fatalf("ListFineTuneJobs error: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != "ftjob-2" || jobs[1].FineTunedModel != "ft:x" {
+		t.Fatalf("jobs = %+v", jobs)
+	}
+	if err := jobs[0].Cancel(context.Background()); err != nil {
+		t.Fatalf("Cancel error: %v", err)
+	}
+	if jobs[0].Status != "cancelled" {
+		t.Errorf("Status after Cancel = %q", jobs[0].Status)
+	}
+}
+
+func TestCreateFineTuneJob_UnknownHyperparameter(t *testing.T) {
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test"})
+	_, err := c.CreateFineTuneJob(context.Background(), FineTuneRequest{
+		TrainingFileID:  "file-train",
+		Model:           "gpt-4o-mini",
+		Hyperparameters: map[string]any{"epochs": 3},
+	})
+	if err == nil || !strings.Contains(err.Error(), `unknown fine-tuning hyperparameter "epochs"`) {
+		t.Errorf("err = %v", err)
+	}
+}
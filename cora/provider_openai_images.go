@@ -0,0 +1,45 @@
+package cora
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// GenerateImage calls the /images/generations endpoint.
+func (p *openAIProvider) GenerateImage(ctx context.Context, req ImageRequest) (ImageResponse, error) {
+	ireq := openai.ImageRequest{
+		Prompt:  req.Prompt,
+		Model:   req.Model,
+		N:       req.N,
+		Quality: req.Quality,
+	}
+	if req.Width > 0 && req.Height > 0 {
+		ireq.Size = fmt.Sprintf("%dx%d", req.Width, req.Height)
+	}
+
+	res, err := p.client.CreateImage(ctx, ireq)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+
+	var out ImageResponse
+	for _, d := range res.Data {
+		if d.URL != "" {
+			out.URLs = append(out.URLs, d.URL)
+		}
+		if d.B64JSON != "" {
+			b, err := base64.StdEncoding.DecodeString(d.B64JSON)
+			if err != nil {
+				return ImageResponse{}, fmt.Errorf("cora: decode image data: %w", err)
+			}
+			out.Data = append(out.Data, b)
+		}
+		if out.RevisedPrompt == "" {
+			out.RevisedPrompt = d.RevisedPrompt
+		}
+	}
+	return out, nil
+}
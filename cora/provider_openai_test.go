@@ -0,0 +1,375 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+func TestOpenAIRequestFromPlan_ReasoningModel(t *testing.T) {
+	effort := "high"
+	temp := float32(0.7)
+	req := openAIRequestFromPlan(callPlan{
+		Model:           "o3-mini",
+		System:          "Answer in French.",
+		Input:           "What is 2+2?",
+		Temperature:     &temp,
+		ReasoningEffort: &effort,
+	})
+
+	if req.ReasoningEffort != "high" {
+		t.Errorf("expected reasoning_effort high, got %q", req.ReasoningEffort)
+	}
+	if req.Temperature != 0 {
+		t.Errorf("expected temperature to be suppressed, got %v", req.Temperature)
+	}
+	if len(req.Messages) != 1 || req.Messages[0].Role != openai.ChatMessageRoleUser {
+		t.Fatalf("expected a single user message, got %+v", req.Messages)
+	}
+	if want := "Instructions:\nAnswer in French.\n\nWhat is 2+2?"; req.Messages[0].Content != want {
+		t.Errorf("unexpected user message %q", req.Messages[0].Content)
+	}
+}
+
+func TestOpenAIRequestFromPlan_Standard(t *testing.T) {
+	temp := float32(0.7)
+	req := openAIRequestFromPlan(callPlan{Model: "gpt-4o", System: "sys", Input: "hi", Temperature: &temp})
+	if req.ReasoningEffort != "" || req.Temperature != 0.7 {
+		t.Errorf("unexpected reasoning/temperature: %q %v", req.ReasoningEffort, req.Temperature)
+	}
+	if len(req.Messages) != 2 || req.Messages[0].Role != openai.ChatMessageRoleSystem {
+		t.Errorf("expected system + user messages, got %+v", req.Messages)
+	}
+}
+
+func TestOpenAIProofreadRequest_Temperature(t *testing.T) {
+	if req := openAIProofreadRequest(callPlan{Model: "gpt-4o", Input: "hi"}); req.Temperature != 0.2 {
+		t.Errorf("gpt-4o: Temperature = %v, want the 0.2 default", req.Temperature)
+	}
+	temp := float32(0.7)
+	if req := openAIProofreadRequest(callPlan{Model: "gpt-4o", Temperature: &temp}); req.Temperature != 0.7 {
+		t.Errorf("gpt-4o: Temperature = %v, want the plan's 0.7", req.Temperature)
+	}
+	for _, model := range []string{"o1-mini", "o3", "o4-mini", "gpt-5", "gpt-5-mini"} {
+		if req := openAIProofreadRequest(callPlan{Model: model, Temperature: &temp}); req.Temperature != 0 {
+			t.Errorf("%s: Temperature = %v, want none for a reasoning model", model, req.Temperature)
+		}
+	}
+}
+
+func TestOpenAIToCallResult_ThinkingTokens(t *testing.T) {
+	p := &openAIProvider{}
+	res := p.toCallResult(openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "4"}}},
+		Usage: openai.Usage{
+			PromptTokens:            10,
+			CompletionTokens:        50,
+			TotalTokens:             60,
+			CompletionTokensDetails: &openai.CompletionTokensDetails{ReasoningTokens: 42},
+		},
+	})
+	if res.ThinkingTokens == nil || *res.ThinkingTokens != 42 {
+		t.Errorf("expected 42 thinking tokens, got %v", res.ThinkingTokens)
+	}
+}
+
+func TestBuildPlans_InvalidReasoningEffort(t *testing.T) {
+	effort := "extreme"
+	if _, err := buildPlans(ProviderOpenAI, "o3", TextRequest{ReasoningEffort: &effort}, CoraConfig{}, nil); err == nil {
+		t.Error("expected error for invalid ReasoningEffort")
+	}
+}
+
+func TestOpenAIProvider_AzureDeploymentMapping(t *testing.T) {
+	var gotPath, gotVersion, gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotVersion = r.URL.Query().Get("api-version")
+		gotKey = r.Header.Get("api-key")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": "hi"}}},
+		})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{
+		OpenAIAPIKey:     "az-key",
+		OpenAIBaseURL:    srv.URL,
+		OpenAIAPIType:    "azure",
+		AzureDeployments: map[string]string{"gpt-4o": "prod-gpt4o"},
+	})
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-4o", Input: "x"}); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+
+	if gotPath != "/openai/deployments/prod-gpt4o/chat/completions" {
+		t.Errorf("expected deployment path, got %q", gotPath)
+	}
+	if gotVersion != defaultAzureAPIVersion {
+		t.Errorf("expected api-version %q, got %q", defaultAzureAPIVersion, gotVersion)
+	}
+	if gotKey != "az-key" {
+		t.Errorf("expected api-key header, got %q", gotKey)
+	}
+}
+
+func TestOpenAIClientConfig_AzureUnmappedModel(t *testing.T) {
+	oc := openAIClientConfig(CoraConfig{OpenAIAPIType: "azure", OpenAIBaseURL: "https://x.openai.azure.com", OpenAIAPIVersion: "2024-06-01"}, nil)
+	if oc.APIVersion != "2024-06-01" {
+		t.Errorf("expected configured API version, got %q", oc.APIVersion)
+	}
+	if got := oc.AzureModelMapperFunc("gpt-3.5-turbo"); got != "gpt-35-turbo" {
+		t.Errorf("expected default deployment mapping, got %q", got)
+	}
+}
+
+func TestOpenAIProvider_Documents(t *testing.T) {
+	_, err := (&openAIProvider{}).Text(context.Background(), callPlan{
+		Model:     "gpt-4o",
+		Input:     "Summarize",
+		Documents: []DocumentPart{{Data: []byte("%PDF"), MIMEType: "application/pdf"}},
+	})
+	if !errors.Is(err, ErrNotSupportedByProvider) {
+		t.Fatalf("expected ErrNotSupportedByProvider for a PDF, got %v", err)
+	}
+
+	req := openAIRequestFromPlan(callPlan{
+		Model:     "gpt-4o",
+		Input:     "Describe",
+		Documents: []DocumentPart{{Data: []byte{0x89, 'P', 'N', 'G'}, MIMEType: "image/png"}},
+	})
+	parts := req.Messages[len(req.Messages)-1].MultiContent
+	if len(parts) != 2 || parts[1].ImageURL == nil {
+		t.Fatalf("expected text + image parts, got %+v", parts)
+	}
+	if want := "data:image/png;base64,iVBORw=="; parts[1].ImageURL.URL != want {
+		t.Errorf("image URL = %q, want %q", parts[1].ImageURL.URL, want)
+	}
+}
+
+func TestOpenAIRequestFromPlan_MessageRoles(t *testing.T) {
+	req := openAIRequestFromPlan(callPlan{Model: "gpt-4o", Messages: []Message{
+		{Role: "user", Content: "Hi"},
+		{Role: "model", Content: "Hello"},
+	}})
+	if len(req.Messages) != 2 || req.Messages[1].Role != "assistant" {
+		t.Errorf("unexpected messages %+v", req.Messages)
+	}
+}
+
+func TestOpenAIProvider_MaxToolRounds(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{
+			{"message": toolCallMessage("call_1", "lookup", `{}`)},
+		}})
+	}))
+	defer srv.Close()
+
+	maxRounds := 1
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:      ProviderOpenAI,
+		Model:         "gpt-test",
+		Mode:          ModeToolCalling,
+		Input:         "loop forever",
+		Tools:         []CoraTool{{Name: "lookup", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers:  map[string]CoraToolHandler{"lookup": func(ctx context.Context, args map[string]any) (any, error) { return "again", nil }},
+		MaxToolRounds: &maxRounds,
+	})
+	if err == nil || !strings.Contains(err.Error(), "exceeded maximum tool call rounds (1)") {
+		t.Fatalf("expected max rounds error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the loop to stop before a second model call, got %d calls", calls)
+	}
+}
+
+func TestOpenAIProvider_MaxTotalToolCalls(t *testing.T) {
+	var bodies []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		msg := map[string]any{"role": "assistant", "content": "Paris is sunny and 20C."}
+		if body["tool_choice"] != "none" {
+			n := len(bodies)
+			msg = map[string]any{"role": "assistant", "tool_calls": []map[string]any{
+				{"id": fmt.Sprintf("call_%d_a", n), "type": "function", "function": map[string]any{"name": "lookup", "arguments": `{}`}},
+				{"id": fmt.Sprintf("call_%d_b", n), "type": "function", "function": map[string]any{"name": "lookup", "arguments": `{}`}},
+			}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{{"message": msg}}})
+	}))
+	defer srv.Close()
+
+	var executed int
+	maxCalls := 3
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Mode:     ModeToolCalling,
+		Input:    "Weather in Paris?",
+		Tools:    []CoraTool{{Name: "lookup", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{"lookup": func(ctx context.Context, args map[string]any) (any, error) {
+			executed++
+			return "data", nil
+		}},
+		MaxTotalToolCalls: &maxCalls,
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "Paris is sunny and 20C." {
+		t.Errorf("unexpected text %q", resp.Text)
+	}
+	if executed != 2 || len(bodies) != 3 {
+		t.Fatalf("expected 2 executed calls over 3 model calls, got %d calls and %d requests", executed, len(bodies))
+	}
+	msgs := bodies[2]["messages"].([]any)
+	last := msgs[len(msgs)-1].(map[string]any)
+	if last["role"] != "system" || !strings.Contains(last["content"].(string), "You have used 2/3 allowed tool calls") {
+		t.Errorf("expected the budget note as the last message, got %v", last)
+	}
+}
+
+func TestOpenAIProvider_NChoices(t *testing.T) {
+	var gotN int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			N int `json:"n"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotN = body.N
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{
+			{"index": 0, "message": map[string]any{"role": "assistant", "content": "a longer answer"}},
+			{"index": 1, "message": map[string]any{"role": "assistant", "content": "short"}},
+			{"index": 2, "message": map[string]any{"role": "assistant", "content": "medium one"}},
+		}})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	n := 3
+	resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi", N: &n})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotN != 3 {
+		t.Errorf("request n = %d, want 3", gotN)
+	}
+	if len(resp.Choices) != 3 || resp.Choices[2].Text != "medium one" {
+		t.Fatalf("expected 3 choices, got %+v", resp.Choices)
+	}
+	if resp.Text != "a longer answer" {
+		t.Errorf("Text = %q, want the first choice", resp.Text)
+	}
+
+	bestOf := 3
+	resp, err = c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi again", BestOf: &bestOf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text != "short" || resp.Choices != nil {
+		t.Errorf("BestOf: Text = %q, Choices = %v; want the shortest choice only", resp.Text, resp.Choices)
+	}
+
+	zero := 0
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi", N: &zero}); err == nil {
+		t.Error("expected an error for N = 0")
+	}
+}
+
+func TestOpenAIRequestFromPlan_StrictJSON(t *testing.T) {
+	schema := map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type":    "object",
+		"properties": map[string]any{
+			"city":    map[string]any{"$ref": "#/$defs/name"},
+			"default": map[string]any{"type": "string", "default": "x"},
+			"unit":    map[string]any{"type": "string", "enum": []any{"c", "f"}},
+			"where":   map[string]any{"$ref": "#/$defs/name"},
+			"note":    map[string]any{"description": "anything"},
+		},
+		"required": []any{"city", "default"},
+		"$defs":    map[string]any{"name": map[string]any{"type": "string"}},
+	}
+
+	req := openAIRequestFromPlan(callPlan{Model: "gpt-4o", Input: "hi", Structured: true, ResponseSchema: schema})
+	if req.ResponseFormat.JSONSchema.Strict {
+		t.Error("expected strict to be off when disabled")
+	}
+	if got := req.ResponseFormat.JSONSchema.Schema.(rawJSONSchema).m; !reflect.DeepEqual(got, schema) {
+		t.Errorf("schema without strict = %v, want it unchanged", got)
+	}
+
+	req = openAIRequestFromPlan(callPlan{Model: "gpt-4o", Input: "hi", Structured: true, StrictJSON: true, ResponseSchema: schema})
+	blob, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body struct {
+		ResponseFormat struct {
+			JSONSchema struct {
+				Strict bool           `json:"strict"`
+				Schema map[string]any `json:"schema"`
+			} `json:"json_schema"`
+		} `json:"response_format"`
+	}
+	if err := json.Unmarshal(blob, &body); err != nil {
+		t.Fatal(err)
+	}
+	if !body.ResponseFormat.JSONSchema.Strict {
+		t.Errorf("expected strict: true in %s", blob)
+	}
+	want := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"city":    map[string]any{"type": "string"},
+			"default": map[string]any{"type": "string"},
+			"unit":    map[string]any{"type": []any{"string", "null"}, "enum": []any{"c", "f", nil}},
+			"where":   map[string]any{"type": []any{"string", "null"}},
+			"note":    map[string]any{"anyOf": []any{map[string]any{"description": "anything"}, map[string]any{"type": "null"}}},
+		},
+		"required":             []any{"city", "default", "note", "unit", "where"},
+		"additionalProperties": false,
+	}
+	if !reflect.DeepEqual(body.ResponseFormat.JSONSchema.Schema, want) {
+		t.Errorf("normalized schema = %v, want %v", body.ResponseFormat.JSONSchema.Schema, want)
+	}
+	if _, ok := schema["$defs"]; !ok {
+		t.Error("normalizeSchemaForOpenAI modified the caller's schema")
+	}
+}
+
+func TestOpenAIProvider_SystemFingerprintMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"system_fingerprint":"fp_44709d6fcb","choices":[{"message":{"role":"assistant","content":"hi"}}]}`))
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if got := resp.Metadata["system_fingerprint"]; got != "fp_44709d6fcb" {
+		t.Errorf("Metadata[system_fingerprint] = %v, want fp_44709d6fcb", got)
+	}
+}
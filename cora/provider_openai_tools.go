@@ -2,6 +2,7 @@ package cora
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 
@@ -9,27 +10,43 @@ import (
 )
 
 // executeToolLoop handles multi-round tool calling for OpenAI.
-func (p *openAIProvider) executeToolLoop(ctx context.Context, req openai.ChatCompletionRequest, plan callPlan) (callResult, error) {
-	executor := NewToolExecutor(plan.ToolHandlers).
-		WithMaxRounds(5).
-		WithParallel(false).
-		WithStopOnError(true)
+func (p *openAIProvider) executeToolLoop(ctx context.Context, req openai.ChatCompletionRequest, plan callPlan) (_ callResult, err error) {
+	defer traceRegion(ctx, plan.trace, "cora.executeToolLoop")()
+	executor := plan.toolExecutor()
 
 	msgs := req.Messages
 	roundCount := 0
+	// toolRounds counts the rounds in which the model called tools; the
+	// others (the answer, agent continuation prompts) are not tool rounds.
+	toolRounds := 0
+	var trace []string
+	var graph *ToolCallGraph
+	if plan.RecordToolGraph {
+		graph = &ToolCallGraph{}
+	}
+
+	var lastCalls []string
+	// Terminal errors carry the state of the loop.
+	defer func() {
+		err = newToolLoopError(err, plan, roundCount, executor.maxRounds, lastCalls, len(msgs))
+	}()
 
+	var lastText string
 	for {
 		roundCount++
 		if roundCount > executor.maxRounds {
 			return callResult{}, fmt.Errorf("exceeded maximum tool call rounds (%d)", executor.maxRounds)
 		}
+		if roundCount > 1 && plan.DynamicSystemPrompt != nil {
+			if extra := plan.DynamicSystemPrompt(roundCount, lastText); extra != "" {
+				msgs = append(msgs, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: extra})
+			}
+		}
 
 		// Make API call
-		resp, err := p.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-			Model:    req.Model,
-			Messages: msgs,
-			Tools:    req.Tools,
-		})
+		roundReq := req
+		roundReq.Messages = msgs
+		resp, err := p.client.CreateChatCompletion(ctx, roundReq)
 		if err != nil {
 			return callResult{}, err
 		}
@@ -39,10 +56,44 @@ func (p *openAIProvider) executeToolLoop(ctx context.Context, req openai.ChatCom
 		}
 
 		choice := resp.Choices[0]
+		lastText = choice.Message.Content
+		if plan.ReAct {
+			trace = append(trace, parseReActTrace(choice.Message.Content)...)
+		}
 
 		// No tool calls, return final answer
 		if len(choice.Message.ToolCalls) == 0 {
-			return p.toCallResult(resp), nil
+			if plan.AgentStopSignal != "" {
+				if _, done := agentFinalAnswer(lastText, plan.AgentStopSignal); !done {
+					msgs = append(msgs, choice.Message, openai.ChatCompletionMessage{
+						Role:    openai.ChatMessageRoleUser,
+						Content: agentContinuePrompt(plan.AgentStopSignal),
+					})
+					continue
+				}
+			}
+			cr := p.toCallResult(resp)
+			cr.RawProviderResponse = rawResponse(plan, resp)
+			cr.ToolCallGraph = graph
+			cr.ToolRounds = toolRounds
+			if plan.ReAct {
+				cr.Text = reActFinalAnswer(cr.Text)
+				cr.ReasoningTrace = trace
+			}
+			if plan.AgentStopSignal != "" {
+				cr.Text, _ = agentFinalAnswer(cr.Text, plan.AgentStopSignal)
+				cr.AgentSteps = roundCount
+			}
+			return cr, nil
+		}
+
+		toolRounds++
+
+		// Over the call budget: skip this round's calls and ask for an answer.
+		if note, over := executor.callBudgetNote(len(choice.Message.ToolCalls)); over {
+			msgs = append(msgs, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: note})
+			req.ToolChoice = "none"
+			continue
 		}
 
 		// Append assistant message with tool calls
@@ -58,14 +109,32 @@ func (p *openAIProvider) executeToolLoop(ctx context.Context, req openai.ChatCom
 			calls[i] = toolCallRequest{name: tc.Function.Name, args: args}
 		}
 
-		results, err := executor.executeBatch(ctx, calls)
+		lastCalls = toolCallNames(calls)
+		results, err := executor.executeRound(ctx, roundCount, calls)
+		if graph != nil {
+			for i, r := range results {
+				graph.AddCall(roundCount, calls[i].name, executor.maskArgs(calls[i].name, calls[i].args), r.result, r.duration)
+			}
+		}
 		if err != nil {
 			return callResult{}, err
 		}
 
-		// Append tool results as messages
+		// Append tool results as messages. Images returned in a
+		// ToolResultPart follow all tool messages, which must come first.
+		var images []openai.ChatMessagePart
 		for i, result := range results {
-			resultJSON, _ := json.Marshal(result.result)
+			content := result.result
+			if part, ok := asToolResultPart(result.result); ok {
+				content = part.Text
+				if img := part.image(); img != nil {
+					images = append(images, openai.ChatMessagePart{
+						Type:     openai.ChatMessagePartTypeImageURL,
+						ImageURL: &openai.ChatMessageImageURL{URL: "data:" + img.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(img.Data)},
+					})
+				}
+			}
+			resultJSON, _ := json.Marshal(content)
 			msgs = append(msgs, openai.ChatCompletionMessage{
 				Role:       openai.ChatMessageRoleTool,
 				Content:    string(resultJSON),
@@ -73,5 +142,9 @@ func (p *openAIProvider) executeToolLoop(ctx context.Context, req openai.ChatCom
 				ToolCallID: choice.Message.ToolCalls[i].ID,
 			})
 		}
+		if len(images) > 0 {
+			parts := append([]openai.ChatMessagePart{{Type: openai.ChatMessagePartTypeText, Text: "Images returned by the tool calls above:"}}, images...)
+			msgs = append(msgs, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, MultiContent: parts})
+		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,88 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestCallPlanClone(t *testing.T) {
+	temp := float32(0.2)
+	plan := callPlan{
+		Messages:        []Message{{Role: "user", Content: "hi"}},
+		Temperature:     &temp,
+		Labels:          map[string]string{"team": "core"},
+		ResponseSchema:  map[string]any{"type": "object", "required": []any{"a"}},
+		Tools:           []CoraTool{{Name: "lookup", ParametersSchema: map[string]any{"properties": map[string]any{"q": map[string]any{"type": "string"}}}}},
+		ProviderOptions: map[string]any{"safety_settings": []any{map[string]any{"category": "x"}}},
+	}
+
+	c := plan.Clone()
+	c.Messages[0].Content = "changed"
+	*c.Temperature = 1
+	c.Labels["team"] = "other"
+	c.ResponseSchema["required"].([]any)[0] = "b"
+	c.Tools[0].Name = "other"
+	c.Tools[0].ParametersSchema["properties"].(map[string]any)["q"].(map[string]any)["type"] = "integer"
+	c.ProviderOptions["safety_settings"].([]any)[0].(map[string]any)["category"] = "y"
+
+	if plan.Messages[0].Content != "hi" || *plan.Temperature != 0.2 || plan.Labels["team"] != "core" {
+		t.Errorf("clone shares messages, options or labels: %+v", plan)
+	}
+	if plan.ResponseSchema["required"].([]any)[0] != "a" {
+		t.Error("clone shares the response schema")
+	}
+	if plan.Tools[0].Name != "lookup" || plan.Tools[0].ParametersSchema["properties"].(map[string]any)["q"].(map[string]any)["type"] != "string" {
+		t.Error("clone shares the tools")
+	}
+	if plan.ProviderOptions["safety_settings"].([]any)[0].(map[string]any)["category"] != "x" {
+		t.Error("clone shares the provider options")
+	}
+}
+
+// TestCallPlanClone_ConcurrentCallers shares one plan between concurrent
+// provider calls that each modify their copy; run with -race.
+func TestCallPlanClone_ConcurrentCallers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{
+			{"message": map[string]any{"role": "assistant", "content": "ok"}},
+		}})
+	}))
+	defer srv.Close()
+
+	pc, err := newOpenAIProvider(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared := callPlan{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Input:    "hi",
+		Tools:    []CoraTool{{Name: "lookup", ParametersSchema: map[string]any{"type": "object", "properties": map[string]any{}}}},
+		Labels:   map[string]string{"team": "core"},
+	}
+
+	var wg sync.WaitGroup
+	for i := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := pc.Text(context.Background(), shared); err != nil {
+				t.Errorf("Text error: %v", err)
+			}
+			own := shared.Clone()
+			own.Tools[0].ParametersSchema["properties"].(map[string]any)["n"] = i
+			own.Tools = append(own.Tools, CoraTool{Name: "extra"})
+			own.Labels["caller"] = "x"
+		}()
+	}
+	wg.Wait()
+
+	if len(shared.Tools) != 1 || len(shared.Tools[0].ParametersSchema["properties"].(map[string]any)) != 0 || len(shared.Labels) != 1 {
+		t.Errorf("shared plan was modified: %+v", shared)
+	}
+}
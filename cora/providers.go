@@ -0,0 +1,45 @@
+package cora
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// registeredProviders holds every concrete provider cora can call. Each
+// provider file registers itself from an init function.
+var registeredProviders = map[Provider]struct{}{}
+
+func registerProvider(p Provider) {
+	registeredProviders[p] = struct{}{}
+}
+
+// KnownProviders returns all registered providers, sorted by name.
+func KnownProviders() []Provider {
+	out := make([]Provider, 0, len(registeredProviders))
+	for p := range registeredProviders {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// String returns the provider name, e.g. "openai".
+func (p Provider) String() string {
+	return string(p)
+}
+
+// Valid reports whether p is a registered provider. ProviderAuto is a
+// routing sentinel, not a provider, and is therefore not valid here.
+func (p Provider) Valid() bool {
+	_, ok := registeredProviders[p]
+	return ok
+}
+
+func unknownProviderError(p Provider) error {
+	names := make([]string, 0, len(registeredProviders))
+	for _, kp := range KnownProviders() {
+		names = append(names, string(kp))
+	}
+	return fmt.Errorf("cora: unknown provider %q; known providers are: %s", p, strings.Join(names, ", "))
+}
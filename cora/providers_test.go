@@ -0,0 +1,51 @@
+package cora
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestKnownProviders(t *testing.T) {
+	want := []Provider{ProviderBedrock, ProviderCohere, ProviderGoogle, ProviderMistral, ProviderOpenAI}
+	if got := KnownProviders(); !reflect.DeepEqual(got, want) {
+		t.Errorf("KnownProviders() = %v, want %v", got, want)
+	}
+	for _, p := range want {
+		if !p.Valid() {
+			t.Errorf("expected %q to be valid", p)
+		}
+	}
+	if Provider("goggle").Valid() || ProviderAuto.Valid() {
+		t.Error("expected typo and ProviderAuto to be invalid")
+	}
+}
+
+func TestText_UnknownProviderListsValidOptions(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	_, err := c.Text(context.Background(), TextRequest{Provider: "goggle", Model: "m", Input: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "known providers are: bedrock, cohere, google, mistral, openai") {
+		t.Errorf("expected error listing valid providers, got %v", err)
+	}
+}
+
+func FuzzProviderValid(f *testing.F) {
+	for _, seed := range []string{"openai", "google", "goggle", "", "auto", "\x00"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		p := Provider(s)
+		if p.Valid() != slices.Contains(KnownProviders(), p) {
+			t.Errorf("Valid() disagrees with KnownProviders() for %q", s)
+		}
+	})
+}
+
+func TestProviderString(t *testing.T) {
+	if got := fmt.Sprint(ProviderGoogle); got != "google" {
+		t.Errorf("fmt.Sprint(ProviderGoogle) = %q", got)
+	}
+}
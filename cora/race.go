@@ -0,0 +1,55 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Race sends req to every provider in providers concurrently and returns the
+// first successful response, cancelling the remaining in-flight calls.
+// req.Model is used for req.Provider; other providers use their configured
+// default model. If req.RaceTimeout is set, it bounds the whole operation.
+// If every provider fails, the returned error joins all failures.
+func (c *Client) Race(ctx context.Context, req TextRequest, providers []Provider) (TextResponse, error) {
+	if len(providers) == 0 {
+		return TextResponse{}, errors.New("cora: Race requires at least one provider")
+	}
+
+	if req.RaceTimeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, req.RaceTimeout)
+		defer cancelTimeout()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type raceResult struct {
+		provider Provider
+		resp     TextResponse
+		err      error
+	}
+	results := make(chan raceResult, len(providers))
+
+	for _, p := range providers {
+		preq := req
+		if p != req.Provider {
+			preq.Model = ""
+		}
+		preq.Provider = p
+		go func() {
+			resp, err := c.Text(ctx, preq)
+			results <- raceResult{provider: preq.Provider, resp: resp, err: err}
+		}()
+	}
+
+	var errs []error
+	for range providers {
+		r := <-results
+		if r.err == nil {
+			return r.resp, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", r.provider, r.err))
+	}
+	return TextResponse{}, fmt.Errorf("cora: all providers failed: %w", errors.Join(errs...))
+}
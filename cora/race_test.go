@@ -0,0 +1,85 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// delayedProvider answers with text after delay, or fails with err.
+type delayedProvider struct {
+	delay time.Duration
+	text  string
+	err   error
+}
+
+func (d *delayedProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	select {
+	case <-ctx.Done():
+		return callResult{}, ctx.Err()
+	case <-time.After(d.delay):
+	}
+	if d.err != nil {
+		return callResult{}, d.err
+	}
+	return callResult{Text: d.text}, nil
+}
+
+func TestRace_FastestWins(t *testing.T) {
+	c := &Client{cfg: CoraConfig{DefaultModelGoogle: "gemini-test"}}
+	c.openai = &delayedProvider{delay: 500 * time.Millisecond, text: "slow"}
+	c.google = &delayedProvider{delay: 5 * time.Millisecond, text: "fast"}
+
+	start := time.Now()
+	resp, err := c.Race(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Input:    "hi",
+		Mode:     ModeBasic,
+	}, []Provider{ProviderOpenAI, ProviderGoogle})
+	if err != nil {
+		t.Fatalf("Race error: %v", err)
+	}
+	if resp.Provider != ProviderGoogle || resp.Text != "fast" {
+		t.Errorf("expected google to win with %q, got %s %q", "fast", resp.Provider, resp.Text)
+	}
+	if resp.Model != "gemini-test" {
+		t.Errorf("expected default google model, got %q", resp.Model)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("Race waited for the slow provider: %v", elapsed)
+	}
+}
+
+func TestRace_AllFail(t *testing.T) {
+	c := &Client{cfg: CoraConfig{DefaultModelGoogle: "gemini-test"}}
+	errOpenAI := errors.New("openai down")
+	errGoogle := errors.New("google down")
+	c.openai = &delayedProvider{err: errOpenAI}
+	c.google = &delayedProvider{err: errGoogle}
+
+	_, err := c.Race(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Input:    "hi",
+	}, []Provider{ProviderOpenAI, ProviderGoogle})
+	if !errors.Is(err, errOpenAI) || !errors.Is(err, errGoogle) {
+		t.Errorf("expected combined error with both failures, got %v", err)
+	}
+}
+
+func TestRace_Timeout(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = &delayedProvider{delay: time.Second, text: "late"}
+
+	_, err := c.Race(context.Background(), TextRequest{
+		Provider:    ProviderOpenAI,
+		Model:       "gpt-test",
+		Input:       "hi",
+		RaceTimeout: 20 * time.Millisecond,
+	}, []Provider{ProviderOpenAI})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package cora
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimit caps the calls Client.Text makes to one provider (see
+// CoraConfig.RateLimits). Zero fields are unlimited.
+type RateLimit struct {
+	// RequestsPerMinute spaces calls evenly, one every minute/RequestsPerMinute.
+	RequestsPerMinute int `json:"requests_per_minute" yaml:"requests_per_minute"`
+	// TokensPerMinute limits the estimated prompt tokens sent per minute. Up
+	// to a minute's worth may be sent at once.
+	TokensPerMinute int `json:"tokens_per_minute" yaml:"tokens_per_minute"`
+}
+
+// providerLimiter is the token buckets of one provider's RateLimit.
+type providerLimiter struct {
+	requests *rate.Limiter
+	tokens   *rate.Limiter
+}
+
+func newProviderLimiter(rl RateLimit) *providerLimiter {
+	l := &providerLimiter{}
+	if rl.RequestsPerMinute > 0 {
+		l.requests = rate.NewLimiter(rate.Every(time.Minute/time.Duration(rl.RequestsPerMinute)), 1)
+	}
+	if rl.TokensPerMinute > 0 {
+		l.tokens = rate.NewLimiter(rate.Limit(float64(rl.TokensPerMinute)/60), rl.TokensPerMinute)
+	}
+	return l
+}
+
+// waitRateLimit blocks until plan's provider may be called under
+// CoraConfig.RateLimits. It fails without waiting when ctx's deadline would
+// pass first.
+func (c *Client) waitRateLimit(ctx context.Context, plan callPlan) error {
+	l := c.rateLimiter(plan.Provider)
+	if l == nil {
+		return nil
+	}
+	if l.requests != nil {
+		if err := l.requests.Wait(ctx); err != nil {
+			return fmt.Errorf("cora: waiting for %s rate limit: %w", plan.Provider, err)
+		}
+	}
+	if l.tokens != nil {
+		// A prompt larger than the bucket waits for a full bucket.
+		n := min(estimatePlanTokens(plan), l.tokens.Burst())
+		if err := l.tokens.WaitN(ctx, n); err != nil {
+			return fmt.Errorf("cora: waiting for %s rate limit: %w", plan.Provider, err)
+		}
+	}
+	return nil
+}
+
+// rateLimiter returns the limiter for provider p, creating it on first use,
+// or nil when p has no rate limit.
+func (c *Client) rateLimiter(p Provider) *providerLimiter {
+	rl, ok := c.cfg.RateLimits[p]
+	if !ok || (rl.RequestsPerMinute <= 0 && rl.TokensPerMinute <= 0) {
+		return nil
+	}
+	c.limitersMu.Lock()
+	defer c.limitersMu.Unlock()
+	if c.limiters == nil {
+		c.limiters = make(map[Provider]*providerLimiter)
+	}
+	l, ok := c.limiters[p]
+	if !ok {
+		l = newProviderLimiter(rl)
+		c.limiters[p] = l
+	}
+	return l
+}
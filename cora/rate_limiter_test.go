@@ -0,0 +1,76 @@
+package cora
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimits_SpreadsRequests(t *testing.T) {
+	fake := &fakeProvider{}
+	c := &Client{
+		cfg:    CoraConfig{RateLimits: map[Provider]RateLimit{ProviderOpenAI: {RequestsPerMinute: 1200}}},
+		openai: fake,
+	}
+
+	// 1200 per minute is one call every 50ms: 5 calls take at least 200ms.
+	const calls = 5
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "m", Input: "hi"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < (calls-1)*50*time.Millisecond {
+		t.Errorf("%d calls finished in %v, want at least %v", calls, elapsed, (calls-1)*50*time.Millisecond)
+	}
+	if got := len(fake.ReceivedPlans()); got != calls {
+		t.Errorf("provider received %d calls, want %d", got, calls)
+	}
+}
+
+func TestRateLimits_ContextDeadline(t *testing.T) {
+	c := &Client{
+		cfg:    CoraConfig{RateLimits: map[Provider]RateLimit{ProviderOpenAI: {RequestsPerMinute: 1}}},
+		openai: &fakeProvider{},
+	}
+	req := TextRequest{Provider: ProviderOpenAI, Model: "m", Input: "hi"}
+	if _, err := c.Text(context.Background(), req); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+
+	// The next slot is a minute away, past the deadline: fail fast.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.Text(ctx, req)
+	if err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Fatalf("expected a rate limit error, got %v", err)
+	}
+	if time.Since(start) > 50*time.Millisecond {
+		t.Errorf("expected the call to fail without waiting")
+	}
+}
+
+func TestRateLimits_Tokens(t *testing.T) {
+	c := &Client{cfg: CoraConfig{RateLimits: map[Provider]RateLimit{ProviderOpenAI: {TokensPerMinute: 10}}}}
+	l := c.rateLimiter(ProviderOpenAI)
+	if l == nil || l.requests != nil || l.tokens == nil {
+		t.Fatalf("unexpected limiter %+v", l)
+	}
+	plan := callPlan{Provider: ProviderOpenAI, Input: strings.Repeat("word ", 8)} // ~10 tokens
+	if err := c.waitRateLimit(context.Background(), plan); err != nil {
+		t.Fatal(err)
+	}
+	if tokens := l.tokens.Tokens(); tokens > 1 {
+		t.Errorf("expected the bucket to be drained, %g tokens left", tokens)
+	}
+}
@@ -0,0 +1,92 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIncludeRawResponse_OpenAI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","model":"gpt-test","choices":[{"finish_reason":"stop","message":{"role":"assistant","content":"hi"}}],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`))
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	req := TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"}
+	resp, err := c.Text(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.RawProviderResponse != nil {
+		t.Errorf("expected no raw response without IncludeRawResponse, got %s", resp.RawProviderResponse)
+	}
+
+	req.IncludeRawResponse = true
+	resp, err = c.Text(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	var raw struct {
+		ID      string `json:"id"`
+		Choices []struct {
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+		Usage struct {
+			TotalTokens int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(resp.RawProviderResponse, &raw); err != nil {
+		t.Fatalf("RawProviderResponse %q: %v", resp.RawProviderResponse, err)
+	}
+	if raw.ID != "chatcmpl-1" || len(raw.Choices) != 1 || raw.Choices[0].FinishReason != "stop" || raw.Usage.TotalTokens != 4 {
+		t.Errorf("unexpected raw response %s", resp.RawProviderResponse)
+	}
+}
+
+func TestIncludeRawResponse_GoogleToolLoop(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		parts := []map[string]any{{"text": "done"}}
+		if calls == 1 {
+			parts = []map[string]any{{"functionCall": map[string]any{"name": "lookup", "args": map[string]any{}}}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates":    []map[string]any{{"content": map[string]any{"role": "model", "parts": parts}, "finishReason": "STOP"}},
+			"modelVersion":  "gemini-test-001",
+			"usageMetadata": map[string]any{"promptTokenCount": 5, "candidatesTokenCount": 1, "totalTokenCount": 6},
+		})
+	}))
+	defer srv.Close()
+
+	tools, handlers := lookupTool()
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderGoogle, Model: "gemini-test", Input: "Look it up.", Mode: ModeToolCalling,
+		Tools: tools, ToolHandlers: handlers, IncludeRawResponse: true,
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	var raw struct {
+		ModelVersion string `json:"modelVersion"`
+		Candidates   []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+	}
+	if err := json.Unmarshal(resp.RawProviderResponse, &raw); err != nil {
+		t.Fatalf("RawProviderResponse %q: %v", resp.RawProviderResponse, err)
+	}
+	if raw.ModelVersion != "gemini-test-001" || len(raw.Candidates) != 1 || raw.Candidates[0].Content.Parts[0].Text != "done" {
+		t.Errorf("expected the final response, got %s", resp.RawProviderResponse)
+	}
+}
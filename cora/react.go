@@ -0,0 +1,41 @@
+package cora
+
+import "strings"
+
+const reActInstructions = `Solve the task by interleaving reasoning and tool use in the ReAct format.
+For each step, write:
+Thought: your reasoning about what to do next
+Action: the tool you will call and why
+Then call the tool. Its result is returned to you as the Observation.
+Repeat Thought/Action/Observation until you can answer, then reply with:
+Final Answer: your answer to the user`
+
+// reActSystemPrompt prepends the ReAct instructions to the user's system prompt.
+func reActSystemPrompt(system string) string {
+	if strings.TrimSpace(system) == "" {
+		return reActInstructions
+	}
+	return reActInstructions + "\n\n" + system
+}
+
+// parseReActTrace extracts the Thought: and Action: lines from a model turn.
+func parseReActTrace(text string) []string {
+	var trace []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Thought:") || strings.HasPrefix(line, "Action:") {
+			trace = append(trace, line)
+		}
+	}
+	return trace
+}
+
+// reActFinalAnswer returns the text after "Final Answer:", or text unchanged
+// if the model did not use the marker.
+func reActFinalAnswer(text string) string {
+	const marker = "Final Answer:"
+	if i := strings.LastIndex(text, marker); i >= 0 {
+		return strings.TrimSpace(text[i+len(marker):])
+	}
+	return text
+}
@@ -0,0 +1,51 @@
+package cora
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildPlans_ReActSystemPrompt(t *testing.T) {
+	req := TextRequest{
+		Mode:   ModeReAct,
+		System: "Be concise.",
+		Tools:  []CoraTool{{Name: "search", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{
+			"search": func(ctx context.Context, args map[string]any) (any, error) { return "ok", nil },
+		},
+	}
+	plans, err := buildPlans(ProviderOpenAI, "gpt-test", req, CoraConfig{}, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	if len(plans) != 1 {
+		t.Fatalf("expected 1 plan, got %d", len(plans))
+	}
+	plan := plans[0]
+	for _, kw := range []string{"Thought:", "Action:", "Observation", "Final Answer:", "Be concise."} {
+		if !strings.Contains(plan.System, kw) {
+			t.Errorf("expected ReAct system prompt to contain %q", kw)
+		}
+	}
+	if !plan.ReAct || len(plan.Tools) != 1 || len(plan.ToolHandlers) != 1 {
+		t.Errorf("expected tool-calling plan with ReAct enabled, got %+v", plan)
+	}
+
+	if _, err := buildPlans(ProviderOpenAI, "gpt-test", TextRequest{Mode: ModeReAct}, CoraConfig{}, nil); err == nil {
+		t.Error("expected error for ModeReAct without tools")
+	}
+}
+
+func TestParseReActTrace(t *testing.T) {
+	text := "Thought: I need the weather.\nAction: call get_weather for Paris\nsome other text"
+	want := []string{"Thought: I need the weather.", "Action: call get_weather for Paris"}
+	if got := parseReActTrace(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseReActTrace = %q, want %q", got, want)
+	}
+
+	if got := reActFinalAnswer("Thought: done.\nFinal Answer: It is sunny."); got != "It is sunny." {
+		t.Errorf("reActFinalAnswer = %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package cora
+
+import (
+	"errors"
+	"slices"
+)
+
+// requestKey identifies a request for the response cache and request
+// deduplication: two requests with the same key send the same prompt with
+// the same options, so they may share a response. It is derived from the
+// request's built call plans, so every field that shapes the prompt (system
+// prompt extensions, chunk position, schema, grounding...) is covered,
+// plus the request fields that shape the response after the call.
+//
+// It fails for requests whose plans or response depend on functions, such
+// as tool calls and SelectCandidate, which cannot be compared.
+func (c *Client) requestKey(req TextRequest) (string, error) {
+	if req.Mode.usesTools() || req.SelectCandidate != nil {
+		return "", errors.New("cora: request cannot be keyed")
+	}
+	model, err := c.resolveModel(req)
+	if err != nil {
+		return "", err
+	}
+	plans, err := buildPlans(req.Provider, model, req, c.cfg, c.promptOptimizer)
+	if err != nil {
+		return "", err
+	}
+	keys := make([]planKey, len(plans))
+	for i, p := range plans {
+		keys[i] = planKey{callPlan: p}
+	}
+	return hashKey(struct {
+		Mode   TextMode
+		N      *int
+		BestOf *int
+		Plans  []planKey
+	}{req.Mode, req.N, req.BestOf, keys})
+}
+
+// planKey is the JSON form of a callPlan in requestKey. Its fields shadow
+// the plan's functions, which cannot be marshaled, and the client-level tool
+// settings, which do not reach the provider; they are always nil.
+type planKey struct {
+	callPlan
+	ToolHandlers        *struct{} `json:",omitempty"`
+	StreamingHandlers   *struct{} `json:",omitempty"`
+	ToolObserver        *struct{} `json:",omitempty"`
+	DynamicSystemPrompt *struct{} `json:",omitempty"`
+	ToolCacheTTL        *struct{} `json:",omitempty"`
+	ToolCacheMaxSize    *struct{} `json:",omitempty"`
+	ToolRetryConfig     *struct{} `json:",omitempty"`
+}
+
+// cloneTextResponse copies resp's JSON so that callers sharing a cached or
+// deduplicated response cannot modify each other's.
+func cloneTextResponse(resp TextResponse) TextResponse {
+	resp.JSON = cloneJSONMap(resp.JSON)
+	if resp.Choices != nil {
+		resp.Choices = slices.Clone(resp.Choices)
+		for i := range resp.Choices {
+			resp.Choices[i] = cloneTextResponse(resp.Choices[i])
+		}
+	}
+	return resp
+}
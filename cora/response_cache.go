@@ -0,0 +1,19 @@
+package cora
+
+// responseCacheKey returns the cache key for req and whether the request may
+// be served from (and stored in) the client's response cache. Tool-calling
+// requests and sampled requests (Temperature > 0) are not deterministic and
+// therefore bypass the cache.
+func (c *Client) responseCacheKey(req TextRequest) (string, bool) {
+	if c.responses == nil {
+		return "", false
+	}
+	if req.Temperature != nil && *req.Temperature > 0 {
+		return "", false
+	}
+	key, err := c.requestKey(req)
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
@@ -0,0 +1,85 @@
+package cora
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingProvider counts calls and echoes the input back.
+type countingProvider struct {
+	calls atomic.Int32
+}
+
+func (cp *countingProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
+	cp.calls.Add(1)
+	return callResult{Text: "echo: " + plan.Input}, nil
+}
+
+func TestText_ResponseCache_Hit(t *testing.T) {
+	cp := &countingProvider{}
+	c := New(CoraConfig{ResponseCacheTTL: time.Minute, ResponseCacheMaxSize: 10})
+	c.openai = cp
+
+	req := TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Input:    "What are your opening hours?",
+		Mode:     ModeBasic,
+	}
+
+	first, err := c.Text(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	second, err := c.Text(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+
+	if n := cp.calls.Load(); n != 1 {
+		t.Errorf("expected provider to be called once, got %d", n)
+	}
+	if first.Text != second.Text {
+		t.Errorf("expected cached text %q, got %q", first.Text, second.Text)
+	}
+	if hits, _ := c.responses.Stats(); hits != 1 {
+		t.Errorf("expected 1 cache hit, got %d", hits)
+	}
+}
+
+func TestText_ResponseCache_Bypass(t *testing.T) {
+	temp := float32(0.7)
+	testCases := []struct {
+		name string
+		req  TextRequest
+	}{
+		{"temperature above zero", TextRequest{Mode: ModeBasic, Temperature: &temp}},
+		{"tool calling", TextRequest{
+			Mode:  ModeToolCalling,
+			Tools: []CoraTool{{Name: "noop"}},
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cp := &countingProvider{}
+			c := New(CoraConfig{ResponseCacheTTL: time.Minute, ResponseCacheMaxSize: 10})
+			c.openai = cp
+
+			tc.req.Provider = ProviderOpenAI
+			tc.req.Model = "gpt-test"
+			tc.req.Input = "hello"
+			for i := 0; i < 2; i++ {
+				if _, err := c.Text(context.Background(), tc.req); err != nil {
+					t.Fatalf("Text error: %v", err)
+				}
+			}
+
+			if n := cp.calls.Load(); n != 2 {
+				t.Errorf("expected cache to be bypassed, provider called %d times", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRoundSystemPrompts_Google(t *testing.T) {
+	var systems []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			SystemInstruction *struct {
+				Parts []struct{ Text string } `json:"parts"`
+			} `json:"systemInstruction"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		system := ""
+		if body.SystemInstruction != nil && len(body.SystemInstruction.Parts) > 0 {
+			system = body.SystemInstruction.Parts[0].Text
+		}
+		systems = append(systems, system)
+		parts := []map[string]any{{"text": "done"}}
+		if len(systems) < 3 {
+			parts = []map[string]any{{"functionCall": map[string]any{"name": "lookup", "args": map[string]any{}}}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": parts}}},
+		})
+	}))
+	defer srv.Close()
+
+	tools, handlers := lookupTool()
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:     ProviderGoogle,
+		Model:        "gemini-test",
+		Input:        "Look it up twice.",
+		System:       "Be brief.",
+		Mode:         ModeToolCalling,
+		Tools:        tools,
+		ToolHandlers: handlers,
+		RoundSystemPrompts: map[int]string{
+			0: "Use metric units.",
+			2: "Format the final answer as a table.",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	want := []string{
+		"Be brief.\n\nUse metric units.",
+		"Be brief.\n\nUse metric units.",
+		"Be brief.\n\nUse metric units.\n\nFormat the final answer as a table.",
+	}
+	if len(systems) != len(want) {
+		t.Fatalf("system instructions = %q", systems)
+	}
+	for i := range want {
+		if systems[i] != want[i] {
+			t.Errorf("call %d: system instruction = %q, want %q", i+1, systems[i], want[i])
+		}
+	}
+}
+
+func TestRoundSystemPrompts_OpenAIUnsupported(t *testing.T) {
+	tools, handlers := lookupTool()
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test"})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi", Mode: ModeToolCalling,
+		Tools: tools, ToolHandlers: handlers, RoundSystemPrompts: map[int]string{1: "Answer now."},
+	})
+	if !errors.Is(err, ErrNotSupportedByProvider) {
+		t.Errorf("err = %v, want ErrNotSupportedByProvider", err)
+	}
+}
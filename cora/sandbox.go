@@ -0,0 +1,142 @@
+package cora
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultSandboxMaxOutputBytes is ProcessSandbox.MaxOutputBytes when unset.
+const defaultSandboxMaxOutputBytes = 1 << 20
+
+// ProcessSandbox runs a tool as a separate process: the call's arguments
+// are written to its stdin as a JSON object and its stdout, parsed as JSON,
+// is the tool result. A crash or runaway loop stays out of the calling
+// process, and the limits below bound its time, memory, CPU and output.
+//
+// It is process isolation only: the process runs as the calling user with
+// its filesystem and network access. Run untrusted code in a container or
+// similar (set Command to e.g. "docker") rather than relying on it alone.
+type ProcessSandbox struct {
+	Command string
+	Args    []string
+	// Timeout kills the process when it runs longer (default: no limit
+	// beyond the call's context).
+	Timeout time.Duration
+
+	// MaxMemoryMB caps the process's address space (RLIMIT_AS).
+	// Linux only; 0 means no limit.
+	MaxMemoryMB int
+	// MaxCPUSeconds caps the CPU time the process may use (RLIMIT_CPU);
+	// it is killed when it exceeds it. Linux only; 0 means no limit. It
+	// stands in for a CPU percentage: rlimits can only bound total CPU
+	// seconds, and throttling to a share of a CPU needs cgroups.
+	//
+	// With either limit set, the process is started through /bin/sh, which
+	// applies them with ulimit and then execs Command: Go cannot call
+	// setrlimit in the child between fork and exec, and calling it in the
+	// parent would limit the calling process.
+	MaxCPUSeconds int
+	// MaxOutputBytes caps the stdout the process may write; the call fails
+	// when it writes more. 0 means 1 MiB. Stderr, used only in error
+	// messages, is capped to the same size.
+	MaxOutputBytes int
+}
+
+// AddSandboxedFunc registers a tool whose handler runs in sandbox. schema
+// is the tool's JSON parameters schema.
+func (tb *ToolBuilder) AddSandboxedFunc(name, description string, schema map[string]any, sandbox ProcessSandbox) error {
+	if sandbox.Command == "" {
+		return fmt.Errorf("cora: sandboxed tool %s has no command", name)
+	}
+	if sandbox.MaxMemoryMB < 0 || sandbox.MaxCPUSeconds < 0 || sandbox.MaxOutputBytes < 0 {
+		return fmt.Errorf("cora: sandboxed tool %s: resource limits must not be negative", name)
+	}
+	if (sandbox.MaxMemoryMB > 0 || sandbox.MaxCPUSeconds > 0) && !sandboxLimitsSupported {
+		return fmt.Errorf("cora: sandboxed tool %s: resource limits are only supported on Linux", name)
+	}
+
+	tb.tools = append(tb.tools, CoraTool{
+		Name:             name,
+		Description:      description,
+		ParametersSchema: schema,
+	})
+	tb.handlers[name] = sandbox.handler()
+	return nil
+}
+
+func (s ProcessSandbox) handler() CoraToolHandler {
+	return func(ctx context.Context, args map[string]any) (any, error) {
+		if args == nil {
+			args = map[string]any{}
+		}
+		input, err := json.Marshal(args)
+		if err != nil {
+			return nil, fmt.Errorf("cora: encoding sandbox input: %w", err)
+		}
+		if s.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+			defer cancel()
+		}
+
+		limit := s.MaxOutputBytes
+		if limit == 0 {
+			limit = defaultSandboxMaxOutputBytes
+		}
+		stdout, stderr := &cappedBuffer{limit: limit}, &cappedBuffer{limit: limit}
+		cmd := s.command(ctx)
+		cmd.Stdin = bytes.NewReader(input)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("cora: starting sandbox %s: %w", s.Command, err)
+		}
+		if err := cmd.Wait(); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, fmt.Errorf("cora: sandbox %s: %w", s.Command, ctxErr)
+			}
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return nil, fmt.Errorf("cora: sandbox %s: %w: %s", s.Command, err, msg)
+			}
+			return nil, fmt.Errorf("cora: sandbox %s: %w", s.Command, err)
+		}
+
+		if stdout.truncated {
+			return nil, fmt.Errorf("cora: sandbox %s wrote more than %d bytes", s.Command, limit)
+		}
+		out := bytes.TrimSpace(stdout.Bytes())
+		if len(out) == 0 {
+			return nil, nil
+		}
+		var result any
+		if err := json.Unmarshal(out, &result); err != nil {
+			return nil, fmt.Errorf("cora: sandbox %s wrote invalid JSON: %w", s.Command, err)
+		}
+		return result, nil
+	}
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the
+// rest, so a process writing too much neither grows memory nor blocks on a
+// full pipe.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		b.buf.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) Bytes() []byte  { return b.buf.Bytes() }
+func (b *cappedBuffer) String() string { return b.buf.String() }
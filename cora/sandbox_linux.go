@@ -0,0 +1,30 @@
+package cora
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+const sandboxLimitsSupported = true
+
+// command returns the sandbox's command. With resource limits, it runs
+// under /bin/sh, which sets the limits with ulimit and then execs the
+// command, so the limits are in place before any of the command's code runs.
+// (Go cannot run code between fork and exec, and setting them with prlimit
+// after start would leave the process unlimited until then.)
+func (s ProcessSandbox) command(ctx context.Context) *exec.Cmd {
+	if s.MaxMemoryMB <= 0 && s.MaxCPUSeconds <= 0 {
+		return exec.CommandContext(ctx, s.Command, s.Args...)
+	}
+	script := ""
+	if s.MaxMemoryMB > 0 {
+		// ulimit -v (RLIMIT_AS) is in KiB.
+		script += fmt.Sprintf("ulimit -v %d && ", s.MaxMemoryMB<<10)
+	}
+	if s.MaxCPUSeconds > 0 {
+		script += fmt.Sprintf("ulimit -t %d && ", s.MaxCPUSeconds)
+	}
+	script += `exec "$0" "$@"`
+	return exec.CommandContext(ctx, "/bin/sh", append([]string{"-c", script, s.Command}, s.Args...)...)
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package cora
+
+import (
+	"context"
+	"os/exec"
+)
+
+const sandboxLimitsSupported = false
+
+// command returns the sandbox's command. AddSandboxedFunc rejects resource
+// limits off Linux.
+func (s ProcessSandbox) command(ctx context.Context) *exec.Cmd {
+	return exec.CommandContext(ctx, s.Command, s.Args...)
+}
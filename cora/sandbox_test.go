@@ -0,0 +1,99 @@
+package cora
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddSandboxedFunc(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs /bin/echo")
+	}
+	tb := NewToolBuilder()
+	schema := map[string]any{"type": "object", "properties": map[string]any{"code": map[string]any{"type": "string"}}}
+	err := tb.AddSandboxedFunc("run", "Run code", schema, ProcessSandbox{
+		Command: "/bin/echo",
+		Args:    []string{`{"stdout":"hi","exit_code":0}`},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("AddSandboxedFunc error: %v", err)
+	}
+	tools, handlers := tb.Build()
+	if len(tools) != 1 || tools[0].Name != "run" {
+		t.Fatalf("unexpected tools %+v", tools)
+	}
+	result, err := handlers["run"](context.Background(), map[string]any{"code": "print('hi')"})
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	m, _ := result.(map[string]any)
+	if m["stdout"] != "hi" || m["exit_code"] != float64(0) {
+		t.Errorf("unexpected result %#v", result)
+	}
+
+	// Output that is not JSON is an error.
+	_ = tb.AddSandboxedFunc("plain", "Plain text", nil, ProcessSandbox{Command: "/bin/echo", Args: []string{"not json"}})
+	_, handlers = tb.Build()
+	if _, err := handlers["plain"](context.Background(), nil); err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+		t.Errorf("expected invalid JSON error, got %v", err)
+	}
+}
+
+func TestAddSandboxedFunc_Limits(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("resource limits are Linux only")
+	}
+	tb := NewToolBuilder()
+	if err := tb.AddSandboxedFunc("cpu", "", nil, ProcessSandbox{Command: "/bin/echo", MaxCPUSeconds: -1}); err == nil {
+		t.Error("expected an error for a negative MaxCPUSeconds")
+	}
+	err := tb.AddSandboxedFunc("limited", "", nil, ProcessSandbox{
+		Command:       "/bin/echo",
+		Args:          []string{`"ok"`},
+		Timeout:       2 * time.Second,
+		MaxMemoryMB:   256,
+		MaxCPUSeconds: 1,
+	})
+	if err != nil {
+		t.Fatalf("AddSandboxedFunc error: %v", err)
+	}
+	// The limits are in place when the command starts.
+	err = tb.AddSandboxedFunc("limits", "", nil, ProcessSandbox{
+		Command:       "/bin/sh",
+		Args:          []string{"-c", `echo "\"$(ulimit -v) $(ulimit -t)\""`},
+		MaxMemoryMB:   256,
+		MaxCPUSeconds: 3,
+	})
+	if err != nil {
+		t.Fatalf("AddSandboxedFunc error: %v", err)
+	}
+	_, handlers := tb.Build()
+	if result, err := handlers["limited"](context.Background(), nil); err != nil || result != "ok" {
+		t.Errorf("got %v, %v", result, err)
+	}
+	if result, err := handlers["limits"](context.Background(), nil); err != nil || result != "262144 3" {
+		t.Errorf("limits seen by the command = %v, %v; want 262144 3", result, err)
+	}
+}
+
+func TestAddSandboxedFunc_MaxOutputBytes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs /bin/echo")
+	}
+	tb := NewToolBuilder()
+	if err := tb.AddSandboxedFunc("big", "", nil, ProcessSandbox{
+		Command:        "/bin/echo",
+		Args:           []string{`"more than sixteen bytes"`},
+		MaxOutputBytes: 16,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	_, handlers := tb.Build()
+	if _, err := handlers["big"](context.Background(), nil); err == nil || !strings.Contains(err.Error(), "more than 16 bytes") {
+		t.Errorf("expected an output limit error, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package cora
+
+import (
+	"regexp"
+	"strings"
+)
+
+// InputSanitizer neutralizes prompt injection attempts in untrusted input
+// (see TextRequest.SanitizeInput).
+type InputSanitizer interface {
+	Sanitize(input string) string
+}
+
+// injectionDelimiters match the role and instruction markers of common chat
+// templates, which injected text uses to pose as system instructions.
+var injectionDelimiters = regexp.MustCompile(`(?i)\[/?INST\]|<<\s*/?\s*SYS\s*>>|<\|[^|>]*\|>|</?\s*(system|assistant|user|user_input|instructions?)\s*>|#{3,}`)
+
+// DefaultInputSanitizer removes chat template delimiters such as [INST],
+// <<SYS>>, <|im_start|>, <system> and ### from the input and wraps the
+// result in <user_input> tags, so that the model can tell it apart from
+// the instructions.
+type DefaultInputSanitizer struct{}
+
+// Sanitize returns input with delimiters removed, wrapped in <user_input> tags.
+func (DefaultInputSanitizer) Sanitize(input string) string {
+	cleaned := injectionDelimiters.ReplaceAllString(input, "")
+	return "<user_input>" + strings.TrimSpace(cleaned) + "</user_input>"
+}
+
+// inputSanitizer returns cfg.InputSanitizer, or DefaultInputSanitizer.
+func inputSanitizer(cfg CoraConfig) InputSanitizer {
+	if cfg.InputSanitizer != nil {
+		return cfg.InputSanitizer
+	}
+	return DefaultInputSanitizer{}
+}
@@ -0,0 +1,62 @@
+package cora
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Default ModeScore scale used when TextRequest.ScoreMin or ScoreMax is nil.
+const (
+	defaultScoreMin = 1
+	defaultScoreMax = 10
+)
+
+// scoreRange returns the request's score scale with defaults applied.
+func scoreRange(req TextRequest) (lo, hi int) {
+	lo, hi = defaultScoreMin, defaultScoreMax
+	if req.ScoreMin != nil {
+		lo = *req.ScoreMin
+	}
+	if req.ScoreMax != nil {
+		hi = *req.ScoreMax
+	}
+	return lo, hi
+}
+
+// validateScoreRequest checks the ModeScore fields of req.
+func validateScoreRequest(req TextRequest) error {
+	if strings.TrimSpace(req.ScoreRubric) == "" {
+		return errors.New("cora: ScoreRubric must be provided for ModeScore")
+	}
+	if lo, hi := scoreRange(req); lo >= hi {
+		return fmt.Errorf("cora: ScoreMin (%d) must be less than ScoreMax (%d)", lo, hi)
+	}
+	return nil
+}
+
+// scoreSystemPrompt prepends the scoring instructions to the user's system prompt.
+func scoreSystemPrompt(system, rubric string, lo, hi int) string {
+	prompt := fmt.Sprintf(`Score the user's text against the rubric below on a scale from %d to %d, where %d is worst and %d is best.
+Reply with a JSON object {"score": <number from %d to %d>, "rationale": "<brief justification>"}.
+
+Rubric:
+%s`, lo, hi, lo, hi, lo, hi, rubric)
+	if strings.TrimSpace(system) == "" {
+		return prompt
+	}
+	return prompt + "\n\n" + system
+}
+
+// scoreResponseSchema is the structured response schema for ModeScore.
+func scoreResponseSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"score":     map[string]any{"type": "number"},
+			"rationale": map[string]any{"type": "string"},
+		},
+		"required":             []string{"score", "rationale"},
+		"additionalProperties": false,
+	}
+}
@@ -0,0 +1,99 @@
+package cora
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildPlans_Score(t *testing.T) {
+	plans, err := buildPlans(ProviderOpenAI, "gpt-test", TextRequest{
+		Mode:        ModeScore,
+		Input:       "Paris is the capital of France.",
+		ScoreRubric: "Factual accuracy",
+	}, CoraConfig{}, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	plan := plans[0]
+	for _, kw := range []string{"Factual accuracy", "from 1 to 10", `"rationale"`} {
+		if !strings.Contains(plan.System, kw) {
+			t.Errorf("expected system prompt to contain %q:\n%s", kw, plan.System)
+		}
+	}
+	if !plan.Structured || !reflect.DeepEqual(plan.ResponseSchema["required"], []string{"score", "rationale"}) {
+		t.Errorf("unexpected response schema %v", plan.ResponseSchema)
+	}
+	if err := validateResponseSchema(plan.ResponseSchema); err != nil {
+		t.Errorf("score schema should be a valid response schema: %v", err)
+	}
+}
+
+// The classify and score prompts extend the system prompt as rewritten by the
+// optimizer, not the request's original one.
+func TestBuildPlans_ScoreAndClassifyOptimizedSystem(t *testing.T) {
+	for _, req := range []TextRequest{
+		{Mode: ModeScore, ScoreRubric: "Factual accuracy"},
+		{Mode: ModeClassify, ClassifyLabels: []string{"spam", "ham"}},
+	} {
+		req.Input, req.System = "Paris is the capital of France.", "You are a strict grader. Be brief."
+		plans, err := buildPlans(ProviderOpenAI, "o1-mini", req, CoraConfig{}, ModelSpecificOptimizer(nil))
+		if err != nil {
+			t.Fatalf("%s: buildPlans error: %v", req.Mode, err)
+		}
+		if system := plans[0].System; strings.Contains(system, "strict grader") || !strings.Contains(system, "Be brief.") {
+			t.Errorf("%s: system prompt ignores the optimizer:\n%s", req.Mode, system)
+		}
+	}
+}
+
+func TestBuildPlans_ScoreValidation(t *testing.T) {
+	n := func(v int) *int { return &v }
+	tests := []struct {
+		name    string
+		req     TextRequest
+		wantErr string
+	}{
+		{"missing rubric", TextRequest{Mode: ModeScore}, "ScoreRubric"},
+		{"inverted range", TextRequest{Mode: ModeScore, ScoreRubric: "r", ScoreMin: n(5), ScoreMax: n(3)}, "ScoreMin (5) must be less than ScoreMax (3)"},
+		{"min above default max", TextRequest{Mode: ModeScore, ScoreRubric: "r", ScoreMin: n(10)}, "ScoreMin (10)"},
+		{"custom range", TextRequest{Mode: ModeScore, ScoreRubric: "r", ScoreMin: n(-1), ScoreMax: n(1)}, ""},
+		{"zero min", TextRequest{Mode: ModeScore, ScoreRubric: "r", ScoreMin: n(0), ScoreMax: n(5)}, ""},
+		{"zero max", TextRequest{Mode: ModeScore, ScoreRubric: "r", ScoreMin: n(-5), ScoreMax: n(0)}, ""},
+		{"min at default max", TextRequest{Mode: ModeScore, ScoreRubric: "r", ScoreMax: n(1)}, "ScoreMin (1) must be less than ScoreMax (1)"},
+	}
+	for _, tt := range tests {
+		_, err := buildPlans(ProviderOpenAI, "gpt-test", tt.req, CoraConfig{}, nil)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+	if lo, hi := scoreRange(TextRequest{ScoreMin: n(0), ScoreMax: n(5)}); lo != 0 || hi != 5 {
+		t.Errorf("scoreRange = [%d, %d], want [0, 5]", lo, hi)
+	}
+}
+
+func TestText_ScoreReturnsScore(t *testing.T) {
+	fake := (&fakeProvider{}).WithJSON(map[string]any{"score": 8.0, "rationale": "Accurate."})
+	c := &Client{cfg: CoraConfig{}, openai: fake}
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider:    ProviderOpenAI,
+		Model:       "gpt-test",
+		Mode:        ModeScore,
+		Input:       "Paris is the capital of France.",
+		ScoreRubric: "Factual accuracy",
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Score == nil || *resp.Score != 8 || resp.JSON["rationale"] != "Accurate." {
+		t.Errorf("unexpected score %v, JSON %v", resp.Score, resp.JSON)
+	}
+}
@@ -0,0 +1,99 @@
+package cora
+
+import (
+	"cmp"
+	"context"
+	"sync"
+	"time"
+)
+
+// semanticCache stores Text() responses keyed by the embedding of their
+// input, so that a differently worded but similar prompt can be served from
+// the cache. Entries only match requests with the same scope: the response
+// cache key of the request with its Input cleared (provider, model, system
+// prompt, mode, history, ...).
+type semanticCache struct {
+	mu        sync.Mutex
+	entries   []semanticCacheEntry
+	ttl       time.Duration
+	maxSize   int
+	threshold float64
+}
+
+type semanticCacheEntry struct {
+	scope     string
+	vector    []float32
+	resp      TextResponse
+	timestamp time.Time
+}
+
+func newSemanticCache(ttl time.Duration, maxSize int, threshold float64) *semanticCache {
+	return &semanticCache{ttl: ttl, maxSize: maxSize, threshold: threshold}
+}
+
+// lookup returns the response of the most similar unexpired entry in scope
+// whose similarity to vec reaches the threshold.
+func (s *semanticCache) lookup(scope string, vec []float32) (TextResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	best, bestScore := -1, s.threshold
+	for i, e := range s.entries {
+		if e.scope != scope || time.Since(e.timestamp) > s.ttl {
+			continue
+		}
+		if score := CosineSimilarity(vec, e.vector); score >= bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return TextResponse{}, false
+	}
+	return s.entries[best].resp, true
+}
+
+// store adds an entry, evicting expired entries and then the oldest one when
+// the cache is full.
+func (s *semanticCache) store(scope string, vec []float32, resp TextResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.entries) >= s.maxSize {
+		live := s.entries[:0]
+		for _, e := range s.entries {
+			if time.Since(e.timestamp) <= s.ttl {
+				live = append(live, e)
+			}
+		}
+		s.entries = live
+	}
+	if len(s.entries) >= s.maxSize {
+		// Entries are appended in time order, so the first is the oldest.
+		s.entries = append(s.entries[:0], s.entries[1:]...)
+	}
+	s.entries = append(s.entries, semanticCacheEntry{scope: scope, vector: vec, resp: resp, timestamp: time.Now()})
+}
+
+// semanticCacheScope returns the scope and input embedding of req for the
+// semantic cache. ok is false when the semantic cache is disabled, req is not
+// cacheable or the embedding fails; the request then bypasses the cache.
+func (c *Client) semanticCacheScope(ctx context.Context, req TextRequest) (scope string, vec []float32, ok bool) {
+	if c.semantic == nil {
+		return "", nil, false
+	}
+	scoped := req
+	scoped.Input = ""
+	scope, cacheable := c.responseCacheKey(scoped)
+	if !cacheable {
+		return "", nil, false
+	}
+	res, err := c.Embed(ctx, EmbedRequest{
+		Provider: cmp.Or(c.cfg.SemanticCacheEmbedProvider, req.Provider),
+		Model:    c.cfg.SemanticCacheEmbedModel,
+		Input:    []string{req.Input},
+	})
+	if err != nil || len(res.Embeddings) != 1 {
+		return "", nil, false
+	}
+	return scope, res.Embeddings[0], true
+}
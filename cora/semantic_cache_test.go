@@ -0,0 +1,82 @@
+package cora
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// embeddingProvider answers Text like fakeProvider and embeds inputs using
+// a fixed table of vectors.
+type embeddingProvider struct {
+	*fakeProvider
+	vectors map[string][]float32
+	embeds  int
+}
+
+func (p *embeddingProvider) Embed(ctx context.Context, model string, input []string) (EmbedResponse, error) {
+	p.embeds++
+	out := EmbedResponse{}
+	for _, s := range input {
+		out.Embeddings = append(out.Embeddings, p.vectors[s])
+	}
+	return out, nil
+}
+
+func TestSemanticCache_ServesSimilarInput(t *testing.T) {
+	fake := &embeddingProvider{
+		fakeProvider: &fakeProvider{finalOut: "fresh answer"},
+		vectors: map[string][]float32{
+			"How tall is Mount Everest?":       {0.99, 0.1, 0},
+			"What is the capital of Portugal?": {0, 0.1, 0.99},
+		},
+	}
+	c := New(CoraConfig{
+		ResponseCacheTTL:        time.Minute,
+		ResponseCacheMaxSize:    10,
+		SemanticCacheThreshold:  0.95,
+		SemanticCacheEmbedModel: "embed-test",
+	})
+	c.openai = fake
+
+	req := TextRequest{Provider: ProviderOpenAI, Model: "gpt-test"}
+	scope, _ := c.responseCacheKey(req) // Input is empty, as in semanticCacheScope
+	c.semantic.store(scope, []float32{1, 0.1, 0}, TextResponse{Text: "8,849 m"})
+
+	req.Input = "How tall is Mount Everest?"
+	resp, err := c.Text(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "8,849 m" || len(fake.ReceivedPlans()) != 0 {
+		t.Errorf("expected a semantic cache hit, got %q after %d provider calls", resp.Text, len(fake.ReceivedPlans()))
+	}
+
+	req.Input = "What is the capital of Portugal?"
+	if resp, err := c.Text(context.Background(), req); err != nil || resp.Text != "fresh answer" {
+		t.Errorf("expected a cache miss for a dissimilar input, got %q, %v", resp.Text, err)
+	}
+
+	// A matching vector under a different system prompt is out of scope.
+	req.Input = "How tall is Mount Everest?"
+	req.System = "Answer in feet."
+	if resp, err := c.Text(context.Background(), req); err != nil || resp.Text != "fresh answer" {
+		t.Errorf("expected a cache miss for a different system prompt, got %q, %v", resp.Text, err)
+	}
+	if fake.embeds != 3 {
+		t.Errorf("expected 3 embedding calls, got %d", fake.embeds)
+	}
+}
+
+func TestSemanticCache_Eviction(t *testing.T) {
+	s := newSemanticCache(time.Minute, 2, 0.9)
+	s.store("scope", []float32{1, 0}, TextResponse{Text: "a"})
+	s.store("scope", []float32{0, 1}, TextResponse{Text: "b"})
+	s.store("scope", []float32{1, 1}, TextResponse{Text: "c"})
+	if _, ok := s.lookup("scope", []float32{1, 0}); ok {
+		t.Error("oldest entry should have been evicted")
+	}
+	if resp, ok := s.lookup("scope", []float32{0, 1}); !ok || resp.Text != "b" {
+		t.Errorf("expected entry b, got %q, %v", resp.Text, ok)
+	}
+}
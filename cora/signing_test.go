@@ -0,0 +1,45 @@
+package cora
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHMACRequestSigner(t *testing.T) {
+	var auth, ts, wantSig string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, ts = r.Header.Get("Authorization"), r.Header.Get(HMACTimestampHeader)
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		fmt.Fprintf(mac, "%s\n%s\n%s\n%s", r.Method, r.URL.RequestURI(), ts, hex.EncodeToString(sum[:]))
+		wantSig = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`)
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{
+		OpenAIAPIKey:  "sk-test",
+		OpenAIBaseURL: srv.URL,
+		RequestSigner: HMACRequestSigner("key-1", "s3cret"),
+	})
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "m", Input: "hi"}); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if ts == "" {
+		t.Errorf("missing %s header", HMACTimestampHeader)
+	}
+	if want := "HMAC-SHA256 KeyId=key-1, Signature=" + wantSig; auth != want {
+		t.Errorf("Authorization = %q, want %q", auth, want)
+	}
+}
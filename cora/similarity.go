@@ -0,0 +1,126 @@
+package cora
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sync"
+)
+
+// CosineSimilarity returns the cosine of the angle between a and b, in [-1, 1].
+// It returns 0 when the vectors differ in length or either has zero norm.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		na += x * x
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// EmbeddingMatch is a search hit: the position of the vector in the corpus,
+// its ID when searching an EmbeddingIndex, and its cosine similarity.
+type EmbeddingMatch struct {
+	Index int
+	ID    string
+	Score float64
+}
+
+// TopK returns the k vectors of corpus most similar to query, best first.
+func TopK(query []float32, corpus [][]float32, k int) []EmbeddingMatch {
+	if k <= 0 {
+		return nil
+	}
+	matches := make([]EmbeddingMatch, len(corpus))
+	for i, v := range corpus {
+		matches[i] = EmbeddingMatch{Index: i, Score: CosineSimilarity(query, v)}
+	}
+	slices.SortStableFunc(matches, func(a, b EmbeddingMatch) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return matches[:min(k, len(matches))]
+}
+
+// EmbeddingIndex is an in-memory vector index with exact (brute-force)
+// cosine search. It is safe for concurrent use; the zero value is ready to use.
+type EmbeddingIndex struct {
+	mu   sync.RWMutex
+	ids  []string
+	vecs [][]float32
+	pos  map[string]int
+}
+
+// Add stores vec under id, replacing any previous vector with the same id.
+func (x *EmbeddingIndex) Add(id string, vec []float32) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.pos == nil {
+		x.pos = make(map[string]int)
+	}
+	if i, ok := x.pos[id]; ok {
+		x.vecs[i] = vec
+		return
+	}
+	x.pos[id] = len(x.ids)
+	x.ids = append(x.ids, id)
+	x.vecs = append(x.vecs, vec)
+}
+
+// Search returns the k entries most similar to query, best first.
+// EmbeddingMatch.Index is the insertion order of the entry.
+func (x *EmbeddingIndex) Search(query []float32, k int) []EmbeddingMatch {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	matches := TopK(query, x.vecs, k)
+	for i := range matches {
+		matches[i].ID = x.ids[matches[i].Index]
+	}
+	return matches
+}
+
+// Len returns the number of entries in the index.
+func (x *EmbeddingIndex) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.ids)
+}
+
+type embeddingIndexEntry struct {
+	ID     string    `json:"id"`
+	Vector []float32 `json:"vector"`
+}
+
+// Save writes the index to w as JSON; read it back with LoadEmbeddingIndex.
+func (x *EmbeddingIndex) Save(w io.Writer) error {
+	x.mu.RLock()
+	entries := make([]embeddingIndexEntry, len(x.ids))
+	for i, id := range x.ids {
+		entries[i] = embeddingIndexEntry{ID: id, Vector: x.vecs[i]}
+	}
+	x.mu.RUnlock()
+	return json.NewEncoder(w).Encode(entries)
+}
+
+// LoadEmbeddingIndex reads an index written by EmbeddingIndex.Save.
+func LoadEmbeddingIndex(r io.Reader) (*EmbeddingIndex, error) {
+	var entries []embeddingIndexEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("cora: decode embedding index: %w", err)
+	}
+	x := &EmbeddingIndex{}
+	for _, e := range entries {
+		x.Add(e.ID, e.Vector)
+	}
+	return x, nil
+}
@@ -0,0 +1,65 @@
+package cora
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	for _, tc := range []struct {
+		a, b []float32
+		want float64
+	}{
+		{[]float32{1, 0}, []float32{1, 0}, 1},
+		{[]float32{1, 0}, []float32{0, 1}, 0},
+		{[]float32{1, 1}, []float32{-1, -1}, -1},
+		{[]float32{1, 0}, []float32{1, 0, 0}, 0}, // length mismatch
+		{[]float32{0, 0}, []float32{1, 0}, 0},    // zero norm
+	} {
+		if got := CosineSimilarity(tc.a, tc.b); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("CosineSimilarity(%v, %v) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	corpus := [][]float32{{0, 1}, {1, 0.1}, {-1, 0}, {1, 0.5}}
+	got := TopK([]float32{1, 0}, corpus, 2)
+	if len(got) != 2 || got[0].Index != 1 || got[1].Index != 3 {
+		t.Errorf("unexpected matches %+v", got)
+	}
+	if got := TopK([]float32{1, 0}, corpus, 10); len(got) != len(corpus) {
+		t.Errorf("expected k to be capped at corpus size, got %d", len(got))
+	}
+}
+
+func TestEmbeddingIndex_SearchAndPersist(t *testing.T) {
+	var idx EmbeddingIndex
+	idx.Add("cat", []float32{0.9, 0.1, 0})
+	idx.Add("dog", []float32{0.8, 0.3, 0})
+	idx.Add("car", []float32{0, 0.1, 0.9})
+	if idx.Len() != 3 {
+		t.Fatalf("expected 3 entries, got %d", idx.Len())
+	}
+
+	best := idx.Search([]float32{0, 0, 1}, 1)
+	if len(best) != 1 || best[0].ID != "car" {
+		t.Fatalf("expected car as top match, got %+v", best)
+	}
+
+	var buf bytes.Buffer
+	if err := idx.Save(&buf); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	loaded, err := LoadEmbeddingIndex(&buf)
+	if err != nil {
+		t.Fatalf("LoadEmbeddingIndex error: %v", err)
+	}
+	if loaded.Len() != 3 {
+		t.Fatalf("expected 3 entries after load, got %d", loaded.Len())
+	}
+	if best := loaded.Search([]float32{1, 0, 0}, 1); best[0].ID != "cat" {
+		t.Errorf("expected cat as top match after load, got %+v", best)
+	}
+}
@@ -0,0 +1,56 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// ErrRequestTooLarge is returned when a provider request body exceeds
+// CoraConfig.MaxRequestBodyBytes (see TextRequest.OverrideSizeLimit).
+var ErrRequestTooLarge = errors.New("cora: request body exceeds MaxRequestBodyBytes")
+
+type sizeLimitOverrideKey struct{}
+
+type requestBodyCounterKey struct{}
+
+// withSizeLimitOverride returns a context whose provider requests are exempt
+// from MaxRequestBodyBytes.
+func withSizeLimitOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sizeLimitOverrideKey{}, true)
+}
+
+// withRequestBodyCounter returns a context whose provider requests add their
+// body sizes to the returned counter.
+func withRequestBodyCounter(ctx context.Context) (context.Context, *atomic.Int64) {
+	n := &atomic.Int64{}
+	return context.WithValue(ctx, requestBodyCounterKey{}, n), n
+}
+
+// sizeLimitTransport rejects request bodies larger than limit (when
+// positive) before they are sent, and counts the bytes of those it sends
+// for TextResponse.RequestBodyBytes. Bodies of unknown length pass
+// unchecked.
+type sizeLimitTransport struct {
+	base  http.RoundTripper
+	limit int64
+}
+
+func (t *sizeLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if size := req.ContentLength; size > 0 {
+		override, _ := ctx.Value(sizeLimitOverrideKey{}).(bool)
+		if t.limit > 0 && size > t.limit && !override {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, fmt.Errorf("%w: %d bytes, limit %d", ErrRequestTooLarge, size, t.limit)
+		}
+		if n, ok := ctx.Value(requestBodyCounterKey{}).(*atomic.Int64); ok {
+			n.Add(size)
+		}
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,46 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMaxRequestBodyBytes(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL, MaxRequestBodyBytes: 1024})
+	req := TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: strings.Repeat("x", 2048)}
+
+	_, err := c.Text(context.Background(), req)
+	if !errors.Is(err, ErrRequestTooLarge) {
+		t.Fatalf("expected ErrRequestTooLarge, got %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Fatal("the oversized request reached the server")
+	}
+
+	req.OverrideSizeLimit = true
+	resp, err := c.Text(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Text with OverrideSizeLimit: %v", err)
+	}
+	if resp.RequestBodyBytes <= 2048 {
+		t.Errorf("RequestBodyBytes = %d, want more than the 2048-byte input", resp.RequestBodyBytes)
+	}
+
+	resp, err = c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil || resp.RequestBodyBytes == 0 || resp.RequestBodyBytes > 1024 {
+		t.Errorf("small request: RequestBodyBytes = %d, err %v", resp.RequestBodyBytes, err)
+	}
+}
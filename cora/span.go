@@ -0,0 +1,123 @@
+package cora
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SpanRecorder receives a TextSpan after every Text() call, independent of
+// any tracing library.
+type SpanRecorder interface {
+	RecordSpan(span TextSpan)
+}
+
+// TextSpan describes one Text() call, successful or not. Provider and
+// Model are those that answered, after any fallback; ToolRounds counts the
+// rounds in which the model called tools.
+type TextSpan struct {
+	ID       string
+	Provider string
+	Model    string
+	Mode     TextMode
+
+	StartTime time.Time
+	EndTime   time.Time
+
+	PromptTokens     int
+	CompletionTokens int
+	EstimatedCost    float64 // USD, 0 when unknown
+
+	Error      error
+	Labels     map[string]string
+	ToolRounds int
+}
+
+// Duration returns EndTime - StartTime.
+func (s TextSpan) Duration() time.Duration { return s.EndTime.Sub(s.StartTime) }
+
+// WithSpanRecorder sets the recorder that receives a TextSpan after every
+// Text() call, and returns c for chaining.
+func (c *Client) WithSpanRecorder(rec SpanRecorder) *Client {
+	c.spans = rec
+	return c
+}
+
+func (c *Client) recordSpan(req TextRequest, resp TextResponse, start time.Time, err error) {
+	if c.spans == nil {
+		return
+	}
+	span := TextSpan{
+		ID:         newAuditID(),
+		Provider:   string(cmp.Or(resp.UsedProvider, req.Provider)),
+		Model:      cmp.Or(resp.UsedModel, req.Model),
+		Mode:       req.Mode,
+		StartTime:  start,
+		EndTime:    time.Now(),
+		Error:      err,
+		Labels:     mergeLabels(c.cfg.DefaultLabels, req.Labels),
+		ToolRounds: resp.ToolRounds,
+
+		PromptTokens:     derefInt(resp.PromptTokens),
+		CompletionTokens: derefInt(resp.CompletionTokens),
+	}
+	if resp.EstimatedCostUSD != nil {
+		span.EstimatedCost = *resp.EstimatedCostUSD
+	}
+	c.spans.RecordSpan(span)
+}
+
+// LogSpanRecorder returns a SpanRecorder that logs each span at INFO, or at
+// ERROR for failed calls.
+func LogSpanRecorder(logger *slog.Logger) SpanRecorder {
+	return logSpanRecorder{logger: logger}
+}
+
+type logSpanRecorder struct {
+	logger *slog.Logger
+}
+
+func (r logSpanRecorder) RecordSpan(span TextSpan) {
+	attrs := []slog.Attr{
+		slog.String("span_id", span.ID),
+		slog.String("provider", span.Provider),
+		slog.String("model", span.Model),
+		slog.String("mode", span.Mode.String()),
+		slog.Int64("duration_ms", span.Duration().Milliseconds()),
+		slog.Int("prompt_tokens", span.PromptTokens),
+		slog.Int("completion_tokens", span.CompletionTokens),
+		slog.Float64("estimated_cost", span.EstimatedCost),
+		slog.Int("tool_rounds", span.ToolRounds),
+	}
+	for k, v := range span.Labels {
+		attrs = append(attrs, slog.String("label."+k, v))
+	}
+	if span.Error != nil {
+		r.logger.LogAttrs(context.Background(), slog.LevelError, "cora: text span", append(attrs, slog.Any("error", span.Error))...)
+		return
+	}
+	r.logger.LogAttrs(context.Background(), slog.LevelInfo, "cora: text span", attrs...)
+}
+
+// SliceSpanRecorder keeps every recorded span in memory, e.g. for tests. It
+// is safe for concurrent use.
+type SliceSpanRecorder struct {
+	mu    sync.Mutex
+	spans []TextSpan
+}
+
+// RecordSpan appends span.
+func (r *SliceSpanRecorder) RecordSpan(span TextSpan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+// Spans returns the recorded spans, oldest first.
+func (r *SliceSpanRecorder) Spans() []TextSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]TextSpan(nil), r.spans...)
+}
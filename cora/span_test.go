@@ -0,0 +1,97 @@
+package cora
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSpanRecorder_Text(t *testing.T) {
+	rec := &SliceSpanRecorder{}
+	c := (&Client{cfg: CoraConfig{DefaultLabels: map[string]string{"env": "test"}}}).WithSpanRecorder(rec)
+	c.openai = &fakeProvider{finalOut: "hello"}
+	c.google = &failingProvider{err: errors.New("boom")}
+
+	if _, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi", Labels: map[string]string{"team": "core"},
+	}); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	_, _ = c.Text(context.Background(), TextRequest{Provider: ProviderGoogle, Model: "gemini-test", Input: "hi"})
+
+	spans := rec.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	ok := spans[0]
+	if ok.ID == "" || ok.Provider != "openai" || ok.Model != "gpt-test" || ok.Mode != ModeBasic || ok.Error != nil {
+		t.Errorf("unexpected span %+v", ok)
+	}
+	if ok.StartTime.IsZero() || ok.EndTime.Before(ok.StartTime) {
+		t.Errorf("span times %v - %v", ok.StartTime, ok.EndTime)
+	}
+	if ok.Labels["team"] != "core" || ok.Labels["env"] != "test" {
+		t.Errorf("span labels = %v", ok.Labels)
+	}
+	if spans[1].Error == nil || spans[1].Provider != "google" {
+		t.Errorf("expected a failed google span, got %+v", spans[1])
+	}
+}
+
+func TestSpanRecorder_ToolRounds(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		msg := map[string]any{"role": "assistant", "content": "done"}
+		if calls == 1 {
+			msg = toolCallMessage("call_1", "lookup", `{}`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": msg}},
+			"usage":   map[string]any{"prompt_tokens": 10, "completion_tokens": 2, "total_tokens": 12},
+		})
+	}))
+	defer srv.Close()
+
+	rec := &SliceSpanRecorder{}
+	tools, handlers := lookupTool()
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL}).WithSpanRecorder(rec)
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI, Model: "gpt-test", Input: "Look it up.", Mode: ModeToolCalling, Tools: tools, ToolHandlers: handlers,
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.ToolRounds != 1 {
+		t.Errorf("ToolRounds = %d, want 1", resp.ToolRounds)
+	}
+	span := rec.Spans()[0]
+	if span.ToolRounds != 1 || span.PromptTokens != 10 || span.CompletionTokens != 2 {
+		t.Errorf("unexpected span %+v", span)
+	}
+}
+
+func TestLogSpanRecorder(t *testing.T) {
+	var buf bytes.Buffer
+	rec := LogSpanRecorder(slog.New(slog.NewTextHandler(&buf, nil)))
+	rec.RecordSpan(TextSpan{ID: "s1", Provider: "openai", Model: "gpt-test", PromptTokens: 3})
+	rec.RecordSpan(TextSpan{ID: "s2", Provider: "google", Error: errors.New("boom")})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], "level=INFO") || !strings.Contains(lines[0], "span_id=s1") || !strings.Contains(lines[0], "prompt_tokens=3") {
+		t.Errorf("unexpected line %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "level=ERROR") || !strings.Contains(lines[1], "error=boom") {
+		t.Errorf("unexpected line %q", lines[1])
+	}
+}
@@ -3,14 +3,21 @@ package cora
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 // Stream executes a streaming text generation request.
 func (c *Client) Stream(ctx context.Context, req StreamRequest) (*StreamResponse, error) {
-	if req.Provider != ProviderOpenAI && req.Provider != ProviderGoogle {
-		return nil, fmt.Errorf("cora: unknown provider %q", req.Provider)
+	if !req.Provider.Valid() {
+		return nil, unknownProviderError(req.Provider)
+	}
+	switch req.Mode {
+	case ModeBasic, ModeToolCalling, ModeTwoStepEnhance:
+	default:
+		return nil, fmt.Errorf("cora: mode %v is not supported by Stream", req.Mode)
 	}
 
 	model := req.Model
@@ -26,12 +33,26 @@ func (c *Client) Stream(ctx context.Context, req StreamRequest) (*StreamResponse
 		}
 	}
 
+	var err error
+	if req.Input, err = c.checkContentPolicy(ctx, req.Provider, req.Input, req.System); err != nil {
+		return nil, err
+	}
+	if req.Messages, err = c.checkMessagesPolicy(ctx, req.Provider, req.Messages); err != nil {
+		return nil, err
+	}
+
 	// Apply defaults to stream options
 	opts := req.StreamOptions
 	if opts.BufferSize == 0 {
 		opts.BufferSize = 100
 	}
 
+	// The stream holds a concurrency slot until it ends.
+	release, err := c.acquireSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create cancellable context
 	streamCtx, cancel := context.WithCancel(ctx)
 
@@ -39,7 +60,10 @@ func (c *Client) Stream(ctx context.Context, req StreamRequest) (*StreamResponse
 	events := make(chan StreamEvent, opts.BufferSize)
 
 	// Create orchestrator
+	start := time.Now()
 	orchestrator := &streamOrchestrator{
+		start:    start,
+		ended:    make(chan struct{}),
 		ctx:      streamCtx,
 		client:   c,
 		req:      req,
@@ -47,19 +71,38 @@ func (c *Client) Stream(ctx context.Context, req StreamRequest) (*StreamResponse
 		opts:     opts,
 		events:   events,
 		cancel:   cancel,
+		release:  release,
 		toolWait: make(map[string]chan any),
+		declared: toolNameSet(req.Tools),
 	}
 
 	// Start streaming in background
+	c.logStart(ctx, req.Provider, model, streamMode(req))
 	go orchestrator.run()
 
 	return &StreamResponse{
 		Events:           events,
 		Cancel:           cancel,
 		SubmitToolResult: orchestrator.submitToolResult,
+		provider:         req.Provider,
+		model:            model,
+		startedAt:        start,
+		metrics:          orchestrator.waitMetrics,
+		checkpoint:       orchestrator.checkpoint,
+		inject:           orchestrator.injectTool,
 	}, nil
 }
 
+// Metrics blocks until the stream ends and returns its metrics. The events
+// must be drained meanwhile, e.g. by another goroutine. Streams not started
+// by Client.Stream have no metrics.
+func (resp *StreamResponse) Metrics() StreamMetrics {
+	if resp.metrics == nil {
+		return StreamMetrics{}
+	}
+	return resp.metrics()
+}
+
 // streamOrchestrator manages the lifecycle of a stream.
 type streamOrchestrator struct {
 	ctx    context.Context
@@ -69,106 +112,278 @@ type streamOrchestrator struct {
 	opts   StreamOptions
 	events chan StreamEvent
 	cancel context.CancelFunc
+	// release frees the client concurrency slot held by the stream.
+	release func()
 
 	// Tool execution state
 	toolWaitMu sync.Mutex
 	toolWait   map[string]chan any
+
+	// Tools added by StreamResponse.InjectTool: declared names the request's
+	// own tools, injected maps names to injectedTools, and applied (only
+	// touched by the run goroutine) names those already added to req.
+	declared map[string]bool
+	injected sync.Map
+	applied  map[string]bool
+
+	// Collected for the audit log; only written by the run goroutine.
+	output    strings.Builder
+	lastUsage *StreamUsage
+
+	// checkpointMu guards output, round and toolHistory against
+	// StreamResponse.Checkpoint, which reads them from other goroutines.
+	checkpointMu sync.Mutex
+	round        int
+	toolHistory  []CheckpointToolCall
+
+	// seq is the number of the last event sent (see
+	// StreamEvent.SequenceNumber). sendMu serializes sends so that a number
+	// is only used up by an event that was sent.
+	sendMu sync.Mutex
+	seq    int
+
+	// completionTokens counts streamed tokens against req.MaxTokenBudget.
+	completionTokens int
+	overBudget       bool
+
+	// Stream metrics: start is when Stream was called; metrics is set and
+	// ended closed just before events is closed.
+	start        time.Time
+	firstChunkAt time.Time
+	chunks       int
+	metrics      StreamMetrics
+	ended        chan struct{}
 }
 
 func (so *streamOrchestrator) run() {
 	defer close(so.events)
+	defer so.finishMetrics()
+	defer so.release()
+
+	start := time.Now()
+	err := so.stream()
+	if so.overBudget {
+		err = ErrTokenBudgetExceeded
+	}
+	var usage StreamUsage
+	if so.lastUsage != nil {
+		usage = *so.lastUsage
+	}
+	so.client.logDone(so.ctx, so.req.Provider, so.model, streamMode(so.req), start, usage.PromptTokens, usage.CompletionTokens, err)
+	if so.lastUsage != nil {
+		if cost, ok := estimateCost(so.client.cfg, so.model, usage.PromptTokens, usage.CompletionTokens); ok {
+			so.client.addSpend(cost)
+		}
+	}
+	so.client.auditStream(so.ctx, so.req, so.model, so.output.String(), so.lastUsage, err)
 
-	// Get provider client
-	pc, err := so.client.ensureProvider(so.req.Provider)
+	if so.overBudget {
+		// The stream context is already cancelled, so send unconditionally.
+		so.emit(StreamEvent{Type: EventTypeError, Err: err, Timestamp: time.Now()}, true)
+		return
+	}
 	if err != nil {
 		so.sendError(err)
 		return
 	}
 
-	// Delegate to provider-specific streaming
-	switch so.req.Provider {
-	case ProviderOpenAI:
-		err = so.streamOpenAI(pc.(*openAIProvider))
-	case ProviderGoogle:
-		err = so.streamGoogle(pc.(*googleProvider))
+	// Send completion event
+	so.emit(StreamEvent{Type: EventTypeDone, Usage: so.doneUsage(), Timestamp: time.Now()}, true)
+}
+
+// finishMetrics computes the stream's metrics and releases waitMetrics.
+func (so *streamOrchestrator) finishMetrics() {
+	m := StreamMetrics{
+		TotalChunks:     so.chunks,
+		TotalCharacters: utf8.RuneCountInString(so.output.String()),
+		Duration:        time.Since(so.start),
+	}
+	if !so.firstChunkAt.IsZero() {
+		m.TimeToFirstToken = so.firstChunkAt.Sub(so.start)
+	}
+	if m.Duration > 0 {
+		m.TokensPerSecond = float64(so.completionTokens) / m.Duration.Seconds()
+	}
+	so.metrics = m
+	close(so.ended)
+}
+
+// waitMetrics blocks until the stream ends and returns its metrics.
+func (so *streamOrchestrator) waitMetrics() StreamMetrics {
+	<-so.ended
+	return so.metrics
+}
+
+// doneUsage returns the usage reported with EventTypeDone: with
+// StreamOptions.EstimateUsage, the last reported usage (if any) plus the
+// estimated completion tokens of the streamed text; otherwise nil.
+func (so *streamOrchestrator) doneUsage() *StreamUsage {
+	if !so.opts.EstimateUsage {
+		return nil
+	}
+	var usage StreamUsage
+	if so.lastUsage != nil {
+		usage = *so.lastUsage
 	}
+	usage.EstimatedCompletionTokens = estimateTokens(so.output.String())
+	return &usage
+}
 
+// stream delegates to the provider-specific streaming implementation.
+func (so *streamOrchestrator) stream() error {
+	pc, err := so.client.providerFor(so.req.Provider, so.model)
 	if err != nil {
-		so.sendError(err)
-		return
+		return err
+	}
+	if so.req.Mode == ModeTwoStepEnhance {
+		input, err := so.proofread(pc)
+		if err != nil {
+			return err
+		}
+		so.req.Input = input
 	}
+	so.resume()
 
-	// Send completion event
-	so.events <- StreamEvent{
-		Type:      EventTypeDone,
-		provider:  so.req.Provider,
-		timestamp: time.Now(),
+	switch p := pc.(type) {
+	case *openAIProvider:
+		return so.streamOpenAI(p)
+	case *googleProvider:
+		return so.streamGoogle(p)
+	default:
+		return so.streamFromText(pc)
+	}
+}
+
+// proofread runs the clean-up step of ModeTwoStepEnhance with a regular Text
+// call and returns the improved input.
+func (so *streamOrchestrator) proofread(pc providerClient) (string, error) {
+	plan := callPlan{
+		Provider:        so.req.Provider,
+		Model:           so.model,
+		Input:           so.req.Input,
+		Temperature:     so.req.Temperature,
+		MaxOutputTokens: so.req.MaxOutputTokens,
+		Proofread:       true,
+	}
+	res, err := pc.Text(so.ctx, plan)
+	return res.Text, err
+}
+
+// streamFromText serves providers without native streaming by running a
+// regular Text call and emitting its result as a single chunk.
+func (so *streamOrchestrator) streamFromText(pc providerClient) error {
+	plan := callPlan{
+		Provider:        so.req.Provider,
+		Model:           so.model,
+		System:          so.req.System,
+		Input:           so.req.Input,
+		Messages:        so.req.Messages,
+		Documents:       imageDocuments(so.req.Images),
+		Temperature:     so.req.Temperature,
+		MaxOutputTokens: so.req.MaxOutputTokens,
+		Tools:           so.req.Tools,
+		ToolHandlers:    so.req.ToolHandlers,
+	}
+
+	res, err := pc.Text(so.ctx, plan)
+	if err != nil {
+		return err
+	}
+	if res.Text != "" {
+		so.sendChunk(res.Text)
+	}
+	if so.opts.IncludeUsage && res.TotalTokens != nil {
+		so.sendUsage(&StreamUsage{
+			PromptTokens:     derefInt(res.PromptTokens),
+			CompletionTokens: derefInt(res.CompletionTokens),
+			TotalTokens:      *res.TotalTokens,
+		})
+	}
+	return nil
+}
+
+// maxToolRounds returns the stream's tool round limit.
+func (so *streamOrchestrator) maxToolRounds() int {
+	if so.req.MaxToolRounds != nil {
+		return *so.req.MaxToolRounds
+	}
+	return streamMaxToolRounds
+}
+
+func derefInt(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+// chargeTokens sets the completion token count to n if that is higher and
+// cancels the stream once it exceeds req.MaxTokenBudget.
+func (so *streamOrchestrator) chargeTokens(n int) {
+	so.completionTokens = max(so.completionTokens, n)
+	if so.req.MaxTokenBudget > 0 && so.completionTokens > so.req.MaxTokenBudget && !so.overBudget {
+		so.overBudget = true
+		so.cancel()
 	}
 }
 
 func (so *streamOrchestrator) sendChunk(text string) {
-	select {
-	case <-so.ctx.Done():
+	if so.overBudget {
+		return
+	}
+	so.chargeTokens(so.completionTokens + estimateTokens(text))
+	if so.overBudget {
 		return
-	case so.events <- StreamEvent{
-		Type:      EventTypeChunk,
-		Text:      text,
-		provider:  so.req.Provider,
-		timestamp: time.Now(),
-	}:
 	}
+	so.checkpointMu.Lock()
+	so.output.WriteString(text)
+	so.checkpointMu.Unlock()
+	if so.chunks == 0 {
+		so.firstChunkAt = time.Now()
+	}
+	so.chunks++
+	so.emit(StreamEvent{Type: EventTypeChunk, Text: text, Timestamp: time.Now()}, false)
 }
 
 func (so *streamOrchestrator) sendToolCallRequest(tc *StreamToolCall) {
-	select {
-	case <-so.ctx.Done():
-		return
-	case so.events <- StreamEvent{
-		Type:      EventTypeToolCallRequest,
-		ToolCall:  tc,
-		provider:  so.req.Provider,
-		timestamp: time.Now(),
-	}:
-	}
+	so.checkpointMu.Lock()
+	so.toolHistory = append(so.toolHistory, CheckpointToolCall{Name: tc.Name, Args: tc.Arguments})
+	so.checkpointMu.Unlock()
+	so.emit(StreamEvent{Type: EventTypeToolCallRequest, ToolCall: tc, Timestamp: time.Now()}, false)
 }
 
 func (so *streamOrchestrator) sendToolCallResult(tr *StreamToolResult) {
-	select {
-	case <-so.ctx.Done():
-		return
-	case so.events <- StreamEvent{
-		Type:       EventTypeToolCallResult,
-		ToolResult: tr,
-		provider:   so.req.Provider,
-		timestamp:  time.Now(),
-	}:
-	}
+	so.emit(StreamEvent{Type: EventTypeToolCallResult, ToolResult: tr, Timestamp: time.Now()}, false)
+}
+
+// runToolHandler runs handler for tc between EventTypeToolCallStarted and
+// EventTypeToolCallCompleted events.
+func (so *streamOrchestrator) runToolHandler(tc *StreamToolCall, handler CoraToolHandler) (any, error) {
+	so.sendToolEvent(StreamEvent{Type: EventTypeToolCallStarted, ToolCall: tc})
+	result, err := handler(so.ctx, tc.Arguments)
+	so.sendToolEvent(StreamEvent{Type: EventTypeToolCallCompleted, ToolResult: &StreamToolResult{
+		ToolCallID: tc.ID,
+		Name:       tc.Name,
+		Result:     result,
+		Err:        err,
+	}})
+	return result, err
+}
+
+// sendToolEvent stamps and sends a tool lifecycle event.
+func (so *streamOrchestrator) sendToolEvent(ev StreamEvent) {
+	ev.Timestamp = time.Now()
+	so.emit(ev, false)
 }
 
 func (so *streamOrchestrator) sendUsage(usage *StreamUsage) {
-	select {
-	case <-so.ctx.Done():
-		return
-	case so.events <- StreamEvent{
-		Type:      EventTypeUsage,
-		Usage:     usage,
-		provider:  so.req.Provider,
-		timestamp: time.Now(),
-	}:
-	}
+	so.lastUsage = usage
+	so.chargeTokens(usage.CompletionTokens)
+	so.emit(StreamEvent{Type: EventTypeUsage, Usage: usage, Timestamp: time.Now()}, false)
 }
 
 func (so *streamOrchestrator) sendError(err error) {
-	select {
-	case <-so.ctx.Done():
-		return
-	case so.events <- StreamEvent{
-		Type:      EventTypeError,
-		Err:       err,
-		provider:  so.req.Provider,
-		timestamp: time.Now(),
-	}:
-	}
+	so.emit(StreamEvent{Type: EventTypeError, Err: err, Timestamp: time.Now()}, false)
 }
 
 // submitToolResult is called by user to manually submit tool results (pause mode)
@@ -189,7 +404,8 @@ func (so *streamOrchestrator) submitToolResult(toolCallID string, result any) er
 	}
 }
 
-// waitForToolResult blocks until tool result is submitted (pause mode)
+// waitForToolResult blocks until tool result is submitted (pause mode), or
+// returns a ToolTimeoutResult once StreamOptions.PauseTimeout has passed.
 func (so *streamOrchestrator) waitForToolResult(toolCallID string) (any, error) {
 	ch := make(chan any, 1)
 	so.toolWaitMu.Lock()
@@ -202,10 +418,36 @@ func (so *streamOrchestrator) waitForToolResult(toolCallID string) (any, error)
 		so.toolWaitMu.Unlock()
 	}()
 
+	var timeout <-chan time.Time
+	if so.opts.PauseTimeout > 0 {
+		timer := time.NewTimer(so.opts.PauseTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
 	select {
 	case result := <-ch:
 		return result, nil
+	case <-timeout:
+		if so.opts.OnToolTimeout != nil {
+			so.opts.OnToolTimeout(toolCallID)
+		}
+		return ToolTimeoutResult{
+			ToolCallID: toolCallID,
+			Error:      fmt.Sprintf("no result was submitted within %v", so.opts.PauseTimeout),
+		}, nil
 	case <-so.ctx.Done():
 		return nil, so.ctx.Err()
 	}
-}
\ No newline at end of file
+}
+
+// streamMode reports the TextMode a stream corresponds to, for logs and audit entries.
+func streamMode(req StreamRequest) TextMode {
+	if req.Mode == ModeTwoStepEnhance {
+		return ModeTwoStepEnhance
+	}
+	if len(req.Tools) > 0 {
+		return ModeToolCalling
+	}
+	return ModeBasic
+}
@@ -0,0 +1,75 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStream_MaxTokenBudget(t *testing.T) {
+	chunks := make([]string, 100)
+	for i := range chunks {
+		chunks[i] = "abcd" // one estimated token each
+	}
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = openAIChunkServer(t, 0, 0, chunks...)
+
+	resp, err := c.Stream(context.Background(), StreamRequest{
+		Provider:       ProviderOpenAI,
+		Model:          "gpt-test",
+		Input:          "write a lot",
+		MaxTokenBudget: 10,
+	})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+
+	var text strings.Builder
+	var streamErr error
+	for ev := range resp.Events {
+		switch ev.Type {
+		case EventTypeChunk:
+			text.WriteString(ev.Text)
+		case EventTypeError:
+			streamErr = ev.Err
+		case EventTypeDone:
+			t.Error("expected no done event after the budget was exceeded")
+		}
+	}
+	if !errors.Is(streamErr, ErrTokenBudgetExceeded) {
+		t.Fatalf("expected ErrTokenBudgetExceeded, got %v", streamErr)
+	}
+	if got := estimateTokens(text.String()); got != 10 {
+		t.Errorf("expected the stream to stop at 10 tokens, got %d", got)
+	}
+}
+
+func TestStream_EstimateUsage(t *testing.T) {
+	chunks := []string{"The quick brown fox ", "jumps over ", "the lazy dog, ", "twice over."}
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = openAIChunkServer(t, 0, 0, chunks...)
+
+	resp, err := c.Stream(context.Background(), StreamRequest{
+		Provider:      ProviderOpenAI,
+		Model:         "gpt-test",
+		Input:         "tell me about the fox",
+		StreamOptions: StreamOptions{EstimateUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	var done *StreamUsage
+	for ev := range resp.Events {
+		if ev.Type == EventTypeDone {
+			done = ev.Usage
+		}
+	}
+	if done == nil {
+		t.Fatal("expected usage on the done event")
+	}
+	want := float64(len(strings.Join(chunks, ""))) / 4
+	if got := float64(done.EstimatedCompletionTokens); got < want*0.8 || got > want*1.2 {
+		t.Errorf("EstimatedCompletionTokens = %v, want within 20%% of %v", got, want)
+	}
+}
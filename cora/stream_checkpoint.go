@@ -0,0 +1,104 @@
+package cora
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// StreamCheckpoint is the state of a stream, captured by
+// StreamResponse.Checkpoint so that a stream whose connection dropped can be
+// resumed by passing it as StreamOptions.Checkpoint. It can be saved as JSON.
+type StreamCheckpoint struct {
+	// ReceivedText is the text streamed so far.
+	ReceivedText string `json:"received_text"`
+	// ToolCallHistory holds the tool calls the model made so far, oldest first.
+	ToolCallHistory []CheckpointToolCall `json:"tool_call_history,omitempty"`
+	// Round is the tool round that was streaming (1-based).
+	Round int `json:"round"`
+}
+
+// CheckpointToolCall is a tool call recorded in a StreamCheckpoint.
+type CheckpointToolCall struct {
+	Name string         `json:"name"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// Checkpoint captures the stream's current state. It may be called while the
+// stream runs or after it failed. Streams not started by Client.Stream have
+// an empty checkpoint.
+func (resp *StreamResponse) Checkpoint() StreamCheckpoint {
+	if resp.checkpoint == nil {
+		return StreamCheckpoint{}
+	}
+	return resp.checkpoint()
+}
+
+// checkpoint returns the stream's current state.
+func (so *streamOrchestrator) checkpoint() StreamCheckpoint {
+	so.checkpointMu.Lock()
+	defer so.checkpointMu.Unlock()
+	return StreamCheckpoint{
+		ReceivedText:    so.output.String(),
+		ToolCallHistory: slices.Clone(so.toolHistory),
+		Round:           so.round,
+	}
+}
+
+// resume applies StreamOptions.Checkpoint. The received text is sent again
+// as the first chunk, as if it had just been streamed. Neither provider can
+// resume a response, so the rest is simulated by re-prompting: the original
+// input becomes a user turn, followed by the partial answer and a prompt to
+// continue it that names the tools already called.
+func (so *streamOrchestrator) resume() {
+	cp := so.opts.Checkpoint
+	if cp == nil {
+		return
+	}
+	so.checkpointMu.Lock()
+	so.round = cp.Round
+	so.toolHistory = slices.Clone(cp.ToolCallHistory)
+	so.checkpointMu.Unlock()
+	if cp.ReceivedText == "" && len(cp.ToolCallHistory) == 0 {
+		return
+	}
+
+	msgs := append(slices.Clone(so.req.Messages), Message{Role: "user", Content: so.req.Input})
+	if cp.ReceivedText != "" {
+		so.sendChunk(cp.ReceivedText)
+		msgs = append(msgs, Message{Role: "assistant", Content: cp.ReceivedText})
+	}
+	so.req.Messages = msgs
+	so.req.Input = resumePrompt(*cp)
+}
+
+// resumePrompt asks the model to continue the answer interrupted at cp.
+func resumePrompt(cp StreamCheckpoint) string {
+	var b strings.Builder
+	b.WriteString("Your previous answer was interrupted. Continue it exactly where it stopped, without repeating any of it.")
+	if len(cp.ToolCallHistory) > 0 {
+		b.WriteString(" You already called these tools; do not call them again with the same arguments:")
+		for _, tc := range cp.ToolCallHistory {
+			args, _ := json.Marshal(tc.Args)
+			fmt.Fprintf(&b, "\n- %s(%s)", tc.Name, args)
+		}
+	}
+	return b.String()
+}
+
+// firstRound returns the tool round a stream starts in: 1, or the round of
+// the checkpoint it resumes.
+func (so *streamOrchestrator) firstRound() int {
+	if so.opts.Checkpoint != nil {
+		return max(1, so.opts.Checkpoint.Round)
+	}
+	return 1
+}
+
+// setRound records the tool round being streamed.
+func (so *streamOrchestrator) setRound(round int) {
+	so.checkpointMu.Lock()
+	so.round = round
+	so.checkpointMu.Unlock()
+}
@@ -0,0 +1,123 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+func TestStreamCheckpoint_Resume(t *testing.T) {
+	// Round 1 streams a tool call; round 2 drops the connection after its
+	// first chunk; the resumed stream finishes the answer.
+	var requests int
+	var resumed openai.ChatCompletionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/event-stream")
+		var chunks []string
+		switch requests {
+		case 1:
+			chunks = []string{
+				`{"choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]}}]}`,
+				`{"choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+			}
+		case 2:
+			fmt.Fprint(w, `data: {"choices":[{"index":0,"delta":{"content":"It is "}}]}`+"\n\n")
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		default:
+			_ = json.NewDecoder(r.Body).Decode(&resumed)
+			chunks = []string{`{"choices":[{"index":0,"delta":{"content":"sunny."},"finish_reason":"stop"}]}`}
+		}
+		for _, c := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", c)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	maxRounds := 2
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	req := StreamRequest{
+		Provider:      ProviderOpenAI,
+		Model:         "gpt-test",
+		Input:         "Weather in Paris?",
+		MaxToolRounds: &maxRounds,
+		Tools:         []CoraTool{{Name: "get_weather", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{
+			"get_weather": func(ctx context.Context, args map[string]any) (any, error) { return "sunny", nil },
+		},
+	}
+	resp, err := c.Stream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	var failed bool
+	for ev := range resp.Events {
+		failed = failed || ev.Type == EventTypeError
+	}
+	if !failed {
+		t.Fatal("expected the dropped connection to fail the stream")
+	}
+
+	cp := resp.Checkpoint()
+	if cp.ReceivedText != "It is " || cp.Round != 2 || len(cp.ToolCallHistory) != 1 || cp.ToolCallHistory[0].Name != "get_weather" {
+		t.Fatalf("unexpected checkpoint %+v", cp)
+	}
+
+	req.StreamOptions.Checkpoint = &cp
+	resp, err = c.Stream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	var text strings.Builder
+	for ev := range resp.Events {
+		if ev.Type == EventTypeError {
+			t.Fatalf("resumed stream error: %v", ev.Err)
+		}
+		text.WriteString(ev.Text)
+	}
+	if got := text.String(); got != "It is sunny." {
+		t.Errorf("resumed text = %q", got)
+	}
+
+	msgs := resumed.Messages
+	if len(msgs) != 3 || msgs[0].Content != "Weather in Paris?" || msgs[1].Role != "assistant" || msgs[1].Content != "It is " {
+		t.Fatalf("unexpected resumed conversation %+v", msgs)
+	}
+	if prompt := msgs[2].Content; !strings.Contains(prompt, "Continue it exactly where it stopped") || !strings.Contains(prompt, `get_weather({"city":"Paris"})`) {
+		t.Errorf("unexpected resume prompt %q", prompt)
+	}
+	if got := resp.Checkpoint(); got.ReceivedText != "It is sunny." || got.Round != 2 {
+		t.Errorf("checkpoint after resume = %+v", got)
+	}
+}
+
+func TestStreamCheckpoint_JSON(t *testing.T) {
+	cp := StreamCheckpoint{
+		ReceivedText:    "It is ",
+		ToolCallHistory: []CheckpointToolCall{{Name: "get_weather", Args: map[string]any{"city": "Paris"}}},
+		Round:           2,
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var got StreamCheckpoint
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if got.ReceivedText != cp.ReceivedText || got.Round != 2 || len(got.ToolCallHistory) != 1 ||
+		got.ToolCallHistory[0].Name != "get_weather" || got.ToolCallHistory[0].Args["city"] != "Paris" {
+		t.Errorf("round trip = %+v, want %+v", got, cp)
+	}
+	if want := `get_weather({"city":"Paris"})`; !strings.Contains(resumePrompt(got), want) {
+		t.Errorf("resume prompt of the decoded checkpoint lacks %s", want)
+	}
+}
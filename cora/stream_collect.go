@@ -0,0 +1,115 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Text drains the stream and returns the concatenated text chunks and the
+// last reported usage. The first error event is returned after the channel
+// is drained. If ctx is done first, the stream is cancelled, drained and
+// ctx's error returned.
+func (resp *StreamResponse) Text(ctx context.Context) (string, StreamUsage, error) {
+	out, usage, err := resp.collect(ctx)
+	return out.Text, usage, err
+}
+
+// CollectText drains the stream like Text and returns the result as a
+// TextResponse, with the text of every chunk kept in Chunks and the stream's
+// start time in StreamedAt.
+func (resp *StreamResponse) CollectText(ctx context.Context) (TextResponse, error) {
+	out, _, err := resp.collect(ctx)
+	return out, err
+}
+
+// CollectJSON is CollectText with the collected text parsed as a JSON object
+// into JSON.
+func (resp *StreamResponse) CollectJSON(ctx context.Context) (TextResponse, error) {
+	out, _, err := resp.collect(ctx)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal([]byte(out.Text), &out.JSON); err != nil {
+		return out, fmt.Errorf("cora: stream output is not a JSON object: %w", err)
+	}
+	return out, nil
+}
+
+// collect drains the stream for Text and CollectText.
+func (resp *StreamResponse) collect(ctx context.Context) (TextResponse, StreamUsage, error) {
+	out := TextResponse{
+		Provider:     resp.provider,
+		Model:        resp.model,
+		UsedProvider: resp.provider,
+		UsedModel:    resp.model,
+		StreamedAt:   resp.startedAt,
+		Chunks:       []string{},
+	}
+	var text strings.Builder
+	var usage StreamUsage
+	var err error
+	finish := func() (TextResponse, StreamUsage, error) {
+		out.Text = text.String()
+		if !out.StreamedAt.IsZero() {
+			out.Latency = time.Since(out.StreamedAt)
+		}
+		if usage.TotalTokens > 0 {
+			pt, ct, tt := usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens
+			out.PromptTokens, out.CompletionTokens, out.TotalTokens = &pt, &ct, &tt
+		}
+		return out, usage, err
+	}
+	done := ctx.Done()
+	for {
+		select {
+		case ev, ok := <-resp.Events:
+			if !ok {
+				return finish()
+			}
+			if out.StreamedAt.IsZero() {
+				out.StreamedAt = ev.Timestamp
+			}
+			switch ev.Type {
+			case EventTypeChunk:
+				text.WriteString(ev.Text)
+				out.Chunks = append(out.Chunks, ev.Text)
+			case EventTypeUsage, EventTypeDone:
+				if ev.Usage != nil {
+					usage = *ev.Usage
+				}
+				if ev.Model != "" {
+					out.UsedModel = ev.Model
+				}
+			case EventTypeError:
+				if err == nil {
+					err = ev.Err
+				}
+			}
+		case <-done:
+			if resp.Cancel != nil {
+				resp.Cancel()
+			}
+			if err == nil {
+				err = ctx.Err()
+			}
+			done = nil // keep draining until the stream closes the channel
+		}
+	}
+}
+
+// JSON drains the stream like Text and parses the collected text as a JSON
+// object.
+func (resp *StreamResponse) JSON(ctx context.Context) (map[string]any, StreamUsage, error) {
+	text, usage, err := resp.Text(ctx)
+	if err != nil {
+		return nil, usage, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(text), &m); err != nil {
+		return nil, usage, fmt.Errorf("cora: stream output is not a JSON object: %w", err)
+	}
+	return m, usage, nil
+}
@@ -0,0 +1,106 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStreamResponse_Text(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}, openai: usageProvider{}}
+	resp, err := c.Stream(context.Background(), StreamRequest{
+		Provider:      ProviderOpenAI,
+		Model:         "gpt-test",
+		Input:         "hi",
+		StreamOptions: StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	text, usage, err := resp.Text(context.Background())
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if text != "ok" || usage != (StreamUsage{PromptTokens: 12, CompletionTokens: 7, TotalTokens: 19}) {
+		t.Errorf("got text %q, usage %+v", text, usage)
+	}
+
+	boom := errors.New("boom")
+	c.openai = (&fakeProvider{finalOut: "partial"}).WithErrorAfterN(0, boom)
+	resp, err = c.Stream(context.Background(), StreamRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	if _, _, err := resp.Text(context.Background()); !errors.Is(err, boom) {
+		t.Errorf("expected the stream error, got %v", err)
+	}
+	if _, ok := <-resp.Events; ok {
+		t.Error("expected the events channel to be drained and closed")
+	}
+}
+
+func TestStreamResponse_JSON(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = openAIChunkServer(t, 0, 0, `{"city": `, `"Paris", `, `"temp": 21}`)
+	resp, err := c.Stream(context.Background(), StreamRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	got, _, err := resp.JSON(context.Background())
+	if err != nil {
+		t.Fatalf("JSON error: %v", err)
+	}
+	if want := map[string]any{"city": "Paris", "temp": 21.0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	c.openai = &fakeProvider{finalOut: "not json"}
+	resp, err = c.Stream(context.Background(), StreamRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	if _, _, err := resp.JSON(context.Background()); err == nil {
+		t.Error("expected an error for non-JSON output")
+	}
+}
+
+func TestStreamResponse_CollectText(t *testing.T) {
+	chunks := []string{"The ", "quick ", "brown ", "fox"}
+	c := &Client{cfg: CoraConfig{}, openai: openAIChunkServer(t, 0, 0, chunks...)}
+	before := time.Now()
+	resp, err := c.Stream(context.Background(), StreamRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	out, err := resp.CollectText(context.Background())
+	if err != nil {
+		t.Fatalf("CollectText error: %v", err)
+	}
+	if !reflect.DeepEqual(out.Chunks, chunks) || out.Text != "The quick brown fox" {
+		t.Errorf("got chunks %q, text %q", out.Chunks, out.Text)
+	}
+	if out.StreamedAt.Before(before) || out.StreamedAt.After(time.Now()) {
+		t.Errorf("unexpected StreamedAt %v", out.StreamedAt)
+	}
+	if out.Provider != ProviderOpenAI || out.Model != "gpt-test" {
+		t.Errorf("got provider %q, model %q", out.Provider, out.Model)
+	}
+
+	c.openai = openAIChunkServer(t, 0, 0, `{"a": `, `1}`)
+	resp, err = c.Stream(context.Background(), StreamRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	out, err = resp.CollectJSON(context.Background())
+	if err != nil || len(out.Chunks) != 2 || out.JSON["a"] != 1.0 {
+		t.Errorf("got %+v, %v", out, err)
+	}
+
+	c.openai = &fakeProvider{}
+	text, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil || text.Chunks != nil || !text.StreamedAt.IsZero() {
+		t.Errorf("expected no chunks from Text, got %q at %v (%v)", text.Chunks, text.StreamedAt, err)
+	}
+}
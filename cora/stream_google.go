@@ -23,38 +23,75 @@ func (so *streamOrchestrator) streamGoogle(p *googleProvider) error {
 		cfg.MaxOutputTokens = int32(*so.req.MaxOutputTokens)
 	}
 
-	// Add tools
-	if len(so.req.Tools) > 0 {
-		cfg.Tools = toGenAITools(so.req.Tools)
-		cfg.ToolConfig = &genai.ToolConfig{
-			FunctionCallingConfig: &genai.FunctionCallingConfig{
-				Mode: genai.FunctionCallingConfigModeAny,
-			},
+	setGoogleStreamTools(cfg, so.req.Tools)
+
+	history := buildGoogleHistory(so.req.Messages, buildGoogleContents(so.req.Input, so.req.Images))
+
+	// Each round streams one response; function calls are executed and their
+	// responses appended before the next round streams the model's answer.
+	maxRounds := so.maxToolRounds()
+	for round := so.firstRound(); ; round++ {
+		if round > maxRounds {
+			return fmt.Errorf("exceeded maximum tool call rounds (%d)", maxRounds)
+		}
+		so.setRound(round)
+		if so.applyInjectedTools() {
+			setGoogleStreamTools(cfg, so.req.Tools)
+		}
+		modelContent, fcs, err := so.streamGoogleRound(p, history, cfg)
+		if err != nil {
+			return err
+		}
+		if len(fcs) == 0 {
+			return nil
 		}
-	}
 
-	// Start streaming
-	for result, err := range p.client.Models.GenerateContentStream(
-		so.ctx,
-		so.model,
-		genai.Text(so.req.Input),
-		cfg,
-	) {
+		respContent, err := so.handleGoogleToolCalls(p, fcs)
 		if err != nil {
 			return err
 		}
+		history = append(history, modelContent, respContent)
+	}
+}
+
+// setGoogleStreamTools adds tools to cfg, if any. Auto mode lets the model
+// answer once it has the tool results.
+func setGoogleStreamTools(cfg *genai.GenerateContentConfig, tools []CoraTool) {
+	if len(tools) == 0 {
+		return
+	}
+	cfg.Tools = toGenAITools(tools)
+	cfg.ToolConfig = &genai.ToolConfig{
+		FunctionCallingConfig: &genai.FunctionCallingConfig{
+			Mode: genai.FunctionCallingConfigModeAuto,
+		},
+	}
+}
+
+// streamGoogleRound streams a single response, forwarding text and usage
+// events, and returns the model's full content and any function calls.
+func (so *streamOrchestrator) streamGoogleRound(
+	p *googleProvider,
+	history []*genai.Content,
+	cfg *genai.GenerateContentConfig,
+) (*genai.Content, []*genai.FunctionCall, error) {
+	modelContent := &genai.Content{Role: genai.RoleModel}
+	var fcs []*genai.FunctionCall
+
+	for result, err := range p.client.Models.GenerateContentStream(so.ctx, so.model, history, cfg) {
+		if err != nil {
+			return nil, nil, err
+		}
 
 		// Send text chunks
 		if text := result.Text(); text != "" {
 			so.sendChunk(text)
 		}
 
-		// Handle tool calls
-		if fcs := result.FunctionCalls(); len(fcs) > 0 {
-			if err := so.handleGoogleToolCalls(p, fcs); err != nil {
-				return err
-			}
+		if len(result.Candidates) > 0 && result.Candidates[0].Content != nil {
+			modelContent.Parts = append(modelContent.Parts, result.Candidates[0].Content.Parts...)
 		}
+		fcs = append(fcs, result.FunctionCalls()...)
 
 		// Send usage
 		if result.UsageMetadata != nil {
@@ -66,20 +103,22 @@ func (so *streamOrchestrator) streamGoogle(p *googleProvider) error {
 		}
 	}
 
-	return nil
+	return modelContent, fcs, nil
 }
 
 func (so *streamOrchestrator) handleGoogleToolCalls(
 	p *googleProvider,
 	calls []*genai.FunctionCall,
-) error {
+) (*genai.Content, error) {
+	respContent := &genai.Content{Role: genai.RoleUser}
 	for _, fc := range calls {
 		// Send tool call request
-		so.sendToolCallRequest(&StreamToolCall{
+		tc := &StreamToolCall{
 			ID:        fc.Name, // Google doesn't provide ID in stream
 			Name:      fc.Name,
 			Arguments: fc.Args,
-		})
+		}
+		so.sendToolCallRequest(tc)
 
 		// Execute tool
 		var result any
@@ -89,9 +128,9 @@ func (so *streamOrchestrator) handleGoogleToolCalls(
 		case ToolExecutionAuto, ToolExecutionParallel:
 			handler, ok := so.req.ToolHandlers[fc.Name]
 			if !ok {
-				return fmt.Errorf("no handler for tool %s", fc.Name)
+				return nil, fmt.Errorf("no handler for tool %s", fc.Name)
 			}
-			result, execErr = handler(so.ctx, fc.Args)
+			result, execErr = so.runToolHandler(tc, handler)
 
 		case ToolExecutionPause:
 			result, execErr = so.waitForToolResult(fc.Name)
@@ -106,9 +145,16 @@ func (so *streamOrchestrator) handleGoogleToolCalls(
 		})
 
 		if execErr != nil {
-			return execErr
+			return nil, execErr
 		}
+
+		respContent.Parts = append(respContent.Parts, &genai.Part{
+			FunctionResponse: &genai.FunctionResponse{
+				Name:     fc.Name,
+				Response: functionResponsePayload(result),
+			},
+		})
 	}
 
-	return nil
-}
\ No newline at end of file
+	return respContent, nil
+}
@@ -0,0 +1,333 @@
+package cora
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// googleStreamServer serves streamGenerateContent, answering round n with
+// respond(n) as a single server-sent event.
+func googleStreamServer(t *testing.T, respond func(round int) map[string]any) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var rounds atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "streamGenerateContent") {
+			t.Errorf("unexpected request %s", r.URL.Path)
+			return
+		}
+		part := respond(int(rounds.Add(1)))
+		b, _ := json.Marshal(map[string]any{
+			"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{part}}}},
+		})
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: %s\n\n", b)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &rounds
+}
+
+func TestStreamGoogle_ToolRounds(t *testing.T) {
+	srv, rounds := googleStreamServer(t, func(round int) map[string]any {
+		if round == 1 {
+			return map[string]any{"functionCall": map[string]any{"name": "get_weather", "args": map[string]any{"city": "Paris"}}}
+		}
+		return map[string]any{"text": "Sunny in Paris"}
+	})
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	resp, err := c.Stream(context.Background(), StreamRequest{
+		Provider: ProviderGoogle,
+		Model:    "gemini-test",
+		Input:    "Weather in Paris?",
+		Tools:    []CoraTool{{Name: "get_weather", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{"get_weather": func(ctx context.Context, args map[string]any) (any, error) {
+			return map[string]any{"sky": "sunny"}, nil
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+
+	var text string
+	var toolResults int
+	for ev := range resp.Events {
+		switch ev.Type {
+		case EventTypeChunk:
+			text += ev.Text
+		case EventTypeToolCallResult:
+			toolResults++
+		case EventTypeError:
+			t.Fatalf("stream error: %v", ev.Err)
+		}
+	}
+	if text != "Sunny in Paris" || toolResults != 1 || rounds.Load() != 2 {
+		t.Errorf("got text %q, %d tool results, %d rounds", text, toolResults, rounds.Load())
+	}
+}
+
+func TestStreamGoogle_ToolLifecycleEvents(t *testing.T) {
+	srv, _ := googleStreamServer(t, func(int) map[string]any {
+		return map[string]any{"functionCall": map[string]any{"name": "lookup", "args": map[string]any{}}}
+	})
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	resp, err := c.Stream(context.Background(), StreamRequest{
+		Provider: ProviderGoogle,
+		Model:    "gemini-test",
+		Input:    "look it up",
+		Tools:    []CoraTool{{Name: "lookup", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{"lookup": func(ctx context.Context, args map[string]any) (any, error) {
+			return nil, fmt.Errorf("backend down")
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+
+	var types []StreamEventType
+	for ev := range resp.Events {
+		types = append(types, ev.Type)
+		switch ev.Type {
+		case EventTypeToolCallStarted:
+			if ev.ToolCall == nil || ev.ToolCall.Name != "lookup" {
+				t.Errorf("started event without the tool call: %+v", ev.ToolCall)
+			}
+		case EventTypeToolCallCompleted:
+			if ev.ToolResult == nil || ev.ToolResult.Err == nil {
+				t.Errorf("completed event should carry the handler error, got %+v", ev.ToolResult)
+			}
+		}
+	}
+	want := []StreamEventType{EventTypeToolCallRequest, EventTypeToolCallStarted, EventTypeToolCallCompleted, EventTypeToolCallResult, EventTypeError}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("event types = %v, want %v", types, want)
+	}
+}
+
+func TestStreamGoogle_MaxToolRounds(t *testing.T) {
+	srv, rounds := googleStreamServer(t, func(int) map[string]any {
+		return map[string]any{"functionCall": map[string]any{"name": "lookup", "args": map[string]any{}}}
+	})
+
+	maxRounds := 2
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	resp, err := c.Stream(context.Background(), StreamRequest{
+		Provider:      ProviderGoogle,
+		Model:         "gemini-test",
+		Input:         "loop",
+		Tools:         []CoraTool{{Name: "lookup", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers:  map[string]CoraToolHandler{"lookup": func(ctx context.Context, args map[string]any) (any, error) { return "again", nil }},
+		MaxToolRounds: &maxRounds,
+	})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+
+	var streamErr error
+	for ev := range resp.Events {
+		if ev.Type == EventTypeError {
+			streamErr = ev.Err
+		}
+	}
+	if streamErr == nil || !strings.Contains(streamErr.Error(), "exceeded maximum tool call rounds (2)") {
+		t.Fatalf("expected max rounds error, got %v", streamErr)
+	}
+	if rounds.Load() != 2 {
+		t.Errorf("expected 2 streamed rounds, got %d", rounds.Load())
+	}
+}
+
+func TestBuildGoogleContents(t *testing.T) {
+	if got := buildGoogleContents("hi", nil); !reflect.DeepEqual(got, genai.Text("hi")) {
+		t.Errorf("expected genai.Text without images, got %+v", got)
+	}
+
+	png := []byte{0x89, 'P', 'N', 'G'}
+	got := buildGoogleContents("What is this?", []ImagePart{{Data: png, MIMEType: "image/png"}, {Data: []byte("jpg"), MIMEType: "image/jpeg"}})
+	if len(got) != 1 || got[0].Role != genai.RoleUser {
+		t.Fatalf("expected a single user content, got %+v", got)
+	}
+	parts := got[0].Parts
+	if len(parts) != 3 || parts[0].Text != "What is this?" {
+		t.Fatalf("expected text + 2 image parts, got %+v", parts)
+	}
+	if parts[1].InlineData == nil || parts[1].InlineData.MIMEType != "image/png" || !bytes.Equal(parts[1].InlineData.Data, png) {
+		t.Errorf("unexpected first image part %+v", parts[1].InlineData)
+	}
+	if parts[2].InlineData == nil || parts[2].InlineData.MIMEType != "image/jpeg" {
+		t.Errorf("unexpected second image part %+v", parts[2].InlineData)
+	}
+}
+
+func TestBuildGoogleHistory(t *testing.T) {
+	history := []Message{
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "hello"},
+		{Role: "user", Content: "what's new?"},
+		{Role: "assistant", Content: "not much"},
+	}
+	contents := buildGoogleHistory(history, genai.Text("bye"))
+	if len(contents) != 5 {
+		t.Fatalf("expected 5 contents for a 2-turn history plus input, got %d", len(contents))
+	}
+	for i, want := range []genai.Role{genai.RoleUser, genai.RoleModel, genai.RoleUser, genai.RoleModel, genai.RoleUser} {
+		if contents[i].Role != string(want) {
+			t.Errorf("content %d: role %q, want %q", i, contents[i].Role, want)
+		}
+	}
+	if got := contents[4].Parts[0].Text; got != "bye" {
+		t.Errorf("last content should be the input, got %q", got)
+	}
+}
+
+func TestStreamGoogle_History(t *testing.T) {
+	var roles []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Contents []struct {
+				Role string `json:"role"`
+			} `json:"contents"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, c := range body.Contents {
+			roles = append(roles, c.Role)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"candidates":[{"content":{"role":"model","parts":[{"text":"ok"}]}}]}`+"\n\n")
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	resp, err := c.Stream(context.Background(), StreamRequest{
+		Provider: ProviderGoogle,
+		Model:    "gemini-test",
+		Input:    "and now?",
+		Messages: []Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	for ev := range resp.Events {
+		if ev.Type == EventTypeError {
+			t.Fatalf("stream error: %v", ev.Err)
+		}
+	}
+	if want := []string{"user", "model", "user"}; !reflect.DeepEqual(roles, want) {
+		t.Errorf("streamed contents roles = %v, want %v", roles, want)
+	}
+}
+
+func TestStreamGoogle_TwoStepEnhance(t *testing.T) {
+	var answerInput atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		event := func(text string) []byte {
+			b, _ := json.Marshal(map[string]any{
+				"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{{"text": text}}}}},
+			})
+			return b
+		}
+		if bytes.Contains(body, []byte("writing assistant")) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(event("What is the answer?"))
+			return
+		}
+		var req struct {
+			Contents []struct {
+				Parts []struct{ Text string } `json:"parts"`
+			} `json:"contents"`
+		}
+		_ = json.Unmarshal(body, &req)
+		answerInput.Store(req.Contents[len(req.Contents)-1].Parts[0].Text)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, text := range []string{"The answer ", "is 42."} {
+			fmt.Fprintf(w, "data: %s\n\n", event(text))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer srv.Close()
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+
+	resp, err := c.Stream(context.Background(), StreamRequest{Provider: ProviderGoogle, Model: "gemini-test", Mode: ModeTwoStepEnhance, Input: "wat is teh answer"})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	var chunks []string
+	for ev := range resp.Events {
+		switch ev.Type {
+		case EventTypeChunk:
+			chunks = append(chunks, ev.Text)
+		case EventTypeError:
+			t.Fatalf("stream error: %v", ev.Err)
+		}
+	}
+	if want := []string{"The answer ", "is 42."}; !reflect.DeepEqual(chunks, want) {
+		t.Errorf("streamed chunks %q, want only the answer's %q", chunks, want)
+	}
+	if got := answerInput.Load(); got != "What is the answer?" {
+		t.Errorf("answer step got input %q, want the proofread text", got)
+	}
+}
+
+func TestStreamGoogle_PauseTimeout(t *testing.T) {
+	srv, rounds := googleStreamServer(t, func(round int) map[string]any {
+		if round == 1 {
+			return map[string]any{"functionCall": map[string]any{"name": "approve", "args": map[string]any{}}}
+		}
+		return map[string]any{"text": "Not approved in time."}
+	})
+
+	var timedOut []string
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	resp, err := c.Stream(context.Background(), StreamRequest{
+		Provider: ProviderGoogle,
+		Model:    "gemini-test",
+		Input:    "Deploy?",
+		Tools:    []CoraTool{{Name: "approve", ParametersSchema: map[string]any{"type": "object"}}},
+		StreamOptions: StreamOptions{
+			ToolExecutionMode: ToolExecutionPause,
+			PauseTimeout:      20 * time.Millisecond,
+			OnToolTimeout:     func(id string) { timedOut = append(timedOut, id) },
+		},
+	})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+
+	var text strings.Builder
+	var result *StreamToolResult
+	for ev := range resp.Events {
+		switch ev.Type {
+		case EventTypeChunk:
+			text.WriteString(ev.Text)
+		case EventTypeToolCallResult:
+			result = ev.ToolResult
+		case EventTypeError:
+			t.Fatalf("unexpected stream error: %v", ev.Err)
+		}
+	}
+	if result == nil {
+		t.Fatal("expected a tool result event")
+	}
+	if tr, ok := result.Result.(ToolTimeoutResult); !ok || tr.ToolCallID != "approve" || tr.Error == "" {
+		t.Errorf("expected a ToolTimeoutResult, got %#v", result.Result)
+	}
+	if len(timedOut) != 1 || timedOut[0] != "approve" {
+		t.Errorf("OnToolTimeout calls = %v", timedOut)
+	}
+	if rounds.Load() != 2 || text.String() != "Not approved in time." {
+		t.Errorf("expected the stream to continue, got %d rounds and %q", rounds.Load(), text.String())
+	}
+}
@@ -0,0 +1,89 @@
+package cora
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// injectedTool is a tool added to a running stream by
+// StreamResponse.InjectTool.
+type injectedTool struct {
+	tool    CoraTool
+	handler CoraToolHandler
+}
+
+// InjectTool adds a tool to a running stream, e.g. to expose a booking tool
+// once the model has asked for the weather. The model can call it from the
+// next tool round on; the round streaming now keeps its tools. It fails if
+// the stream has ended or already has a tool of that name.
+func (resp *StreamResponse) InjectTool(tool CoraTool, handler CoraToolHandler) error {
+	if resp.inject == nil {
+		return errors.New("cora: InjectTool requires a stream started by Client.Stream")
+	}
+	return resp.inject(tool, handler)
+}
+
+// injectTool stores tool for applyInjectedTools. It may be called from any
+// goroutine.
+func (so *streamOrchestrator) injectTool(tool CoraTool, handler CoraToolHandler) error {
+	if strings.TrimSpace(tool.Name) == "" || handler == nil {
+		return errors.New("cora: InjectTool requires a tool name and a handler")
+	}
+	select {
+	case <-so.ended:
+		return errors.New("cora: InjectTool: the stream has ended")
+	default:
+	}
+	if so.declared[tool.Name] {
+		return fmt.Errorf("cora: InjectTool: the stream already has a tool %q", tool.Name)
+	}
+	if _, loaded := so.injected.LoadOrStore(tool.Name, injectedTool{tool: tool, handler: handler}); loaded {
+		return fmt.Errorf("cora: InjectTool: tool %q was already injected", tool.Name)
+	}
+	return nil
+}
+
+// applyInjectedTools adds the tools injected since the last call to
+// so.req.Tools and so.req.ToolHandlers, in name order, and reports whether
+// there were any. It is called before each tool round.
+func (so *streamOrchestrator) applyInjectedTools() bool {
+	var added []injectedTool
+	so.injected.Range(func(key, value any) bool {
+		if !so.applied[key.(string)] {
+			added = append(added, value.(injectedTool))
+		}
+		return true
+	})
+	if len(added) == 0 {
+		return false
+	}
+	slices.SortFunc(added, func(a, b injectedTool) int { return strings.Compare(a.tool.Name, b.tool.Name) })
+
+	if so.applied == nil {
+		so.applied = make(map[string]bool)
+	}
+	handlers := maps.Clone(so.req.ToolHandlers)
+	if handlers == nil {
+		handlers = make(map[string]CoraToolHandler)
+	}
+	tools := slices.Clip(so.req.Tools)
+	for _, it := range added {
+		tools = append(tools, it.tool)
+		handlers[it.tool.Name] = it.handler
+		so.applied[it.tool.Name] = true
+	}
+	so.req.Tools, so.req.ToolHandlers = tools, handlers
+	return true
+}
+
+// toolNameSet returns the names of tools.
+func toolNameSet(tools []CoraTool) map[string]bool {
+	names := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		names[t.Name] = true
+	}
+	return names
+}
@@ -0,0 +1,118 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+func TestStreamInjectTool(t *testing.T) {
+	toolCall := func(id, name string) []string {
+		return []string{
+			fmt.Sprintf(`{"choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":%q,"type":"function","function":{"name":%q,"arguments":"{}"}}]}}]}`, id, name),
+			`{"choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+		}
+	}
+	var offered [][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body openai.ChatCompletionRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		var names []string
+		for _, tool := range body.Tools {
+			names = append(names, tool.Function.Name)
+		}
+		offered = append(offered, names)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		chunks := []string{`{"choices":[{"index":0,"delta":{"content":"Booked."},"finish_reason":"stop"}]}`}
+		switch len(offered) {
+		case 1:
+			chunks = toolCall("call_1", "get_weather")
+		case 2:
+			chunks = toolCall("call_2", "book_trip")
+		}
+		for _, c := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", c)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	// The handler runs on the stream's goroutine, so it gets resp through a channel.
+	started := make(chan *StreamResponse, 1)
+	var booked bool
+	bookTrip := CoraTool{Name: "book_trip", ParametersSchema: map[string]any{"type": "object"}}
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	resp, err := c.Stream(context.Background(), StreamRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Input:    "Book a trip somewhere sunny.",
+		Tools:    []CoraTool{{Name: "get_weather", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{
+			"get_weather": func(ctx context.Context, args map[string]any) (any, error) {
+				// Once the model asks for the weather, offer booking.
+				if err := (<-started).InjectTool(bookTrip, func(ctx context.Context, args map[string]any) (any, error) {
+					booked = true
+					return "confirmed", nil
+				}); err != nil {
+					t.Errorf("InjectTool error: %v", err)
+				}
+				return "sunny", nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	started <- resp
+	var text strings.Builder
+	for ev := range resp.Events {
+		if ev.Type == EventTypeError {
+			t.Fatalf("stream error: %v", ev.Err)
+		}
+		text.WriteString(ev.Text)
+	}
+
+	if text.String() != "Booked." || !booked {
+		t.Errorf("text %q, booked %v", text.String(), booked)
+	}
+	want := [][]string{{"get_weather"}, {"get_weather", "book_trip"}, {"get_weather", "book_trip"}}
+	if !slices.EqualFunc(offered, want, slices.Equal) {
+		t.Errorf("tools offered per round = %v, want %v", offered, want)
+	}
+
+	if err := resp.InjectTool(bookTrip, func(ctx context.Context, args map[string]any) (any, error) { return nil, nil }); err == nil {
+		t.Error("expected InjectTool to fail after the stream ended")
+	}
+}
+
+func TestStreamInjectTool_Conflicts(t *testing.T) {
+	so := &streamOrchestrator{ended: make(chan struct{}), declared: toolNameSet([]CoraTool{{Name: "lookup"}})}
+	h := func(ctx context.Context, args map[string]any) (any, error) { return nil, nil }
+
+	if err := so.injectTool(CoraTool{Name: "lookup"}, h); err == nil {
+		t.Error("expected an error for a declared tool")
+	}
+	if err := so.injectTool(CoraTool{Name: "search"}, h); err != nil {
+		t.Fatalf("injectTool error: %v", err)
+	}
+	if err := so.injectTool(CoraTool{Name: "search"}, h); err == nil {
+		t.Error("expected an error for a tool injected twice")
+	}
+	if err := so.injectTool(CoraTool{Name: "fetch"}, nil); err == nil {
+		t.Error("expected an error for a missing handler")
+	}
+	if !so.applyInjectedTools() || so.applyInjectedTools() {
+		t.Error("expected the injected tool to be applied exactly once")
+	}
+	if len(so.req.Tools) != 1 || so.req.ToolHandlers["search"] == nil {
+		t.Errorf("req tools = %v", so.req.Tools)
+	}
+}
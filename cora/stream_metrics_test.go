@@ -0,0 +1,48 @@
+package cora
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStream_Metrics(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	// One estimated token per chunk.
+	c.openai = openAIChunkServer(t, 50*time.Millisecond, 20*time.Millisecond, "abcd", "efgh", "ijkl", "mnop")
+
+	resp, err := c.Stream(context.Background(), StreamRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	go func() {
+		for range resp.Events {
+		}
+	}()
+
+	m := resp.Metrics()
+	if m.TotalChunks != 4 || m.TotalCharacters != 16 {
+		t.Errorf("chunks = %d, characters = %d, want 4 and 16", m.TotalChunks, m.TotalCharacters)
+	}
+	if m.TimeToFirstToken < 50*time.Millisecond || m.TimeToFirstToken >= m.Duration {
+		t.Errorf("TimeToFirstToken = %v, want at least 50ms and below Duration %v", m.TimeToFirstToken, m.Duration)
+	}
+	if m.Duration < 110*time.Millisecond {
+		t.Errorf("Duration = %v, want at least 110ms", m.Duration)
+	}
+	if want := 4 / m.Duration.Seconds(); math.Abs(m.TokensPerSecond-want) > 1e-9 {
+		t.Errorf("TokensPerSecond = %v, want %v", m.TokensPerSecond, want)
+	}
+
+	// Metrics does not block once the stream has ended.
+	if again := resp.Metrics(); again != m {
+		t.Errorf("second Metrics call = %+v, want %+v", again, m)
+	}
+}
+
+func TestStream_MetricsBareResponse(t *testing.T) {
+	if m := (&StreamResponse{}).Metrics(); m != (StreamMetrics{}) {
+		t.Errorf("Metrics of a bare StreamResponse = %+v", m)
+	}
+}
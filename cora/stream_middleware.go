@@ -0,0 +1,67 @@
+package cora
+
+import (
+	"slices"
+	"strings"
+)
+
+// StreamMiddleware sees every event of a stream before it reaches
+// StreamResponse.Events. It passes the event, possibly modified, on by
+// calling next, and swallows it by not calling next. Middleware is shared
+// by all streams of the client and must be safe for concurrent use.
+type StreamMiddleware func(event StreamEvent, next func(StreamEvent))
+
+// UseStreamMiddleware appends stream middleware to the client. The first
+// middleware registered is the outermost: it sees each event first.
+func (c *Client) UseStreamMiddleware(mw ...StreamMiddleware) {
+	c.streamMiddleware = append(c.streamMiddleware, mw...)
+}
+
+// ReplaceTextMiddleware replaces every occurrence of old with new in the
+// text of chunk events. A match split across two chunks is not replaced.
+func ReplaceTextMiddleware(old, new string) StreamMiddleware {
+	return func(event StreamEvent, next func(StreamEvent)) {
+		if event.Type == EventTypeChunk {
+			event.Text = strings.ReplaceAll(event.Text, old, new)
+		}
+		next(event)
+	}
+}
+
+// DropEventMiddleware swallows the events of the given types.
+func DropEventMiddleware(types ...StreamEventType) StreamMiddleware {
+	return func(event StreamEvent, next func(StreamEvent)) {
+		if !slices.Contains(types, event.Type) {
+			next(event)
+		}
+	}
+}
+
+// emit passes ev through the client's stream middleware and sends what
+// comes out, numbered in sending order. Sending gives up once the stream's
+// context is done, unless force is set; an event not sent takes no number,
+// so the numbers of sent events have no gaps.
+func (so *streamOrchestrator) emit(ev StreamEvent, force bool) {
+	ev.provider = so.req.Provider
+	h := func(ev StreamEvent) {
+		so.sendMu.Lock()
+		defer so.sendMu.Unlock()
+		ev.SequenceNumber = so.seq + 1
+		if force {
+			so.events <- ev
+		} else {
+			select {
+			case <-so.ctx.Done():
+				return
+			case so.events <- ev:
+			}
+		}
+		so.seq = ev.SequenceNumber
+	}
+	mws := so.client.streamMiddleware
+	for i := len(mws) - 1; i >= 0; i-- {
+		mw, next := mws[i], h
+		h = func(ev StreamEvent) { mw(ev, next) }
+	}
+	h(ev)
+}
@@ -0,0 +1,68 @@
+package cora
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStreamMiddleware_ReplaceText(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = openAIChunkServer(t, 0, 0, "what the heck ", "is ", "heck?")
+	var seen []StreamEventType
+	c.UseStreamMiddleware(
+		func(event StreamEvent, next func(StreamEvent)) {
+			seen = append(seen, event.Type)
+			next(event)
+		},
+		ReplaceTextMiddleware("heck", "h***"),
+		DropEventMiddleware(EventTypeDone),
+	)
+
+	resp, err := c.Stream(context.Background(), StreamRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "hi"})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	var text strings.Builder
+	var events []StreamEvent
+	for ev := range resp.Events {
+		events = append(events, ev)
+		text.WriteString(ev.Text)
+	}
+
+	if got := text.String(); got != "what the h*** is h***?" {
+		t.Errorf("streamed text = %q", got)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected the 3 chunks without the done event, got %+v", events)
+	}
+	for i, ev := range events {
+		if ev.Type != EventTypeChunk || ev.SequenceNumber != i+1 {
+			t.Errorf("event %d: type %v, sequence number %d", i, ev.Type, ev.SequenceNumber)
+		}
+	}
+	// The outermost middleware still saw the dropped event.
+	if len(seen) != 4 || seen[3] != EventTypeDone {
+		t.Errorf("first middleware saw %v", seen)
+	}
+}
+
+func TestStreamEmit_NoSequenceGaps(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	so := &streamOrchestrator{
+		ctx:    ctx,
+		client: &Client{},
+		events: make(chan StreamEvent, 1),
+	}
+	so.emit(StreamEvent{Type: EventTypeChunk, Text: "a"}, false)
+	cancel()
+	// The buffer is full and the stream cancelled, so this one is dropped.
+	so.emit(StreamEvent{Type: EventTypeChunk, Text: "b"}, false)
+	first := <-so.events
+	so.emit(StreamEvent{Type: EventTypeError}, true)
+	last := <-so.events
+
+	if first.SequenceNumber != 1 || last.SequenceNumber != 2 {
+		t.Errorf("sequence numbers %d and %d, want 1 and 2", first.SequenceNumber, last.SequenceNumber)
+	}
+}
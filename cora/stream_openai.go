@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	openai "github.com/sashabaranov/go-openai"
@@ -19,10 +20,10 @@ func (so *streamOrchestrator) streamOpenAI(p *openAIProvider) error {
 		})
 	}
 
-	msgs = append(msgs, openai.ChatCompletionMessage{
-		Role:    openai.ChatMessageRoleUser,
-		Content: so.req.Input,
-	})
+	for _, m := range so.req.Messages {
+		msgs = append(msgs, openai.ChatCompletionMessage{Role: messageRole(m.Role), Content: m.Content})
+	}
+	msgs = append(msgs, openAIUserMessage(so.req.Input, imageDocuments(so.req.Images)))
 
 	req := openai.ChatCompletionRequest{
 		Model:    so.model,
@@ -38,33 +39,85 @@ func (so *streamOrchestrator) streamOpenAI(p *openAIProvider) error {
 	}
 
 	// Add tools if provided
-	if len(so.req.Tools) > 0 {
-		req.Tools = make([]openai.Tool, len(so.req.Tools))
-		for i, t := range so.req.Tools {
-			req.Tools[i] = openai.Tool{
-				Type: openai.ToolTypeFunction,
-				Function: &openai.FunctionDefinition{
-					Name:        t.Name,
-					Description: t.Description,
-					Parameters:  t.ParametersSchema,
-				},
-			}
-		}
-	}
+	req.Tools = streamOpenAITools(so.req.Tools)
 
 	// Include usage if requested
 	if so.opts.IncludeUsage {
 		req.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
 	}
 
+	// Each round streams one completion; tool calls are executed and their
+	// results appended before the next round streams the model's answer.
+	maxRounds := so.maxToolRounds()
+	for round := so.firstRound(); ; round++ {
+		if round > maxRounds {
+			return fmt.Errorf("exceeded maximum tool call rounds (%d)", maxRounds)
+		}
+		so.setRound(round)
+		if so.applyInjectedTools() {
+			req.Tools = streamOpenAITools(so.req.Tools)
+		}
+		req.Messages = msgs
+		toolCalls, content, err := so.streamOpenAIRound(p, req)
+		if err != nil {
+			return err
+		}
+		if len(toolCalls) == 0 {
+			return nil
+		}
+
+		indexes := make([]int, 0, len(toolCalls))
+		for idx := range toolCalls {
+			indexes = append(indexes, idx)
+		}
+		sort.Ints(indexes)
+		calls := make([]openai.ToolCall, 0, len(toolCalls))
+		for _, idx := range indexes {
+			calls = append(calls, *toolCalls[idx])
+		}
+		msgs = append(msgs, openai.ChatCompletionMessage{
+			Role:      openai.ChatMessageRoleAssistant,
+			Content:   content,
+			ToolCalls: calls,
+		})
+		if err := so.handleOpenAIToolCalls(p, calls, &msgs); err != nil {
+			return err
+		}
+	}
+}
+
+// streamOpenAITools converts tools to OpenAI function tools (nil for none).
+func streamOpenAITools(tools []CoraTool) []openai.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	out := make([]openai.Tool, len(tools))
+	for i, t := range tools {
+		out[i] = openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.ParametersSchema,
+			},
+		}
+	}
+	return out
+}
+
+// streamOpenAIRound streams a single completion, forwarding text and usage
+// events, and returns the tool calls the model requested (if any) along with
+// the text it produced.
+func (so *streamOrchestrator) streamOpenAIRound(p *openAIProvider, req openai.ChatCompletionRequest) (map[int]*openai.ToolCall, string, error) {
 	stream, err := p.client.CreateChatCompletionStream(so.ctx, req)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 	defer stream.Close()
 
 	// Track tool calls being built incrementally
 	toolCalls := make(map[int]*openai.ToolCall)
+	var content strings.Builder
 
 	for {
 		response, err := stream.Recv()
@@ -72,10 +125,14 @@ func (so *streamOrchestrator) streamOpenAI(p *openAIProvider) error {
 			if err == io.EOF {
 				break
 			}
-			return err
+			return nil, "", err
 		}
 
 		if len(response.Choices) == 0 {
+			// Usage-only chunk (sent last when IncludeUsage is set)
+			if response.Usage != nil {
+				so.sendOpenAIUsage(response.Usage)
+			}
 			continue
 		}
 
@@ -84,63 +141,54 @@ func (so *streamOrchestrator) streamOpenAI(p *openAIProvider) error {
 
 		// Handle text chunks
 		if delta.Content != "" {
+			content.WriteString(delta.Content)
 			so.sendChunk(delta.Content)
 		}
 
 		// Handle tool calls (incremental)
-		if len(delta.ToolCalls) > 0 {
-			for _, tc := range delta.ToolCalls {
-				idx := *tc.Index
-				if _, exists := toolCalls[idx]; !exists {
-					toolCalls[idx] = &openai.ToolCall{
-						Index: tc.Index,
-						ID:    tc.ID,
-						Type:  tc.Type,
-						Function: openai.FunctionCall{
-							Name:      tc.Function.Name,
-							Arguments: tc.Function.Arguments,
-						},
-					}
-				} else {
-					// Append arguments incrementally
-					toolCalls[idx].Function.Arguments += tc.Function.Arguments
-				}
+		for _, tc := range delta.ToolCalls {
+			idx := 0
+			if tc.Index != nil {
+				idx = *tc.Index
+			}
+			if existing, ok := toolCalls[idx]; ok {
+				// Append arguments incrementally
+				existing.Function.Arguments += tc.Function.Arguments
+				continue
+			}
+			toolCalls[idx] = &openai.ToolCall{
+				Index: tc.Index,
+				ID:    tc.ID,
+				Type:  tc.Type,
+				Function: openai.FunctionCall{
+					Name:      tc.Function.Name,
+					Arguments: tc.Function.Arguments,
+				},
 			}
 		}
 
 		// Handle usage metadata
 		if response.Usage != nil {
-			so.sendUsage(&StreamUsage{
-				PromptTokens:     response.Usage.PromptTokens,
-				CompletionTokens: response.Usage.CompletionTokens,
-				TotalTokens:      response.Usage.TotalTokens,
-			})
-		}
-
-		// Check finish reason
-		if choice.FinishReason == "tool_calls" && len(toolCalls) > 0 {
-			if err := so.handleOpenAIToolCalls(p, toolCalls, &msgs); err != nil {
-				return err
-			}
-			// Reset for next round
-			toolCalls = make(map[int]*openai.ToolCall)
+			so.sendOpenAIUsage(response.Usage)
 		}
 	}
 
-	return nil
+	return toolCalls, content.String(), nil
+}
+
+func (so *streamOrchestrator) sendOpenAIUsage(u *openai.Usage) {
+	so.sendUsage(&StreamUsage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	})
 }
 
 func (so *streamOrchestrator) handleOpenAIToolCalls(
 	p *openAIProvider,
-	toolCalls map[int]*openai.ToolCall,
+	calls []openai.ToolCall,
 	msgs *[]openai.ChatCompletionMessage,
 ) error {
-	// Convert map to slice
-	calls := make([]openai.ToolCall, len(toolCalls))
-	for idx, tc := range toolCalls {
-		calls[idx] = *tc
-	}
-
 	// Parse and execute each tool call
 	for _, tc := range calls {
 		var args map[string]any
@@ -149,12 +197,13 @@ func (so *streamOrchestrator) handleOpenAIToolCalls(
 		}
 
 		// Send tool call request event
-		so.sendToolCallRequest(&StreamToolCall{
+		call := &StreamToolCall{
 			ID:           tc.ID,
 			Name:         tc.Function.Name,
 			Arguments:    args,
 			ArgumentsRaw: tc.Function.Arguments,
-		})
+		}
+		so.sendToolCallRequest(call)
 
 		// Execute tool based on mode
 		var result any
@@ -166,7 +215,7 @@ func (so *streamOrchestrator) handleOpenAIToolCalls(
 			if !ok {
 				return fmt.Errorf("no handler for tool %s", tc.Function.Name)
 			}
-			result, execErr = handler(so.ctx, args)
+			result, execErr = so.runToolHandler(call, handler)
 
 		case ToolExecutionPause:
 			result, execErr = so.waitForToolResult(tc.ID)
@@ -193,7 +242,5 @@ func (so *streamOrchestrator) handleOpenAIToolCalls(
 		})
 	}
 
-	// Continue stream with tool results
-	// (Simplified - would need to create a new stream request here)
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,48 @@
+package cora
+
+import "container/heap"
+
+// OrderedEvents returns the stream's events in SequenceNumber order. Events
+// that arrive early are held back until the ones before them have been
+// delivered; when Events closes, any held events are flushed in order.
+//
+// OrderedEvents consumes Events, so read from one or the other, not both.
+func (resp *StreamResponse) OrderedEvents() <-chan StreamEvent {
+	out := make(chan StreamEvent, cap(resp.Events))
+	go func() {
+		defer close(out)
+		var pending eventHeap
+		next := 1
+		for ev := range resp.Events {
+			if ev.SequenceNumber < next {
+				// Unnumbered, or older than what was already delivered.
+				out <- ev
+				continue
+			}
+			heap.Push(&pending, ev)
+			for len(pending) > 0 && pending[0].SequenceNumber == next {
+				out <- heap.Pop(&pending).(StreamEvent)
+				next++
+			}
+		}
+		for len(pending) > 0 {
+			out <- heap.Pop(&pending).(StreamEvent)
+		}
+	}()
+	return out
+}
+
+// eventHeap is a min-heap of stream events by SequenceNumber.
+type eventHeap []StreamEvent
+
+func (h eventHeap) Len() int           { return len(h) }
+func (h eventHeap) Less(i, j int) bool { return h[i].SequenceNumber < h[j].SequenceNumber }
+func (h eventHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *eventHeap) Push(x any)        { *h = append(*h, x.(StreamEvent)) }
+
+func (h *eventHeap) Pop() any {
+	old := *h
+	ev := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return ev
+}
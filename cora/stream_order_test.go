@@ -0,0 +1,55 @@
+package cora
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStreamResponse_OrderedEvents(t *testing.T) {
+	events := make(chan StreamEvent, 8)
+	for _, seq := range []int{3, 1, 2, 6, 4} {
+		events <- StreamEvent{Type: EventTypeChunk, SequenceNumber: seq}
+	}
+	close(events)
+
+	resp := &StreamResponse{Events: events}
+	var got []int
+	for ev := range resp.OrderedEvents() {
+		got = append(got, ev.SequenceNumber)
+	}
+	// 5 never arrives: 6 is held back until the stream closes.
+	want := []int{1, 2, 3, 4, 6}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestStream_SequenceNumbers(t *testing.T) {
+	srv, _ := googleStreamServer(t, func(int) map[string]any { return map[string]any{"text": "hi"} })
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	resp, err := c.Stream(context.Background(), StreamRequest{Provider: ProviderGoogle, Model: "gemini-test", Input: "hello"})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+
+	last := 0
+	var n int
+	for ev := range resp.OrderedEvents() {
+		n++
+		if ev.SequenceNumber != last+1 {
+			t.Errorf("event %d has sequence number %d, want %d", n, ev.SequenceNumber, last+1)
+		}
+		if ev.Timestamp.IsZero() {
+			t.Errorf("event %d has no timestamp", n)
+		}
+		last = ev.SequenceNumber
+	}
+	if n < 2 {
+		t.Errorf("expected a chunk and a done event, got %d events", n)
+	}
+}
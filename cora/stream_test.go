@@ -2,8 +2,16 @@ package cora
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	openai "github.com/sashabaranov/go-openai"
 )
 
 func TestStream_BasicChunks(t *testing.T) {
@@ -38,8 +46,193 @@ func TestStream_BasicChunks(t *testing.T) {
 }
 
 func TestStream_ToolCalls(t *testing.T) {
-	// Test will be implementation-specific
-	// Would mock tool call events in stream
+	// Fake OpenAI endpoint: the first round streams a tool call, the second
+	// round (which must carry the tool result) streams the final answer.
+	var rounds int
+	var secondRound openai.ChatCompletionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rounds++
+		w.Header().Set("Content-Type", "text/event-stream")
+		var chunks []string
+		if rounds == 1 {
+			chunks = []string{
+				`{"choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`,
+				`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":\"Paris\"}"}}]}}]}`,
+				`{"choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+			}
+		} else {
+			_ = json.NewDecoder(r.Body).Decode(&secondRound)
+			chunks = []string{
+				`{"choices":[{"index":0,"delta":{"content":"It is "}}]}`,
+				`{"choices":[{"index":0,"delta":{"content":"sunny."},"finish_reason":"stop"}]}`,
+			}
+		}
+		for _, c := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", c)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	resp, err := c.Stream(context.Background(), StreamRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Input:    "Weather in Paris?",
+		Tools:    []CoraTool{{Name: "get_weather", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{
+			"get_weather": func(ctx context.Context, args map[string]any) (any, error) {
+				return map[string]any{"city": args["city"], "forecast": "sunny"}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	defer resp.Cancel()
+
+	var types []StreamEventType
+	var text strings.Builder
+	for event := range resp.Events {
+		types = append(types, event.Type)
+		switch event.Type {
+		case EventTypeChunk:
+			text.WriteString(event.Text)
+		case EventTypeToolCallRequest:
+			if event.ToolCall.Name != "get_weather" || event.ToolCall.Arguments["city"] != "Paris" {
+				t.Errorf("unexpected tool call %+v", event.ToolCall)
+			}
+		case EventTypeError:
+			t.Fatalf("stream error: %v", event.Err)
+		}
+	}
+
+	want := []StreamEventType{EventTypeToolCallRequest, EventTypeToolCallStarted, EventTypeToolCallCompleted, EventTypeToolCallResult, EventTypeChunk, EventTypeChunk, EventTypeDone}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("event types = %v, want %v", types, want)
+	}
+	if text.String() != "It is sunny." {
+		t.Errorf("expected final answer after tool round, got %q", text.String())
+	}
+	if rounds != 2 {
+		t.Fatalf("expected 2 streaming rounds, got %d", rounds)
+	}
+
+	// Second round: user, assistant tool call, tool result.
+	msgs := secondRound.Messages
+	if len(msgs) != 3 || len(msgs[1].ToolCalls) != 1 || msgs[2].Role != openai.ChatMessageRoleTool || msgs[2].ToolCallID != "call_1" {
+		t.Errorf("unexpected second round messages: %+v", msgs)
+	}
+}
+
+func TestStream_GoogleToolCalls(t *testing.T) {
+	// Object results are sent as they are; anything else is wrapped as
+	// {"output": result}.
+	for _, tc := range []struct {
+		name   string
+		result any
+		key    string
+	}{
+		{"object", map[string]any{"forecast": "sunny"}, "forecast"},
+		{"string", "sunny", "output"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			testStreamGoogleToolCall(t, tc.result, tc.key)
+		})
+	}
+}
+
+// testStreamGoogleToolCall streams a Google tool-call round trip whose handler
+// returns result and checks that the function response carries "sunny" under
+// key.
+func testStreamGoogleToolCall(t *testing.T, result any, key string) {
+	// Fake Gemini endpoint: the first round streams a function call, the
+	// second round (which must carry the function response) streams the answer.
+	var rounds int
+	var secondRound struct {
+		Contents []struct {
+			Role  string           `json:"role"`
+			Parts []map[string]any `json:"parts"`
+		} `json:"contents"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rounds++
+		w.Header().Set("Content-Type", "text/event-stream")
+		var chunks []string
+		if rounds == 1 {
+			chunks = []string{
+				`{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"get_weather","args":{"city":"Paris"}}}]}}]}`,
+			}
+		} else {
+			_ = json.NewDecoder(r.Body).Decode(&secondRound)
+			chunks = []string{
+				`{"candidates":[{"content":{"role":"model","parts":[{"text":"It is "}]}}]}`,
+				`{"candidates":[{"content":{"role":"model","parts":[{"text":"sunny."}]},"finishReason":"STOP"}]}`,
+			}
+		}
+		for _, c := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", c)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	resp, err := c.Stream(context.Background(), StreamRequest{
+		Provider: ProviderGoogle,
+		Model:    "gemini-test",
+		Input:    "Weather in Paris?",
+		Tools:    []CoraTool{{Name: "get_weather", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{
+			"get_weather": func(ctx context.Context, args map[string]any) (any, error) {
+				if args["city"] != "Paris" {
+					t.Errorf("unexpected arguments %v", args)
+				}
+				return result, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	defer resp.Cancel()
+
+	var types []StreamEventType
+	var text strings.Builder
+	for event := range resp.Events {
+		types = append(types, event.Type)
+		switch event.Type {
+		case EventTypeChunk:
+			text.WriteString(event.Text)
+		case EventTypeToolCallRequest:
+			if event.ToolCall.Name != "get_weather" || event.ToolCall.Arguments["city"] != "Paris" {
+				t.Errorf("unexpected tool call %+v", event.ToolCall)
+			}
+		case EventTypeError:
+			t.Fatalf("stream error: %v", event.Err)
+		}
+	}
+
+	want := []StreamEventType{EventTypeToolCallRequest, EventTypeToolCallStarted, EventTypeToolCallCompleted, EventTypeToolCallResult, EventTypeChunk, EventTypeChunk, EventTypeDone}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("event types = %v, want %v", types, want)
+	}
+	if text.String() != "It is sunny." {
+		t.Errorf("expected final answer after tool round, got %q", text.String())
+	}
+	if rounds != 2 {
+		t.Fatalf("expected 2 streaming rounds, got %d", rounds)
+	}
+
+	// Second round: user, model function call, function response.
+	contents := secondRound.Contents
+	if len(contents) != 3 || contents[1].Parts[0]["functionCall"] == nil {
+		t.Fatalf("unexpected second round contents: %+v", contents)
+	}
+	fr, _ := contents[2].Parts[0]["functionResponse"].(map[string]any)
+	response, _ := fr["response"].(map[string]any)
+	if fr["name"] != "get_weather" || response[key] != "sunny" {
+		t.Errorf("unexpected function response %+v", contents[2].Parts)
+	}
 }
 
 func TestStream_Cancel(t *testing.T) {
@@ -70,4 +263,85 @@ func TestStream_Cancel(t *testing.T) {
 	if eventCount == 0 {
 		t.Error("expected some events before cancel")
 	}
-}
\ No newline at end of file
+}
+
+// openAIChunkServer serves an OpenAI chat completion stream that sends each
+// chunk as its own event, after delay and then gap apart, and returns a
+// provider talking to it.
+func openAIChunkServer(t *testing.T, delay, gap time.Duration, chunks ...string) providerClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i, chunk := range chunks {
+			if i > 0 {
+				time.Sleep(gap)
+			}
+			data, _ := json.Marshal(map[string]any{"choices": []map[string]any{
+				{"index": 0, "delta": map[string]any{"content": chunk}},
+			}})
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(srv.Close)
+	pc, err := newOpenAIProvider(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pc
+}
+
+func TestStream_FromText(t *testing.T) {
+	fp := &fakeProvider{finalOut: "Hello world"}
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = fp
+
+	// A provider without native streaming goes through streamFromText.
+	so := &streamOrchestrator{
+		ctx:    context.Background(),
+		client: c,
+		req:    StreamRequest{Provider: ProviderOpenAI, Input: "hi"},
+		events: make(chan StreamEvent, 10),
+	}
+	if err := so.streamFromText(fp); err != nil {
+		t.Fatalf("streamFromText error: %v", err)
+	}
+	close(so.events)
+
+	var chunks []string
+	for ev := range so.events {
+		chunks = append(chunks, ev.Text)
+	}
+	if !reflect.DeepEqual(chunks, []string{"Hello world"}) {
+		t.Errorf("unexpected chunks %q", chunks)
+	}
+	if plans := fp.ReceivedPlans(); len(plans) != 1 || plans[0].Input != "hi" {
+		t.Errorf("unexpected plans %+v", plans)
+	}
+}
+
+func TestStream_FakeErrorAfterN(t *testing.T) {
+	errBoom := fmt.Errorf("boom")
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = (&fakeProvider{finalOut: "ok"}).WithErrorAfterN(1, errBoom)
+
+	for i, wantErr := range []bool{false, true} {
+		resp, err := c.Stream(context.Background(), StreamRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "x"})
+		if err != nil {
+			t.Fatalf("Stream error: %v", err)
+		}
+		var gotErr error
+		for ev := range resp.Events {
+			if ev.Type == EventTypeError {
+				gotErr = ev.Err
+			}
+		}
+		if (gotErr != nil) != wantErr {
+			t.Errorf("stream %d: error = %v, want error: %v", i+1, gotErr, wantErr)
+		}
+	}
+}
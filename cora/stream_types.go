@@ -13,6 +13,20 @@ type StreamRequest struct {
 	Input  string
 	System string
 
+	// Mode is ModeBasic (the default), ModeToolCalling or ModeTwoStepEnhance.
+	// In ModeTwoStepEnhance the input is first proofread with a regular,
+	// non-streaming call, and the improved text is then streamed to the
+	// model; only the answer is sent as chunks. The answer depends on the
+	// whole proofread text, so its first chunk follows the proofread step.
+	Mode TextMode
+
+	// Messages are earlier conversation turns sent before Input, oldest
+	// first, with Role "user" or "assistant".
+	Messages []Message
+
+	// Images are sent inline with Input.
+	Images []ImagePart
+
 	// Generation parameters
 	Temperature     *float32
 	MaxOutputTokens *int
@@ -20,6 +34,13 @@ type StreamRequest struct {
 	// Tool support in streams
 	Tools        []CoraTool
 	ToolHandlers map[string]CoraToolHandler
+	// MaxToolRounds bounds the tool call rounds in the stream (default: 5).
+	MaxToolRounds *int
+
+	// MaxTokenBudget cancels the stream with ErrTokenBudgetExceeded once the
+	// completion exceeds this many tokens (0 = unlimited). Tokens are estimated
+	// from the streamed text, or taken from usage events when available.
+	MaxTokenBudget int
 
 	// Stream-specific options
 	StreamOptions StreamOptions
@@ -33,6 +54,11 @@ type StreamOptions struct {
 	// IncludeUsage requests usage metadata in the final event
 	IncludeUsage bool
 
+	// EstimateUsage reports StreamUsage.EstimatedCompletionTokens with
+	// EventTypeDone, estimated from the streamed text (about 4 characters per
+	// token). Useful when the provider reports no usage or IncludeUsage is off.
+	EstimateUsage bool
+
 	// FlushInterval sets minimum time between chunk deliveries (rate limiting)
 	FlushInterval time.Duration
 
@@ -41,8 +67,31 @@ type StreamOptions struct {
 
 	// ToolExecutionMode controls how tools are executed
 	ToolExecutionMode ToolExecutionMode
+
+	// PauseTimeout bounds how long ToolExecutionPause waits for
+	// SubmitToolResult; 0 waits until the stream is cancelled. On timeout
+	// the model receives a ToolTimeoutResult and the stream continues.
+	PauseTimeout time.Duration
+
+	// OnToolTimeout, if set, is called with the ID of each tool call whose
+	// result was not submitted within PauseTimeout.
+	OnToolTimeout func(toolCallID string)
+
+	// Checkpoint, if set, resumes a dropped stream from the state captured
+	// by StreamResponse.Checkpoint (see StreamCheckpoint).
+	Checkpoint *StreamCheckpoint
+}
+
+// ToolTimeoutResult is the tool result sent to the model when no result is
+// submitted within StreamOptions.PauseTimeout.
+type ToolTimeoutResult struct {
+	ToolCallID string `json:"tool_call_id"`
+	Error      string `json:"error"`
 }
 
+// streamMaxToolRounds is the default bound on tool call rounds in a stream.
+const streamMaxToolRounds = 5
+
 // ToolExecutionMode determines tool execution strategy during streaming.
 type ToolExecutionMode int
 
@@ -62,10 +111,10 @@ type StreamEvent struct {
 	// Text content (for EventTypeChunk)
 	Text string
 
-	// Tool call request (for EventTypeToolCallRequest)
+	// Tool call request (for EventTypeToolCallRequest and EventTypeToolCallStarted)
 	ToolCall *StreamToolCall
 
-	// Tool call result (for EventTypeToolCallResult)
+	// Tool call result (for EventTypeToolCallResult and EventTypeToolCallCompleted)
 	ToolResult *StreamToolResult
 
 	// Metadata (for EventTypeDone)
@@ -75,9 +124,14 @@ type StreamEvent struct {
 	// Error (for EventTypeError)
 	Err error
 
+	// Timestamp is when the event was produced.
+	Timestamp time.Time
+	// SequenceNumber orders the events of a stream: the first event is 1 and
+	// each later one is one higher. See StreamResponse.OrderedEvents.
+	SequenceNumber int
+
 	// Internal fields
-	provider  Provider
-	timestamp time.Time
+	provider Provider
 }
 
 // StreamEventType identifies the event kind.
@@ -96,13 +150,19 @@ const (
 	EventTypeDone
 	// EventTypeError signals an error occurred
 	EventTypeError
+	// EventTypeToolCallStarted is sent just before cora runs a tool's
+	// handler, after its EventTypeToolCallRequest
+	EventTypeToolCallStarted
+	// EventTypeToolCallCompleted is sent when the handler returns, whether
+	// or not it failed, before its EventTypeToolCallResult
+	EventTypeToolCallCompleted
 )
 
 // StreamToolCall represents a tool invocation request from the model.
 type StreamToolCall struct {
-	ID          string
-	Name        string
-	Arguments   map[string]any
+	ID           string
+	Name         string
+	Arguments    map[string]any
 	ArgumentsRaw string // Raw JSON before parsing
 }
 
@@ -119,6 +179,10 @@ type StreamUsage struct {
 	PromptTokens     int
 	CompletionTokens int
 	TotalTokens      int
+
+	// EstimatedCompletionTokens is set on EventTypeDone when
+	// StreamOptions.EstimateUsage is on (see there).
+	EstimatedCompletionTokens int
 }
 
 // StreamResponse provides control over an active stream.
@@ -128,4 +192,30 @@ type StreamResponse struct {
 
 	// SubmitToolResult manually submits a tool result (for ToolExecutionPause mode)
 	SubmitToolResult func(toolCallID string, result any) error
-}
\ No newline at end of file
+
+	// Set by the client, for CollectText.
+	provider  Provider
+	model     string
+	startedAt time.Time
+
+	// metrics blocks until the stream ends and returns its metrics.
+	metrics func() StreamMetrics
+	// checkpoint captures the stream's current state.
+	checkpoint func() StreamCheckpoint
+	// inject adds a tool to the running stream.
+	inject func(CoraTool, CoraToolHandler) error
+}
+
+// StreamMetrics measures the speed of a stream (see StreamResponse.Metrics).
+type StreamMetrics struct {
+	// TimeToFirstToken is the time from the Stream call to the first chunk;
+	// zero if no chunk was sent.
+	TimeToFirstToken time.Duration
+	// TokensPerSecond is the completion tokens, as reported by the provider
+	// or estimated from the text, over Duration.
+	TokensPerSecond float64
+	TotalChunks     int
+	TotalCharacters int
+	// Duration is the time from the Stream call to the end of the stream.
+	Duration time.Duration
+}
@@ -0,0 +1,256 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStreamingToolHandler_GoogleFunctionResponses(t *testing.T) {
+	var calls int
+	var lastParts []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var body struct {
+			Contents []struct {
+				Parts []map[string]any `json:"parts"`
+			} `json:"contents"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		lastParts = body.Contents[len(body.Contents)-1].Parts
+
+		w.Header().Set("Content-Type", "application/json")
+		part := map[string]any{"text": "Found 3 rows."}
+		if calls == 1 {
+			part = map[string]any{"functionCall": map[string]any{"name": "query_db", "args": map[string]any{"sql": "SELECT *"}}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{part}}}},
+		})
+	}))
+	defer srv.Close()
+
+	// Simulates a slow query that reports progress as rows arrive.
+	queryDB := func(ctx context.Context, args map[string]any, results chan<- any) error {
+		for i := 1; i <= 3; i++ {
+			time.Sleep(time.Millisecond)
+			results <- map[string]any{"row": i}
+		}
+		return nil
+	}
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider:          ProviderGoogle,
+		Model:             "gemini-test",
+		Mode:              ModeToolCalling,
+		Input:             "How many rows?",
+		Tools:             []CoraTool{{Name: "query_db", ParametersSchema: map[string]any{"type": "object"}}},
+		StreamingHandlers: map[string]StreamingToolHandler{"query_db": queryDB},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "Found 3 rows." {
+		t.Errorf("unexpected text %q", resp.Text)
+	}
+
+	if len(lastParts) != 3 {
+		t.Fatalf("expected 3 function response parts, got %d: %v", len(lastParts), lastParts)
+	}
+	for i, p := range lastParts {
+		fr, _ := p["functionResponse"].(map[string]any)
+		if fr["name"] != "query_db" {
+			t.Errorf("part %d: unexpected function response %v", i, p)
+		}
+		if row := fr["response"].(map[string]any)["row"]; row != float64(i+1) {
+			t.Errorf("part %d: expected row %d, got %v", i, i+1, row)
+		}
+	}
+}
+
+func TestStreamingToolHandler_OpenAIToolMessage(t *testing.T) {
+	var calls int
+	var toolContent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var body struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, m := range body.Messages {
+			if m.Role == "tool" {
+				toolContent = m.Content
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		msg := map[string]any{"role": "assistant", "content": "Found 2 rows."}
+		if calls == 1 {
+			msg = toolCallMessage("call_1", "query_db", `{"sql":"SELECT *"}`)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{{"message": msg}}})
+	}))
+	defer srv.Close()
+
+	queryDB := func(ctx context.Context, args map[string]any, results chan<- any) error {
+		for i := 1; i <= 2; i++ {
+			results <- map[string]any{"row": i}
+		}
+		return nil
+	}
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider:          ProviderOpenAI,
+		Model:             "gpt-test",
+		Mode:              ModeToolCalling,
+		Input:             "How many rows?",
+		Tools:             []CoraTool{{Name: "query_db", ParametersSchema: map[string]any{"type": "object"}}},
+		StreamingHandlers: map[string]StreamingToolHandler{"query_db": queryDB},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "Found 2 rows." {
+		t.Errorf("unexpected text %q", resp.Text)
+	}
+	if toolContent != `[{"row":1},{"row":2}]` {
+		t.Errorf("unexpected tool message content %q", toolContent)
+	}
+}
+
+func TestRunStreamingToolHandler_CapsResults(t *testing.T) {
+	h := func(ctx context.Context, args map[string]any, results chan<- any) error {
+		for i := 0; i < maxStreamingToolResults*2; i++ {
+			results <- i
+		}
+		return nil
+	}
+	partials, err := runStreamingToolHandler(context.Background(), h, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(partials) != maxStreamingToolResults {
+		t.Errorf("expected %d partials, got %d", maxStreamingToolResults, len(partials))
+	}
+}
+
+func TestFunctionResponsePayload_WrapsScalars(t *testing.T) {
+	if got := functionResponsePayload("42 rows"); got["output"] != "42 rows" {
+		t.Errorf("expected scalar to be wrapped, got %v", got)
+	}
+	if got := functionResponsePayload(map[string]any{"a": 1}); got["a"] != 1 {
+		t.Errorf("expected object to pass through, got %v", got)
+	}
+}
+
+func TestChunkedToolResult_GoogleFunctionResponses(t *testing.T) {
+	var calls int
+	var lastParts []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var body struct {
+			Contents []struct {
+				Parts []map[string]any `json:"parts"`
+			} `json:"contents"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		lastParts = body.Contents[len(body.Contents)-1].Parts
+
+		w.Header().Set("Content-Type", "application/json")
+		part := map[string]any{"text": "Scraped 3 pages."}
+		if calls == 1 {
+			part = map[string]any{"functionCall": map[string]any{"name": "scrape", "args": map[string]any{"url": "https://example.com"}}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{part}}}},
+		})
+	}))
+	defer srv.Close()
+
+	scrape := func(ctx context.Context, args map[string]any) (any, error) {
+		return ChunkedToolResult{Chunks: []any{
+			map[string]any{"page": 1}, map[string]any{"page": 2}, map[string]any{"page": 3},
+		}}, nil
+	}
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider:     ProviderGoogle,
+		Model:        "gemini-test",
+		Mode:         ModeToolCalling,
+		Input:        "Scrape the site",
+		Tools:        []CoraTool{{Name: "scrape", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{"scrape": scrape},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "Scraped 3 pages." {
+		t.Errorf("unexpected text %q", resp.Text)
+	}
+	if len(lastParts) != 3 {
+		t.Fatalf("expected 3 function response parts, got %d: %v", len(lastParts), lastParts)
+	}
+	for i, p := range lastParts {
+		fr, _ := p["functionResponse"].(map[string]any)
+		if page := fr["response"].(map[string]any)["page"]; fr["name"] != "scrape" || page != float64(i+1) {
+			t.Errorf("part %d: unexpected function response %v", i, p)
+		}
+	}
+}
+
+func TestChunkedToolResult_OpenAIToolMessage(t *testing.T) {
+	var calls int
+	var toolContent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var body struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, m := range body.Messages {
+			if m.Role == "tool" {
+				toolContent = m.Content
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		msg := map[string]any{"role": "assistant", "content": "done"}
+		if calls == 1 {
+			msg = toolCallMessage("call_1", "scrape", `{}`)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{{"message": msg}}})
+	}))
+	defer srv.Close()
+
+	scrape := func(ctx context.Context, args map[string]any) (any, error) {
+		return &ChunkedToolResult{Chunks: []any{"a", "b"}, Final: "end"}, nil
+	}
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:     ProviderOpenAI,
+		Model:        "gpt-test",
+		Mode:         ModeToolCalling,
+		Input:        "Scrape the site",
+		Tools:        []CoraTool{{Name: "scrape", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{"scrape": scrape},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if toolContent != `["a","b","end"]` {
+		t.Errorf("unexpected tool message content %q", toolContent)
+	}
+}
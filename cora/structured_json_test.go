@@ -0,0 +1,111 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var cityResponseSchema = map[string]any{
+	"type":       "object",
+	"properties": map[string]any{"city": map[string]any{"type": "string"}},
+	"required":   []any{"city"},
+}
+
+func TestStructuredJSON_OpenAISchema(t *testing.T) {
+	var formats []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ResponseFormat map[string]any `json:"response_format"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		formats = append(formats, body.ResponseFormat)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{
+			{"message": map[string]any{"role": "assistant", "content": `{"city":"Paris"}`}},
+		}})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	req := TextRequest{
+		Provider:       ProviderOpenAI,
+		Model:          "gpt-test",
+		Mode:           ModeStructuredJSON,
+		Input:          "Capital of France?",
+		ResponseSchema: cityResponseSchema,
+	}
+	resp, err := c.Text(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.JSON["city"] != "Paris" {
+		t.Errorf("JSON = %v", resp.JSON)
+	}
+	req.SchemaName = "city_answer"
+	if _, err := c.Text(context.Background(), req); err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+
+	for i, want := range []string{"cora_response", "city_answer"} {
+		f := formats[i]
+		js, _ := f["json_schema"].(map[string]any)
+		if f["type"] != "json_schema" || js["name"] != want || js["strict"] != true {
+			t.Errorf("call %d: response_format = %v, want strict json_schema named %q", i+1, f, want)
+		}
+		if schema, _ := js["schema"].(map[string]any); schema["additionalProperties"] != false {
+			t.Errorf("call %d: schema = %v, want it normalized for strict mode", i+1, schema)
+		}
+	}
+}
+
+func TestStructuredJSON_GoogleSchema(t *testing.T) {
+	var gen map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			GenerationConfig map[string]any `json:"generationConfig"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gen = body.GenerationConfig
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{{"text": `{"city":"Paris"}`}}}}},
+		})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider:       ProviderGoogle,
+		Model:          "gemini-test",
+		Mode:           ModeStructuredJSON,
+		Input:          "Capital of France?",
+		ResponseSchema: cityResponseSchema,
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.JSON["city"] != "Paris" {
+		t.Errorf("JSON = %v", resp.JSON)
+	}
+	if gen["responseMimeType"] != "application/json" {
+		t.Errorf("responseMimeType = %v", gen["responseMimeType"])
+	}
+	if schema, _ := gen["responseJsonSchema"].(map[string]any); schema["type"] != "object" {
+		t.Errorf("responseJsonSchema = %v, want the request schema", gen["responseJsonSchema"])
+	}
+}
+
+func TestStructuredJSON_InvalidSchemaName(t *testing.T) {
+	_, err := buildPlans(ProviderOpenAI, "gpt-test", TextRequest{
+		Mode:           ModeStructuredJSON,
+		ResponseSchema: cityResponseSchema,
+		SchemaName:     "city answer",
+	}, CoraConfig{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid SchemaName") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -3,65 +3,11 @@ package cora
 import (
 	"context"
 	"errors"
+	"maps"
+	"strings"
 	"testing"
-	"time"
 )
 
-// fake provider used to isolate orchestration logic unit tests (no network).
-type fakeProvider struct {
-	lastPlan callPlan
-
-	// configurable canned responses
-	proofreadOut string
-	firstOut     string
-	finalOut     string
-
-	// simulate tool call round by returning an indicator in firstOut,
-	// then finalOut on the second round
-	supportsTools bool
-}
-
-func (f *fakeProvider) Text(ctx context.Context, plan callPlan) (callResult, error) {
-	f.lastPlan = plan
-
-	// Simulate slow operations budget
-	select {
-	case <-ctx.Done():
-		return callResult{}, ctx.Err()
-	case <-time.After(1 * time.Millisecond):
-	}
-
-	if plan.Proofread {
-		return callResult{Text: coalesce(f.proofreadOut, "[proofread]")}, nil
-	}
-
-	// Tool calling: first response "requests a tool", then final answer.
-	if f.supportsTools && len(plan.Tools) > 0 && len(plan.ToolHandlers) > 0 {
-		if f.firstOut != "" {
-			// First round with "tool call"
-			out := callResult{Text: f.firstOut}
-			// mutate to simulate tool loop consumption; next call returns final
-			f.firstOut = ""
-			return out, nil
-		}
-		return callResult{Text: coalesce(f.finalOut, "final after tool")}, nil
-	}
-
-	// Structured JSON: return a JSON blob string
-	if plan.Structured && len(plan.ResponseSchema) > 0 {
-		return callResult{Text: `{"ok":true,"items":[1,2,3]}`, JSON: map[string]any{"ok": true, "items": []any{1.0, 2.0, 3.0}}}, nil
-	}
-
-	return callResult{Text: coalesce(f.finalOut, "ok")}, nil
-}
-
-func coalesce(s string, def string) string {
-	if s != "" {
-		return s
-	}
-	return def
-}
-
 func TestText_Basic_Mode(t *testing.T) {
 	c := &Client{cfg: CoraConfig{}}
 	c.openai = &fakeProvider{finalOut: "hello world"}
@@ -154,16 +100,69 @@ func TestText_ToolCalling_Mode(t *testing.T) {
 
 func TestBuildPlans_Errors(t *testing.T) {
 	cfg := CoraConfig{}
-	_, err := buildPlans(ProviderOpenAI, "gpt", TextRequest{Mode: ModeStructuredJSON}, cfg)
+	_, err := buildPlans(ProviderOpenAI, "gpt", TextRequest{Mode: ModeStructuredJSON}, cfg, nil)
 	if err == nil {
 		t.Fatal("expected error for missing ResponseSchema")
 	}
-	_, err = buildPlans(ProviderOpenAI, "gpt", TextRequest{Mode: ModeToolCalling}, cfg)
+	_, err = buildPlans(ProviderOpenAI, "gpt", TextRequest{Mode: ModeToolCalling}, cfg, nil)
 	if err == nil {
 		t.Fatal("expected error for missing Tools")
 	}
 }
 
+func TestBuildPlans_DefaultLabels(t *testing.T) {
+	defaults := map[string]string{"service": "my-service", "env": "production"}
+	cfg := CoraConfig{DefaultLabels: defaults}
+	req := TextRequest{Input: "hi", Labels: map[string]string{"env": "staging", "user": "42"}}
+
+	plans, err := buildPlans(ProviderOpenAI, "gpt", req, cfg, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	want := map[string]string{"service": "my-service", "env": "staging", "user": "42"}
+	if !maps.Equal(plans[0].Labels, want) {
+		t.Errorf("expected labels %v, got %v", want, plans[0].Labels)
+	}
+	if defaults["env"] != "production" || len(defaults) != 2 || len(req.Labels) != 2 {
+		t.Errorf("merging mutated its inputs: defaults %v, request %v", defaults, req.Labels)
+	}
+
+	plans, _ = buildPlans(ProviderOpenAI, "gpt", TextRequest{Input: "hi"}, cfg, nil)
+	if !maps.Equal(plans[0].Labels, defaults) {
+		t.Errorf("expected default labels, got %v", plans[0].Labels)
+	}
+}
+
+func TestBuildPlans_InvalidResponseSchema(t *testing.T) {
+	str := map[string]any{"type": "string"}
+	tests := []struct {
+		name   string
+		schema map[string]any
+		want   string
+	}{
+		{"missing root type", map[string]any{"properties": map[string]any{"a": str}}, `root type must be "object"`},
+		{"non-object root", map[string]any{"type": "array", "items": str}, `root type must be "object"`},
+		{"properties not an object", map[string]any{"type": "object", "properties": []any{"a"}}, "properties must be an object"},
+		{"property not an object", map[string]any{"type": "object", "properties": map[string]any{"a": "string"}}, "$.properties.a: must be an object"},
+		{"unknown property type", map[string]any{"type": "object", "properties": map[string]any{"a": map[string]any{"type": "int"}}}, `unknown type "int"`},
+		{"required not a list", map[string]any{"type": "object", "properties": map[string]any{"a": str}, "required": "a"}, "must be a list of strings"},
+		{"required entry not a string", map[string]any{"type": "object", "properties": map[string]any{"a": str}, "required": []any{1}}, "entries must be strings"},
+		{"required undeclared", map[string]any{"type": "object", "properties": map[string]any{"a": str}, "required": []string{"b"}}, `"b" is not a declared property`},
+		{"nested items type", map[string]any{"type": "object", "properties": map[string]any{"a": map[string]any{"type": "array", "items": map[string]any{"type": "text"}}}}, `$.properties.a.items: unknown type "text"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildPlans(ProviderOpenAI, "gpt", TextRequest{Mode: ModeStructuredJSON, ResponseSchema: tt.schema}, CoraConfig{}, nil)
+			if err == nil {
+				t.Fatal("expected schema validation error")
+			}
+			if !strings.Contains(err.Error(), "cora: invalid ResponseSchema") || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected %q in error, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
 func TestClient_ModelRequired(t *testing.T) {
 	c := New(CoraConfig{})
 	_, err := c.Text(context.Background(), TextRequest{
@@ -179,8 +178,8 @@ func TestClient_ModelRequired(t *testing.T) {
 func TestEnsureProvider_Unsupported(t *testing.T) {
 	c := &Client{}
 	_, err := c.ensureProvider("unknown")
-	if err == nil {
-		t.Fatal("expected error for unknown provider")
+	if err == nil || err.Error() != `cora: unknown provider "unknown"; known providers are: bedrock, cohere, google, mistral, openai` {
+		t.Fatalf("expected an unknown provider error listing the known providers, got %v", err)
 	}
 }
 
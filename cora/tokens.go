@@ -0,0 +1,120 @@
+package cora
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+// Token estimation methods reported in TokenCount.EstimationMethod.
+const (
+	// TokenEstimationExact means the count was computed with the model's tokenizer locally.
+	TokenEstimationExact = "exact"
+	// TokenEstimationAPI means the count was returned by the provider's token counting endpoint.
+	TokenEstimationAPI = "api"
+	// TokenEstimationHeuristic means the count was approximated at ~4 characters per token.
+	TokenEstimationHeuristic = "heuristic"
+)
+
+// TokenCount is a pre-flight estimate of the prompt tokens a request will use.
+type TokenCount struct {
+	PromptTokens     int
+	EstimationMethod string
+}
+
+// tokenCounter is implemented by providers that can count prompt tokens
+// without generating a response.
+type tokenCounter interface {
+	CountTokens(ctx context.Context, plan callPlan) (int, error)
+}
+
+// CountTokens estimates how many prompt tokens req would consume, without
+// generating a response. OpenAI requests are tokenized locally, Google
+// requests use the countTokens endpoint, and anything else (or any failure
+// to count exactly) falls back to a 4-characters-per-token heuristic.
+func (c *Client) CountTokens(ctx context.Context, req TextRequest) (TokenCount, error) {
+	model, err := c.resolveModel(req)
+	if err != nil {
+		return TokenCount{}, err
+	}
+	plans, err := buildPlans(req.Provider, model, req, c.cfg, c.promptOptimizer)
+	if err != nil {
+		return TokenCount{}, err
+	}
+	// The last plan carries the user's system prompt and the main request.
+	plan := plans[len(plans)-1]
+
+	if plan.Provider == ProviderOpenAI {
+		if n, ok := countOpenAITokens(plan); ok {
+			return TokenCount{PromptTokens: n, EstimationMethod: TokenEstimationExact}, nil
+		}
+	} else if pc, err := c.providerFor(plan.Provider, plan.Model); err == nil {
+		if tc, ok := pc.(tokenCounter); ok {
+			n, err := tc.CountTokens(ctx, plan)
+			if err == nil {
+				return TokenCount{PromptTokens: n, EstimationMethod: TokenEstimationAPI}, nil
+			}
+			if ctx.Err() != nil {
+				return TokenCount{}, ctx.Err()
+			}
+		}
+	}
+
+	return TokenCount{PromptTokens: estimatePlanTokens(plan), EstimationMethod: TokenEstimationHeuristic}, nil
+}
+
+// estimateTokens approximates the token count of text at ~4 characters per token.
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// estimatePlanTokens approximates the prompt tokens of a plan.
+func estimatePlanTokens(plan callPlan) int {
+	return estimateTokens(plan.System) + estimateMessagesTokens(plan.Messages) + estimateTokens(plan.Input)
+}
+
+// estimateMessagesTokens approximates the tokens of messages' contents.
+func estimateMessagesTokens(messages []Message) int {
+	n := 0
+	for _, m := range messages {
+		n += estimateTokens(m.Content)
+	}
+	return n
+}
+
+var tiktokenLoaderOnce sync.Once
+
+// countOpenAITokens tokenizes the plan's chat messages with the model's BPE
+// encoding, following OpenAI's accounting of ~3 tokens of framing per message
+// plus 3 tokens priming the reply. It reports false for unknown models.
+func countOpenAITokens(plan callPlan) (int, bool) {
+	tiktokenLoaderOnce.Do(func() {
+		// Use the embedded BPE ranks instead of downloading them at runtime.
+		tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+	})
+	enc, err := tiktoken.EncodingForModel(plan.Model)
+	if err != nil {
+		return 0, false
+	}
+
+	const tokensPerMessage, replyPriming = 3, 3
+	count := replyPriming
+	addMessage := func(role, content string) {
+		count += tokensPerMessage + len(enc.EncodeOrdinary(role)) + len(enc.EncodeOrdinary(content))
+	}
+	if strings.TrimSpace(plan.System) != "" {
+		addMessage("system", plan.System)
+	}
+	for _, m := range plan.Messages {
+		addMessage(messageRole(m.Role), m.Content)
+	}
+	// As in the request itself, an empty input is not sent after messages.
+	if plan.Input != "" || len(plan.Messages) == 0 {
+		addMessage("user", plan.Input)
+	}
+	return count, true
+}
@@ -0,0 +1,119 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCountTokens_Heuristic(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.google = &fakeProvider{} // no token counting support
+
+	count, err := c.CountTokens(context.Background(), TextRequest{
+		Provider: ProviderGoogle,
+		Model:    "gemini-test",
+		System:   "Be brief.",              // 9 chars -> 3 tokens
+		Input:    "How many tokens is it?", // 22 chars -> 6 tokens
+	})
+	if err != nil {
+		t.Fatalf("CountTokens error: %v", err)
+	}
+	if count.EstimationMethod != TokenEstimationHeuristic {
+		t.Errorf("expected heuristic estimation, got %q", count.EstimationMethod)
+	}
+	if count.PromptTokens != 9 {
+		t.Errorf("expected 9 prompt tokens, got %d", count.PromptTokens)
+	}
+}
+
+func TestCountTokens_OpenAIExact(t *testing.T) {
+	c := New(CoraConfig{}) // counting is local, no API key needed
+
+	count, err := c.CountTokens(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-4o",
+		Input:    "hello world",
+	})
+	if err != nil {
+		t.Fatalf("CountTokens error: %v", err)
+	}
+	if count.EstimationMethod != TokenEstimationExact {
+		t.Fatalf("expected exact estimation, got %q", count.EstimationMethod)
+	}
+	// "hello world" is 2 tokens, "user" is 1, plus 3 framing and 3 priming tokens.
+	if count.PromptTokens != 9 {
+		t.Errorf("expected 9 prompt tokens, got %d", count.PromptTokens)
+	}
+}
+
+func TestCountTokens_OpenAIExactMessages(t *testing.T) {
+	c := New(CoraConfig{})
+
+	count, err := c.CountTokens(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-4o",
+		Input:    "hello world",
+		Messages: []Message{{Role: "user", Content: "hello world"}},
+	})
+	if err != nil {
+		t.Fatalf("CountTokens error: %v", err)
+	}
+	// The earlier turn adds another 6 tokens to the 9 of the input alone.
+	if count.EstimationMethod != TokenEstimationExact || count.PromptTokens != 15 {
+		t.Errorf("expected an exact count of 15, got %+v", count)
+	}
+}
+
+func TestCountTokens_GoogleMessages(t *testing.T) {
+	var contents []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, ":countTokens") {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		var body struct {
+			Contents []map[string]any `json:"contents"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		contents = body.Contents
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"totalTokens": 42})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	count, err := c.CountTokens(context.Background(), TextRequest{
+		Provider: ProviderGoogle,
+		Model:    "gemini-test",
+		Input:    "And tomorrow?",
+		Messages: []Message{{Role: "user", Content: "Weather today?"}, {Role: "assistant", Content: "Sunny."}},
+	})
+	if err != nil {
+		t.Fatalf("CountTokens error: %v", err)
+	}
+	if count.EstimationMethod != TokenEstimationAPI || count.PromptTokens != 42 {
+		t.Errorf("expected the API count of 42, got %+v", count)
+	}
+	if len(contents) != 3 || contents[1]["role"] != "model" {
+		t.Errorf("expected both earlier turns and the input to be counted, got %v", contents)
+	}
+}
+
+func TestCountTokens_UnknownOpenAIModelFallsBack(t *testing.T) {
+	c := New(CoraConfig{})
+
+	count, err := c.CountTokens(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "grok-4",
+		Input:    "abcdefgh",
+	})
+	if err != nil {
+		t.Fatalf("CountTokens error: %v", err)
+	}
+	if count.EstimationMethod != TokenEstimationHeuristic || count.PromptTokens != 2 {
+		t.Errorf("expected heuristic count of 2, got %+v", count)
+	}
+}
@@ -0,0 +1,181 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Defaults of the cache of auto-completed tool arguments.
+const (
+	autoCompleteCacheTTL  = 10 * time.Minute
+	autoCompleteCacheSize = 256
+)
+
+// autoCompleteContextTurns is how many of the last conversation turns are
+// shown to the completion model.
+const autoCompleteContextTurns = 10
+
+const autoCompletePrompt = `An AI assistant called the function %q with some of its optional parameters left out.
+Choose sensible values for the missing parameters from the function's description, its schema and the arguments given.
+Reply with a JSON object holding only the missing parameters.
+
+Conversation so far:
+%s
+Description: %s
+Parameters (JSON schema): %s
+Arguments given: %s
+Missing parameters: %v`
+
+// argCompleter fills in optional tool arguments the model left out (see
+// ToolExecutor.WithAutoCompleteArgs).
+type argCompleter struct {
+	client   *Client
+	provider Provider
+	model    string
+	// cache maps a tool name and its original arguments to the completed
+	// values.
+	cache *ToolCache
+}
+
+// WithAutoCompleteArgs asks model, through client, for the optional
+// arguments missing from a tool call before running it, and merges the
+// values it suggests into the call. The model also sees the last turns of the
+// conversation that led to the call (see WithConversation). Successful completions are cached per
+// tool and arguments. A failed completion is logged and the call runs with
+// the arguments the model gave. It has no effect without WithValidator, which
+// supplies the tool schemas.
+func (te *ToolExecutor) WithAutoCompleteArgs(client *Client, provider Provider, model string) *ToolExecutor {
+	te.autoComplete = &argCompleter{
+		client:   client,
+		provider: provider,
+		model:    model,
+		cache:    NewToolCache(autoCompleteCacheTTL, autoCompleteCacheSize),
+	}
+	return te
+}
+
+// WithConversation sets the conversation that led to the tool loop, whose
+// last turns WithAutoCompleteArgs shows to the completion model. The tool
+// loops of Client.Text set it from the request's messages and input.
+func (te *ToolExecutor) WithConversation(messages []Message) *ToolExecutor {
+	te.conversation = slices.Clone(messages)
+	return te
+}
+
+// completeArgs returns call with the optional arguments missing from its
+// args added, as suggested by the completion model. call.args is not
+// modified.
+func (te *ToolExecutor) completeArgs(ctx context.Context, call toolCallRequest) toolCallRequest {
+	tool, ok := te.validator.tools[call.name]
+	if !ok || call.args == nil {
+		return call
+	}
+	missing := missingOptionalArgs(tool.ParametersSchema, call.args)
+	if len(missing) == 0 {
+		return call
+	}
+
+	ac := te.autoComplete
+	values, _, found := ac.cache.Get(call.name, call.args)
+	if !found {
+		var err error
+		values, err = ac.complete(ctx, tool, call.args, missing, te.conversation)
+		if err != nil {
+			te.log(ctx, slog.LevelWarn, "cora: auto-completing tool arguments failed",
+				slog.String("tool", call.name), slog.Any("error", err))
+			return call
+		}
+		ac.cache.Set(call.name, call.args, values, nil)
+	}
+	args := maps.Clone(call.args)
+	maps.Copy(args, values.(map[string]any))
+	call.args = args
+	return call
+}
+
+// complete asks the completion model for the missing arguments of a call to
+// tool made in conversation, and returns those it suggested.
+func (ac *argCompleter) complete(ctx context.Context, tool CoraTool, args map[string]any, missing []string, conversation []Message) (map[string]any, error) {
+	schema, err := json.Marshal(tool.ParametersSchema)
+	if err != nil {
+		return nil, fmt.Errorf("cora: encoding schema of tool %s: %w", tool.Name, err)
+	}
+	given, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("cora: encoding arguments of tool %s: %w", tool.Name, err)
+	}
+	props, _ := tool.ParametersSchema["properties"].(map[string]any)
+	missingProps := make(map[string]any, len(missing))
+	for _, name := range missing {
+		missingProps[name] = props[name]
+	}
+
+	resp, err := ac.client.Text(ctx, TextRequest{
+		Provider:       ac.provider,
+		Model:          ac.model,
+		Mode:           ModeStructuredJSON,
+		Input:          fmt.Sprintf(autoCompletePrompt, tool.Name, formatTurns(conversation), tool.Description, schema, given, missing),
+		ResponseSchema: map[string]any{"type": "object", "properties": missingProps},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cora: auto-completing arguments of tool %s: %w", tool.Name, err)
+	}
+	values := make(map[string]any)
+	for _, name := range missing {
+		if v, ok := resp.JSON[name]; ok && v != nil {
+			values[name] = v
+		}
+	}
+	return values, nil
+}
+
+// formatTurns renders the last autoCompleteContextTurns non-empty turns of
+// conversation, one "role: content" line each.
+func formatTurns(conversation []Message) string {
+	var b strings.Builder
+	var turns []Message
+	for _, m := range conversation {
+		if m.Content != "" {
+			turns = append(turns, m)
+		}
+	}
+	for _, m := range turns[max(0, len(turns)-autoCompleteContextTurns):] {
+		fmt.Fprintf(&b, "%s: %s\n", messageRole(m.Role), m.Content)
+	}
+	if b.Len() == 0 {
+		return "(none)\n"
+	}
+	return b.String()
+}
+
+// missingOptionalArgs returns the sorted names of the properties of schema
+// that are neither required nor present in args.
+func missingOptionalArgs(schema map[string]any, args map[string]any) []string {
+	props, _ := schema["properties"].(map[string]any)
+	required := make(map[string]bool)
+	switch r := schema["required"].(type) {
+	case []string:
+		for _, name := range r {
+			required[name] = true
+		}
+	case []any:
+		for _, v := range r {
+			if name, ok := v.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+	var missing []string
+	for _, name := range slices.Sorted(maps.Keys(props)) {
+		if _, ok := args[name]; !ok && !required[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
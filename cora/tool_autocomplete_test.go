@@ -0,0 +1,96 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var temperatureTool = CoraTool{
+	Name:        "temperature",
+	Description: "Returns the current temperature in a city.",
+	ParametersSchema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"city": map[string]any{"type": "string"},
+			"unit": map[string]any{"type": "string", "enum": []any{"celsius", "fahrenheit"}},
+		},
+		"required": []any{"city"},
+	},
+}
+
+func TestToolExecutor_AutoCompleteArgs(t *testing.T) {
+	fp := (&fakeProvider{}).WithJSON(map[string]any{"unit": "celsius", "city": "Rome"})
+	c := &Client{cfg: CoraConfig{}, openai: fp}
+
+	var got []map[string]any
+	te := NewToolExecutor(map[string]CoraToolHandler{
+		"temperature": func(ctx context.Context, args map[string]any) (any, error) {
+			got = append(got, args)
+			return "21", nil
+		},
+	}).WithValidator([]CoraTool{temperatureTool}).
+		WithAutoCompleteArgs(c, ProviderOpenAI, "gpt-test").
+		WithConversation([]Message{{Role: "user", Content: "I am in Europe. How warm is Paris?"}})
+
+	for range 2 {
+		call := toolCallRequest{name: "temperature", args: map[string]any{"city": "Paris"}}
+		if _, err := te.executeSingleCall(context.Background(), call); err != nil {
+			t.Fatalf("executeSingleCall error: %v", err)
+		}
+		if _, ok := call.args["unit"]; ok {
+			t.Error("expected the model's arguments to be left unchanged")
+		}
+	}
+	for i, args := range got {
+		if args["unit"] != "celsius" || args["city"] != "Paris" {
+			t.Errorf("call %d: args = %v, want the unit completed and the city kept", i+1, args)
+		}
+	}
+	plans := fp.ReceivedPlans()
+	if len(plans) != 1 {
+		t.Fatalf("expected 1 completion call (the second one cached), got %d", len(plans))
+	}
+	if !strings.Contains(plans[0].Input, "[unit]") || !plans[0].Structured ||
+		!strings.Contains(plans[0].Input, "user: I am in Europe. How warm is Paris?") {
+		t.Errorf("unexpected completion plan %+v", plans[0])
+	}
+
+	// Complete calls need no completion.
+	call := toolCallRequest{name: "temperature", args: map[string]any{"city": "Oslo", "unit": "fahrenheit"}}
+	if _, err := te.executeSingleCall(context.Background(), call); err != nil {
+		t.Fatalf("executeSingleCall error: %v", err)
+	}
+	if len(fp.ReceivedPlans()) != 1 {
+		t.Error("expected no completion call for complete arguments")
+	}
+}
+
+func TestToolExecutor_AutoCompleteArgsFailure(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}, openai: &failingProvider{err: errors.New("boom")}}
+	var got map[string]any
+	te := NewToolExecutor(map[string]CoraToolHandler{
+		"temperature": func(ctx context.Context, args map[string]any) (any, error) {
+			got = args
+			return "21", nil
+		},
+	}).WithValidator([]CoraTool{temperatureTool}).WithAutoCompleteArgs(c, ProviderOpenAI, "gpt-test")
+
+	call := toolCallRequest{name: "temperature", args: map[string]any{"city": "Paris"}}
+	if _, err := te.executeSingleCall(context.Background(), call); err != nil {
+		t.Fatalf("executeSingleCall error: %v", err)
+	}
+	if _, ok := got["unit"]; ok || got["city"] != "Paris" {
+		t.Errorf("args = %v, want the call to run with the model's arguments", got)
+	}
+
+	// Failures are not cached: the next call asks again.
+	c.openai = (&fakeProvider{}).WithJSON(map[string]any{"unit": "celsius"})
+	if _, err := te.executeSingleCall(context.Background(), call); err != nil {
+		t.Fatalf("executeSingleCall error: %v", err)
+	}
+	if got["unit"] != "celsius" {
+		t.Errorf("args = %v, want the unit completed once the model answers", got)
+	}
+}
@@ -3,6 +3,7 @@ package cora
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -194,7 +195,7 @@ func TestBuildPlans_WithToolConfiguration(t *testing.T) {
 		},
 	}
 
-	plans, err := buildPlans(ProviderGoogle, "gemini-test", req, cfg)
+	plans, err := buildPlans(ProviderGoogle, "gemini-test", req, cfg, nil)
 	if err != nil {
 		t.Fatalf("buildPlans error: %v", err)
 	}
@@ -228,18 +229,18 @@ func TestBuildPlans_WithToolConfiguration(t *testing.T) {
 
 // TestToolExecutor_ParallelExecution tests that parallel execution works correctly
 func TestToolExecutor_ParallelExecution(t *testing.T) {
-	callCount := 0
+	var callCount atomic.Int32
 	handlers := map[string]CoraToolHandler{
 		"tool1": func(ctx context.Context, args map[string]any) (any, error) {
-			callCount++
+			callCount.Add(1)
 			return "result1", nil
 		},
 		"tool2": func(ctx context.Context, args map[string]any) (any, error) {
-			callCount++
+			callCount.Add(1)
 			return "result2", nil
 		},
 		"tool3": func(ctx context.Context, args map[string]any) (any, error) {
-			callCount++
+			callCount.Add(1)
 			return "result3", nil
 		},
 	}
@@ -262,8 +263,8 @@ func TestToolExecutor_ParallelExecution(t *testing.T) {
 		t.Errorf("expected 3 results, got %d", len(results))
 	}
 
-	if callCount != 3 {
-		t.Errorf("expected 3 calls, got %d", callCount)
+	if n := callCount.Load(); n != 3 {
+		t.Errorf("expected 3 calls, got %d", n)
 	}
 
 	// Check all results are correct
@@ -337,3 +338,55 @@ func TestToolExecutor_StopOnError(t *testing.T) {
 		}
 	})
 }
+
+func TestToolCalling_FakeInjectedToolCall(t *testing.T) {
+	fp := (&fakeProvider{finalOut: "It is 21C."}).
+		InjectToolCall("get_temp", map[string]any{"city": "Rome"}).
+		WithDelay(5 * time.Millisecond)
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = fp
+
+	var gotCity any
+	start := time.Now()
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Mode:     ModeToolCalling,
+		Input:    "Temperature in Rome?",
+		Tools:    []CoraTool{{Name: "get_temp"}},
+		ToolHandlers: map[string]CoraToolHandler{
+			"get_temp": func(ctx context.Context, args map[string]any) (any, error) {
+				gotCity = args["city"]
+				return 21, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if time.Since(start) < 5*time.Millisecond {
+		t.Error("expected the configured delay to apply")
+	}
+	if gotCity != "Rome" || len(fp.toolResults) != 1 || fp.toolResults[0] != 21 {
+		t.Errorf("expected injected tool call to reach the handler, got city %v results %v", gotCity, fp.toolResults)
+	}
+	if resp.Text != "It is 21C." {
+		t.Errorf("unexpected text %q", resp.Text)
+	}
+	if plans := fp.ReceivedPlans(); len(plans) != 1 || len(plans[0].Tools) != 1 {
+		t.Errorf("unexpected plans %+v", plans)
+	}
+}
+
+func TestToolCalling_FakeJSON(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = (&fakeProvider{}).WithJSON(map[string]any{"answer": "yes"})
+
+	resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-test", Input: "?"})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.JSON["answer"] != "yes" || resp.Text != `{"answer":"yes"}` {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
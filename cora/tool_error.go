@@ -0,0 +1,46 @@
+package cora
+
+import (
+	"context"
+	"errors"
+)
+
+// ToolErrorResponse is sent to the model in place of a tool's result when the
+// tool fails and the executor does not stop on errors (StopOnToolError set to
+// false), so the model can retry, fix its arguments or use another tool.
+type ToolErrorResponse struct {
+	Error      string `json:"error"`
+	Code       string `json:"code"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Codes of ToolErrorResponse.
+const (
+	ToolErrorInvalidArguments = "invalid_arguments"
+	ToolErrorUnknownTool      = "unknown_tool"
+	ToolErrorTimeout          = "timeout"
+	ToolErrorFailed           = "tool_failed"
+)
+
+// newToolErrorResponse describes err for the model. code is the error's
+// ToolErrorResponse code if already known, or empty to derive it from err.
+func newToolErrorResponse(code string, err error) ToolErrorResponse {
+	if code == "" {
+		code = ToolErrorFailed
+		if errors.Is(err, context.DeadlineExceeded) {
+			code = ToolErrorTimeout
+		}
+	}
+	resp := ToolErrorResponse{Error: err.Error(), Code: code}
+	switch code {
+	case ToolErrorInvalidArguments:
+		resp.Suggestion = "Fix the arguments to match the tool's parameters and call it again."
+	case ToolErrorUnknownTool:
+		resp.Suggestion = "Call one of the declared tools instead."
+	case ToolErrorTimeout:
+		resp.Suggestion = "Retry once, or answer without this tool's result."
+	default:
+		resp.Suggestion = "Retry if the error looks transient, or use another tool."
+	}
+	return resp
+}
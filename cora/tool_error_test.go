@@ -0,0 +1,95 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestToolErrorResponse_SentToModel(t *testing.T) {
+	var history []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Contents []map[string]any `json:"contents"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		history = body.Contents
+		part := map[string]any{"text": "The weather service is down."}
+		if len(history) == 1 {
+			part = map[string]any{"functionCall": map[string]any{"name": "weather", "args": map[string]any{"city": "Oslo"}}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{part}}}},
+		})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	stop := false
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider:        ProviderGoogle,
+		Model:           "gemini-test",
+		Input:           "Weather in Oslo?",
+		Mode:            ModeToolCalling,
+		StopOnToolError: &stop,
+		Tools:           []CoraTool{{Name: "weather", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{
+			"weather": func(ctx context.Context, args map[string]any) (any, error) {
+				return nil, errors.New("service unavailable")
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.Text != "The weather service is down." {
+		t.Errorf("unexpected text %q", resp.Text)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 contents in the second round, got %d", len(history))
+	}
+	parts, _ := history[2]["parts"].([]any)
+	if len(parts) != 1 {
+		t.Fatalf("expected one function response, got %v", history[2])
+	}
+	fr, _ := parts[0].(map[string]any)["functionResponse"].(map[string]any)
+	got, _ := fr["response"].(map[string]any)
+	if got["error"] != "service unavailable" || got["code"] != ToolErrorFailed || got["suggestion"] == "" {
+		t.Errorf("expected a ToolErrorResponse, got %v", got)
+	}
+}
+
+func TestToolErrorResponse_Codes(t *testing.T) {
+	tools := []CoraTool{{Name: "add", ParametersSchema: map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"a": map[string]any{"type": "number"}},
+		"required":   []any{"a"},
+	}}}
+	handlers := map[string]CoraToolHandler{
+		"add": func(ctx context.Context, args map[string]any) (any, error) { return args["a"], nil },
+	}
+	executor := NewToolExecutor(handlers).WithValidator(tools).WithStopOnError(false)
+	results, err := executor.executeBatch(context.Background(), []toolCallRequest{
+		{name: "add", args: map[string]any{}},
+		{name: "missing", args: map[string]any{}},
+	})
+	if err != nil {
+		t.Fatalf("executeBatch error: %v", err)
+	}
+	for i, want := range []string{ToolErrorInvalidArguments, ToolErrorUnknownTool} {
+		got, ok := results[i].result.(ToolErrorResponse)
+		if !ok || got.Code != want {
+			t.Errorf("call %d: expected code %q, got %#v", i, want, results[i].result)
+		}
+	}
+
+	executor = NewToolExecutor(handlers)
+	results, _ = executor.executeBatch(context.Background(), []toolCallRequest{{name: "missing"}})
+	if results[0].result != nil {
+		t.Errorf("expected no error response when stopping on errors, got %v", results[0].result)
+	}
+}
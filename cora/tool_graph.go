@@ -0,0 +1,88 @@
+package cora
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ToolCallGraph records the tool calls of a tool-calling loop, grouped by
+// round. Every call in a round can depend on the results of the previous
+// round, which is how the graph's edges are drawn. It is safe for concurrent use.
+type ToolCallGraph struct {
+	mu     sync.Mutex
+	rounds []ToolCallRound
+}
+
+// ToolCallRound is one model turn's worth of tool calls.
+type ToolCallRound struct {
+	Round int              `json:"round"`
+	Calls []ToolCallRecord `json:"calls"`
+}
+
+// ToolCallRecord describes a single tool invocation.
+type ToolCallRecord struct {
+	Name     string         `json:"name"`
+	Args     map[string]any `json:"args,omitempty"`
+	Result   any            `json:"result,omitempty"`
+	Duration time.Duration  `json:"duration_ns"`
+}
+
+// AddCall records a call made in round (1-based).
+func (g *ToolCallGraph) AddCall(round int, name string, args map[string]any, result any, dur time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	rec := ToolCallRecord{Name: name, Args: args, Result: result, Duration: dur}
+	for i := range g.rounds {
+		if g.rounds[i].Round == round {
+			g.rounds[i].Calls = append(g.rounds[i].Calls, rec)
+			return
+		}
+	}
+	g.rounds = append(g.rounds, ToolCallRound{Round: round, Calls: []ToolCallRecord{rec}})
+}
+
+// Rounds returns a copy of the recorded rounds in the order they were added.
+func (g *ToolCallGraph) Rounds() []ToolCallRound {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	out := make([]ToolCallRound, len(g.rounds))
+	for i, r := range g.rounds {
+		out[i] = ToolCallRound{Round: r.Round, Calls: append([]ToolCallRecord(nil), r.Calls...)}
+	}
+	return out
+}
+
+// MarshalDOT renders the graph in Graphviz DOT format. Each call is a node
+// labeled with the tool name and duration; each call has an edge from every
+// call of the previous round, starting at an "input" node.
+func (g *ToolCallGraph) MarshalDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph tool_calls {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  input [shape=box];\n")
+
+	prev := []string{"input"}
+	for _, r := range g.Rounds() {
+		ids := make([]string, len(r.Calls))
+		for i, c := range r.Calls {
+			ids[i] = fmt.Sprintf("r%d_%d", r.Round, i)
+			fmt.Fprintf(&b, "  %s [label=%q];\n", ids[i], fmt.Sprintf("%s\n%s", c.Name, c.Duration.Round(time.Millisecond)))
+		}
+		for _, from := range prev {
+			for _, to := range ids {
+				fmt.Fprintf(&b, "  %s -> %s;\n", from, to)
+			}
+		}
+		prev = ids
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// MarshalJSON encodes the graph as its list of rounds.
+func (g *ToolCallGraph) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.Rounds())
+}
@@ -0,0 +1,81 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestToolCallGraph_TwoRounds(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		msg := map[string]any{"role": "assistant", "content": "It is sunny in Paris."}
+		switch calls {
+		case 1:
+			msg = toolCallMessage("call_1", "get_location", `{}`)
+		case 2:
+			msg = toolCallMessage("call_2", "get_weather", `{"city":"Paris"}`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{{"message": msg}}})
+	}))
+	defer srv.Close()
+
+	handler := func(ctx context.Context, args map[string]any) (any, error) {
+		return map[string]any{"ok": true}, nil
+	}
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Mode:     ModeToolCalling,
+		Input:    "Weather here?",
+		Tools: []CoraTool{
+			{Name: "get_location", ParametersSchema: map[string]any{"type": "object"}},
+			{Name: "get_weather", ParametersSchema: map[string]any{"type": "object"}},
+		},
+		ToolHandlers:    map[string]CoraToolHandler{"get_location": handler, "get_weather": handler},
+		RecordToolGraph: true,
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if resp.ToolCallGraph == nil {
+		t.Fatal("expected a ToolCallGraph")
+	}
+
+	rounds := resp.ToolCallGraph.Rounds()
+	if len(rounds) != 2 || rounds[0].Calls[0].Name != "get_location" || rounds[1].Calls[0].Name != "get_weather" {
+		t.Fatalf("unexpected rounds %+v", rounds)
+	}
+
+	dot := resp.ToolCallGraph.MarshalDOT()
+	for _, want := range []string{"digraph tool_calls", `r1_0 [label="get_location`, `r2_0 [label="get_weather`, "input -> r1_0", "r1_0 -> r2_0"} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, dot)
+		}
+	}
+
+	blob, err := json.Marshal(resp.ToolCallGraph)
+	if err != nil {
+		t.Fatalf("MarshalJSON error: %v", err)
+	}
+	if !strings.Contains(string(blob), `"name":"get_weather"`) {
+		t.Errorf("unexpected JSON %s", blob)
+	}
+}
+
+func toolCallMessage(id, name, args string) map[string]any {
+	return map[string]any{
+		"role": "assistant",
+		"tool_calls": []map[string]any{{
+			"id":       id,
+			"type":     "function",
+			"function": map[string]any{"name": name, "arguments": args},
+		}},
+	}
+}
@@ -0,0 +1,74 @@
+package cora
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ToolLoopError is returned when a multi-round tool loop stops without a
+// final answer: the round limit was hit, a tool call failed or a provider
+// call failed. Unwrap returns the cause, so errors.Is and errors.As still see
+// it.
+type ToolLoopError struct {
+	Cause    error
+	Provider string
+	Model    string
+	// Round is the 1-based round in which the loop stopped; MaxRounds is the
+	// executor's limit.
+	Round     int
+	MaxRounds int
+	// LastToolCalls names the tools the model called in the last round that
+	// requested any.
+	LastToolCalls []string
+	// ConversationTurns is the number of messages in the conversation when
+	// the loop stopped.
+	ConversationTurns int
+}
+
+func (e *ToolLoopError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "cora: %s tool loop (%s) stopped in round %d of %d", e.Provider, e.Model, e.Round, e.MaxRounds)
+	if len(e.LastToolCalls) > 0 {
+		fmt.Fprintf(&b, " after calling %s", strings.Join(e.LastToolCalls, ", "))
+	}
+	fmt.Fprintf(&b, ": %v", e.Cause)
+	return b.String()
+}
+
+func (e *ToolLoopError) Unwrap() error { return e.Cause }
+
+// AsToolLoopError reports whether err wraps a *ToolLoopError and returns it.
+func AsToolLoopError(err error) (*ToolLoopError, bool) {
+	var tle *ToolLoopError
+	if errors.As(err, &tle) {
+		return tle, true
+	}
+	return nil, false
+}
+
+// newToolLoopError wraps cause, a terminal error of a tool loop, with the
+// loop's state. round may exceed maxRounds when the limit was hit.
+func newToolLoopError(cause error, plan callPlan, round, maxRounds int, lastCalls []string, turns int) error {
+	if cause == nil {
+		return nil
+	}
+	return &ToolLoopError{
+		Cause:             cause,
+		Provider:          string(plan.Provider),
+		Model:             plan.Model,
+		Round:             min(round, maxRounds),
+		MaxRounds:         maxRounds,
+		LastToolCalls:     lastCalls,
+		ConversationTurns: turns,
+	}
+}
+
+// toolCallNames returns the name of each call.
+func toolCallNames(calls []toolCallRequest) []string {
+	names := make([]string, len(calls))
+	for i, c := range calls {
+		names[i] = c.name
+	}
+	return names
+}
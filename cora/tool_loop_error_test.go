@@ -0,0 +1,84 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestToolLoopError_MaxRounds(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{
+			{"message": toolCallMessage("call_1", "lookup", `{}`)},
+		}})
+	}))
+	defer srv.Close()
+
+	maxRounds := 2
+	tools, handlers := lookupTool()
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI, Model: "gpt-test", Input: "loop forever",
+		Mode: ModeToolCalling, Tools: tools, ToolHandlers: handlers, MaxToolRounds: &maxRounds,
+	})
+	tle, ok := AsToolLoopError(err)
+	if !ok {
+		t.Fatalf("expected a ToolLoopError, got %v", err)
+	}
+	if tle.Provider != "openai" || tle.Model != "gpt-test" || tle.Round != 2 || tle.MaxRounds != 2 {
+		t.Errorf("unexpected error %+v", tle)
+	}
+	if len(tle.LastToolCalls) != 1 || tle.LastToolCalls[0] != "lookup" {
+		t.Errorf("LastToolCalls = %v", tle.LastToolCalls)
+	}
+	// user, then assistant call and tool result for each of the 2 rounds
+	if tle.ConversationTurns != 5 {
+		t.Errorf("ConversationTurns = %d, want 5", tle.ConversationTurns)
+	}
+	if !strings.Contains(err.Error(), "exceeded maximum tool call rounds (2)") || !strings.Contains(err.Error(), "after calling lookup") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestToolLoopError_Unwrap(t *testing.T) {
+	handlerErr := errors.New("lookup service down")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{
+				{"functionCall": map[string]any{"name": "lookup", "args": map[string]any{}}},
+			}}}},
+		})
+	}))
+	defer srv.Close()
+
+	tools, _ := lookupTool()
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderGoogle, Model: "gemini-test", Input: "Look it up.", Mode: ModeToolCalling, Tools: tools,
+		ToolHandlers: map[string]CoraToolHandler{"lookup": func(ctx context.Context, args map[string]any) (any, error) {
+			return nil, handlerErr
+		}},
+	})
+	if !errors.Is(err, handlerErr) {
+		t.Fatalf("expected the handler error to unwrap, got %v", err)
+	}
+	tle, ok := AsToolLoopError(err)
+	if !ok || tle.Provider != "google" || tle.Round != 1 || tle.LastToolCalls[0] != "lookup" {
+		t.Errorf("unexpected error %+v", tle)
+	}
+
+	wrapped := fmt.Errorf("request failed: %w", tle)
+	if got, ok := AsToolLoopError(wrapped); !ok || got != tle {
+		t.Error("AsToolLoopError did not find the wrapped error")
+	}
+	if _, ok := AsToolLoopError(handlerErr); ok {
+		t.Error("AsToolLoopError matched an unrelated error")
+	}
+}
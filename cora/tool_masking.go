@@ -0,0 +1,59 @@
+package cora
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"time"
+)
+
+// redactedArg replaces the value of a sensitive tool argument.
+const redactedArg = "[REDACTED]"
+
+// MaskSensitiveArgs returns args with the values of tool.SensitiveArgs
+// replaced by "[REDACTED]". args itself is not modified; it is returned as
+// is when there is nothing to mask.
+func MaskSensitiveArgs(tool CoraTool, args map[string]any) map[string]any {
+	return maskArgs(tool.SensitiveArgs, args)
+}
+
+func maskArgs(sensitive []string, args map[string]any) map[string]any {
+	var masked map[string]any
+	for _, name := range sensitive {
+		if _, ok := args[name]; !ok {
+			continue
+		}
+		if masked == nil {
+			masked = maps.Clone(args)
+		}
+		masked[name] = redactedArg
+	}
+	if masked == nil {
+		return args
+	}
+	return masked
+}
+
+// maskArgs masks the sensitive arguments of the named tool (see
+// WithSensitiveArgs).
+func (te *ToolExecutor) maskArgs(name string, args map[string]any) map[string]any {
+	return maskArgs(te.sensitiveArgs[name], args)
+}
+
+// LoggingToolMiddleware logs each call of tool at DEBUG level with its
+// arguments, SensitiveArgs masked, and its duration and error.
+func LoggingToolMiddleware(logger *slog.Logger, tool CoraTool) ToolMiddleware {
+	return func(next CoraToolHandler) CoraToolHandler {
+		return func(ctx context.Context, args map[string]any) (any, error) {
+			start := time.Now()
+			result, err := next(ctx, args)
+			logger.LogAttrs(ctx, slog.LevelDebug, "cora: tool call",
+				slog.String("tool", tool.Name),
+				slog.Any("args", MaskSensitiveArgs(tool, args)),
+				slog.Duration("duration", time.Since(start)),
+				slog.Any("error", err),
+			)
+			return result, err
+		}
+	}
+}
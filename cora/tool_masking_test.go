@@ -0,0 +1,76 @@
+package cora
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSensitiveArgs_Masked(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		msg := map[string]any{"role": "assistant", "content": "Logged in."}
+		if calls == 1 {
+			msg = toolCallMessage("call_1", "login", `{"user":"ada","password":"hunter2"}`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{{"message": msg}}})
+	}))
+	defer srv.Close()
+
+	login := CoraTool{
+		Name:             "login",
+		ParametersSchema: map[string]any{"type": "object", "properties": map[string]any{"user": map[string]any{"type": "string"}, "password": map[string]any{"type": "string"}}},
+		SensitiveArgs:    []string{"password"},
+	}
+	var logs bytes.Buffer
+	logged := LoggingToolMiddleware(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})), login)
+	var received any
+	var events []ToolEvent
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI, Model: "gpt-test", Input: "Log in.", Mode: ModeToolCalling,
+		Tools: []CoraTool{login},
+		ToolHandlers: map[string]CoraToolHandler{"login": logged(func(ctx context.Context, args map[string]any) (any, error) {
+			received = args["password"]
+			return "ok", nil
+		})},
+		ToolObserver:    func(ctx context.Context, ev ToolEvent) { events = append(events, ev) },
+		RecordToolGraph: true,
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+
+	if received != "hunter2" {
+		t.Errorf("handler received password %v", received)
+	}
+	if len(events) != 1 || events[0].Args["password"] != "[REDACTED]" || events[0].Args["user"] != "ada" {
+		t.Errorf("observer events = %+v", events)
+	}
+	if args := resp.ToolCallGraph.Rounds()[0].Calls[0].Args; args["password"] != "[REDACTED]" {
+		t.Errorf("graph args = %v", args)
+	}
+	if out := logs.String(); strings.Contains(out, "hunter2") || !strings.Contains(out, "[REDACTED]") {
+		t.Errorf("log output = %q", out)
+	}
+}
+
+func TestMaskSensitiveArgs(t *testing.T) {
+	tool := CoraTool{Name: "connect", SensitiveArgs: []string{"api_key", "token"}}
+	args := map[string]any{"host": "db", "api_key": "secret"}
+
+	masked := MaskSensitiveArgs(tool, args)
+	if masked["api_key"] != "[REDACTED]" || masked["host"] != "db" || len(masked) != 2 {
+		t.Errorf("masked = %v", masked)
+	}
+	if args["api_key"] != "secret" {
+		t.Error("MaskSensitiveArgs modified its input")
+	}
+}
@@ -0,0 +1,45 @@
+package cora
+
+import (
+	"context"
+	"time"
+)
+
+// ToolEvent describes one executed tool call.
+type ToolEvent struct {
+	Tool     string
+	Args     map[string]any
+	Result   any
+	Err      error
+	Cached   bool
+	Duration time.Duration
+	// Round is the 1-based tool round the call belongs to.
+	Round int
+	// Labels are the request's labels (TextRequest.Labels merged with
+	// CoraConfig.DefaultLabels).
+	Labels map[string]string
+}
+
+// ToolObserver is notified after every tool call of a tool loop. With
+// ParallelTools it may be called concurrently.
+type ToolObserver func(ctx context.Context, event ToolEvent)
+
+// observe reports result to the executor's observer, if any.
+func (te *ToolExecutor) observe(ctx context.Context, call toolCallRequest, result toolCallResult) {
+	if te.observer == nil {
+		return
+	}
+	te.mu.Lock()
+	round := te.rounds
+	te.mu.Unlock()
+	te.observer(ctx, ToolEvent{
+		Tool:     call.name,
+		Args:     te.maskArgs(call.name, call.args),
+		Result:   result.result,
+		Err:      result.err,
+		Cached:   result.cached,
+		Duration: result.duration,
+		Round:    round,
+		Labels:   te.labels,
+	})
+}
@@ -0,0 +1,63 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestToolObserver_LabelsOnEveryRound(t *testing.T) {
+	var headers []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get(LabelsHeader))
+		msg := map[string]any{"role": "assistant", "content": "done"}
+		if n := len(headers); n <= 2 {
+			msg = toolCallMessage(fmt.Sprintf("call_%d", n), "lookup", `{}`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{{"message": msg}}})
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var events []ToolEvent
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:     ProviderOpenAI,
+		Model:        "gpt-test",
+		Mode:         ModeToolCalling,
+		Input:        "look it up twice",
+		Labels:       map[string]string{"request_id": "abc123"},
+		Tools:        []CoraTool{{Name: "lookup", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{"lookup": func(ctx context.Context, args map[string]any) (any, error) { return "data", nil }},
+		ToolObserver: func(ctx context.Context, ev ToolEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, ev)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 observer events, got %d", len(events))
+	}
+	for i, ev := range events {
+		if ev.Labels["request_id"] != "abc123" || ev.Round != i+1 || ev.Tool != "lookup" || ev.Result != "data" {
+			t.Errorf("event %d = %+v", i, ev)
+		}
+	}
+	if len(headers) != 3 {
+		t.Fatalf("expected 3 model calls, got %d", len(headers))
+	}
+	for i, h := range headers {
+		if h != `{"request_id":"abc123"}` {
+			t.Errorf("model call %d: %s = %q", i+1, LabelsHeader, h)
+		}
+	}
+}
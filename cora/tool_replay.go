@@ -0,0 +1,136 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ToolCallReplayer builds tool handlers that replay scripted results, for
+// testing agent logic without real tools:
+//
+//	r := cora.NewToolCallReplayer(t).
+//		Expect("get_weather", map[string]any{"city": "Paris"}).Return("sunny", nil)
+//	req.ToolHandlers = r.Build()
+//	// ... run the request ...
+//	if err := r.Verify(); err != nil { t.Fatal(err) }
+//
+// Calls must arrive in the expected order; out-of-order calls and unexpected
+// arguments are reported through tb.Error. Expect with nil args matches any
+// arguments. It is safe for concurrent use.
+type ToolCallReplayer struct {
+	tb TestReporter
+
+	mu       sync.Mutex
+	expected []expectedToolCall
+	next     int
+}
+
+type expectedToolCall struct {
+	name   string
+	args   map[string]any
+	result any
+	err    error
+}
+
+// TestReporter is the part of testing.TB that ToolCallReplayer reports
+// failures to, so that cora does not depend on package testing.
+type TestReporter interface {
+	Helper()
+	Error(args ...any)
+}
+
+// NewToolCallReplayer returns an empty replayer that reports failures to tb,
+// usually a *testing.T.
+func NewToolCallReplayer(tb TestReporter) *ToolCallReplayer {
+	return &ToolCallReplayer{tb: tb}
+}
+
+// Expect appends an expected call of the tool name with args.
+func (r *ToolCallReplayer) Expect(name string, args map[string]any) *ToolCallReplayer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expected = append(r.expected, expectedToolCall{name: name, args: args})
+	return r
+}
+
+// Return sets the result of the most recently expected call.
+func (r *ToolCallReplayer) Return(result any, err error) *ToolCallReplayer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.expected) == 0 {
+		panic("cora: ToolCallReplayer.Return called before Expect")
+	}
+	r.expected[len(r.expected)-1].result = result
+	r.expected[len(r.expected)-1].err = err
+	return r
+}
+
+// Build returns a handler for every expected tool name.
+func (r *ToolCallReplayer) Build() map[string]CoraToolHandler {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	handlers := make(map[string]CoraToolHandler)
+	for _, e := range r.expected {
+		name := e.name
+		handlers[name] = func(ctx context.Context, args map[string]any) (any, error) {
+			return r.call(name, args)
+		}
+	}
+	return handlers
+}
+
+func (r *ToolCallReplayer) call(name string, args map[string]any) (any, error) {
+	r.tb.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.next >= len(r.expected) {
+		err := fmt.Errorf("cora: unexpected call %d to tool %q: all %d expected calls were made", r.next+1, name, len(r.expected))
+		r.tb.Error(err)
+		return nil, err
+	}
+	e := r.expected[r.next]
+	if e.name != name {
+		err := fmt.Errorf("cora: call %d: expected tool %q, got %q", r.next+1, e.name, name)
+		r.tb.Error(err)
+		return nil, err
+	}
+	if e.args != nil && !sameJSON(e.args, args) {
+		err := fmt.Errorf("cora: call %d to tool %q: expected args %v, got %v", r.next+1, name, e.args, args)
+		r.tb.Error(err)
+		return nil, err
+	}
+	r.next++
+	return e.result, e.err
+}
+
+// Verify reports an error if some expected calls were not made.
+func (r *ToolCallReplayer) Verify() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.next < len(r.expected) {
+		missing := make([]string, 0, len(r.expected)-r.next)
+		for _, e := range r.expected[r.next:] {
+			missing = append(missing, e.name)
+		}
+		return fmt.Errorf("cora: %d of %d expected tool calls were not made: %v", len(missing), len(r.expected), missing)
+	}
+	return nil
+}
+
+// sameJSON compares a and b after a JSON round-trip, so that e.g. int(1)
+// matches the float64(1) a model's arguments decode to.
+func sameJSON(a, b map[string]any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	var va, vb any
+	_ = json.Unmarshal(ja, &va)
+	_ = json.Unmarshal(jb, &vb)
+	return reflect.DeepEqual(va, vb)
+}
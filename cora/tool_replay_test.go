@@ -0,0 +1,67 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestToolCallReplayer_InOrder(t *testing.T) {
+	errNotFound := errors.New("not found")
+	r := NewToolCallReplayer(t).
+		Expect("search", map[string]any{"q": "golang", "limit": 2}).Return([]string{"a", "b"}, nil).
+		Expect("fetch", map[string]any{"id": "a"}).Return("doc a", nil).
+		Expect("fetch", nil).Return(nil, errNotFound)
+
+	// A fake tool loop: one call per round, as a model would issue them.
+	executor := NewToolExecutor(r.Build()).WithStopOnError(false)
+	rounds := [][]toolCallRequest{
+		{{name: "search", args: map[string]any{"q": "golang", "limit": float64(2)}}},
+		{{name: "fetch", args: map[string]any{"id": "a"}}},
+		{{name: "fetch", args: map[string]any{"id": "b"}}},
+	}
+	var last []toolCallResult
+	for _, calls := range rounds {
+		res, err := executor.executeBatch(context.Background(), calls)
+		if err != nil {
+			t.Fatalf("executeBatch error: %v", err)
+		}
+		last = res
+	}
+
+	if !errors.Is(last[0].err, errNotFound) {
+		t.Errorf("expected scripted error from the third call, got %v", last[0].err)
+	}
+	if err := r.Verify(); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
+
+func TestToolCallReplayer_Failures(t *testing.T) {
+	rec := &recordingTB{TB: t}
+	r := NewToolCallReplayer(rec).
+		Expect("a", map[string]any{"x": 1}).Return("ok", nil).
+		Expect("b", nil).Return("ok", nil)
+	handlers := r.Build()
+
+	if _, err := handlers["b"](context.Background(), nil); err == nil {
+		t.Error("expected an out-of-order error")
+	}
+	if _, err := handlers["a"](context.Background(), map[string]any{"x": 2}); err == nil {
+		t.Error("expected an argument mismatch error")
+	}
+	if rec.errors != 2 {
+		t.Errorf("expected 2 reported failures, got %d", rec.errors)
+	}
+	if err := r.Verify(); err == nil {
+		t.Error("expected Verify to report missing calls")
+	}
+}
+
+// recordingTB counts reported errors instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors int
+}
+
+func (r *recordingTB) Error(args ...any) { r.errors++ }
@@ -0,0 +1,113 @@
+package cora
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var chartPNG = []byte{0x89, 'P', 'N', 'G'}
+
+func getChart(ctx context.Context, args map[string]any) (any, error) {
+	return ToolResultPart{Text: "Revenue by month", Image: &ImagePart{Data: chartPNG}, MIMEType: "image/png"}, nil
+}
+
+func TestToolResultPart_GoogleBlob(t *testing.T) {
+	var calls int
+	var lastParts []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var body struct {
+			Contents []struct {
+				Parts []map[string]any `json:"parts"`
+			} `json:"contents"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		lastParts = body.Contents[len(body.Contents)-1].Parts
+
+		w.Header().Set("Content-Type", "application/json")
+		part := map[string]any{"text": "Revenue peaks in June."}
+		if calls == 1 {
+			part = map[string]any{"functionCall": map[string]any{"name": "get_chart", "args": map[string]any{}}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{"content": map[string]any{"role": "model", "parts": []map[string]any{part}}}},
+		})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{GoogleAPIKey: "g-test", GoogleBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:     ProviderGoogle,
+		Model:        "gemini-test",
+		Mode:         ModeToolCalling,
+		Input:        "When does revenue peak?",
+		Tools:        []CoraTool{{Name: "get_chart", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{"get_chart": getChart},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if len(lastParts) != 2 {
+		t.Fatalf("expected function response + blob parts, got %v", lastParts)
+	}
+	fr, _ := lastParts[0]["functionResponse"].(map[string]any)
+	if fr["name"] != "get_chart" || fr["response"].(map[string]any)["output"] != "Revenue by month" {
+		t.Errorf("unexpected function response %v", lastParts[0])
+	}
+	blob, _ := lastParts[1]["inlineData"].(map[string]any)
+	if blob["mimeType"] != "image/png" || blob["data"] != base64.StdEncoding.EncodeToString(chartPNG) {
+		t.Errorf("unexpected blob part %v", lastParts[1])
+	}
+}
+
+func TestToolResultPart_OpenAIImageMessage(t *testing.T) {
+	var calls int
+	var msgs []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var body struct {
+			Messages []map[string]any `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		msgs = body.Messages
+
+		w.Header().Set("Content-Type", "application/json")
+		msg := map[string]any{"role": "assistant", "content": "June."}
+		if calls == 1 {
+			msg = toolCallMessage("call_1", "get_chart", `{}`)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{{"message": msg}}})
+	}))
+	defer srv.Close()
+
+	c := New(CoraConfig{OpenAIAPIKey: "sk-test", OpenAIBaseURL: srv.URL})
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:     ProviderOpenAI,
+		Model:        "gpt-test",
+		Mode:         ModeToolCalling,
+		Input:        "When does revenue peak?",
+		Tools:        []CoraTool{{Name: "get_chart", ParametersSchema: map[string]any{"type": "object"}}},
+		ToolHandlers: map[string]CoraToolHandler{"get_chart": getChart},
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if len(msgs) != 4 {
+		t.Fatalf("expected user, assistant, tool and image messages, got %v", msgs)
+	}
+	if msgs[2]["role"] != "tool" || msgs[2]["content"] != `"Revenue by month"` {
+		t.Errorf("unexpected tool message %v", msgs[2])
+	}
+	parts, _ := msgs[3]["content"].([]any)
+	if msgs[3]["role"] != "user" || len(parts) != 2 {
+		t.Fatalf("unexpected image message %v", msgs[3])
+	}
+	url := parts[1].(map[string]any)["image_url"].(map[string]any)["url"]
+	if want := "data:image/png;base64," + base64.StdEncoding.EncodeToString(chartPNG); url != want {
+		t.Errorf("image URL = %v, want %s", url, want)
+	}
+}
@@ -5,16 +5,34 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/rand"
+	"net/http"
 	"time"
 )
 
 // RetryConfig configures retry behavior for tool execution.
 type RetryConfig struct {
-	MaxAttempts     int
-	InitialBackoff  time.Duration
-	MaxBackoff      time.Duration
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
 	BackoffMultiplier float64
-	RetryableErrors []error // Specific errors that should trigger retry
+	RetryableErrors   []error // Specific errors that should trigger retry
+
+	// JitterFactor randomizes each backoff so that tool calls failing
+	// together do not retry in lockstep. It ranges from 0 (no jitter) to 1
+	// (uniform between 0 and the computed backoff); a factor f waits between
+	// (1-f) and 1 times the backoff.
+	JitterFactor float64
+
+	// MinDeadlineBuffer is the context time a retry needs left after its
+	// backoff. When the context's deadline is closer than the backoff plus
+	// this buffer, the handler gives up instead of sleeping into the
+	// deadline (default: 0, retry whenever the backoff fits).
+	MinDeadlineBuffer time.Duration
+
+	// OnRetry, if set, is called before each retry with the 1-based number of
+	// the attempt that failed and its error.
+	OnRetry func(attempt int, err error)
 }
 
 var DefaultRetryConfig = RetryConfig{
@@ -22,6 +40,7 @@ var DefaultRetryConfig = RetryConfig{
 	InitialBackoff:    100 * time.Millisecond,
 	MaxBackoff:        10 * time.Second,
 	BackoffMultiplier: 2.0,
+	JitterFactor:      0.2,
 }
 
 // RetryableToolHandler wraps a tool handler with retry logic.
@@ -44,8 +63,14 @@ func RetryableToolHandler(handler CoraToolHandler, config RetryConfig) CoraToolH
 
 			// Check if we have more attempts
 			if attempt < config.MaxAttempts-1 {
-				backoff := calculateBackoff(attempt, config)
-				
+				backoff := calculateBackoffWithJitter(attempt, config)
+				if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff+config.MinDeadlineBuffer {
+					return nil, fmt.Errorf("context deadline too close for retry: %w", lastErr)
+				}
+				if config.OnRetry != nil {
+					config.OnRetry(attempt+1, err)
+				}
+
 				select {
 				case <-ctx.Done():
 					return nil, ctx.Err()
@@ -61,9 +86,11 @@ func RetryableToolHandler(handler CoraToolHandler, config RetryConfig) CoraToolH
 
 func isRetryable(err error, retryableErrors []error) bool {
 	if len(retryableErrors) == 0 {
-		// Default: retry on common transient errors
-		return errors.Is(err, context.DeadlineExceeded) || 
-			   errors.Is(err, context.Canceled)
+		// Default: retry on common transient errors and rate limiting
+		var ce *CoraError
+		return errors.Is(err, context.DeadlineExceeded) ||
+			errors.Is(err, context.Canceled) ||
+			errors.As(err, &ce) && ce.HTTPStatusCode == http.StatusTooManyRequests
 	}
 
 	for _, retryableErr := range retryableErrors {
@@ -80,4 +107,15 @@ func calculateBackoff(attempt int, config RetryConfig) time.Duration {
 		backoff = float64(config.MaxBackoff)
 	}
 	return time.Duration(backoff)
-}
\ No newline at end of file
+}
+
+// calculateBackoffWithJitter is calculateBackoff reduced by a random share of
+// up to config.JitterFactor of it.
+func calculateBackoffWithJitter(attempt int, config RetryConfig) time.Duration {
+	backoff := calculateBackoff(attempt, config)
+	if config.JitterFactor <= 0 {
+		return backoff
+	}
+	jitter := min(config.JitterFactor, 1) * rand.Float64()
+	return time.Duration(float64(backoff) * (1 - jitter))
+}
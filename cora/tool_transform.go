@@ -0,0 +1,69 @@
+package cora
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ToolOutputTransformer rewrites the result of the named tool before it is
+// sent back to the model (see ToolExecutor.WithOutputTransformer).
+type ToolOutputTransformer func(name string, result any) (any, error)
+
+// TruncateJSONOutput returns a transformer that leaves results whose JSON
+// encoding fits in maxBytes unchanged and replaces larger ones with the
+// first maxBytes of their JSON, as a string ending in a truncation note.
+func TruncateJSONOutput(maxBytes int) ToolOutputTransformer {
+	return func(name string, result any) (any, error) {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) <= maxBytes {
+			return result, nil
+		}
+		cut := max(maxBytes, 0)
+		for cut > 0 && !utf8.RuneStart(b[cut]) {
+			cut--
+		}
+		return fmt.Sprintf("%s... [truncated: showing %d of %d bytes]", b[:cut], cut, len(b)), nil
+	}
+}
+
+// SelectJSONFields returns a transformer that keeps only the named
+// top-level fields of an object result, or of each object in an array
+// result. Other results are left unchanged.
+func SelectJSONFields(fields []string) ToolOutputTransformer {
+	return func(name string, result any) (any, error) {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		var decoded any
+		if err := json.Unmarshal(b, &decoded); err != nil {
+			return nil, err
+		}
+		switch v := decoded.(type) {
+		case map[string]any:
+			return selectFields(v, fields), nil
+		case []any:
+			for i, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					v[i] = selectFields(m, fields)
+				}
+			}
+			return v, nil
+		}
+		return result, nil
+	}
+}
+
+func selectFields(m map[string]any, fields []string) map[string]any {
+	out := make(map[string]any, len(fields))
+	for _, f := range fields {
+		if v, ok := m[f]; ok {
+			out[f] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package cora
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestToolExecutor_OutputTransformer(t *testing.T) {
+	rows := make([]map[string]any, 1000)
+	for i := range rows {
+		rows[i] = map[string]any{"id": i, "name": "row"}
+	}
+	te := NewToolExecutor(map[string]CoraToolHandler{
+		"query": func(ctx context.Context, args map[string]any) (any, error) { return rows, nil },
+		"small": func(ctx context.Context, args map[string]any) (any, error) { return map[string]any{"ok": true}, nil },
+	}).WithOutputTransformer(TruncateJSONOutput(100))
+
+	results, err := te.executeBatch(context.Background(), []toolCallRequest{{name: "query"}, {name: "small"}})
+	if err != nil {
+		t.Fatalf("executeBatch error: %v", err)
+	}
+	s, ok := results[0].result.(string)
+	if !ok || !strings.HasPrefix(s, `[{"id":0,`) || !strings.Contains(s, "[truncated: showing 100 of") {
+		t.Errorf("unexpected truncated result %v", results[0].result)
+	}
+	if !reflect.DeepEqual(results[1].result, map[string]any{"ok": true}) {
+		t.Errorf("small result should be unchanged, got %v", results[1].result)
+	}
+}
+
+func TestSelectJSONFields(t *testing.T) {
+	sel := SelectJSONFields([]string{"id", "email"})
+	got, err := sel("users", []map[string]any{{"id": 1, "email": "a@x", "password_hash": "..."}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []any{map[string]any{"id": float64(1), "email": "a@x"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
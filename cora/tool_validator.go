@@ -1,8 +1,11 @@
 package cora
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 // ToolValidator validates tool call arguments against the tool's schema.
@@ -14,6 +17,13 @@ type ToolValidator struct {
 func NewToolValidator(tools []CoraTool) *ToolValidator {
 	toolMap := make(map[string]CoraTool)
 	for _, t := range tools {
+		// Inline local $defs references so validation sees the referenced types.
+		if defs, ok := t.ParametersSchema["$defs"].(map[string]any); ok {
+			t.ParametersSchema = resolveRef(t.ParametersSchema, defs)
+		}
+		if defs, ok := t.ResultSchema["$defs"].(map[string]any); ok {
+			t.ResultSchema = resolveRef(t.ResultSchema, defs)
+		}
 		toolMap[t.Name] = t
 	}
 	return &ToolValidator{tools: toolMap}
@@ -29,12 +39,59 @@ func (tv *ToolValidator) ValidateCall(name string, args map[string]any) error {
 	if len(tool.ParametersSchema) == 0 {
 		return nil // No schema to validate against
 	}
+	return validateObject(tool.ParametersSchema, args)
+}
+
+// ValidateResult checks a handler's result against the tool's ResultSchema.
+// Tools without a ResultSchema accept any result.
+func (tv *ToolValidator) ValidateResult(name string, result any) error {
+	tool, exists := tv.tools[name]
+	if !exists {
+		return fmt.Errorf("unknown tool: %s", name)
+	}
+
+	if len(tool.ResultSchema) == 0 {
+		return nil
+	}
+	return checkResult(tool.ResultSchema, result)
+}
+
+// checkResult validates result against schema as the model will see it,
+// i.e. after a round trip through JSON.
+func checkResult(schema map[string]any, result any) error {
+	var decoded any
+	b, err := json.Marshal(result)
+	if err == nil {
+		err = json.Unmarshal(b, &decoded)
+	}
+	if err != nil {
+		return err
+	}
+	return validateResult(schema, decoded)
+}
+
+// validateResult checks a tool result, JSON-decoded into value, against
+// schema: its type and, for objects, the required and declared properties.
+func validateResult(schema map[string]any, value any) error {
+	if err := validateType("result", value, schema); err != nil {
+		return err
+	}
+	if m, ok := value.(map[string]any); ok {
+		if err := validateObject(schema, m); err != nil {
+			return fmt.Errorf("result: %w", err)
+		}
+	}
+	return nil
+}
 
+// validateObject checks args against an object schema's required fields and
+// property types.
+func validateObject(schema map[string]any, args map[string]any) error {
 	// Validate required fields
-	required, ok := tool.ParametersSchema["required"].([]string)
+	required, ok := schema["required"].([]string)
 	if !ok {
 		// Try []any (from JSON unmarshal)
-		if reqAny, ok := tool.ParametersSchema["required"].([]any); ok {
+		if reqAny, ok := schema["required"].([]any); ok {
 			required = make([]string, len(reqAny))
 			for i, v := range reqAny {
 				if s, ok := v.(string); ok {
@@ -51,7 +108,7 @@ func (tv *ToolValidator) ValidateCall(name string, args map[string]any) error {
 	}
 
 	// Validate types of provided arguments
-	properties, ok := tool.ParametersSchema["properties"].(map[string]any)
+	properties, ok := schema["properties"].(map[string]any)
 	if !ok {
 		return nil // No property definitions
 	}
@@ -67,20 +124,154 @@ func (tv *ToolValidator) ValidateCall(name string, args map[string]any) error {
 			continue
 		}
 
-		expectedType, ok := propMap["type"].(string)
+		if err := validateType(argName, argValue, propMap); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// argCoercion records one argument converted by coerceCall.
+type argCoercion struct {
+	param    string
+	from, to any
+}
+
+// coerceCall converts, in place, arguments whose value does not match the
+// primitive type declared in the tool's schema when a safe conversion exists:
+// numeric strings to numbers, numbers to strings and "true"/"false" to
+// booleans. It returns the conversions it made.
+func (tv *ToolValidator) coerceCall(name string, args map[string]any) []argCoercion {
+	tool, exists := tv.tools[name]
+	if !exists {
+		return nil
+	}
+	properties, ok := tool.ParametersSchema["properties"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var coerced []argCoercion
+	for argName, argValue := range args {
+		propMap, ok := properties[argName].(map[string]any)
 		if !ok {
 			continue
 		}
+		expectedType, _ := propMap["type"].(string)
+		if v, ok := coerceValue(argValue, expectedType); ok {
+			args[argName] = v
+			coerced = append(coerced, argCoercion{param: argName, from: argValue, to: v})
+		}
+	}
+	return coerced
+}
 
-		if err := validateType(argName, argValue, expectedType); err != nil {
+// coerceValue converts value to expectedType, reporting false when value
+// already matches or cannot be converted safely.
+func coerceValue(value any, expectedType string) (any, bool) {
+	switch expectedType {
+	case "number", "integer":
+		if s, ok := value.(string); ok {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+				return f, true
+			}
+		}
+	case "string":
+		switch value.(type) {
+		case float64, float32, int, int64:
+			return fmt.Sprintf("%v", value), true
+		}
+	case "boolean":
+		switch value {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	return nil, false
+}
+
+// validateType checks value against the composition keywords (allOf, anyOf,
+// oneOf) and the "type" of the given property schema.
+func validateType(name string, value any, schema map[string]any) error {
+	if subs, ok := schemaList(schema["allOf"]); ok {
+		if err := validateAllOf(name, value, subs); err != nil {
+			return err
+		}
+	}
+	if subs, ok := schemaList(schema["anyOf"]); ok {
+		if err := validateAnyOf(name, value, subs); err != nil {
+			return err
+		}
+	}
+	if subs, ok := schemaList(schema["oneOf"]); ok {
+		if err := validateOneOf(name, value, subs); err != nil {
 			return err
 		}
 	}
 
+	expectedType, ok := schema["type"].(string)
+	if !ok {
+		return nil
+	}
+	return validatePrimitiveType(name, value, expectedType)
+}
+
+// validateAllOf requires value to match every sub-schema.
+func validateAllOf(name string, value any, subs []map[string]any) error {
+	for i, sub := range subs {
+		if err := validateType(name, value, sub); err != nil {
+			return fmt.Errorf("parameter %s: allOf schema %d not satisfied: %w", name, i, err)
+		}
+	}
 	return nil
 }
 
-func validateType(name string, value any, expectedType string) error {
+// validateAnyOf requires value to match at least one sub-schema.
+func validateAnyOf(name string, value any, subs []map[string]any) error {
+	for _, sub := range subs {
+		if validateType(name, value, sub) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("parameter %s: value does not match any anyOf schema", name)
+}
+
+// validateOneOf requires value to match exactly one sub-schema.
+func validateOneOf(name string, value any, subs []map[string]any) error {
+	matches := 0
+	for _, sub := range subs {
+		if validateType(name, value, sub) == nil {
+			matches++
+		}
+	}
+	if matches != 1 {
+		return fmt.Errorf("parameter %s: expected value to match exactly one oneOf schema, matched %d", name, matches)
+	}
+	return nil
+}
+
+// schemaList extracts the sub-schemas of a composition keyword.
+func schemaList(v any) ([]map[string]any, bool) {
+	switch t := v.(type) {
+	case []map[string]any:
+		return t, true
+	case []any:
+		out := make([]map[string]any, 0, len(t))
+		for _, item := range t {
+			if m, ok := item.(map[string]any); ok {
+				out = append(out, m)
+			}
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+func validatePrimitiveType(name string, value any, expectedType string) error {
 	if value == nil {
 		return nil // Null values pass (handled by required check)
 	}
@@ -120,4 +311,55 @@ func validateType(name string, value any, expectedType string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// resolveRef returns a copy of schema where every {"$ref": "#/$defs/Name"} is
+// replaced by the referenced schema from defs. Unknown and circular references
+// are left unresolved.
+func resolveRef(schema map[string]any, defs map[string]any) map[string]any {
+	return resolveRefVisited(schema, defs, make(map[string]bool))
+}
+
+func resolveRefVisited(schema map[string]any, defs map[string]any, visited map[string]bool) map[string]any {
+	if ref, ok := schema["$ref"].(string); ok {
+		name, local := strings.CutPrefix(ref, "#/$defs/")
+		if !local || visited[ref] {
+			return schema // external ref or cycle detected
+		}
+		def, ok := defs[name].(map[string]any)
+		if !ok {
+			return schema
+		}
+		visited[ref] = true
+		resolved := resolveRefVisited(def, defs, visited)
+		delete(visited, ref)
+		return resolved
+	}
+
+	out := make(map[string]any, len(schema))
+	for k, v := range schema {
+		out[k] = resolveRefValue(v, defs, visited)
+	}
+	return out
+}
+
+func resolveRefValue(v any, defs map[string]any, visited map[string]bool) any {
+	switch t := v.(type) {
+	case map[string]any:
+		return resolveRefVisited(t, defs, visited)
+	case []any:
+		out := make([]any, len(t))
+		for i, item := range t {
+			out[i] = resolveRefValue(item, defs, visited)
+		}
+		return out
+	case []map[string]any:
+		out := make([]map[string]any, len(t))
+		for i, item := range t {
+			out[i] = resolveRefVisited(item, defs, visited)
+		}
+		return out
+	default:
+		return v
+	}
+}
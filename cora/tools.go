@@ -27,10 +27,23 @@ func NewToolBuilder() *ToolBuilder {
 // The function signature should be: func(ctx context.Context, params YourStructType) (result any, err error)
 // The params struct's fields and tags are used to generate the JSON schema.
 func (tb *ToolBuilder) AddFunc(name, description string, handlerFunc any) error {
+	return tb.AddFuncWithMiddleware(name, description, handlerFunc)
+}
+
+// ToolMiddleware wraps a tool handler, e.g. to log or meter its calls.
+type ToolMiddleware func(next CoraToolHandler) CoraToolHandler
+
+// AddFuncWithMiddleware is AddFunc with the tool's handler wrapped in
+// middleware, which then applies to this tool only. The first middleware is
+// the outermost: mw[0](mw[1](...(handler))).
+func (tb *ToolBuilder) AddFuncWithMiddleware(name, description string, handlerFunc any, mw ...ToolMiddleware) error {
 	handler, schema, err := wrapFunction(handlerFunc)
 	if err != nil {
 		return fmt.Errorf("failed to wrap function %s: %w", name, err)
 	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
 
 	tb.tools = append(tb.tools, CoraTool{
 		Name:             name,
@@ -47,45 +60,109 @@ func (tb *ToolBuilder) AddTool(tool CoraTool, handler CoraToolHandler) {
 	tb.handlers[tool.Name] = handler
 }
 
+// AddBuiltinComputerTool registers Anthropic's computer-use tool for a display
+// of the given size. The handler receives the "action" and its arguments
+// (e.g. "coordinate", "text") in the args map.
+func (tb *ToolBuilder) AddBuiltinComputerTool(displayWidthPx, displayHeightPx int, handler CoraToolHandler) {
+	tb.AddTool(CoraTool{
+		Name:        "computer",
+		BuiltinType: BuiltinToolComputer,
+		BuiltinOptions: map[string]any{
+			"display_width_px":  displayWidthPx,
+			"display_height_px": displayHeightPx,
+		},
+	}, handler)
+}
+
+// AddBuiltinBashTool registers Anthropic's bash tool. The handler receives the
+// "command" (or "restart") argument in the args map.
+func (tb *ToolBuilder) AddBuiltinBashTool(handler CoraToolHandler) {
+	tb.AddTool(CoraTool{Name: "bash", BuiltinType: BuiltinToolBash}, handler)
+}
+
+// AddBuiltinTextEditorTool registers Anthropic's text editor tool. The handler
+// receives the editor "command" (view, create, str_replace, ...) and its
+// arguments in the args map.
+func (tb *ToolBuilder) AddBuiltinTextEditorTool(handler CoraToolHandler) {
+	tb.AddTool(CoraTool{Name: "str_replace_editor", BuiltinType: BuiltinToolTextEditor}, handler)
+}
+
 // Build returns the finalized tools and handlers for use in a TextRequest.
 func (tb *ToolBuilder) Build() ([]CoraTool, map[string]CoraToolHandler) {
 	return tb.tools, tb.handlers
 }
 
-// wrapFunction inspects a Go function and generates a tool handler + JSON schema.
-// Expected signature: func(ctx context.Context, input T) (output any, err error)
-func wrapFunction(fn any) (CoraToolHandler, map[string]any, error) {
-	fnVal := reflect.ValueOf(fn)
-	fnType := fnVal.Type()
+// SchemaForFunc returns the JSON schema AddFunc would generate for fn's
+// parameter struct, without creating a handler. fn must have the signature
+// func(ctx context.Context, input T) (output any, err error).
+func SchemaForFunc(fn any) (map[string]any, error) {
+	paramsType, err := funcParamsType(fn)
+	if err != nil {
+		return nil, err
+	}
+	return generateSchemaFromStruct(paramsType)
+}
+
+// MustSchemaForFunc is like SchemaForFunc but panics if fn has an invalid signature.
+func MustSchemaForFunc(fn any) map[string]any {
+	schema, err := SchemaForFunc(fn)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// SchemaForType returns the JSON schema for T using the same rules as AddFunc
+// (json and description tags). Structs produce an "object" schema; other
+// types map to their JSON schema primitive.
+func SchemaForType[T any]() map[string]any {
+	return typeToSchema(reflect.TypeFor[T]())
+}
+
+// funcParamsType validates a tool function's signature and returns the type
+// of its parameter struct.
+func funcParamsType(fn any) (reflect.Type, error) {
+	fnType := reflect.TypeOf(fn)
 
 	// Validate function signature
-	if fnType.Kind() != reflect.Func {
-		return nil, nil, errors.New("handler must be a function")
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return nil, errors.New("handler must be a function")
 	}
 	if fnType.NumIn() != 2 {
-		return nil, nil, errors.New("function must have exactly 2 parameters: (context.Context, ParamsStruct)")
+		return nil, errors.New("function must have exactly 2 parameters: (context.Context, ParamsStruct)")
 	}
 	if fnType.NumOut() != 2 {
-		return nil, nil, errors.New("function must return exactly 2 values: (result any, error)")
+		return nil, errors.New("function must return exactly 2 values: (result any, error)")
 	}
 
 	// Check context.Context as first param
 	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
 	if !fnType.In(0).Implements(ctxType) {
-		return nil, nil, errors.New("first parameter must be context.Context")
+		return nil, errors.New("first parameter must be context.Context")
 	}
 
 	// Check error as second return
 	errType := reflect.TypeOf((*error)(nil)).Elem()
 	if !fnType.Out(1).Implements(errType) {
-		return nil, nil, errors.New("second return value must be error")
+		return nil, errors.New("second return value must be error")
 	}
 
 	// Extract parameter struct type
 	paramsType := fnType.In(1)
 	if paramsType.Kind() != reflect.Struct {
-		return nil, nil, errors.New("second parameter must be a struct")
+		return nil, errors.New("second parameter must be a struct")
 	}
+	return paramsType, nil
+}
+
+// wrapFunction inspects a Go function and generates a tool handler + JSON schema.
+// Expected signature: func(ctx context.Context, input T) (output any, err error)
+func wrapFunction(fn any) (CoraToolHandler, map[string]any, error) {
+	paramsType, err := funcParamsType(fn)
+	if err != nil {
+		return nil, nil, err
+	}
+	fnVal := reflect.ValueOf(fn)
 
 	// Generate JSON schema from struct
 	schema, err := generateSchemaFromStruct(paramsType)
@@ -145,23 +222,11 @@ func generateSchemaFromStruct(t reflect.Type) (map[string]any, error) {
 			continue
 		}
 
-		// Extract JSON tag name
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "-" {
+		fieldName, omitempty, ok := jsonFieldName(field)
+		if !ok {
 			continue // Skip fields with json:"-"
 		}
-		fieldName := field.Name
-		if jsonTag != "" {
-			parts := strings.Split(jsonTag, ",")
-			if parts[0] != "" {
-				fieldName = parts[0]
-			}
-			// Check for omitempty
-			if !contains(parts, "omitempty") {
-				required = append(required, fieldName)
-			}
-		} else {
-			// No json tag, field is required by default
+		if !omitempty {
 			required = append(required, fieldName)
 		}
 
@@ -188,6 +253,21 @@ func generateSchemaFromStruct(t reflect.Type) (map[string]any, error) {
 	return schema, nil
 }
 
+// jsonFieldName returns the JSON name of a struct field and whether it is
+// omitempty; ok is false for fields tagged json:"-".
+func jsonFieldName(field reflect.StructField) (name string, omitempty, ok bool) {
+	jsonTag := field.Tag.Get("json")
+	if jsonTag == "-" {
+		return "", false, false
+	}
+	name = field.Name
+	parts := strings.Split(jsonTag, ",")
+	if parts[0] != "" {
+		name = parts[0]
+	}
+	return name, contains(parts[1:], "omitempty"), true
+}
+
 // typeToSchema maps a Go reflect.Type to a JSON schema primitive.
 func typeToSchema(t reflect.Type) map[string]any {
 	schema := make(map[string]any)
@@ -231,4 +311,4 @@ func contains(slice []string, item string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
@@ -0,0 +1,41 @@
+package cora
+
+import "fmt"
+
+// Compose returns a new builder holding the tools of every base, in order.
+// The bases are not modified. Compose panics if two bases register a tool
+// with the same name; tool sets are usually composed once at start-up, where
+// a collision is a programming error.
+func Compose(bases ...*ToolBuilder) *ToolBuilder {
+	tb := NewToolBuilder()
+	for _, base := range bases {
+		if base == nil {
+			continue
+		}
+		for _, tool := range base.tools {
+			if tb.toolIndex(tool.Name) >= 0 {
+				panic(fmt.Sprintf("cora: Compose: tool %q is defined more than once", tool.Name))
+			}
+			tb.AddTool(tool, base.handlers[tool.Name])
+		}
+	}
+	return tb
+}
+
+// Subset returns a new builder holding only the named tools, in the order
+// given, e.g. to expose fewer tools on a limited-capability endpoint. It
+// fails if any name is not registered. tb is not modified.
+func (tb *ToolBuilder) Subset(names ...string) (*ToolBuilder, error) {
+	sub := NewToolBuilder()
+	for _, name := range names {
+		if sub.toolIndex(name) >= 0 {
+			continue
+		}
+		i := tb.toolIndex(name)
+		if i < 0 {
+			return nil, fmt.Errorf("cora: Subset: unknown tool %q", name)
+		}
+		sub.AddTool(tb.tools[i], tb.handlers[name])
+	}
+	return sub, nil
+}
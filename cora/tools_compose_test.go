@@ -0,0 +1,76 @@
+package cora
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func namedTool(name string) (CoraTool, CoraToolHandler) {
+	return CoraTool{Name: name, Description: name + " tool"}, func(ctx context.Context, args map[string]any) (any, error) {
+		return name, nil
+	}
+}
+
+func builderOf(names ...string) *ToolBuilder {
+	tb := NewToolBuilder()
+	for _, n := range names {
+		tb.AddTool(namedTool(n))
+	}
+	return tb
+}
+
+func builtToolNames(tb *ToolBuilder) []string {
+	tools, _ := tb.Build()
+	var names []string
+	for _, t := range tools {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func TestCompose(t *testing.T) {
+	crud := builderOf("create", "read", "update", "delete")
+	extra := builderOf("search")
+
+	full := Compose(crud, extra)
+	if got := builtToolNames(full); !slices.Equal(got, []string{"create", "read", "update", "delete", "search"}) {
+		t.Errorf("composed tools = %v", got)
+	}
+	_, handlers := full.Build()
+	if out, err := handlers["search"](context.Background(), nil); err != nil || out != "search" {
+		t.Errorf("search handler returned %v, %v", out, err)
+	}
+
+	full.AddTool(namedTool("archive"))
+	if len(builtToolNames(crud)) != 4 {
+		t.Error("Compose result shares state with its base")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Compose to panic on a name collision")
+		}
+	}()
+	Compose(crud, builderOf("read"))
+}
+
+func TestToolBuilder_Subset(t *testing.T) {
+	full := builderOf("get_weather", "calculate", "send_email")
+
+	sub, err := full.Subset("calculate", "get_weather")
+	if err != nil {
+		t.Fatalf("Subset error: %v", err)
+	}
+	if got := builtToolNames(sub); !slices.Equal(got, []string{"calculate", "get_weather"}) {
+		t.Errorf("subset tools = %v", got)
+	}
+	_, handlers := sub.Build()
+	if _, ok := handlers["send_email"]; ok || len(handlers) != 2 {
+		t.Errorf("subset handlers = %v", handlers)
+	}
+
+	if _, err := full.Subset("get_weather", "missing"); err == nil {
+		t.Error("expected an error for an unknown tool")
+	}
+}
@@ -0,0 +1,96 @@
+package cora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// minToolDescriptionLen is the description length below which Document
+// rewrites a tool's description.
+const minToolDescriptionLen = 50
+
+const toolDescriptionPrompt = `Write a description for a function that an AI assistant can call as a tool.
+The description tells the assistant what the function does and when to call it.
+Reply with the description only: one to three plain sentences, no quotes or markdown.
+
+Name: %s
+Current description: %s
+Parameters (JSON schema): %s`
+
+// Document asks model to write a better description for every tool whose
+// description is shorter than 50 characters, and stores it in the tool.
+// Built-in provider tools are skipped.
+func (tb *ToolBuilder) Document(ctx context.Context, client *Client, provider Provider, model string) error {
+	_, err := tb.document(ctx, client, provider, model, false)
+	return err
+}
+
+// DocumentDryRun is Document without applying the changes: it returns the
+// generated descriptions by tool name.
+func (tb *ToolBuilder) DocumentDryRun(ctx context.Context, client *Client, provider Provider, model string) (map[string]string, error) {
+	return tb.document(ctx, client, provider, model, true)
+}
+
+func (tb *ToolBuilder) document(ctx context.Context, client *Client, provider Provider, model string, dryRun bool) (map[string]string, error) {
+	generated := make(map[string]string)
+	for _, t := range tb.tools {
+		if t.BuiltinType != "" || len(t.Description) >= minToolDescriptionLen {
+			continue
+		}
+		desc, err := tb.DocumentTool(ctx, client, t.Name, provider, model, dryRun)
+		if err != nil {
+			return generated, err
+		}
+		generated[t.Name] = desc
+	}
+	return generated, nil
+}
+
+// DocumentTool asks model to write a description for the named tool,
+// whatever the length of its current one, and returns it. Unless dryRun is
+// set, the description replaces the tool's.
+func (tb *ToolBuilder) DocumentTool(ctx context.Context, client *Client, name string, provider Provider, model string, dryRun bool) (string, error) {
+	i := tb.toolIndex(name)
+	if i < 0 {
+		return "", fmt.Errorf("cora: no tool named %q", name)
+	}
+	t := tb.tools[i]
+	schema, err := json.Marshal(t.ParametersSchema)
+	if err != nil {
+		return "", fmt.Errorf("cora: encoding schema of tool %s: %w", name, err)
+	}
+	current := t.Description
+	if current == "" {
+		current = "(none)"
+	}
+
+	resp, err := client.Text(ctx, TextRequest{
+		Provider: provider,
+		Model:    model,
+		Mode:     ModeBasic,
+		Input:    fmt.Sprintf(toolDescriptionPrompt, t.Name, current, schema),
+	})
+	if err != nil {
+		return "", fmt.Errorf("cora: documenting tool %s: %w", name, err)
+	}
+	desc := strings.Trim(strings.TrimSpace(resp.Text), `"`)
+	if desc == "" {
+		return "", fmt.Errorf("cora: documenting tool %s: model returned an empty description", name)
+	}
+	if !dryRun {
+		tb.tools[i].Description = desc
+	}
+	return desc, nil
+}
+
+// toolIndex returns the position of the named tool in tb.tools, or -1.
+func (tb *ToolBuilder) toolIndex(name string) int {
+	for i, t := range tb.tools {
+		if t.Name == name {
+			return i
+		}
+	}
+	return -1
+}
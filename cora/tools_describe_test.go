@@ -0,0 +1,45 @@
+package cora
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestToolBuilder_Document(t *testing.T) {
+	const generated = "Looks up the current weather for a city. Call it when the user asks about weather conditions."
+	fp := &fakeProvider{finalOut: `"` + generated + `"`}
+	c := &Client{cfg: CoraConfig{}, openai: fp}
+
+	tb := NewToolBuilder()
+	tb.AddTool(CoraTool{Name: "weather", Description: "weather", ParametersSchema: map[string]any{"type": "object"}}, nil)
+	long := "Converts an amount between two currencies using the latest exchange rates."
+	tb.AddTool(CoraTool{Name: "convert", Description: long}, nil)
+
+	preview, err := tb.DocumentDryRun(context.Background(), c, ProviderOpenAI, "gpt-test")
+	if err != nil {
+		t.Fatalf("DocumentDryRun error: %v", err)
+	}
+	if len(preview) != 1 || preview["weather"] != generated {
+		t.Errorf("unexpected preview %v", preview)
+	}
+	if tools, _ := tb.Build(); tools[0].Description != "weather" {
+		t.Errorf("dry run changed the description to %q", tools[0].Description)
+	}
+
+	if err := tb.Document(context.Background(), c, ProviderOpenAI, "gpt-test"); err != nil {
+		t.Fatalf("Document error: %v", err)
+	}
+	tools, _ := tb.Build()
+	if tools[0].Description != generated || tools[1].Description != long {
+		t.Errorf("unexpected descriptions %q, %q", tools[0].Description, tools[1].Description)
+	}
+	plans := fp.ReceivedPlans()
+	if len(plans) != 2 || !strings.Contains(plans[0].Input, "Name: weather") {
+		t.Errorf("unexpected prompts %+v", plans)
+	}
+
+	if _, err := tb.DocumentTool(context.Background(), c, "missing", ProviderOpenAI, "gpt-test", false); err == nil {
+		t.Error("expected an error for an unknown tool")
+	}
+}
@@ -0,0 +1,117 @@
+package cora
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AddFuncWithDoc is AddFunc that also takes field descriptions from the doc
+// comments of the parameter struct's fields, so documented Go code needs no
+// description tags. pkgPath locates the source declaring the struct: a
+// directory, or an import path resolved with go/build. A description tag
+// takes precedence over the field's comment.
+func (tb *ToolBuilder) AddFuncWithDoc(name, description string, handlerFunc any, pkgPath string) error {
+	handler, schema, err := wrapFunction(handlerFunc)
+	if err != nil {
+		return fmt.Errorf("failed to wrap function %s: %w", name, err)
+	}
+	paramsType, _ := funcParamsType(handlerFunc)
+	docs, err := structFieldDocs(pkgPath, paramsType.Name())
+	if err != nil {
+		return fmt.Errorf("cora: reading field docs for tool %s: %w", name, err)
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	for i := 0; i < paramsType.NumField(); i++ {
+		field := paramsType.Field(i)
+		jsonName, _, ok := jsonFieldName(field)
+		prop, _ := props[jsonName].(map[string]any)
+		if !ok || !field.IsExported() || prop == nil || docs[field.Name] == "" {
+			continue
+		}
+		if _, tagged := prop["description"]; !tagged {
+			prop["description"] = docs[field.Name]
+		}
+	}
+
+	tb.tools = append(tb.tools, CoraTool{
+		Name:             name,
+		Description:      description,
+		ParametersSchema: schema,
+	})
+	tb.handlers[name] = handler
+	return nil
+}
+
+// structFieldDocs parses the Go files of the package at pkgPath, test files
+// included, and returns the doc comment (or, failing that, the line comment)
+// of each field of the struct type typeName, keyed by field name.
+func structFieldDocs(pkgPath, typeName string) (map[string]string, error) {
+	if typeName == "" {
+		return nil, fmt.Errorf("parameter struct must be a named type")
+	}
+	dir := pkgPath
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		pkg, err := build.Import(pkgPath, ".", build.FindOnly)
+		if err != nil {
+			return nil, err
+		}
+		dir = pkg.Dir
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if st := findStructType(f, typeName); st != nil {
+			return fieldComments(st), nil
+		}
+	}
+	return nil, fmt.Errorf("type %s not found in %s", typeName, dir)
+}
+
+// findStructType returns the declaration of struct type name in f, or nil.
+func findStructType(f *ast.File, name string) *ast.StructType {
+	var found *ast.StructType
+	ast.Inspect(f, func(n ast.Node) bool {
+		if ts, ok := n.(*ast.TypeSpec); ok && ts.Name.Name == name {
+			found, _ = ts.Type.(*ast.StructType)
+		}
+		return found == nil
+	})
+	return found
+}
+
+// fieldComments returns the comment of each named field of st as a single
+// line of plain text.
+func fieldComments(st *ast.StructType) map[string]string {
+	var p doc.Package
+	docs := make(map[string]string)
+	for _, field := range st.Fields.List {
+		cg := field.Doc
+		if cg == nil {
+			cg = field.Comment
+		}
+		if cg == nil {
+			continue
+		}
+		text := strings.Join(strings.Fields(string(p.Text(cg.Text()))), " ")
+		for _, ident := range field.Names {
+			docs[ident.Name] = text
+		}
+	}
+	return docs
+}
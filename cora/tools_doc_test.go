@@ -0,0 +1,47 @@
+package cora
+
+import (
+	"context"
+	"testing"
+)
+
+// forecastParams is documented in this file for TestAddFuncWithDoc.
+type forecastParams struct {
+	// City is the name of the city to
+	// forecast, e.g. "Paris".
+	City string `json:"city"`
+	Days int    `json:"days,omitempty"` // Number of days ahead.
+	// Units is overridden by the description tag.
+	Units string `json:"units,omitempty" description:"metric or imperial"`
+	Raw   bool   `json:"raw,omitempty"`
+}
+
+func TestAddFuncWithDoc(t *testing.T) {
+	tb := NewToolBuilder()
+	err := tb.AddFuncWithDoc("forecast", "Weather forecast", func(ctx context.Context, p forecastParams) (any, error) {
+		return p.City, nil
+	}, ".")
+	if err != nil {
+		t.Fatalf("AddFuncWithDoc error: %v", err)
+	}
+	tools, handlers := tb.Build()
+	props := tools[0].ParametersSchema["properties"].(map[string]any)
+	want := map[string]any{
+		"city":  `City is the name of the city to forecast, e.g. "Paris".`,
+		"days":  "Number of days ahead.",
+		"units": "metric or imperial",
+		"raw":   nil,
+	}
+	for field, desc := range want {
+		if got := props[field].(map[string]any)["description"]; got != desc {
+			t.Errorf("%s description = %v, want %v", field, got, desc)
+		}
+	}
+	if res, err := handlers["forecast"](context.Background(), map[string]any{"city": "Oslo"}); err != nil || res != "Oslo" {
+		t.Errorf("handler returned %v, %v", res, err)
+	}
+
+	if err := tb.AddFuncWithDoc("bad", "", func(ctx context.Context, p struct{ A string }) (any, error) { return nil, nil }, "."); err == nil {
+		t.Error("expected an error for an unnamed parameter struct")
+	}
+}
@@ -3,24 +3,60 @@ package cora
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// ToolExecutor handles tool call execution with configurable behavior.
+// ToolExecutor handles tool call execution with configurable behavior. An
+// executor serves a single tool loop: its rounds, metrics and caches describe
+// that loop, so each loop builds its own. It is safe for the concurrent calls
+// of a parallel round.
 type ToolExecutor struct {
-	handlers    map[string]CoraToolHandler
-	maxRounds   int
-	parallel    bool
-	stopOnError bool
-	cache       *ToolCache
-	validator   *ToolValidator
-	retryConfig *RetryConfig
+	handlers  map[string]CoraToolHandler
+	streaming map[string]StreamingToolHandler
+	maxRounds int
+	// maxTotalCalls caps the calls executed across all rounds (0 = unlimited).
+	maxTotalCalls int
+	parallel      bool
+	stopOnError   bool
+	cache         *ToolCache
+	validator     *ToolValidator
+	// resultSchemas holds the ResultSchema of each tool (see WithResultValidation).
+	resultSchemas map[string]map[string]any
+	// strictResults fails calls with invalid results even without stopOnError.
+	strictResults bool
+	// sensitiveArgs holds the SensitiveArgs of each tool (see WithSensitiveArgs).
+	sensitiveArgs map[string][]string
+	retryConfig   *RetryConfig
+	coercion      bool
+	logger        *slog.Logger
+	// observer is notified of every executed call, tagged with labels.
+	observer ToolObserver
+	labels   map[string]string
+	// mu guards rounds and roundMetrics.
+	mu     sync.Mutex
+	rounds int
+	// batchTimeout bounds each executeBatch as a whole (0 = no limit).
+	batchTimeout time.Duration
+	// transformer rewrites successful results (see WithOutputTransformer).
+	transformer ToolOutputTransformer
+	// autoComplete fills in missing optional arguments (see WithAutoCompleteArgs).
+	autoComplete *argCompleter
+	// conversation holds the turns that led to the tool loop, given to
+	// autoComplete as context (see WithConversation).
+	conversation []Message
+	// trace annotates batches in execution traces (see CoraConfig.EnableTrace).
+	trace bool
 	
-	// Metrics
-	totalCalls      int
-	successfulCalls int
-	failedCalls     int
-	cachedCalls     int
+	// Metrics, updated concurrently by parallel calls
+	totalCalls      atomic.Int64
+	successfulCalls atomic.Int64
+	failedCalls     atomic.Int64
+	cachedCalls     atomic.Int64
+	roundMetrics    []ToolRoundMetrics
 }
 
 // NewToolExecutor creates a tool executor with default settings.
@@ -39,6 +75,13 @@ func (te *ToolExecutor) WithMaxRounds(max int) *ToolExecutor {
 	return te
 }
 
+// WithMaxTotalCalls caps the number of tool calls executed across all
+// rounds; 0 means unlimited.
+func (te *ToolExecutor) WithMaxTotalCalls(max int) *ToolExecutor {
+	te.maxTotalCalls = max
+	return te
+}
+
 // WithParallel enables parallel execution of multiple tool calls.
 func (te *ToolExecutor) WithParallel(parallel bool) *ToolExecutor {
 	te.parallel = parallel
@@ -63,6 +106,90 @@ func (te *ToolExecutor) WithValidator(tools []CoraTool) *ToolExecutor {
 	return te
 }
 
+// WithResultValidation checks each handler result against its tool's
+// ResultSchema. An invalid result fails the call when stop-on-error is set
+// (the default); otherwise it is logged and passed to the model unchanged.
+func (te *ToolExecutor) WithResultValidation(tools []CoraTool) *ToolExecutor {
+	te.resultSchemas = make(map[string]map[string]any)
+	for _, t := range tools {
+		if t.ResultSchema != nil {
+			te.resultSchemas[t.Name] = t.ResultSchema
+		}
+	}
+	return te
+}
+
+// WithStrictResultValidation treats a result that does not match its
+// tool's ResultSchema as a handler error even when the executor does not
+// stop on errors: the model then receives a ToolErrorResponse instead of the
+// malformed result, which is otherwise only logged.
+func (te *ToolExecutor) WithStrictResultValidation(strict bool) *ToolExecutor {
+	te.strictResults = strict
+	return te
+}
+
+// WithSensitiveArgs masks each tool's SensitiveArgs in the executor's log
+// entries, observer events and recorded tool call graphs.
+func (te *ToolExecutor) WithSensitiveArgs(tools []CoraTool) *ToolExecutor {
+	te.sensitiveArgs = make(map[string][]string)
+	for _, t := range tools {
+		if len(t.SensitiveArgs) > 0 {
+			te.sensitiveArgs[t.Name] = t.SensitiveArgs
+		}
+	}
+	return te
+}
+
+// WithCoercion enables converting mismatched argument types (e.g. "5" for a
+// number field) before validation. It has no effect without WithValidator.
+func (te *ToolExecutor) WithCoercion(enabled bool) *ToolExecutor {
+	te.coercion = enabled
+	return te
+}
+
+// WithLogger sets the logger that receives a DEBUG entry for each coerced
+// argument and a WARN entry for each tolerated invalid result.
+func (te *ToolExecutor) WithLogger(logger *slog.Logger) *ToolExecutor {
+	te.logger = logger
+	return te
+}
+
+// WithObserver sets a function notified after every tool call.
+func (te *ToolExecutor) WithObserver(observer ToolObserver) *ToolExecutor {
+	te.observer = observer
+	return te
+}
+
+// WithLabels attaches the request's labels to every observer event and log
+// entry of the executor.
+func (te *ToolExecutor) WithLabels(labels map[string]string) *ToolExecutor {
+	te.labels = labels
+	return te
+}
+
+// WithStreamingHandlers registers handlers that deliver partial results.
+// They take precedence over regular handlers with the same name and bypass
+// the result cache.
+func (te *ToolExecutor) WithStreamingHandlers(handlers map[string]StreamingToolHandler) *ToolExecutor {
+	te.streaming = handlers
+	return te
+}
+
+// WithBatchTimeout limits the wall-clock time of each round of tool calls as
+// a whole: calls still running when d elapses see their context expire and
+// the round fails, while calls that already finished keep their results.
+func (te *ToolExecutor) WithBatchTimeout(d time.Duration) *ToolExecutor {
+	te.batchTimeout = d
+	return te
+}
+
+// WithOutputTransformer rewrites each successful tool result with t before
+// it is cached and sent back to the model, e.g. to truncate large outputs.
+func (te *ToolExecutor) WithOutputTransformer(t ToolOutputTransformer) *ToolExecutor {
+	te.transformer = t
+	return te
+}
+
 // WithRetry enables retry logic for tool execution.
 func (te *ToolExecutor) WithRetry(config RetryConfig) *ToolExecutor {
 	te.retryConfig = &config
@@ -80,23 +207,83 @@ type toolCallResult struct {
 	result any
 	err    error
 	cached bool
+	// partials holds the results delivered by a StreamingToolHandler or
+	// returned as a ChunkedToolResult.
+	partials []any
+	duration time.Duration
+	// errCode is the ToolErrorResponse code of err, when known up front.
+	errCode string
 }
 
+// maxStreamingToolResults caps the partial results kept per streaming tool call.
+const maxStreamingToolResults = 32
+
 // executeBatch runs multiple tool calls, respecting parallel/serial execution mode.
 func (te *ToolExecutor) executeBatch(ctx context.Context, calls []toolCallRequest) ([]toolCallResult, error) {
 	if len(calls) == 0 {
 		return nil, nil
 	}
+	defer traceRegion(ctx, te.trace, "cora.executeBatch")()
 
 	// Update metrics
-	te.totalCalls += len(calls)
+	te.totalCalls.Add(int64(len(calls)))
+	te.mu.Lock()
+	te.rounds++
+	te.mu.Unlock()
+
+	if te.batchTimeout <= 0 {
+		return te.executeCalls(ctx, calls)
+	}
+	batchCtx, cancel := context.WithTimeout(ctx, te.batchTimeout)
+	defer cancel()
+	results, err := te.executeCalls(batchCtx, calls)
+	if ctx.Err() == nil && batchCtx.Err() != nil {
+		for _, r := range results {
+			if r.err != nil {
+				return results, fmt.Errorf("tool calls exceeded the batch timeout of %v: %w", te.batchTimeout, context.DeadlineExceeded)
+			}
+		}
+	}
+	return results, err
+}
+
+// executeRound is executeBatch for round of a tool loop, recording the
+// round's metrics.
+func (te *ToolExecutor) executeRound(ctx context.Context, round int, calls []toolCallRequest) ([]toolCallResult, error) {
+	results, err := te.executeBatch(ctx, calls)
+	m := ToolRoundMetrics{Round: round, ToolsInvoked: toolCallNames(calls)}
+	for _, r := range results {
+		m.TotalDuration += r.duration
+		m.MaxDuration = max(m.MaxDuration, r.duration)
+		if r.err != nil {
+			m.FailureCount++
+		}
+	}
+	te.mu.Lock()
+	te.roundMetrics = append(te.roundMetrics, m)
+	te.mu.Unlock()
+	return results, err
+}
 
+func (te *ToolExecutor) executeCalls(ctx context.Context, calls []toolCallRequest) ([]toolCallResult, error) {
 	if te.parallel {
 		return te.executeParallel(ctx, calls)
 	}
 	return te.executeSerial(ctx, calls)
 }
 
+// callBudgetNote reports whether executing n more calls would exceed the
+// total call budget and, if so, returns the note asking the model to answer
+// with what it has.
+func (te *ToolExecutor) callBudgetNote(n int) (string, bool) {
+	used := int(te.totalCalls.Load())
+	if te.maxTotalCalls <= 0 || used+n <= te.maxTotalCalls {
+		return "", false
+	}
+	return fmt.Sprintf("You have used %d/%d allowed tool calls. Please answer with the information gathered so far.",
+		used, te.maxTotalCalls), true
+}
+
 type toolCallRequest struct {
 	name string
 	args map[string]any
@@ -106,16 +293,25 @@ func (te *ToolExecutor) executeSerial(ctx context.Context, calls []toolCallReque
 	results := make([]toolCallResult, len(calls))
 
 	for i, call := range calls {
+		if err := ctx.Err(); err != nil {
+			// Out of time: fail the remaining calls without running them.
+			results[i] = te.failedResult(toolCallResult{name: call.name, err: err})
+			te.failedCalls.Add(1)
+			if te.stopOnError {
+				return results, fmt.Errorf("tool %q failed: %w", call.name, err)
+			}
+			continue
+		}
 		result, err := te.executeSingleCall(ctx, call)
 		results[i] = result
 
 		if err != nil {
-			te.failedCalls++
+			te.failedCalls.Add(1)
 			if te.stopOnError {
 				return results, fmt.Errorf("tool %q failed: %w", call.name, err)
 			}
 		} else {
-			te.successfulCalls++
+			te.successfulCalls.Add(1)
 		}
 	}
 
@@ -134,10 +330,10 @@ func (te *ToolExecutor) executeParallel(ctx context.Context, calls []toolCallReq
 			results[i] = result
 			
 			if err != nil {
-				te.failedCalls++
+				te.failedCalls.Add(1)
 				errChan <- fmt.Errorf("tool %q failed: %w", call.name, err)
 			} else {
-				te.successfulCalls++
+				te.successfulCalls.Add(1)
 			}
 			doneChan <- struct{}{}
 		}()
@@ -158,18 +354,77 @@ func (te *ToolExecutor) executeParallel(ctx context.Context, calls []toolCallReq
 	return results, nil
 }
 
+// executeSingleCall runs one tool call and records how long it took.
 func (te *ToolExecutor) executeSingleCall(ctx context.Context, call toolCallRequest) (toolCallResult, error) {
+	start := time.Now()
+	result, err := te.runSingleCall(ctx, call)
+	result.duration = time.Since(start)
+	if err == nil && result.partials == nil {
+		if err = te.validateResult(ctx, call.name, result.result); err != nil {
+			result.err = err
+		}
+	}
+	switch chunked := result.result.(type) {
+	case ChunkedToolResult:
+		result.partials = chunked.responses()
+		result.result = result.partials
+	case *ChunkedToolResult:
+		if chunked != nil {
+			result.partials = chunked.responses()
+			result.result = result.partials
+		}
+	}
+	te.observe(ctx, call, result)
+	if err != nil {
+		result = te.failedResult(result)
+	}
+	return result, err
+}
+
+// hasHandler reports whether the executor has a handler for the named tool.
+func (te *ToolExecutor) hasHandler(name string) bool {
+	_, ok := te.handlers[name]
+	_, streaming := te.streaming[name]
+	return ok || streaming
+}
+
+// failedResult replaces the result of a failed call with a ToolErrorResponse
+// when the executor does not stop on errors, so the model sees what went wrong.
+func (te *ToolExecutor) failedResult(result toolCallResult) toolCallResult {
+	if te.stopOnError || result.err == nil || result.partials != nil {
+		return result
+	}
+	result.result = newToolErrorResponse(result.errCode, result.err)
+	return result
+}
+
+func (te *ToolExecutor) runSingleCall(ctx context.Context, call toolCallRequest) (toolCallResult, error) {
 	// 1. Validate arguments if validator is configured
 	if te.validator != nil {
+		if te.autoComplete != nil {
+			call = te.completeArgs(ctx, call)
+		}
+		if te.coercion {
+			te.coerceArgs(ctx, call)
+		}
 		if err := te.validator.ValidateCall(call.name, call.args); err != nil {
-			return toolCallResult{name: call.name, err: err}, err
+			code := ToolErrorInvalidArguments
+			if !te.hasHandler(call.name) {
+				code = ToolErrorUnknownTool
+			}
+			return toolCallResult{name: call.name, err: err, errCode: code}, err
 		}
 	}
 
+	if h, ok := te.streaming[call.name]; ok {
+		partials, err := runStreamingToolHandler(ctx, h, call.args)
+		return toolCallResult{name: call.name, result: partials, err: err, partials: partials}, err
+	}
+
 	// 2. Check cache if enabled
 	if te.cache != nil {
 		if result, err, found := te.cache.Get(call.name, call.args); found {
-			te.cachedCalls++
+			te.cachedCalls.Add(1)
 			return toolCallResult{name: call.name, result: result, err: err, cached: true}, err
 		}
 	}
@@ -178,10 +433,15 @@ func (te *ToolExecutor) executeSingleCall(ctx context.Context, call toolCallRequ
 	handler, ok := te.handlers[call.name]
 	if !ok {
 		err := fmt.Errorf("no handler for tool %q", call.name)
-		return toolCallResult{name: call.name, err: err}, err
+		return toolCallResult{name: call.name, err: err, errCode: ToolErrorUnknownTool}, err
 	}
 
 	result, err := handler(ctx, call.args)
+	if err == nil && te.transformer != nil {
+		if result, err = te.transformer(call.name, result); err != nil {
+			err = fmt.Errorf("transforming result of tool %q: %w", call.name, err)
+		}
+	}
 
 	// 4. Store in cache if enabled
 	if te.cache != nil {
@@ -191,13 +451,84 @@ func (te *ToolExecutor) executeSingleCall(ctx context.Context, call toolCallRequ
 	return toolCallResult{name: call.name, result: result, err: err}, err
 }
 
+// validateResult checks result against the tool's ResultSchema, taken from
+// WithResultValidation or else from the validator. It returns the validation
+// error only when the executor stops on errors or validates results
+// strictly; otherwise the error is logged and nil is returned.
+func (te *ToolExecutor) validateResult(ctx context.Context, name string, result any) error {
+	var err error
+	if schema, ok := te.resultSchemas[name]; ok {
+		err = checkResult(schema, result)
+	} else if te.validator != nil {
+		err = te.validator.ValidateResult(name, result)
+	}
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("invalid result: %w", err)
+	if te.stopOnError || te.strictResults {
+		return err
+	}
+	te.log(ctx, slog.LevelWarn, "cora: tool result does not match its schema",
+		slog.String("tool", name), slog.Any("error", err))
+	return nil
+}
+
+// coerceArgs applies the validator's type coercions to call.args and logs each.
+func (te *ToolExecutor) coerceArgs(ctx context.Context, call toolCallRequest) {
+	for _, c := range te.validator.coerceCall(call.name, call.args) {
+		if slices.Contains(te.sensitiveArgs[call.name], c.param) {
+			c.from, c.to = redactedArg, redactedArg
+		}
+		te.log(ctx, slog.LevelDebug, "cora: coerced tool argument",
+			slog.String("tool", call.name),
+			slog.String("param", c.param),
+			slog.Any("from", c.from),
+			slog.Any("to", c.to),
+		)
+	}
+}
+
+// log writes an entry to the executor's logger, if any, tagged with the
+// request's labels.
+func (te *ToolExecutor) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if te.logger == nil {
+		return
+	}
+	if len(te.labels) > 0 {
+		attrs = append(attrs, slog.Any("labels", te.labels))
+	}
+	te.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// runStreamingToolHandler runs h and collects its partial results until it
+// returns. Results beyond maxStreamingToolResults are drained and dropped so
+// the handler never blocks.
+func runStreamingToolHandler(ctx context.Context, h StreamingToolHandler, args map[string]any) ([]any, error) {
+	results := make(chan any, maxStreamingToolResults)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(results)
+		errc <- h(ctx, args, results)
+	}()
+
+	var partials []any
+	for v := range results {
+		if len(partials) < maxStreamingToolResults {
+			partials = append(partials, v)
+		}
+	}
+	return partials, <-errc
+}
+
 // Metrics returns execution statistics.
 func (te *ToolExecutor) Metrics() ToolExecutorMetrics {
 	metrics := ToolExecutorMetrics{
-		TotalCalls:      te.totalCalls,
-		SuccessfulCalls: te.successfulCalls,
-		FailedCalls:     te.failedCalls,
-		CachedCalls:     te.cachedCalls,
+		TotalCalls:      int(te.totalCalls.Load()),
+		SuccessfulCalls: int(te.successfulCalls.Load()),
+		FailedCalls:     int(te.failedCalls.Load()),
+		CachedCalls:     int(te.cachedCalls.Load()),
+		RoundMetrics:    te.RoundMetrics(),
 	}
 
 	if te.cache != nil {
@@ -225,4 +556,26 @@ type ToolExecutorMetrics struct {
 	CacheMisses     int
 	CacheHitRate    float64
 	SuccessRate     float64
-}
\ No newline at end of file
+	// RoundMetrics breaks the calls of a tool loop down by round.
+	RoundMetrics []ToolRoundMetrics
+}
+
+// RoundMetrics returns the metrics of each tool loop round that executed
+// calls, in order.
+func (te *ToolExecutor) RoundMetrics() []ToolRoundMetrics {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	return slices.Clone(te.roundMetrics)
+}
+
+// ToolRoundMetrics describes the tool calls of one round of a tool loop.
+// TotalDuration sums the calls' durations, so it exceeds the round's
+// wall-clock time when calls run in parallel; MaxDuration is the slowest
+// call's.
+type ToolRoundMetrics struct {
+	Round         int
+	ToolsInvoked  []string
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+	FailureCount  int
+}
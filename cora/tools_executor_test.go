@@ -1,8 +1,11 @@
 package cora
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 )
@@ -11,7 +14,7 @@ func TestToolCache(t *testing.T) {
 	cache := NewToolCache(1*time.Second, 10)
 
 	args := map[string]any{"x": 5}
-	
+
 	// Miss on first call
 	_, _, found := cache.Get("add", args)
 	if found {
@@ -141,7 +144,7 @@ func TestRetryableToolHandler(t *testing.T) {
 	}
 
 	wrapped := RetryableToolHandler(handler, config)
-	
+
 	result, err := wrapped(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("expected success after retries, got error: %v", err)
@@ -152,4 +155,466 @@ func TestRetryableToolHandler(t *testing.T) {
 	if attempts != 3 {
 		t.Errorf("expected 3 attempts, got %d", attempts)
 	}
-}
\ No newline at end of file
+}
+
+func TestRetryableToolHandler_DeadlineTooClose(t *testing.T) {
+	transientErr := errors.New("transient error")
+	attempts := 0
+	handler := func(ctx context.Context, args map[string]any) (any, error) {
+		attempts++
+		return nil, transientErr
+	}
+	config := RetryConfig{
+		MaxAttempts:       5,
+		InitialBackoff:    20 * time.Millisecond,
+		MaxBackoff:        time.Second,
+		BackoffMultiplier: 4.0,
+		RetryableErrors:   []error{transientErr},
+		MinDeadlineBuffer: 10 * time.Millisecond,
+	}
+
+	// Backoffs are 20ms then 80ms: the first retry fits in the deadline, the
+	// second would sleep past it.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := RetryableToolHandler(handler, config)(ctx, nil)
+	if err == nil || !strings.Contains(err.Error(), "deadline too close") || !errors.Is(err, transientErr) {
+		t.Fatalf("expected deadline abort wrapping the last error, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+	if ctx.Err() != nil || time.Since(start) > 90*time.Millisecond {
+		t.Errorf("expected the handler to give up before the deadline, took %v", time.Since(start))
+	}
+}
+
+func TestToolExecutor_BatchTimeout(t *testing.T) {
+	handlers := map[string]CoraToolHandler{
+		"fast": func(ctx context.Context, args map[string]any) (any, error) {
+			return "done", nil
+		},
+		"slow": func(ctx context.Context, args map[string]any) (any, error) {
+			select {
+			case <-time.After(time.Second):
+				return "late", nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	}
+	calls := []toolCallRequest{{name: "fast"}, {name: "slow"}}
+
+	for _, parallel := range []bool{true, false} {
+		executor := NewToolExecutor(handlers).WithParallel(parallel).WithStopOnError(false).WithBatchTimeout(50 * time.Millisecond)
+		start := time.Now()
+		results, err := executor.executeBatch(context.Background(), calls)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("parallel=%v: expected the batch to time out, got %v", parallel, err)
+		}
+		if time.Since(start) > 500*time.Millisecond {
+			t.Errorf("parallel=%v: batch took %v, want it cut off at the timeout", parallel, time.Since(start))
+		}
+		if results[0].result != "done" || results[0].err != nil {
+			t.Errorf("parallel=%v: expected the finished call to keep its result, got %+v", parallel, results[0])
+		}
+		if !errors.Is(results[1].err, context.DeadlineExceeded) {
+			t.Errorf("parallel=%v: expected the pending call to see the deadline, got %+v", parallel, results[1])
+		}
+	}
+
+	// Within the timeout the batch succeeds.
+	executor := NewToolExecutor(handlers).WithBatchTimeout(time.Second)
+	if _, err := executor.executeBatch(context.Background(), []toolCallRequest{{name: "fast"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCalculateBackoffWithJitter(t *testing.T) {
+	config := DefaultRetryConfig
+	config.JitterFactor = 1
+	a, b := calculateBackoffWithJitter(3, config), calculateBackoffWithJitter(3, config)
+	if a == b {
+		t.Errorf("expected two jittered backoffs to differ, both were %v", a)
+	}
+	full := calculateBackoff(3, config)
+	for _, d := range []time.Duration{a, b} {
+		if d < 0 || d > full {
+			t.Errorf("jittered backoff %v outside [0, %v]", d, full)
+		}
+	}
+
+	config.JitterFactor = 0.2
+	for range 20 {
+		if d := calculateBackoffWithJitter(3, config); d < full*8/10 || d > full {
+			t.Fatalf("backoff %v outside [%v, %v] for JitterFactor 0.2", d, full*8/10, full)
+		}
+	}
+
+	config.JitterFactor = 0
+	if d := calculateBackoffWithJitter(3, config); d != full {
+		t.Errorf("expected no jitter, got %v want %v", d, full)
+	}
+}
+
+func TestToolValidator_ResolvesDefsRef(t *testing.T) {
+	tools := []CoraTool{
+		{
+			Name: "book_trip",
+			ParametersSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"destination": map[string]any{"$ref": "#/$defs/Location"},
+				},
+				"required": []string{"destination"},
+				"$defs": map[string]any{
+					"Location": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"city": map[string]any{"type": "string"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	validator := NewToolValidator(tools)
+
+	err := validator.ValidateCall("book_trip", map[string]any{"destination": map[string]any{"city": "Paris"}})
+	if err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+
+	// The referenced type is an object, so a plain string must be rejected.
+	err = validator.ValidateCall("book_trip", map[string]any{"destination": "Paris"})
+	if err == nil {
+		t.Error("expected validation error for value not matching referenced type")
+	}
+
+	err = validator.ValidateCall("book_trip", map[string]any{})
+	if err == nil {
+		t.Error("expected validation error for missing required field")
+	}
+}
+
+func TestResolveRef_Cycle(t *testing.T) {
+	defs := map[string]any{
+		"Node": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"next": map[string]any{"$ref": "#/$defs/Node"},
+			},
+		},
+	}
+
+	resolved := resolveRef(map[string]any{"$ref": "#/$defs/Node"}, defs)
+	if resolved["type"] != "object" {
+		t.Fatalf("expected top-level ref to resolve, got %v", resolved)
+	}
+	next := resolved["properties"].(map[string]any)["next"].(map[string]any)
+	if next["$ref"] != "#/$defs/Node" {
+		t.Errorf("expected circular ref to stay unresolved, got %v", next)
+	}
+}
+
+func TestToolValidator_Composition(t *testing.T) {
+	stringOrNumber := []any{
+		map[string]any{"type": "string"},
+		map[string]any{"type": "number"},
+	}
+
+	testCases := []struct {
+		name    string
+		schema  map[string]any
+		value   any
+		wantErr bool
+	}{
+		{"oneOf single match", map[string]any{"oneOf": stringOrNumber}, "abc", false},
+		{"oneOf no match", map[string]any{"oneOf": stringOrNumber}, true, true},
+		{"oneOf multiple matches", map[string]any{"oneOf": []any{
+			map[string]any{"type": "number"},
+			map[string]any{"type": "integer"},
+		}}, 3.0, true},
+		{"anyOf match", map[string]any{"anyOf": stringOrNumber}, 4.5, false},
+		{"anyOf no match", map[string]any{"anyOf": stringOrNumber}, []any{1.0}, true},
+		{"allOf all match", map[string]any{"allOf": []map[string]any{
+			{"type": "number"},
+			{"type": "integer"},
+		}}, 3.0, false},
+		{"allOf one fails", map[string]any{"allOf": []map[string]any{
+			{"type": "number"},
+			{"type": "integer"},
+		}}, 3.5, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := NewToolValidator([]CoraTool{{
+				Name: "tool",
+				ParametersSchema: map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"value": tc.schema},
+				},
+			}})
+
+			err := validator.ValidateCall("tool", map[string]any{"value": tc.value})
+			if tc.wantErr && err == nil {
+				t.Error("expected validation error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolExecutor_Coercion(t *testing.T) {
+	tools := []CoraTool{{
+		Name: "scale",
+		ParametersSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"factor": map[string]any{"type": "number"},
+				"label":  map[string]any{"type": "string"},
+				"round":  map[string]any{"type": "boolean"},
+			},
+			"required": []string{"factor"},
+		},
+	}}
+	var got map[string]any
+	handlers := map[string]CoraToolHandler{"scale": func(ctx context.Context, args map[string]any) (any, error) {
+		got = args
+		return "ok", nil
+	}}
+	call := func() toolCallRequest {
+		return toolCallRequest{name: "scale", args: map[string]any{"factor": "5", "label": 3.0, "round": "true"}}
+	}
+
+	if _, err := NewToolExecutor(handlers).WithValidator(tools).executeSingleCall(context.Background(), call()); err == nil {
+		t.Fatal("expected a validation error without coercion")
+	}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	executor := NewToolExecutor(handlers).WithValidator(tools).WithCoercion(true).WithLogger(logger)
+	if _, err := executor.executeSingleCall(context.Background(), call()); err != nil {
+		t.Fatalf("unexpected error with coercion: %v", err)
+	}
+	if got["factor"] != 5.0 || got["label"] != "3" || got["round"] != true {
+		t.Errorf("unexpected coerced args %v", got)
+	}
+	if n := strings.Count(logs.String(), "coerced tool argument"); n != 3 {
+		t.Errorf("expected 3 coercion log entries, got %d:\n%s", n, logs.String())
+	}
+
+	bad := toolCallRequest{name: "scale", args: map[string]any{"factor": "five"}}
+	if _, err := executor.executeSingleCall(context.Background(), bad); err == nil {
+		t.Error("expected a validation error for a non-numeric string")
+	}
+}
+
+func TestToolExecutor_ResultValidation(t *testing.T) {
+	tools := []CoraTool{{
+		Name: "get_price",
+		ResultSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"price": map[string]any{"type": "number"}},
+			"required":   []string{"price"},
+		},
+	}}
+	type priceResult struct {
+		Price string `json:"price"`
+	}
+	handlers := map[string]CoraToolHandler{"get_price": func(ctx context.Context, args map[string]any) (any, error) {
+		return priceResult{Price: "twelve"}, nil // wrong type: string instead of number
+	}}
+	call := toolCallRequest{name: "get_price", args: map[string]any{}}
+
+	executor := NewToolExecutor(handlers).WithResultValidation(tools)
+	res, err := executor.executeSingleCall(context.Background(), call)
+	if err == nil || !strings.Contains(err.Error(), "parameter price: expected number") || res.err == nil {
+		t.Fatalf("expected a result validation error, got %v", err)
+	}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	executor = NewToolExecutor(handlers).WithResultValidation(tools).WithStopOnError(false).WithLogger(logger)
+	res, err = executor.executeSingleCall(context.Background(), call)
+	if err != nil || res.result != (priceResult{Price: "twelve"}) {
+		t.Errorf("expected the invalid result to pass through, got %v, %v", res.result, err)
+	}
+	if !strings.Contains(logs.String(), "tool result does not match its schema") {
+		t.Errorf("expected a warning to be logged, got %q", logs.String())
+	}
+
+	handlers["get_price"] = func(ctx context.Context, args map[string]any) (any, error) {
+		return map[string]any{"price": 12.5}, nil
+	}
+	if _, err := NewToolExecutor(handlers).WithResultValidation(tools).executeSingleCall(context.Background(), call); err != nil {
+		t.Errorf("unexpected error for a valid result: %v", err)
+	}
+}
+
+func TestToolValidator_ValidateResult(t *testing.T) {
+	validator := NewToolValidator([]CoraTool{
+		{Name: "get_price", ResultSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"price": map[string]any{"type": "number"}},
+			"required":   []any{"price"},
+		}},
+		{Name: "anything"},
+	})
+
+	if err := validator.ValidateResult("get_price", map[string]any{"price": 12.5}); err != nil {
+		t.Errorf("unexpected error for a valid result: %v", err)
+	}
+	if err := validator.ValidateResult("get_price", map[string]any{"cost": 12.5}); err == nil || !strings.Contains(err.Error(), "missing required parameter: price") {
+		t.Errorf("expected a missing field error, got %v", err)
+	}
+	if err := validator.ValidateResult("get_price", "12.5"); err == nil {
+		t.Error("expected an error for a string result")
+	}
+	if err := validator.ValidateResult("anything", []int{1, 2}); err != nil {
+		t.Errorf("expected a tool without ResultSchema to accept any result, got %v", err)
+	}
+	if err := validator.ValidateResult("missing", nil); err == nil {
+		t.Error("expected an error for an unknown tool")
+	}
+}
+
+func TestToolExecutor_StrictResultValidation(t *testing.T) {
+	tools := []CoraTool{{
+		Name:         "get_price",
+		ResultSchema: map[string]any{"type": "object", "required": []any{"price"}},
+	}}
+	handlers := map[string]CoraToolHandler{"get_price": func(ctx context.Context, args map[string]any) (any, error) {
+		return map[string]any{"cost": 12.5}, nil
+	}}
+	call := toolCallRequest{name: "get_price", args: map[string]any{}}
+
+	// The validator's ResultSchema is checked without WithResultValidation.
+	lenient := NewToolExecutor(handlers).WithValidator(tools).WithStopOnError(false)
+	res, err := lenient.executeSingleCall(context.Background(), call)
+	if err != nil || res.err != nil {
+		t.Fatalf("expected the invalid result to pass through, got %v", err)
+	}
+
+	strict := NewToolExecutor(handlers).WithValidator(tools).WithStopOnError(false).WithStrictResultValidation(true)
+	res, err = strict.executeSingleCall(context.Background(), call)
+	if err == nil || !strings.Contains(err.Error(), "invalid result") {
+		t.Fatalf("expected a result validation error, got %v", err)
+	}
+	resp, ok := res.result.(ToolErrorResponse)
+	if !ok || !strings.Contains(resp.Error, "missing required parameter: price") {
+		t.Errorf("expected a ToolErrorResponse for the model, got %#v", res.result)
+	}
+}
+
+func TestToolExecutor_RoundMetrics(t *testing.T) {
+	handlers := map[string]CoraToolHandler{
+		"search": func(ctx context.Context, args map[string]any) (any, error) { return "results", nil },
+		"fetch": func(ctx context.Context, args map[string]any) (any, error) {
+			time.Sleep(5 * time.Millisecond)
+			return "page", nil
+		},
+		"broken": func(ctx context.Context, args map[string]any) (any, error) { return nil, errors.New("boom") },
+	}
+	executor := NewToolExecutor(handlers).WithStopOnError(false)
+
+	// A fake three-round tool loop.
+	rounds := [][]toolCallRequest{
+		{{name: "search"}},
+		{{name: "fetch"}, {name: "fetch"}},
+		{{name: "broken"}, {name: "search"}},
+	}
+	for i, calls := range rounds {
+		if _, err := executor.executeRound(context.Background(), i+1, calls); err != nil {
+			t.Fatalf("round %d: %v", i+1, err)
+		}
+	}
+
+	metrics := executor.RoundMetrics()
+	if len(metrics) != 3 {
+		t.Fatalf("expected 3 round metrics, got %+v", metrics)
+	}
+	wantTools := [][]string{{"search"}, {"fetch", "fetch"}, {"broken", "search"}}
+	for i, m := range metrics {
+		if m.Round != i+1 || strings.Join(m.ToolsInvoked, ",") != strings.Join(wantTools[i], ",") {
+			t.Errorf("round %d: %+v", i+1, m)
+		}
+	}
+	if m := metrics[1]; m.MaxDuration < 5*time.Millisecond || m.TotalDuration < 10*time.Millisecond || m.FailureCount != 0 {
+		t.Errorf("unexpected durations or failures for round 2: %+v", m)
+	}
+	if metrics[2].FailureCount != 1 {
+		t.Errorf("round 3 FailureCount = %d, want 1", metrics[2].FailureCount)
+	}
+	if got := executor.Metrics().RoundMetrics; len(got) != 3 {
+		t.Errorf("Metrics().RoundMetrics = %+v", got)
+	}
+}
+
+// TestToolExecutor_ParallelMetrics runs many calls at once against shared
+// counters and the result cache; run it with -race.
+func TestToolExecutor_ParallelMetrics(t *testing.T) {
+	handlers := map[string]CoraToolHandler{
+		"ok":   func(ctx context.Context, args map[string]any) (any, error) { return args["n"], nil },
+		"fail": func(ctx context.Context, args map[string]any) (any, error) { return nil, errors.New("boom") },
+	}
+	executor := NewToolExecutor(handlers).WithParallel(true).WithStopOnError(false).WithCache(time.Minute, 100)
+
+	var calls []toolCallRequest
+	for i := range 40 {
+		name := "ok"
+		if i%4 == 0 {
+			name = "fail"
+		}
+		calls = append(calls, toolCallRequest{name: name, args: map[string]any{"n": i % 8}})
+	}
+	for range 3 {
+		if _, err := executor.executeBatch(context.Background(), calls); err != nil {
+			t.Fatalf("executeBatch error: %v", err)
+		}
+	}
+
+	m := executor.Metrics()
+	if m.TotalCalls != 120 || m.SuccessfulCalls+m.FailedCalls != 120 {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+	if m.FailedCalls != 30 {
+		t.Errorf("FailedCalls = %d, want 30", m.FailedCalls)
+	}
+	if m.CacheHits+m.CacheMisses != 120 || m.CachedCalls != m.CacheHits {
+		t.Errorf("unexpected cache metrics %+v", m)
+	}
+}
+
+// TestToolExecutor_ConcurrentRounds records rounds while their metrics and
+// observer events are read; run it with -race.
+func TestToolExecutor_ConcurrentRounds(t *testing.T) {
+	handlers := map[string]CoraToolHandler{
+		"ok": func(ctx context.Context, args map[string]any) (any, error) { return "ok", nil },
+	}
+	executor := NewToolExecutor(handlers).WithParallel(true).
+		WithObserver(func(ctx context.Context, event ToolEvent) {})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 50 {
+			_ = executor.Metrics()
+		}
+	}()
+	calls := []toolCallRequest{{name: "ok"}, {name: "ok"}}
+	for round := 1; round <= 5; round++ {
+		if _, err := executor.executeRound(context.Background(), round, calls); err != nil {
+			t.Fatalf("executeRound error: %v", err)
+		}
+	}
+	<-done
+	if got := executor.RoundMetrics(); len(got) != 5 {
+		t.Errorf("RoundMetrics = %+v, want 5 rounds", got)
+	}
+}
@@ -0,0 +1,71 @@
+package cora
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestSchemaForFunc(t *testing.T) {
+	tb := NewToolBuilder()
+	if err := tb.AddFunc("get_weather", "Get current weather", getWeather); err != nil {
+		t.Fatalf("AddFunc error: %v", err)
+	}
+	tools, _ := tb.Build()
+
+	schema, err := SchemaForFunc(getWeather)
+	if err != nil {
+		t.Fatalf("SchemaForFunc error: %v", err)
+	}
+	if !reflect.DeepEqual(schema, tools[0].ParametersSchema) {
+		t.Errorf("SchemaForFunc = %v, want the AddFunc schema %v", schema, tools[0].ParametersSchema)
+	}
+	if got := MustSchemaForFunc(getWeather); !reflect.DeepEqual(got, schema) {
+		t.Errorf("MustSchemaForFunc = %v, want %v", got, schema)
+	}
+
+	invalid := []any{
+		nil,
+		"not a function",
+		func(ctx context.Context) (any, error) { return nil, nil },
+		func(ctx context.Context, s string) (any, error) { return nil, nil },
+		func(n int, p WeatherParams) (any, error) { return nil, nil },
+	}
+	for _, fn := range invalid {
+		if _, err := SchemaForFunc(fn); err == nil {
+			t.Errorf("SchemaForFunc(%T): expected an error", fn)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustSchemaForFunc should panic on an invalid signature")
+		}
+	}()
+	MustSchemaForFunc(42)
+}
+
+func TestSchemaForType(t *testing.T) {
+	want := MustSchemaForFunc(getWeather)
+	if got := SchemaForType[WeatherParams](); !reflect.DeepEqual(got, want) {
+		t.Errorf("SchemaForType[WeatherParams] = %v, want %v", got, want)
+	}
+	if got := SchemaForType[*WeatherParams](); !reflect.DeepEqual(got, want) {
+		t.Errorf("SchemaForType[*WeatherParams] = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name string
+		got  map[string]any
+		want map[string]any
+	}{
+		{"string", SchemaForType[string](), map[string]any{"type": "string"}},
+		{"int", SchemaForType[int64](), map[string]any{"type": "integer"}},
+		{"slice", SchemaForType[[]bool](), map[string]any{"type": "array", "items": map[string]any{"type": "boolean"}}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
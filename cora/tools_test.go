@@ -131,4 +131,36 @@ func TestToolExecutor_Serial(t *testing.T) {
 	if results[1].result != 20 {
 		t.Errorf("multiply result: expected 20, got %v", results[1].result)
 	}
-}
\ No newline at end of file
+}
+func TestAddFuncWithMiddleware(t *testing.T) {
+	var logged []string
+	logging := func(tag string) ToolMiddleware {
+		return func(next CoraToolHandler) CoraToolHandler {
+			return func(ctx context.Context, args map[string]any) (any, error) {
+				logged = append(logged, tag)
+				return next(ctx, args)
+			}
+		}
+	}
+
+	tb := NewToolBuilder()
+	if err := tb.AddFuncWithMiddleware("get_weather", "Get weather", getWeather, logging("outer"), logging("inner")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tb.AddFunc("get_weather_quietly", "Get weather", getWeather); err != nil {
+		t.Fatal(err)
+	}
+	_, handlers := tb.Build()
+
+	executor := NewToolExecutor(handlers)
+	calls := []toolCallRequest{
+		{name: "get_weather_quietly", args: map[string]any{"location": "Paris"}},
+		{name: "get_weather", args: map[string]any{"location": "Paris"}},
+	}
+	if _, err := executor.executeBatch(context.Background(), calls); err != nil {
+		t.Fatal(err)
+	}
+	if len(logged) != 2 || logged[0] != "outer" || logged[1] != "inner" {
+		t.Errorf("expected the middleware to run once, outermost first, only for get_weather; got %v", logged)
+	}
+}
@@ -0,0 +1,26 @@
+package cora
+
+import (
+	"context"
+	"runtime/trace"
+)
+
+// traceTask starts a runtime/trace task named name when enabled (see
+// CoraConfig.EnableTrace) and returns its context and end function; when
+// disabled it returns ctx and a no-op.
+func traceTask(ctx context.Context, enabled bool, name string) (context.Context, func()) {
+	if !enabled {
+		return ctx, func() {}
+	}
+	ctx, task := trace.NewTask(ctx, name)
+	return ctx, task.End
+}
+
+// traceRegion starts a runtime/trace region named name when enabled and
+// returns its end function; when disabled it returns a no-op.
+func traceRegion(ctx context.Context, enabled bool, name string) func() {
+	if !enabled {
+		return func() {}
+	}
+	return trace.StartRegion(ctx, name).End
+}
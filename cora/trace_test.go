@@ -0,0 +1,42 @@
+package cora
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+	"testing"
+)
+
+func TestEnableTrace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cora.trace")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := trace.Start(f); err != nil {
+		t.Skipf("tracing unavailable: %v", err)
+	}
+
+	c := &Client{cfg: CoraConfig{EnableTrace: true}, openai: &fakeProvider{}}
+	_, err = c.Text(context.Background(), TextRequest{Provider: ProviderOpenAI, Model: "gpt-trace-test", Input: "hi"})
+	trace.Stop()
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 {
+		t.Fatal("trace file is empty")
+	}
+	for _, name := range []string{"openai/gpt-trace-test", "cora.buildPlans", "cora.openai.Text"} {
+		if !bytes.Contains(data, []byte(name)) {
+			t.Errorf("trace does not mention %q", name)
+		}
+	}
+}
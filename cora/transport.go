@@ -0,0 +1,37 @@
+package cora
+
+import "net/http"
+
+// newProviderTransport returns the transport for provider requests made
+// without a custom HTTP client: http.DefaultTransport, or a clone of it with
+// the config's TLS and connection pool settings applied.
+func newProviderTransport(cfg CoraConfig) http.RoundTripper {
+	tc := cfg.tlsConfig()
+	if tc == nil && !cfg.hasPoolSettings() {
+		return http.DefaultTransport
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if tc != nil {
+		t.TLSClientConfig = tc
+	}
+	if cfg.MaxIdleConns > 0 {
+		t.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if cfg.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = cfg.MaxConnsPerHost
+	}
+	if cfg.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+	t.DisableKeepAlives = cfg.DisableKeepAlives
+	return t
+}
+
+// hasPoolSettings reports whether the config sets any connection pool option.
+func (cfg CoraConfig) hasPoolSettings() bool {
+	return cfg.MaxIdleConns != 0 || cfg.MaxIdleConnsPerHost != 0 || cfg.MaxConnsPerHost != 0 ||
+		cfg.IdleConnTimeout != 0 || cfg.DisableKeepAlives
+}
@@ -0,0 +1,64 @@
+package cora
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// providerTransport returns the *http.Transport under cora's wrappers.
+func providerTransport(t *testing.T, hc *http.Client) *http.Transport {
+	t.Helper()
+	tr, ok := hc.Transport.(*correlationTransport).base.(*sizeLimitTransport).base.(*http.Transport)
+	if !ok {
+		t.Fatalf("unexpected transport chain %#v", hc.Transport)
+	}
+	return tr
+}
+
+func TestProviderTransport_PoolSettings(t *testing.T) {
+	cfg := CoraConfig{
+		MaxIdleConns:        50,
+		MaxIdleConnsPerHost: 10,
+		MaxConnsPerHost:     20,
+		IdleConnTimeout:     45 * time.Second,
+		DisableKeepAlives:   true,
+	}
+	c := New(cfg)
+	shared := providerTransport(t, providerHTTPClient(c.cfg, c.transport, c.cfg.OpenAIHTTPClient))
+	if shared.MaxIdleConns != 50 || shared.MaxIdleConnsPerHost != 10 || shared.MaxConnsPerHost != 20 ||
+		shared.IdleConnTimeout != 45*time.Second || !shared.DisableKeepAlives {
+		t.Errorf("unexpected transport settings %+v", shared)
+	}
+	if shared == http.DefaultTransport {
+		t.Error("expected a dedicated transport, got http.DefaultTransport")
+	}
+	if google := providerTransport(t, providerHTTPClient(c.cfg, c.transport, c.cfg.GoogleHTTPClient)); google != shared {
+		t.Error("expected OpenAI and Google to share the transport")
+	}
+
+	// A provider-specific client keeps its own transport.
+	own := &http.Transport{}
+	cfg.GoogleHTTPClient = &http.Client{Transport: own}
+	c = New(cfg)
+	if google := providerTransport(t, providerHTTPClient(c.cfg, c.transport, c.cfg.GoogleHTTPClient)); google != own {
+		t.Error("expected GoogleHTTPClient's transport")
+	}
+
+	// Without pool or TLS settings the default transport is used.
+	if tr := providerTransport(t, providerHTTPClient(CoraConfig{}, New(CoraConfig{}).transport, nil)); tr != http.DefaultTransport {
+		t.Error("expected http.DefaultTransport")
+	}
+}
+
+func TestValidate_PoolSettings(t *testing.T) {
+	cfg := CoraConfig{MaxConnsPerHost: 4, HTTPClient: &http.Client{}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "cannot be combined with HTTPClient") {
+		t.Errorf("expected a pool/HTTPClient conflict, got %v", err)
+	}
+	cfg = CoraConfig{IdleConnTimeout: -time.Second}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "must not be negative") {
+		t.Errorf("expected a negative setting error, got %v", err)
+	}
+}
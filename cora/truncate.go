@@ -0,0 +1,145 @@
+package cora
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// TruncationStrategy selects which part of an oversized input is dropped when
+// TextRequest.AutoTruncate is enabled.
+type TruncationStrategy int
+
+const (
+	// TruncateEnd keeps the beginning of the input and drops the end.
+	TruncateEnd TruncationStrategy = iota
+	// TruncateStart keeps the end of the input and drops the beginning.
+	TruncateStart
+	// TruncateMiddle keeps the beginning and the end and drops the middle.
+	TruncateMiddle
+)
+
+// truncationMarker joins the kept head and tail in TruncateMiddle.
+const truncationMarker = "..."
+
+// defaultModelContextWindows lists context window sizes (in tokens) of well-known
+// models. Dated variants (e.g. "gpt-4o-2024-08-06") match by prefix.
+var defaultModelContextWindows = map[string]int{
+	"gpt-4o":           128000,
+	"gpt-4o-mini":      128000,
+	"gpt-4-turbo":      128000,
+	"gpt-4":            8192,
+	"gpt-4.1":          1047576,
+	"gpt-3.5-turbo":    16385,
+	"o1":               200000,
+	"o3":               200000,
+	"o3-mini":          200000,
+	"o4-mini":          200000,
+	"gemini-1.5-pro":   2097152,
+	"gemini-1.5-flash": 1048576,
+	"gemini-2.0-flash": 1048576,
+	"gemini-2.5-pro":   1048576,
+	"gemini-2.5-flash": 1048576,
+}
+
+// contextWindowFor returns the context window of model, preferring
+// cfg.ModelContextWindows over the built-in defaults. Exact names win over the
+// longest matching prefix.
+func contextWindowFor(cfg CoraConfig, model string) (int, bool) {
+	for _, windows := range []map[string]int{cfg.ModelContextWindows, defaultModelContextWindows} {
+		if n, ok := windows[model]; ok {
+			return n, true
+		}
+	}
+	for _, windows := range []map[string]int{cfg.ModelContextWindows, defaultModelContextWindows} {
+		best := ""
+		for name := range windows {
+			if strings.HasPrefix(model, name+"-") && len(name) > len(best) {
+				best = name
+			}
+		}
+		if best != "" {
+			return windows[best], true
+		}
+	}
+	return 0, false
+}
+
+// truncateInputForPlan shortens plan's prompt so that the estimated prompt
+// (system prompt, response schema, messages, input and reserved output
+// tokens) fits into the model's context window. The oldest messages are
+// dropped first, then Input is truncated; when Input is empty the newest
+// message carries the request, so it is kept and truncated instead. A positive windowOverride replaces the
+// model's known window. Plans for models with an unknown window and no
+// override are left untouched.
+func truncateInputForPlan(plan *callPlan, cfg CoraConfig, strategy TruncationStrategy, windowOverride int) {
+	window, ok := windowOverride, windowOverride > 0
+	if !ok {
+		window, ok = contextWindowFor(cfg, plan.Model)
+	}
+	if !ok {
+		return
+	}
+	budget := window - estimateTokens(plan.System)
+	if plan.Structured {
+		budget -= estimateTokens(string(jsonMarshalNoErr(plan.ResponseSchema)))
+	}
+	if plan.MaxOutputTokens != nil {
+		budget -= *plan.MaxOutputTokens
+	}
+	budget = max(budget, 0)
+
+	keep := 0
+	if plan.Input == "" {
+		keep = 1
+	}
+	messagesTokens := estimateMessagesTokens(plan.Messages)
+	for len(plan.Messages) > keep && messagesTokens+estimateTokens(plan.Input) > budget {
+		messagesTokens -= estimateTokens(plan.Messages[0].Content)
+		plan.Messages = plan.Messages[1:]
+	}
+
+	if plan.Input != "" || len(plan.Messages) == 0 {
+		plan.Input = truncateToTokens(plan.Input, max(budget-messagesTokens, 0), strategy)
+		return
+	}
+	// Only the newest message is left; copy the slice so the caller's
+	// messages are not modified.
+	plan.Messages = slices.Clone(plan.Messages)
+	last := &plan.Messages[len(plan.Messages)-1]
+	last.Content = truncateToTokens(last.Content, budget, strategy)
+}
+
+// truncateToTokens returns text shortened at word boundaries so that its
+// estimated token count does not exceed maxTokens.
+func truncateToTokens(text string, maxTokens int, strategy TruncationStrategy) string {
+	if estimateTokens(text) <= maxTokens {
+		return text
+	}
+	words := strings.Fields(text)
+
+	keep := func(k int) string {
+		switch strategy {
+		case TruncateStart:
+			return strings.Join(words[len(words)-k:], " ")
+		case TruncateMiddle:
+			if k == 0 {
+				return ""
+			}
+			head := (k + 1) / 2
+			kept := append(append(append([]string{}, words[:head]...), truncationMarker), words[len(words)-(k-head):]...)
+			return strings.Join(kept, " ")
+		default:
+			return strings.Join(words[:k], " ")
+		}
+	}
+
+	// Estimated size grows with the number of kept words; find the largest k that fits.
+	k := sort.Search(len(words)+1, func(k int) bool {
+		return estimateTokens(keep(k)) > maxTokens
+	}) - 1
+	if k <= 0 {
+		return ""
+	}
+	return keep(k)
+}
@@ -0,0 +1,165 @@
+package cora
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncateToTokens(t *testing.T) {
+	input := "one two three four five six seven eight nine ten"
+
+	testCases := []struct {
+		name     string
+		strategy TruncationStrategy
+		want     string
+	}{
+		// 5 tokens ~ 20 characters.
+		{"end", TruncateEnd, "one two three four"},
+		{"start", TruncateStart, "seven eight nine ten"},
+		{"middle", TruncateMiddle, "one two ... nine ten"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := truncateToTokens(input, 5, tc.strategy)
+			if got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+			if n := estimateTokens(got); n > 5 {
+				t.Errorf("truncated text is %d tokens, exceeds window", n)
+			}
+		})
+	}
+}
+
+func TestBuildPlans_AutoTruncate(t *testing.T) {
+	cfg := CoraConfig{ModelContextWindows: map[string]int{"tiny-model": 5}}
+	req := TextRequest{
+		Input:        "one two three four five six seven eight nine ten",
+		Mode:         ModeBasic,
+		AutoTruncate: true,
+	}
+
+	plans, err := buildPlans(ProviderOpenAI, "tiny-model", req, cfg, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	if plans[0].Input != "one two three four" {
+		t.Errorf("unexpected truncated input: %q", plans[0].Input)
+	}
+
+	// Without AutoTruncate the input is sent unchanged.
+	req.AutoTruncate = false
+	plans, _ = buildPlans(ProviderOpenAI, "tiny-model", req, cfg, nil)
+	if plans[0].Input != req.Input {
+		t.Errorf("expected input to be unchanged, got %q", plans[0].Input)
+	}
+}
+
+func TestBuildPlans_AutoTruncateMessages(t *testing.T) {
+	cfg := CoraConfig{ModelContextWindows: map[string]int{"tiny-model": 8}}
+	req := TextRequest{
+		Input:        "one two three",
+		Mode:         ModeBasic,
+		AutoTruncate: true,
+		Messages: []Message{
+			{Role: "user", Content: "an old question nobody needs"},
+			{Role: "assistant", Content: "ok"},
+		},
+		history: []Message{{Role: "user", Content: "the oldest turn of all"}},
+	}
+
+	plans, err := buildPlans(ProviderOpenAI, "tiny-model", req, cfg, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	if len(plans[0].Messages) != 1 || plans[0].Messages[0].Content != "ok" || plans[0].Input != req.Input {
+		t.Errorf("expected the oldest turns dropped and the input kept, got %+v and %q", plans[0].Messages, plans[0].Input)
+	}
+
+	// Without Input, the newest message carries the request and is truncated.
+	req.Input = ""
+	req.history = nil
+	req.Messages = []Message{
+		{Role: "assistant", Content: "hi"},
+		{Role: "user", Content: "one two three four five six seven eight nine ten eleven twelve thirteen fourteen"},
+	}
+	plans, _ = buildPlans(ProviderOpenAI, "tiny-model", req, cfg, nil)
+	if len(plans[0].Messages) != 1 || estimateTokens(plans[0].Messages[0].Content) > 8 ||
+		!strings.HasPrefix(plans[0].Messages[0].Content, "one two") {
+		t.Errorf("unexpected truncated messages %+v", plans[0].Messages)
+	}
+	if !strings.HasSuffix(req.Messages[1].Content, "fourteen") {
+		t.Error("expected the request's messages to be left unchanged")
+	}
+}
+
+func TestBuildPlans_AutoTruncateModeSystemPrompt(t *testing.T) {
+	req := TextRequest{
+		Input:          "one two three four five six seven eight nine ten",
+		Mode:           ModeClassify,
+		ClassifyLabels: []string{"positive", "negative"},
+	}
+	plans, err := buildPlans(ProviderOpenAI, "unknown-model", req, CoraConfig{}, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	// Leave room for 5 input tokens next to the classification prompt and schema.
+	reserved := estimateTokens(plans[0].System) + estimateTokens(string(jsonMarshalNoErr(plans[0].ResponseSchema)))
+	req.AutoTruncate = true
+	req.ContextWindowOverride = reserved + 5
+
+	plans, err = buildPlans(ProviderOpenAI, "unknown-model", req, CoraConfig{}, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	if plans[0].Input != "one two three four" {
+		t.Errorf("unexpected truncated input: %q", plans[0].Input)
+	}
+}
+
+func TestBuildPlans_ContextWindowOverride(t *testing.T) {
+	cfg := CoraConfig{ModelContextWindows: map[string]int{"tiny-model": 5}}
+	input := strings.TrimSpace(strings.Repeat("word ", 40)) // about 50 tokens
+	req := TextRequest{Input: input, Mode: ModeBasic, AutoTruncate: true, ContextWindowOverride: 100}
+
+	plans, err := buildPlans(ProviderOpenAI, "tiny-model", req, cfg, nil)
+	if err != nil {
+		t.Fatalf("buildPlans error: %v", err)
+	}
+	if plans[0].Input != input {
+		t.Errorf("input was truncated to %q despite the 100-token window", plans[0].Input)
+	}
+
+	// A smaller override applies to models with an unknown window too.
+	req.ContextWindowOverride = 10
+	plans, _ = buildPlans(ProviderOpenAI, "unknown-model", req, cfg, nil)
+	if plans[0].Input == input {
+		t.Error("expected the input to be truncated to the 10-token window")
+	}
+
+	req.DisableAutoTruncate = true
+	plans, _ = buildPlans(ProviderOpenAI, "unknown-model", req, cfg, nil)
+	if plans[0].Input != input {
+		t.Errorf("DisableAutoTruncate: input truncated to %q", plans[0].Input)
+	}
+
+	req.ContextWindowOverride = -1
+	if _, err := buildPlans(ProviderOpenAI, "tiny-model", req, cfg, nil); err == nil {
+		t.Error("expected an error for a negative ContextWindowOverride")
+	}
+}
+
+func TestContextWindowFor(t *testing.T) {
+	cfg := CoraConfig{ModelContextWindows: map[string]int{"gpt-4o": 1000}}
+
+	if n, _ := contextWindowFor(cfg, "gpt-4o"); n != 1000 {
+		t.Errorf("expected config override, got %d", n)
+	}
+	if n, _ := contextWindowFor(CoraConfig{}, "gpt-4o-mini-2024-07-18"); n != 128000 {
+		t.Errorf("expected prefix match for dated model, got %d", n)
+	}
+	if _, ok := contextWindowFor(CoraConfig{}, "unknown-model"); ok {
+		t.Error("expected unknown model to have no window")
+	}
+}
@@ -1,16 +1,25 @@
 package cora
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"fmt"
+	"time"
 )
 
 // Provider identifies which backend to use. No auto-detection in this step.
 type Provider string
 
 const (
-	ProviderOpenAI Provider = "openai"
-	ProviderGoogle Provider = "google"
+	ProviderOpenAI  Provider = "openai"
+	ProviderGoogle  Provider = "google"
+	ProviderMistral Provider = "mistral"
+	ProviderCohere  Provider = "cohere"
+	ProviderBedrock Provider = "bedrock"
+
+	// ProviderAuto picks a provider per request from CoraConfig.ProviderWeights.
+	ProviderAuto Provider = "auto"
 )
 
 // TextMode selects orchestration/preset behavior for Text().
@@ -27,8 +36,80 @@ const (
 	// ModeTwoStepEnhance first rewrites/cleans the user's input (spelling/grammar/clarity),
 	// then sends the improved text for the main response.
 	ModeTwoStepEnhance
+	// ModeReAct is ModeToolCalling with a ReAct (Reasoning + Acting) system prompt:
+	// the model writes Thought:/Action: lines before each tool call and ends
+	// with "Final Answer:". The reasoning is returned in TextResponse.ReasoningTrace.
+	ModeReAct
+	// ModeTranscribe transcribes TextRequest.Audio into TextResponse.Text.
+	// OpenAI uses the Whisper transcription endpoint; Google sends the audio
+	// inline to Gemini.
+	ModeTranscribe
+	// ModeClassify assigns the input one of TextRequest.ClassifyLabels. The
+	// predicted label is returned in TextResponse.Text and the structured
+	// {"label", "confidence"} result in TextResponse.JSON.
+	ModeClassify
+	// ModeScore scores the input against TextRequest.ScoreRubric. The
+	// {"score", "rationale"} result is returned in TextResponse.JSON and the
+	// score in TextResponse.Score.
+	ModeScore
+	// ModeAgentLoop is ModeToolCalling for autonomous multi-step tasks: the
+	// model works toward TextRequest.AgentGoal, and turns without tool calls
+	// are answered with a prompt to continue until the model says
+	// AgentStopSignal or AgentMaxSteps is reached. The text after the signal
+	// is returned, and the number of model turns in TextResponse.AgentSteps.
+	ModeAgentLoop
 )
 
+var textModeNames = map[TextMode]string{
+	ModeBasic:          "basic",
+	ModeStructuredJSON: "structured_json",
+	ModeToolCalling:    "tool_calling",
+	ModeTwoStepEnhance: "two_step_enhance",
+	ModeReAct:          "react",
+	ModeTranscribe:     "transcribe",
+	ModeClassify:       "classify",
+	ModeScore:          "score",
+	ModeAgentLoop:      "agent_loop",
+}
+
+// String returns the mode's name, e.g. "tool_calling".
+func (m TextMode) String() string {
+	if name, ok := textModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("TextMode(%d)", int(m))
+}
+
+// usesTools reports whether the mode runs a tool loop.
+func (m TextMode) usesTools() bool {
+	return m == ModeToolCalling || m == ModeReAct || m == ModeAgentLoop
+}
+
+// MarshalJSON encodes the mode as its name.
+func (m TextMode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts a mode name or, for compatibility, its integer value.
+func (m *TextMode) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var n int
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("cora: invalid TextMode %s", data)
+		}
+		*m = TextMode(n)
+		return nil
+	}
+	for mode, modeName := range textModeNames {
+		if modeName == name {
+			*m = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("cora: unknown TextMode %q", name)
+}
+
 // CoraTool declares a callable function the model may request.
 type CoraTool struct {
 	// Name is the unique function name referenced by the model.
@@ -38,11 +119,121 @@ type CoraTool struct {
 	// ParametersSchema is a JSON Schema Object (draft subset).
 	// Keep it provider-agnostic; cora maps it to each provider's format.
 	ParametersSchema map[string]any
+	// ResultSchema optionally describes the handler's result. It is not sent
+	// to the model; see ToolExecutor.WithResultValidation.
+	ResultSchema map[string]any
+	// SensitiveArgs names arguments (e.g. "password") whose values are
+	// replaced by "[REDACTED]" in logs, ToolObserver events and the
+	// ToolCallGraph. The handler still receives the real values.
+	SensitiveArgs []string
+
+	// BuiltinType marks a provider-defined tool (e.g. BuiltinToolComputer) whose
+	// schema is fixed by the provider. Built-in tools have no ParametersSchema;
+	// BuiltinOptions carries their extra settings (e.g. display size).
+	BuiltinType    string
+	BuiltinOptions map[string]any
+}
+
+// DocumentPart attaches a document (e.g. a PDF) to the request, either inline
+// as Data or by URL. MIMEType is required, e.g. "application/pdf".
+type DocumentPart struct {
+	Data     []byte
+	MIMEType string
+	URL      string
+}
+
+// ImagePart is an inline image sent with a StreamRequest's Input.
+// MIMEType is required, e.g. "image/png".
+type ImagePart struct {
+	Data     []byte
+	MIMEType string
+}
+
+// imageDocuments converts images to DocumentParts for the providers'
+// document handling.
+func imageDocuments(images []ImagePart) []DocumentPart {
+	if len(images) == 0 {
+		return nil
+	}
+	docs := make([]DocumentPart, len(images))
+	for i, img := range images {
+		docs[i] = DocumentPart{Data: img.Data, MIMEType: img.MIMEType}
+	}
+	return docs
+}
+
+// AudioInput is the audio to transcribe in ModeTranscribe. Language is an
+// optional ISO-639-1 hint such as "en".
+type AudioInput struct {
+	Data     []byte
+	MIMEType string
+	Language string
 }
 
 // CoraToolHandler is invoked when the model requests a tool call.
 type CoraToolHandler func(ctx context.Context, args map[string]any) (any, error)
 
+// StreamingToolHandler is a tool handler that delivers partial results on
+// results while it runs, e.g. rows of a slow database query. cora closes
+// results after the handler returns, so the handler must not send on it
+// afterwards. Up to 32 partial results are kept per call; later ones are
+// dropped.
+type StreamingToolHandler func(ctx context.Context, args map[string]any, results chan<- any) error
+
+// ChunkedToolResult lets a CoraToolHandler return data it fetched
+// incrementally. On Google each chunk, followed by Final when non-nil, is
+// sent to the model as its own FunctionResponse; OpenAI accepts one message
+// per tool call, so it receives them as a single JSON array.
+type ChunkedToolResult struct {
+	Chunks []any
+	Final  any
+}
+
+// responses returns the chunks followed by Final, if set.
+func (r ChunkedToolResult) responses() []any {
+	out := make([]any, 0, len(r.Chunks)+1)
+	out = append(out, r.Chunks...)
+	if r.Final != nil {
+		out = append(out, r.Final)
+	}
+	return out
+}
+
+// ToolResultPart lets a CoraToolHandler return an image, such as a rendered
+// chart, for the model to look at along with Text. Google receives the
+// image as an inline blob next to the function response; OpenAI tool
+// messages are text only, so the image follows them in a user message.
+// MIMEType is used when Image.MIMEType is empty.
+type ToolResultPart struct {
+	Text     string
+	Image    *ImagePart
+	MIMEType string
+}
+
+// asToolResultPart returns v as a ToolResultPart if it is one, by value or
+// pointer.
+func asToolResultPart(v any) (ToolResultPart, bool) {
+	switch p := v.(type) {
+	case ToolResultPart:
+		return p, true
+	case *ToolResultPart:
+		if p != nil {
+			return *p, true
+		}
+	}
+	return ToolResultPart{}, false
+}
+
+// image returns the part's image with its MIME type filled in, or nil.
+func (p ToolResultPart) image() *ImagePart {
+	if p.Image == nil || len(p.Image.Data) == 0 {
+		return nil
+	}
+	img := *p.Image
+	img.MIMEType = cmp.Or(img.MIMEType, p.MIMEType)
+	return &img
+}
+
 // TextRequest is the unified request for text-style generations.
 type TextRequest struct {
 	// Provider and Model must be set explicitly in this step.
@@ -53,28 +244,231 @@ type TextRequest struct {
 	Input  string
 	System string
 
+	// Messages are earlier conversation turns sent before Input, oldest
+	// first, with Role "user", "assistant" (or Google's "model") or
+	// "system". When Messages is set, an empty Input adds no user turn, so
+	// the last message is the one answered.
+	Messages []Message
+
+	// Documents are sent with Input as part of the user message. Google accepts
+	// PDFs and other documents; OpenAI accepts images only and otherwise
+	// returns ErrNotSupportedByProvider.
+	Documents []DocumentPart
+
+	// FileHandles reference files uploaded with Client.UploadFile (Google only).
+	FileHandles []FileHandle
+
+	// CachedContentName references context cached with
+	// Client.CreateCachedContent (Google only); the request's model must match
+	// the cache's. Later turns of the same ConversationID inherit it.
+	CachedContentName string
+
 	// Mode selects orchestration behavior (see TextMode).
 	Mode TextMode
 
+	// OutputFormat asks for the answer in a format (Markdown, HTML, CSV...)
+	// by extending the system prompt; empty leaves the prompt unchanged.
+	// CSVHeaders lists the expected columns for OutputFormatCSV.
+	OutputFormat OutputFormat
+	CSVHeaders   []string
+
+	// ChunkIndex (from 0) and ChunkTotal mark the request as one part of a
+	// longer input; when ChunkTotal > 0 the position is stated in the system
+	// prompt. Client.TextWithChunking sets both, and combines the answers
+	// according to ChunkMerge.
+	ChunkIndex int
+	ChunkTotal int
+	ChunkMerge ChunkMergeStrategy
+
+	// Audio is the input for ModeTranscribe.
+	Audio AudioInput
+
+	// AudioPart is spoken input sent after Input in the user message
+	// (Google only), e.g. from NewAudioPartFromFile.
+	AudioPart *AudioPart
+
 	// Optional response shaping.
 	Temperature     *float32
 	MaxOutputTokens *int
 
+	// ReasoningEffort ("low", "medium" or "high") targets OpenAI reasoning
+	// models (o1/o3/o4). When set, Temperature is not sent and System is folded
+	// into the user message, since these models reject both.
+	ReasoningEffort *string
+
+	// ThinkingBudget (Google) caps the tokens Gemini 2.5 models spend on
+	// internal reasoning. 0 disables thinking; nil uses the model default.
+	ThinkingBudget *int
+
 	// Structured outputs (ModeStructuredJSON).
 	// Provide a JSON schema that defines the shape of the response object.
+	// Both providers enforce it while decoding: Google through
+	// responseJsonSchema, OpenAI through json_schema with strict: true.
 	ResponseSchema map[string]any
+	// For OpenAI the schema is first normalized for strict mode: $refs are
+	// inlined, unsupported keywords dropped, and optional properties made
+	// required but nullable, so they come back as null. DisableStrictJSON
+	// sends the schema to OpenAI as given, as guidance only, for schemas
+	// strict mode cannot express.
+	DisableStrictJSON bool
+	// SchemaName names ResponseSchema in OpenAI's json_schema response
+	// format (letters, digits, '_' and '-', at most 64); default
+	// "cora_response".
+	SchemaName string
+
+	// N samples this many completions in one call (OpenAI n, Gemini
+	// candidateCount); they are returned in TextResponse.Choices. BestOf
+	// samples BestOf completions and returns only the shortest, a cheap proxy
+	// for the lowest perplexity.
+	N      *int
+	BestOf *int
+	// SelectCandidate, if set, picks the sampled completion returned in Text
+	// and JSON when there are several (see SelectLongest and friends).
+	SelectCandidate CandidateSelector
+
+	// Classification (ModeClassify). ClassifyExamples are optional few-shot
+	// examples sent as earlier conversation turns.
+	ClassifyLabels   []string
+	ClassifyExamples []ClassifyExample
+
+	// Scoring (ModeScore). The input is scored on [ScoreMin, ScoreMax];
+	// nil values default to 1 and 10.
+	ScoreRubric string
+	ScoreMin    *int
+	ScoreMax    *int
 
 	// Tool calling (ModeToolCalling).
 	Tools        []CoraTool
 	ToolHandlers map[string]CoraToolHandler
+	// RecordToolGraph records every tool call of the tool loop in
+	// TextResponse.ToolCallGraph.
+	RecordToolGraph bool
+	// ToolObserver, if set, is notified after every tool call with the
+	// request's Labels.
+	ToolObserver ToolObserver
+	// DynamicSystemPrompt, if set, is called before every tool round after
+	// the first with the round number (from 2) and the model's text of the
+	// previous round. A non-empty result adds instructions for the rest of
+	// the loop: Google appends them to the system instruction, OpenAI sends
+	// them as a system message.
+	DynamicSystemPrompt func(round int, lastResponse string) string
+	// RoundSystemPrompts adds instructions to the system instruction from a
+	// given point of a Google tool loop on, keyed by the number of tool
+	// rounds completed: 0 is the first call, whose instruction is System, 1
+	// the call after the first tool results, and so on. Other providers
+	// reject it.
+	RoundSystemPrompts map[int]string
+
+	// ModeAgentLoop settings. AgentGoal is added to the system prompt.
+	// AgentStopSignal is the phrase the model gives when it is done (default:
+	// DefaultAgentStopSignal). AgentMaxSteps bounds the model turns and
+	// replaces MaxToolRounds (default: MaxToolRounds, or 5).
+	AgentGoal       string
+	AgentStopSignal string
+	AgentMaxSteps   *int
+
+	// StreamingHandlers take precedence over ToolHandlers for the same name.
+	// Each partial result is sent to the model as its own function response
+	// (Google) or as a JSON array in the tool message (OpenAI).
+	StreamingHandlers map[string]StreamingToolHandler
 
 	// Tool execution configuration (optional, used with ModeToolCalling).
-	MaxToolRounds  *int  // Maximum number of tool call rounds (default: 5)
-	ParallelTools  *bool // Execute multiple tool calls in parallel (default: false)
+	MaxToolRounds   *int  // Maximum number of tool call rounds (default: 5)
+	ParallelTools   *bool // Execute multiple tool calls in parallel (default: false)
 	StopOnToolError *bool // Stop execution on first tool error (default: true)
 
+	// MaxTotalToolCalls caps the tool calls executed across all rounds (nil =
+	// unlimited). When a round would exceed it, its calls are skipped and the
+	// model is told to answer with the information gathered so far.
+	MaxTotalToolCalls *int
+
+	// CoerceToolArgs converts tool arguments whose type does not match the
+	// schema (e.g. "5" for a number) before validation, when safe.
+	CoerceToolArgs bool
+
 	// Arbitrary per-call labels/metadata (carried provider-side if supported).
+	// Google sends them as request labels (Vertex AI only); OpenAI-compatible
+	// servers receive them as JSON in the X-Cora-Labels header.
 	Labels map[string]string
+
+	// SanitizeInput passes Input through CoraConfig.InputSanitizer (by
+	// default DefaultInputSanitizer) before sending, to neutralize prompt
+	// injection in untrusted, e.g. tenant-provided, input.
+	SanitizeInput bool
+
+	// ProviderOptions passes provider-specific settings that have no
+	// first-class field yet. Supported keys:
+	//   - "safety_settings" (Google): a value that JSON-decodes into []*genai.SafetySetting.
+	ProviderOptions map[string]any
+
+	// IncludeRawResponse sets TextResponse.RawProviderResponse (OpenAI and
+	// Google). It is off by default to save the serialization.
+	IncludeRawResponse bool
+
+	// AutoTruncate shortens the prompt when its estimate exceeds the model's
+	// context window (see CoraConfig.ModelContextWindows): the oldest Messages
+	// and conversation history are dropped first, then Input is truncated.
+	// TruncationStrategy selects which part of the input is dropped (default:
+	// TruncateEnd).
+	AutoTruncate       bool
+	TruncationStrategy TruncationStrategy
+	// ContextWindowOverride, when positive, is the model's context window in
+	// tokens for this call, used instead of CoraConfig.ModelContextWindows and
+	// the built-in defaults. DisableAutoTruncate turns AutoTruncate off for
+	// this call, e.g. when a middleware or template enables it.
+	ContextWindowOverride int
+	DisableAutoTruncate   bool
+
+	// RaceTimeout bounds the whole Client.Race call (0 = no extra deadline).
+	RaceTimeout time.Duration
+
+	// FallbackProviders are tried in order when the request fails with a
+	// retryable CoraError (503, 529 or a network error). FallbackModels is a
+	// parallel slice overriding Model for the fallback at the same index.
+	FallbackProviders []Provider
+	FallbackModels    []string
+
+	// GroundWithSearch (Google) lets Gemini ground its answer in Google Search
+	// results; sources are returned in TextResponse.GroundingMetadata. When
+	// GroundingThreshold is set, dynamic retrieval is used instead and search
+	// only runs if the model's predicted benefit exceeds the threshold (0-1).
+	GroundWithSearch   bool
+	GroundingThreshold *float32
+
+	// VertexSearchGrounding (Google, Vertex AI backend only) grounds the
+	// answer in an enterprise Vertex AI Search data store; the retrieved
+	// documents are returned in TextResponse.GroundingMetadata.
+	VertexSearchGrounding *VertexSearchGrounding
+
+	// ValidateResponse, when set, is called with every response. If it returns
+	// an error and RetryOnValidationFailure is true, the request is sent again
+	// (up to MaxValidationRetries times, default 1) with the rejected answer and
+	// the error appended to Input as feedback.
+	ValidateResponse         func(resp TextResponse) error
+	RetryOnValidationFailure bool
+	MaxValidationRetries     int
+
+	// ConversationID continues a stored conversation when the client has a
+	// ConversationStore (see Client.WithConversationStore): the history is
+	// loaded before the call and this turn is appended to it afterwards.
+	ConversationID string
+
+	// history holds earlier turns loaded from the ConversationStore.
+	history []Message
+
+	// DryRun validates the request and config and returns the planned calls in
+	// TextResponse.DryRunPlan without calling the provider.
+	DryRun bool
+
+	// OverrideSizeLimit exempts this request from
+	// CoraConfig.MaxRequestBodyBytes, for intentionally large calls.
+	OverrideSizeLimit bool
+}
+
+// WithThinking returns a copy of r with ThinkingBudget set to budget.
+func (r TextRequest) WithThinking(budget int) TextRequest {
+	r.ThinkingBudget = &budget
+	return r
 }
 
 // TextResponse is a provider-agnostic result from Text().
@@ -94,6 +488,106 @@ type TextResponse struct {
 	PromptTokens     *int
 	CompletionTokens *int
 	TotalTokens      *int
+	ThinkingTokens   *int // reasoning/thinking tokens, for reasoning models
+	CachedTokens     *int // prompt tokens served from a provider-side cache
+
+	// EstimatedCostUSD is the cost of the call by the client's pricing table
+	// (see CoraConfig.PricingTable); nil when the model or usage is unknown.
+	EstimatedCostUSD *float64
+	// EffectiveCostUSD is EstimatedCostUSD with CachedTokens billed at the
+	// cached input price (ModelPricing.CachedInputPerToken, by default half
+	// the input price); nil when the model or usage is unknown.
+	EffectiveCostUSD *float64
+
+	// RequestBodyBytes is the total size of the request bodies sent to the
+	// provider, over every call the request made (tool rounds, retries).
+	RequestBodyBytes int64
+
+	// Latency is the wall-clock time Text() took to produce the response.
+	Latency time.Duration
+
+	// Chunks holds the text of every EventTypeChunk, in order, when the
+	// response was collected from a stream (see StreamResponse.CollectText);
+	// it is nil for Text(). StreamedAt is when that stream started.
+	Chunks     []string
+	StreamedAt time.Time
+
+	// Choices holds every sampled completion when TextRequest.N > 1; Text
+	// and JSON are those of the first (or of the best, with BestOf or
+	// SelectCandidate).
+	Choices []TextResponse
+
+	// Score is the score returned in ModeScore.
+	Score *float64
+
+	// ReasoningTrace lists the Thought:/Action: steps taken in ModeReAct.
+	ReasoningTrace []string
+
+	// ToolCallGraph holds the recorded tool calls when
+	// TextRequest.RecordToolGraph is set.
+	ToolCallGraph *ToolCallGraph
+	// ToolRounds is the number of rounds in which the model called tools.
+	ToolRounds int
+	// AgentSteps is the number of model turns taken in ModeAgentLoop.
+	AgentSteps int
+
+	// RawProviderResponse is the provider's final response as JSON
+	// (openai.ChatCompletionResponse or genai.GenerateContentResponse), set
+	// when TextRequest.IncludeRawResponse is.
+	RawProviderResponse json.RawMessage
+
+	// TranscriptionLanguage is the spoken language reported for ModeTranscribe.
+	TranscriptionLanguage string
+
+	// GroundingMetadata lists the searches and sources behind a grounded
+	// answer (see TextRequest.GroundWithSearch).
+	GroundingMetadata *GroundingMetadata
+
+	// Metadata carries provider-specific extras that have no typed field,
+	// e.g. "system_fingerprint" from OpenAI or "model_version" and
+	// "grounding_attributions" from Google. Keys are absent when the
+	// provider did not report them.
+	Metadata map[string]any
+
+	// UsedProvider and UsedModel report which provider/model actually
+	// answered (they differ from the request when a fallback was used).
+	UsedProvider Provider
+	UsedModel    string
+
+	// CorrelationID is the ID set on the request context via WithCorrelationID.
+	CorrelationID string
+
+	// DryRunPlan is set when TextRequest.DryRun was used (Text is "dry-run").
+	DryRunPlan *DryRunPlan
+}
+
+// GroundingMetadata describes the web searches used to ground a response.
+type GroundingMetadata struct {
+	WebSearchQueries []string
+	Sources          []GroundingSource
+}
+
+// GroundingSource is a web page or retrieved document that supports a
+// grounded response. Snippet is the retrieved text of a document from a
+// Vertex AI Search data store.
+type GroundingSource struct {
+	Title   string
+	URI     string
+	Domain  string
+	Snippet string
+}
+
+// VertexSearchGrounding selects the Vertex AI Search data store to ground a
+// request in (see TextRequest.VertexSearchGrounding).
+type VertexSearchGrounding struct {
+	// DataStoreID is a data store ID, resolved in the "global" location of
+	// CoraConfig.GoogleProject, or a full resource name
+	// ("projects/.../dataStores/..."). It defaults to
+	// CoraConfig.VertexAIDataStoreID.
+	DataStoreID string
+	// MaxResults caps the search results per query (the API allows up to
+	// 10, its default).
+	MaxResults int
 }
 
 // rawJSONSchema is a thin json.Marshaler wrapper to pass generic schemas
@@ -104,4 +598,4 @@ type rawJSONSchema struct {
 
 func (r rawJSONSchema) MarshalJSON() ([]byte, error) {
 	return json.Marshal(r.m)
-}
\ No newline at end of file
+}
@@ -0,0 +1,57 @@
+package cora
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTextMode_String(t *testing.T) {
+	testCases := []struct {
+		mode TextMode
+		want string
+	}{
+		{ModeBasic, "basic"},
+		{ModeStructuredJSON, "structured_json"},
+		{ModeToolCalling, "tool_calling"},
+		{ModeTwoStepEnhance, "two_step_enhance"},
+		{ModeReAct, "react"},
+		{TextMode(99), "TextMode(99)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			if got := tc.mode.String(); got != tc.want {
+				t.Errorf("String() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTextMode_JSON(t *testing.T) {
+	for mode := range textModeNames {
+		blob, err := json.Marshal(mode)
+		if err != nil {
+			t.Fatalf("marshal %v: %v", mode, err)
+		}
+		if string(blob) != `"`+mode.String()+`"` {
+			t.Errorf("expected %v to marshal as its name, got %s", mode, blob)
+		}
+		var back TextMode
+		if err := json.Unmarshal(blob, &back); err != nil || back != mode {
+			t.Errorf("round trip of %v gave %v (err %v)", mode, back, err)
+		}
+	}
+
+	var req TextRequest
+	if err := json.Unmarshal([]byte(`{"Mode":"tool_calling"}`), &req); err != nil || req.Mode != ModeToolCalling {
+		t.Errorf("expected TextRequest to decode mode name, got %v (err %v)", req.Mode, err)
+	}
+
+	var m TextMode
+	if err := json.Unmarshal([]byte(`3`), &m); err != nil || m != ModeTwoStepEnhance {
+		t.Errorf("expected integer mode to decode, got %v (err %v)", m, err)
+	}
+	if err := json.Unmarshal([]byte(`"bogus"`), &m); err == nil {
+		t.Error("expected error for unknown mode name")
+	}
+}
@@ -0,0 +1,48 @@
+package cora
+
+import (
+	"context"
+	"fmt"
+)
+
+// textValidated runs req with failover and checks the result with
+// req.ValidateResponse, retrying with feedback when configured to.
+func (c *Client) textValidated(ctx context.Context, req TextRequest) (TextResponse, error) {
+	resp, err := c.textWithFallback(ctx, req)
+	if err != nil || req.ValidateResponse == nil {
+		return resp, err
+	}
+
+	retries := 0
+	if req.RetryOnValidationFailure {
+		retries = max(req.MaxValidationRetries, 1)
+	}
+
+	input := req.Input
+	for attempt := 0; ; attempt++ {
+		verr := req.ValidateResponse(resp)
+		if verr == nil {
+			return resp, nil
+		}
+		if attempt >= retries {
+			return TextResponse{}, fmt.Errorf("cora: response rejected by ValidateResponse: %w", verr)
+		}
+
+		c.logRetry(ctx, "validation", req.Provider, req.Model, req.Mode, verr)
+		req.Input = validationFeedbackInput(input, resp, verr)
+		resp, err = c.textWithFallback(ctx, req)
+		if err != nil {
+			return TextResponse{}, err
+		}
+	}
+}
+
+// validationFeedbackInput appends the rejected answer and the reason it was
+// rejected to the original input.
+func validationFeedbackInput(input string, rejected TextResponse, verr error) string {
+	previous := rejected.Text
+	if previous == "" && len(rejected.JSON) > 0 {
+		previous = string(jsonMarshalNoErr(rejected.JSON))
+	}
+	return fmt.Sprintf("%s\n\nYour previous answer:\n%s\n\nYour previous answer was rejected because: %v. Please try again.", input, previous, verr)
+}
@@ -0,0 +1,122 @@
+package cora
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestText_ValidateResponseRetriesWithFeedback(t *testing.T) {
+	fake := &echoProvider{}
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = fake
+
+	calls := 0
+	resp, err := c.Text(context.Background(), TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Input:    "Say hi",
+		ValidateResponse: func(resp TextResponse) error {
+			calls++
+			if calls == 1 {
+				return errors.New("answer must mention the weather")
+			}
+			return nil
+		},
+		RetryOnValidationFailure: true,
+	})
+	if err != nil {
+		t.Fatalf("Text error: %v", err)
+	}
+	if calls != 2 || len(fake.inputs) != 2 {
+		t.Fatalf("expected 2 attempts, got %d validations and %d provider calls", calls, len(fake.inputs))
+	}
+
+	second := fake.inputs[1]
+	if !strings.HasPrefix(second, "Say hi") {
+		t.Errorf("retry should keep the original input, got %q", second)
+	}
+	if !strings.Contains(second, "Your previous answer was rejected because: answer must mention the weather. Please try again.") {
+		t.Errorf("retry is missing the feedback, got %q", second)
+	}
+	if resp.Text != second {
+		t.Errorf("expected the retried response, got %q", resp.Text)
+	}
+}
+
+func TestText_ValidateResponseWithoutRetry(t *testing.T) {
+	fake := &echoProvider{}
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = fake
+
+	verr := errors.New("bad answer")
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:         ProviderOpenAI,
+		Model:            "gpt-test",
+		Input:            "x",
+		ValidateResponse: func(TextResponse) error { return verr },
+	})
+	if !errors.Is(err, verr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(fake.inputs) != 1 {
+		t.Errorf("expected no retry, got %d provider calls", len(fake.inputs))
+	}
+}
+
+func TestText_ValidateResponseGivesUp(t *testing.T) {
+	fake := &echoProvider{}
+	c := &Client{cfg: CoraConfig{}}
+	c.openai = fake
+
+	_, err := c.Text(context.Background(), TextRequest{
+		Provider:                 ProviderOpenAI,
+		Model:                    "gpt-test",
+		Input:                    "x",
+		ValidateResponse:         func(TextResponse) error { return errors.New("never good enough") },
+		RetryOnValidationFailure: true,
+		MaxValidationRetries:     3,
+	})
+	if err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+	if len(fake.inputs) != 4 {
+		t.Errorf("expected 1 call + 3 retries, got %d provider calls", len(fake.inputs))
+	}
+}
+
+func TestText_ValidateResponseNotCached(t *testing.T) {
+	fake := &echoProvider{}
+	c := New(CoraConfig{ResponseCacheTTL: time.Minute, ResponseCacheMaxSize: 10})
+	c.openai = fake
+
+	reject := true
+	req := TextRequest{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-test",
+		Input:    "x",
+		ValidateResponse: func(TextResponse) error {
+			if reject {
+				return errors.New("bad answer")
+			}
+			return nil
+		},
+	}
+	if _, err := c.Text(context.Background(), req); err == nil {
+		t.Fatal("expected a validation error")
+	}
+
+	// The rejected answer was not cached, so the next call asks again and
+	// its accepted answer is cached.
+	reject = false
+	for range 2 {
+		if _, err := c.Text(context.Background(), req); err != nil {
+			t.Fatalf("Text error: %v", err)
+		}
+	}
+	if len(fake.inputs) != 2 {
+		t.Errorf("expected 2 provider calls, got %d", len(fake.inputs))
+	}
+}
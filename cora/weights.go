@@ -0,0 +1,34 @@
+package cora
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+)
+
+// pickWeightedProvider samples a provider from cfg.ProviderWeights.
+// Weights are normalized, so any positive values work.
+func (c *Client) pickWeightedProvider() (Provider, error) {
+	providers := make([]Provider, 0, len(c.cfg.ProviderWeights))
+	var total float64
+	for p, w := range c.cfg.ProviderWeights {
+		if w > 0 {
+			providers = append(providers, p)
+			total += w
+		}
+	}
+	if total == 0 {
+		return "", errors.New("cora: ProviderAuto requires at least one positive entry in ProviderWeights")
+	}
+	// Sort for a stable order; map iteration order is random.
+	sort.Slice(providers, func(i, j int) bool { return providers[i] < providers[j] })
+
+	r := rand.Float64() * total
+	for _, p := range providers {
+		r -= c.cfg.ProviderWeights[p]
+		if r < 0 {
+			return p, nil
+		}
+	}
+	return providers[len(providers)-1], nil
+}
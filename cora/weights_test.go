@@ -0,0 +1,42 @@
+package cora
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestText_ProviderAutoWeights(t *testing.T) {
+	c := &Client{cfg: CoraConfig{
+		DefaultModelOpenAI: "gpt-test",
+		DefaultModelGoogle: "gemini-test",
+		ProviderWeights:    map[Provider]float64{ProviderOpenAI: 9, ProviderGoogle: 1},
+	}}
+	c.openai = &delayedProvider{text: "openai"}
+	c.google = &delayedProvider{text: "google"}
+
+	const calls = 10000
+	counts := map[Provider]int{}
+	for i := 0; i < calls; i++ {
+		resp, err := c.Text(context.Background(), TextRequest{Provider: ProviderAuto, Input: "hi"})
+		if err != nil {
+			t.Fatalf("Text error: %v", err)
+		}
+		counts[resp.UsedProvider]++
+	}
+
+	share := float64(counts[ProviderOpenAI]) / calls
+	if math.Abs(share-0.9) > 0.02 {
+		t.Errorf("expected ~90%% openai, got %.3f (%v)", share, counts)
+	}
+	if counts[ProviderOpenAI]+counts[ProviderGoogle] != calls {
+		t.Errorf("unexpected providers selected: %v", counts)
+	}
+}
+
+func TestText_ProviderAutoWithoutWeights(t *testing.T) {
+	c := &Client{cfg: CoraConfig{}}
+	if _, err := c.Text(context.Background(), TextRequest{Provider: ProviderAuto, Input: "hi"}); err == nil {
+		t.Error("expected error when ProviderWeights is empty")
+	}
+}